@@ -0,0 +1,184 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// An Archive saves every raw RDAP response received by a Client to disk,
+// providing an evidentiary trail of the lookups performed.
+//
+// Each response is saved as two files, in a directory per query:
+//
+//	<Dir>/<request type>/<query>/<timestamp>.body - The raw response body.
+//	<Dir>/<request type>/<query>/<timestamp>.json - An ArchiveRecord.
+//
+// For example, Dir/domain/example.cz/20170102T150405.000000000Z-1.body.
+//
+// Example usage:
+//
+//	client := &rdap.Client{
+//	  Archive: &rdap.Archive{Dir: "/var/lib/rdap-archive"},
+//	}
+type Archive struct {
+	// Directory to save responses in. Created as needed.
+	Dir string
+
+	counter uint64
+}
+
+// ArchiveRecord describes a single archived RDAP response.
+type ArchiveRecord struct {
+	Timestamp   time.Time         `json:"timestamp"`
+	RequestType string            `json:"request_type"`
+	Query       string            `json:"query,omitempty"`
+	URL         string            `json:"url"`
+	StatusCode  int               `json:"status_code"`
+	Header      map[string]string `json:"header,omitempty"`
+	BodyFile    string            `json:"body_file"`
+	BodySHA256  string            `json:"body_sha256"`
+	Tags        map[string]string `json:"tags,omitempty"`
+
+	// Bootstrap lookup which selected the RDAP server, if any.
+	Bootstrap *ArchiveBootstrap `json:"bootstrap,omitempty"`
+}
+
+// ArchiveBootstrap records the Service Registry entry used to select the RDAP
+// server, so the choice of server can be justified after the registry
+// changes.
+type ArchiveBootstrap struct {
+	Registry    string   `json:"registry"`
+	Description string   `json:"description,omitempty"`
+	Publication string   `json:"publication,omitempty"`
+	Version     string   `json:"version,omitempty"`
+	Query       string   `json:"query"`
+	Entry       string   `json:"entry"`
+	URLs        []string `json:"urls"`
+	Stale       bool     `json:"stale,omitempty"`
+}
+
+// NewArchive creates an Archive saving responses under |dir|.
+func NewArchive(dir string) *Archive {
+	return &Archive{
+		Dir: dir,
+	}
+}
+
+// Save archives the response |httpResponse| to the Request |req|.
+//
+// Responses without an HTTP response (e.g. network errors) are not saved.
+func (a *Archive) Save(req *Request, httpResponse *HTTPResponse) error {
+	if httpResponse.Response == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	n := atomic.AddUint64(&a.counter, 1)
+
+	dir := filepath.Join(a.Dir, archiveName(req.Type.String()), archiveName(archiveKey(req, httpResponse)))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%d", now.Format("20060102T150405.000000000Z"), n)
+
+	sum := sha256.Sum256(httpResponse.Body)
+	record := &ArchiveRecord{
+		Timestamp:   now,
+		RequestType: req.Type.String(),
+		Query:       req.Query,
+		URL:         httpResponse.URL,
+		StatusCode:  httpResponse.Response.StatusCode,
+		Header:      map[string]string{},
+		BodyFile:    name + ".body",
+		BodySHA256:  hex.EncodeToString(sum[:]),
+		Tags:        req.Tags,
+	}
+
+	if answer := req.bootstrapAnswer; answer != nil {
+		record.Bootstrap = &ArchiveBootstrap{
+			Registry:    answer.Registry.String(),
+			Description: answer.Description,
+			Publication: answer.Publication,
+			Version:     answer.Version,
+			Query:       answer.Query,
+			Entry:       answer.Entry,
+			Stale:       answer.Stale,
+		}
+
+		for _, u := range answer.URLs {
+			record.Bootstrap.URLs = append(record.Bootstrap.URLs, u.String())
+		}
+	}
+
+	for _, h := range []string{"Content-Type", "Content-Language", "Date", "Last-Modified"} {
+		if v := httpResponse.Response.Header.Get(h); v != "" {
+			record.Header[h] = v
+		}
+	}
+
+	meta, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, record.BodyFile), httpResponse.Body, 0644); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(dir, name+".json"), meta, 0644)
+}
+
+// archiveKey returns the query string to archive |httpResponse| under.
+//
+// For RawRequests (which have no query), the URL's host and path is used.
+func archiveKey(req *Request, httpResponse *HTTPResponse) string {
+	if req.Query != "" {
+		return req.Query
+	}
+
+	key := httpResponse.URL
+	if i := strings.Index(key, "://"); i != -1 {
+		key = key[i+3:]
+	}
+
+	return key
+}
+
+// archiveName returns |s| made safe for use as a single path component, on
+// any platform (e.g. ':' is invalid in Windows file names, so a host:port or
+// an IPv6 address is mapped to '_').
+func archiveName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case r == '.' || r == '-' || r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+
+	if s == "" || s == "." || s == ".." {
+		s = "_" + s
+	}
+
+	if len(s) > 200 {
+		s = s[:200]
+	}
+
+	return s
+}
@@ -0,0 +1,272 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ArchiveIndex is an in-memory index over the responses saved in an Archive.
+//
+// Each archived response is decoded once, when added to the index. Queries
+// then return the matching responses without re-decoding, e.g. to find the
+// domains using a nameserver:
+//
+//	index, err := rdap.NewArchive("/var/lib/rdap-archive").Index()
+//
+//	for _, e := range index.ByNameserver("ns1.badhost.example") {
+//	  fmt.Println(e.Domain, e.Record.Timestamp)
+//	}
+//
+// Queries return every matching archived response (i.e. all snapshots over
+// time), oldest first.
+type ArchiveIndex struct {
+	mu sync.RWMutex
+
+	entries []*ArchiveIndexEntry
+
+	domains     map[string][]*ArchiveIndexEntry
+	registrars  map[string][]*ArchiveIndexEntry
+	nameservers map[string][]*ArchiveIndexEntry
+}
+
+// ArchiveIndexEntry is a single indexed archived response.
+type ArchiveIndexEntry struct {
+	// Path of the ArchiveRecord file.
+	Path string
+
+	// The ArchiveRecord.
+	Record *ArchiveRecord
+
+	// The decoded RDAP object.
+	Object RDAPObject
+
+	// Indexed values. Names are lowercase, without a trailing dot.
+	Domain          string
+	RegistrarIANAID string
+	Nameservers     []string
+}
+
+// Index builds an ArchiveIndex of all responses saved in the Archive.
+//
+// Responses which can't be read or decoded are skipped.
+func (a *Archive) Index() (*ArchiveIndex, error) {
+	x := NewArchiveIndex()
+
+	err := filepath.Walk(a.Dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		} else if info.IsDir() || !strings.HasSuffix(path, ".json") {
+			return nil
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+
+		record := &ArchiveRecord{}
+		if err := json.Unmarshal(data, record); err != nil || record.BodyFile == "" {
+			return nil
+		}
+
+		body, err := ioutil.ReadFile(filepath.Join(filepath.Dir(path), record.BodyFile))
+		if err != nil {
+			return nil
+		}
+
+		x.Add(path, record, body)
+
+		return nil
+	})
+
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	return x, err
+}
+
+// NewArchiveIndex creates an empty ArchiveIndex.
+func NewArchiveIndex() *ArchiveIndex {
+	return &ArchiveIndex{
+		domains:     map[string][]*ArchiveIndexEntry{},
+		registrars:  map[string][]*ArchiveIndexEntry{},
+		nameservers: map[string][]*ArchiveIndexEntry{},
+	}
+}
+
+// Add decodes and indexes the archived response |body|, described by the
+// ArchiveRecord |record| saved at |path|.
+//
+// Returns nil if the response can't be decoded.
+func (x *ArchiveIndex) Add(path string, record *ArchiveRecord, body []byte) *ArchiveIndexEntry {
+	obj, err := NewDecoder(body).Decode()
+	if err != nil {
+		return nil
+	}
+
+	e := &ArchiveIndexEntry{
+		Path:   path,
+		Record: record,
+		Object: obj,
+	}
+
+	switch o := obj.(type) {
+	case *Domain:
+		e.Domain = indexName(firstNonEmpty(o.LDHName, o.UnicodeName))
+		e.RegistrarIANAID = registrarIANAID(o.Entities)
+
+		for _, n := range o.Nameservers {
+			if name := indexName(firstNonEmpty(n.LDHName, n.UnicodeName)); name != "" {
+				e.Nameservers = append(e.Nameservers, name)
+			}
+		}
+	case *Nameserver:
+		if name := indexName(firstNonEmpty(o.LDHName, o.UnicodeName)); name != "" {
+			e.Nameservers = append(e.Nameservers, name)
+		}
+	case *Entity:
+		e.RegistrarIANAID = registrarIANAID([]Entity{*o})
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.entries = append(x.entries, e)
+
+	if e.Domain != "" {
+		x.domains[e.Domain] = addIndexEntry(x.domains[e.Domain], e)
+	}
+
+	if e.RegistrarIANAID != "" {
+		x.registrars[e.RegistrarIANAID] = addIndexEntry(x.registrars[e.RegistrarIANAID], e)
+	}
+
+	for _, n := range e.Nameservers {
+		x.nameservers[n] = addIndexEntry(x.nameservers[n], e)
+	}
+
+	return e
+}
+
+// Len returns the number of indexed responses.
+func (x *ArchiveIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return len(x.entries)
+}
+
+// ByDomain returns the archived Domain responses for the domain |name|.
+func (x *ArchiveIndex) ByDomain(name string) []*ArchiveIndexEntry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return append([]*ArchiveIndexEntry{}, x.domains[indexName(name)]...)
+}
+
+// ByRegistrarIANAID returns the archived Domain (and registrar Entity)
+// responses with the registrar IANA ID |id|.
+func (x *ArchiveIndex) ByRegistrarIANAID(id string) []*ArchiveIndexEntry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return append([]*ArchiveIndexEntry{}, x.registrars[strings.TrimSpace(id)]...)
+}
+
+// ByNameserver returns the archived Domain responses delegated to the
+// nameserver |name|, and any Nameserver responses for it.
+func (x *ArchiveIndex) ByNameserver(name string) []*ArchiveIndexEntry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	return append([]*ArchiveIndexEntry{}, x.nameservers[indexName(name)]...)
+}
+
+// ByASN returns the archived Autnum responses whose range includes the AS
+// number |asn|.
+func (x *ArchiveIndex) ByASN(asn uint32) []*ArchiveIndexEntry {
+	query := &Request{Type: AutnumRequest, Query: strconv.FormatUint(uint64(asn), 10)}
+
+	return x.filter(func(e *ArchiveIndexEntry) bool {
+		_, ok := e.Object.(*Autnum)
+		return ok && verifyResponse(query, e.Object, "") == nil
+	})
+}
+
+// ByPrefix returns the archived IPNetwork responses which include the IP
+// address or CIDR prefix |prefix|, e.g. "192.0.2.0/24".
+func (x *ArchiveIndex) ByPrefix(prefix string) []*ArchiveIndexEntry {
+	return x.filter(func(e *ArchiveIndexEntry) bool {
+		n, ok := e.Object.(*IPNetwork)
+		return ok && ipNetworkContains(n, prefix)
+	})
+}
+
+// filter returns the entries matching |f|, oldest first.
+func (x *ArchiveIndex) filter(f func(e *ArchiveIndexEntry) bool) []*ArchiveIndexEntry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	var result []*ArchiveIndexEntry
+	for _, e := range x.entries {
+		if f(e) {
+			result = addIndexEntry(result, e)
+		}
+	}
+
+	return result
+}
+
+// addIndexEntry adds |e| to |entries|, keeping them sorted oldest first.
+func addIndexEntry(entries []*ArchiveIndexEntry, e *ArchiveIndexEntry) []*ArchiveIndexEntry {
+	i := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Record.Timestamp.After(e.Record.Timestamp)
+	})
+
+	entries = append(entries, nil)
+	copy(entries[i+1:], entries[i:])
+	entries[i] = e
+
+	return entries
+}
+
+// registrarIANAID returns the IANA Registrar ID of the first registrar in
+// |entities|, or an empty string.
+func registrarIANAID(entities []Entity) string {
+	for _, e := range entities {
+		isRegistrar := false
+		for _, r := range e.Roles {
+			if r == "registrar" {
+				isRegistrar = true
+			}
+		}
+
+		if !isRegistrar {
+			continue
+		}
+
+		for _, id := range e.PublicIDs {
+			if id.Type == "IANA Registrar ID" {
+				return strings.TrimSpace(id.Identifier)
+			}
+		}
+	}
+
+	return ""
+}
+
+// indexName returns the domain name |name| in the index's key format.
+func indexName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
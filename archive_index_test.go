@@ -0,0 +1,104 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestArchiveIndex(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	archive := NewArchive(t.TempDir())
+	client := &Client{
+		Verbose: verboseFunc(),
+		Archive: archive,
+	}
+
+	for _, req := range []*Request{
+		NewDomainRequest("example.cz"),
+		NewDomainRequest("example.cz"),
+		NewIPRequest(net.ParseIP("2.0.0.1")),
+	} {
+		if _, err := client.Do(req); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	// Returns a Nameserver object.
+	client.Do(NewDomainRequest("wrong-response-type.cz"))
+
+	index, err := archive.Index()
+	if err != nil {
+		t.Fatalf("Index() error: %s", err)
+	} else if index.Len() != 4 {
+		t.Fatalf("Expected 4 indexed responses, got %d", index.Len())
+	}
+
+	if entries := index.ByDomain("EXAMPLE.CZ."); len(entries) != 2 {
+		t.Errorf("ByDomain: expected 2 snapshots, got %d", len(entries))
+	} else if entries[0].Record.Timestamp.After(entries[1].Record.Timestamp) {
+		t.Errorf("ByDomain: entries not oldest first")
+	}
+
+	if entries := index.ByNameserver("ns2.pipni.cz"); len(entries) != 3 {
+		t.Errorf("ByNameserver: expected 3 entries, got %d", len(entries))
+	}
+
+	if entries := index.ByPrefix("2.0.0.128/25"); len(entries) != 1 {
+		t.Errorf("ByPrefix: expected 1 entry, got %d", len(entries))
+	}
+
+	if entries := index.ByPrefix("2.0.1.0"); len(entries) != 0 {
+		t.Errorf("ByPrefix: expected no entries, got %d", len(entries))
+	}
+}
+
+func TestArchiveIndexAdd(t *testing.T) {
+	index := NewArchiveIndex()
+
+	record := &ArchiveRecord{Timestamp: time.Now()}
+
+	index.Add("autnum.json", record, []byte(`{
+		"objectClassName": "autnum",
+		"handle": "AS64496-AS64511",
+		"startAutnum": 64496,
+		"endAutnum": 64511
+	}`))
+
+	index.Add("domain.json", record, []byte(`{
+		"objectClassName": "domain",
+		"ldhName": "example.com",
+		"entities": [
+			{
+				"objectClassName": "entity",
+				"roles": ["registrar"],
+				"publicIds": [{"type": "IANA Registrar ID", "identifier": "9999"}]
+			}
+		]
+	}`))
+
+	if e := index.Add("malformed.json", record, []byte(`{`)); e != nil {
+		t.Errorf("Expected malformed response to be skipped")
+	}
+
+	if entries := index.ByASN(64500); len(entries) != 1 || entries[0].Path != "autnum.json" {
+		t.Errorf("ByASN: unexpected result %v", entries)
+	}
+
+	if entries := index.ByASN(64512); len(entries) != 0 {
+		t.Errorf("ByASN: expected no entries, got %d", len(entries))
+	}
+
+	if entries := index.ByRegistrarIANAID("9999"); len(entries) != 1 || entries[0].Domain != "example.com" {
+		t.Errorf("ByRegistrarIANAID: unexpected result %v", entries)
+	}
+}
@@ -0,0 +1,75 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientArchive(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	dir := t.TempDir()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+		Archive: NewArchive(dir),
+	}
+
+	req := NewDomainRequest("example.cz")
+	req.Tags = map[string]string{"case": "1234"}
+
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "domain", "example.cz", "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("Expected 1 archive record, got %v", files)
+	}
+
+	data, _ := ioutil.ReadFile(files[0])
+
+	var record ArchiveRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("Bad archive record: %s", err)
+	}
+
+	if record.URL != "https://rdap.nic.cz/domain/example.cz" || record.StatusCode != 200 || record.Tags["case"] != "1234" {
+		t.Errorf("Unexpected archive record %+v", record)
+	}
+
+	if b := record.Bootstrap; b == nil || b.Registry != "dns" || b.Entry != "cz" || b.Publication == "" || len(b.URLs) == 0 {
+		t.Errorf("Unexpected archive bootstrap record %+v", record.Bootstrap)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(filepath.Dir(files[0]), record.BodyFile))
+	if err != nil || !strings.Contains(string(body), `"ldhName"`) {
+		t.Errorf("Archived body missing")
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := map[string]string{
+		"example.cz":                   "example.cz",
+		"..":                           "_..",
+		"rdap.nic.cz/domain/a b?c=d":   "rdap.nic.cz_domain_a_b_c_d",
+		"localhost:8080/ip/2001:db8::": "localhost_8080_ip_2001_db8__",
+	}
+
+	for input, expected := range tests {
+		if actual := archiveName(input); actual != expected {
+			t.Errorf("archiveName(%q) = %q, expected %q", input, actual, expected)
+		}
+	}
+}
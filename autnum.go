@@ -28,4 +28,5 @@ type Autnum struct {
 	Links       []Link
 	Port43      string
 	Events      []Event
+	Redacted    []Redaction
 }
@@ -18,6 +18,32 @@ type Answer struct {
 	// Matching service entry. Empty string if no match.
 	Entry string
 
+	// Service Registry the answer came from.
+	Registry RegistryType
+
+	// The "description", "publication", and "version" fields of the Service
+	// Registry file used, identifying exactly which copy of the file the
+	// answer came from.
+	Description string
+	Publication string
+	Version     string
+
 	// List of RDAP base URLs.
 	URLs []*url.URL
+
+	// True if the Service Registry file was downloaded to answer the
+	// question, false if a cached copy was used.
+	Downloaded bool
+
+	// True if the Service Registry file had expired, but could not be
+	// downloaded, so a stale cached copy was used. See Client.MaxStaleness.
+	Stale bool
+
+	// True if the Service Registry file could not be downloaded, and was not
+	// cached, so the embedded snapshot was used. See Client.EnableEmbedded.
+	Embedded bool
+
+	// True if the answer came from an override (see Client.AddOverride()),
+	// rather than a Service Registry file.
+	Override bool
 }
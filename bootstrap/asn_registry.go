@@ -11,6 +11,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+
+	"github.com/openrdap/rdap/internal/autnum"
 )
 
 type ASNRegistry struct {
@@ -89,10 +91,11 @@ func NewASNRegistry(json []byte) (*ASNRegistry, error) {
 
 // Lookup returns the RDAP base URLs for the AS number question |question|.
 //
-// Example queries are: "AS1234", "as1234", and "1234".
+// Example queries are: "AS1234", "as1234", and "1234". 4-byte AS numbers
+// may also be in asdot notation, e.g. "AS1.10" (for AS65546).
 func (a *ASNRegistry) Lookup(question *Question) (*Answer, error) {
 	var asn uint32
-	asn, err := parseASN(question.Query)
+	asn, err := autnum.Parse(question.Query)
 
 	if err != nil {
 		return nil, err
@@ -122,18 +125,6 @@ func (a *ASNRegistry) File() *File {
 	return a.file
 }
 
-func parseASN(asn string) (uint32, error) {
-	asn = strings.ToLower(asn)
-	asn = strings.TrimLeft(asn, "as")
-	result, err := strconv.ParseUint(asn, 10, 32)
-
-	if err != nil {
-		return 0, err
-	}
-
-	return uint32(result), nil
-}
-
 func parseASNRange(asnRange string) (uint32, uint32, error) {
 	var minASN uint64
 	var maxASN uint64
@@ -165,3 +156,9 @@ func parseASNRange(asnRange string) (uint32, uint32, error) {
 
 	return uint32(minASN), uint32(maxASN), nil
 }
+
+// EntriesFor returns the AS number ranges served by the RDAP base URL
+// |baseURL|. See File.EntriesFor.
+func (a *ASNRegistry) EntriesFor(baseURL string) []string {
+	return a.file.EntriesFor(baseURL)
+}
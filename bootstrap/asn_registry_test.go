@@ -42,6 +42,18 @@ func TestNetRegistryLookupsASN(t *testing.T) {
 			"AS265629-AS266652",
 			[]string{"https://rdap.lacnic.net/rdap/"},
 		},
+		{
+			"AS4.4508",
+			false,
+			"AS265629-AS266652",
+			[]string{"https://rdap.lacnic.net/rdap/"},
+		},
+		{
+			"AS1.65536",
+			true,
+			"",
+			[]string{},
+		},
 		{
 			"not-a-number",
 			true,
@@ -0,0 +1,262 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package cache
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltBootstrapBucket = []byte("bootstrap")
+	boltResponsesBucket = []byte("responses")
+)
+
+// A BoltCache caches Service Registry files, and optionally RDAP responses,
+// in a single-file embedded database (bbolt).
+//
+// Unlike DiskCache's loose files, updates are atomic, and concurrent access
+// by multiple processes is safe: the database is opened for each operation,
+// with a file lock held for its duration.
+//
+// As with DiskCache, files saved by other processes are reported as
+// ShouldReload.
+//
+// For RDAP response caching, see SaveResponse() and LoadResponse(), and
+// rdap.Client.ResponseStore.
+type BoltCache struct {
+	// Duration Service Registry files are stored before they're considered
+	// expired.
+	//
+	// The default is 24 hours.
+	Timeout time.Duration
+
+	// Path of the database file. It's created automatically as needed.
+	Path string
+
+	// Maximum duration to wait for another process to release the database.
+	//
+	// The default is 5 seconds.
+	LockTimeout time.Duration
+
+	mu                sync.Mutex
+	lastLoadedModTime map[string]time.Time
+}
+
+// NewBoltCache creates a new BoltCache, using the database file |path|.
+func NewBoltCache(path string) *BoltCache {
+	return &BoltCache{
+		Timeout:           time.Hour * 24,
+		Path:              path,
+		LockTimeout:       5 * time.Second,
+		lastLoadedModTime: make(map[string]time.Time),
+	}
+}
+
+// SetTimeout sets the duration each Service Registry file can be stored before
+// its State() is Expired.
+func (b *BoltCache) SetTimeout(timeout time.Duration) {
+	b.Timeout = timeout
+}
+
+// Save saves the file |filename| with |data| to the database.
+func (b *BoltCache) Save(filename string, data []byte) error {
+	mtime := time.Now()
+
+	err := b.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltBootstrapBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(filename), encodeBoltValue(mtime, data))
+	})
+
+	if err != nil {
+		return fmt.Errorf("File %s failed to save correctly: %s", filename, err)
+	}
+
+	b.mu.Lock()
+	b.lastLoadedModTime[filename] = mtime
+	b.mu.Unlock()
+
+	return nil
+}
+
+// Load loads the file |filename| from the database.
+//
+// Since Service Registry files do not change much, the file is returned even
+// if its State() is Expired.
+//
+// An error is returned if the file is not in the database.
+func (b *BoltCache) Load(filename string) ([]byte, error) {
+	mtime, data, err := b.get(boltBootstrapBucket, filename)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load %s: %s", filename, err)
+	}
+
+	b.mu.Lock()
+	b.lastLoadedModTime[filename] = mtime
+	b.mu.Unlock()
+
+	return data, nil
+}
+
+// State returns the cache state of the file |filename|.
+//
+// The returned state is one of: Absent, Good, ShouldReload, Expired.
+func (b *BoltCache) State(filename string) FileState {
+	fileModTime, err := b.ModTime(filename)
+	if err != nil {
+		return Absent
+	}
+
+	if !fileModTime.After(time.Now().Add(-b.Timeout)) {
+		return Expired
+	}
+
+	b.mu.Lock()
+	lastLoadedModTime, haveLoaded := b.lastLoadedModTime[filename]
+	b.mu.Unlock()
+
+	if haveLoaded && !fileModTime.After(lastLoadedModTime) {
+		return Good
+	}
+
+	return ShouldReload
+}
+
+// ModTime returns the time the file |filename| was saved.
+func (b *BoltCache) ModTime(filename string) (time.Time, error) {
+	mtime, _, err := b.get(boltBootstrapBucket, filename)
+
+	return mtime, err
+}
+
+// SaveResponse saves the RDAP response |data| under |key|, for |ttl|.
+func (b *BoltCache) SaveResponse(key string, data []byte, ttl time.Duration) error {
+	return b.update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltResponsesBucket)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put([]byte(key), encodeBoltValue(time.Now().Add(ttl), data))
+	})
+}
+
+// LoadResponse returns the RDAP response saved under |key|.
+//
+// An error is returned if the response is not in the database, or has
+// expired.
+func (b *BoltCache) LoadResponse(key string) ([]byte, error) {
+	expires, data, err := b.get(boltResponsesBucket, key)
+	if err != nil {
+		return nil, err
+	} else if time.Now().After(expires) {
+		return nil, errors.New("Response expired")
+	}
+
+	return data, nil
+}
+
+// PurgeExpiredResponses deletes the expired RDAP responses from the
+// database, and returns the number deleted.
+func (b *BoltCache) PurgeExpiredResponses() (int, error) {
+	now := time.Now()
+	n := 0
+
+	err := b.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltResponsesBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if expires, _, err := decodeBoltValue(v); err != nil || now.After(expires) {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+				n++
+			}
+		}
+
+		return nil
+	})
+
+	return n, err
+}
+
+// get returns the time and data saved under |key| in |bucket|.
+func (b *BoltCache) get(bucketName []byte, key string) (time.Time, []byte, error) {
+	if _, err := os.Stat(b.Path); err != nil {
+		return time.Time{}, nil, err
+	}
+
+	db, err := bolt.Open(b.Path, 0664, &bolt.Options{Timeout: b.LockTimeout, ReadOnly: true})
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	defer db.Close()
+
+	var t time.Time
+	var data []byte
+
+	err = db.View(func(tx *bolt.Tx) error {
+		var v []byte
+		if bucket := tx.Bucket(bucketName); bucket != nil {
+			v = bucket.Get([]byte(key))
+		}
+
+		if v == nil {
+			return fmt.Errorf("%s not in cache", key)
+		}
+
+		var err error
+		t, data, err = decodeBoltValue(v)
+
+		// Copy, as |v| is only valid during the transaction.
+		data = append([]byte{}, data...)
+
+		return err
+	})
+
+	return t, data, err
+}
+
+// update runs |f| in a read-write transaction.
+func (b *BoltCache) update(f func(tx *bolt.Tx) error) error {
+	db, err := bolt.Open(b.Path, 0664, &bolt.Options{Timeout: b.LockTimeout})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return db.Update(f)
+}
+
+// encodeBoltValue encodes the time |t| and |data| as a database value.
+func encodeBoltValue(t time.Time, data []byte) []byte {
+	v := make([]byte, 8, 8+len(data))
+	binary.BigEndian.PutUint64(v, uint64(t.UnixNano()))
+
+	return append(v, data...)
+}
+
+// decodeBoltValue decodes a database value encoded by encodeBoltValue().
+func decodeBoltValue(v []byte) (time.Time, []byte, error) {
+	if len(v) < 8 {
+		return time.Time{}, nil, errors.New("malformed cache entry")
+	}
+
+	return time.Unix(0, int64(binary.BigEndian.Uint64(v))), v[8:], nil
+}
@@ -0,0 +1,98 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package cache
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBoltCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rdap.db")
+
+	m1 := NewBoltCache(path)
+	m2 := NewBoltCache(path)
+
+	asn1 := []byte("file 1")
+	asn2 := []byte("file 2")
+
+	if m1.State("asn.json") != Absent {
+		t.Fatalf("asn.json expected absent in m1")
+	}
+
+	if err := m1.Save("asn.json", asn1); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+
+	if m1.State("asn.json") != Good {
+		t.Fatalf("asn.json expected good in m1")
+	} else if m2.State("asn.json") != ShouldReload {
+		t.Fatalf("asn.json expected shouldreload in m2")
+	} else if m1.State("dns.json") != Absent {
+		t.Fatalf("dns.json expected absent in m1")
+	}
+
+	loaded, err := m2.Load("asn.json")
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(loaded, asn1) {
+		t.Fatalf("loaded(%q) != asn1(%q)", loaded, asn1)
+	} else if m2.State("asn.json") != Good {
+		t.Fatalf("asn.json expected good in m2")
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if err := m2.Save("asn.json", asn2); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+
+	if m1.State("asn.json") != ShouldReload {
+		t.Fatalf("asn.json expected shouldreload in m1")
+	}
+
+	m1.SetTimeout(0)
+	if m1.State("asn.json") != Expired {
+		t.Fatalf("asn.json expected expired in m1")
+	}
+
+	if loaded, err := m1.Load("asn.json"); err != nil || !bytes.Equal(loaded, asn2) {
+		t.Fatalf("Load of expired file failed: %q %v", loaded, err)
+	}
+}
+
+func TestBoltCacheResponses(t *testing.T) {
+	b := NewBoltCache(filepath.Join(t.TempDir(), "rdap.db"))
+
+	if _, err := b.LoadResponse("a"); err == nil {
+		t.Fatalf("LoadResponse of absent response unexpectedly succeeded")
+	}
+
+	if err := b.SaveResponse("a", []byte("response a"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := b.SaveResponse("b", []byte("response b"), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := b.LoadResponse("a"); err != nil || string(data) != "response a" {
+		t.Errorf("Unexpected LoadResponse result %q %v", data, err)
+	}
+
+	if _, err := b.LoadResponse("b"); err == nil {
+		t.Errorf("LoadResponse of expired response unexpectedly succeeded")
+	}
+
+	if n, err := b.PurgeExpiredResponses(); err != nil || n != 1 {
+		t.Errorf("Unexpected PurgeExpiredResponses result %d %v", n, err)
+	}
+
+	if _, err := b.LoadResponse("a"); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+}
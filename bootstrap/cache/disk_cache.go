@@ -10,19 +10,29 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	homedir "github.com/mitchellh/go-homedir"
 )
 
 const (
-	defaultCacheDirName = ".openrdap"
+	defaultCacheDirName = "openrdap"
+	legacyCacheDirName  = ".openrdap"
 )
 
 // A DiskCache caches Service Registry files on disk.
 //
-// By default they're saved as $HOME/.openrdap/{asn,dns,ipv4,ipv6}.json. File
-// mtimes are used to calculate cache expiry.
+// By default they're saved in the platform's user cache directory (see
+// os.UserCacheDir()), as e.g. $XDG_CACHE_HOME/openrdap/{asn,dns,ipv4,ipv6}.json
+// on Linux, $HOME/Library/Caches/openrdap/ on macOS, or
+// %LocalAppData%\openrdap\ on Windows. File mtimes are used to calculate cache
+// expiry.
+//
+// The legacy $HOME/.openrdap directory is used instead if it already exists
+// (and the new directory doesn't), or if the user cache directory can't be
+// determined. See DiskCacheOptions.
 //
 // The cache directory is created automatically as needed.
 type DiskCache struct {
@@ -33,30 +43,82 @@ type DiskCache struct {
 
 	// Directory to store cached files in.
 	//
-	// The default is $HOME/.openrdap.
+	// The default is the openrdap directory in the user cache directory, see
+	// DiskCache.
 	Dir string
 
+	// Pruning policy. If set, files are pruned after each Save(), see
+	// Prune().
+	//
+	// MaxAge is the maximum age of cached files, and MaxSize the maximum
+	// total size of the cache directory in bytes. The defaults (zero) are
+	// unlimited.
+	MaxAge  time.Duration
+	MaxSize int64
+
 	lastLoadedModTime map[string]time.Time
 }
 
-// NewDiskCache creates a new DiskCache.
+// DiskCacheOptions specifies options for NewDiskCacheWithOptions.
+type DiskCacheOptions struct {
+	// UseLegacyDir selects the legacy $HOME/.openrdap cache directory,
+	// instead of the user cache directory.
+	UseLegacyDir bool
+}
+
+// NewDiskCache creates a new DiskCache, using the default cache directory.
 func NewDiskCache() *DiskCache {
+	return NewDiskCacheWithOptions(DiskCacheOptions{})
+}
+
+// NewDiskCacheWithOptions creates a new DiskCache, with |options|.
+func NewDiskCacheWithOptions(options DiskCacheOptions) *DiskCache {
 	d := &DiskCache{
 		lastLoadedModTime: make(map[string]time.Time),
 		Timeout:           time.Hour * 24,
 	}
 
-	dir, err := homedir.Dir()
+	var userCacheDir string
+	if !options.UseLegacyDir {
+		userCacheDir, _ = os.UserCacheDir()
+	}
 
-	if err != nil {
+	var legacyDir string
+	if home, err := homedir.Dir(); err == nil {
+		legacyDir = filepath.Join(home, legacyCacheDirName)
+	} else if userCacheDir == "" {
 		panic("Can't determine your home directory")
 	}
 
-	d.Dir = filepath.Join(dir, defaultCacheDirName)
+	d.Dir = chooseCacheDir(userCacheDir, legacyDir)
 
 	return d
 }
 
+// chooseCacheDir returns the cache directory to use, given the user cache
+// directory |userCacheDir|, and the legacy cache directory |legacyDir|
+// (either may be empty string if unknown).
+func chooseCacheDir(userCacheDir string, legacyDir string) string {
+	if userCacheDir == "" {
+		return legacyDir
+	}
+
+	dir := filepath.Join(userCacheDir, defaultCacheDirName)
+
+	// Keep using an existing legacy cache.
+	if legacyDir != "" && isDir(legacyDir) && !isDir(dir) {
+		return legacyDir
+	}
+
+	return dir
+}
+
+func isDir(path string) bool {
+	fileInfo, err := os.Stat(path)
+
+	return err == nil && fileInfo.IsDir()
+}
+
 // InitDir creates the cache directory if it does not already exist.
 //
 // Returns true if the directory was created, or false if it already exists/or
@@ -72,7 +134,7 @@ func (d *DiskCache) InitDir() (bool, error) {
 	}
 
 	if os.IsNotExist(err) {
-		err := os.Mkdir(d.Dir, 0775)
+		err := os.MkdirAll(d.Dir, 0775)
 		if err == nil {
 			return true, nil
 		} else {
@@ -91,18 +153,24 @@ func (d *DiskCache) SetTimeout(timeout time.Duration) {
 
 // Save saves the file |filename| with |data| to disk.
 //
-// The cache directory is created if necessary.
+// The cache directory is created if necessary. The file is replaced
+// atomically, so processes sharing the cache directory never see a partially
+// written file.
 func (d *DiskCache) Save(filename string, data []byte) error {
 	_, err := d.InitDir()
 	if err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(d.cacheDirPath(filename), data, 0664)
+	err = d.writeFile(filename, data)
 	if err != nil {
 		return err
 	}
 
+	if d.MaxAge > 0 || d.MaxSize > 0 {
+		d.prune(filename)
+	}
+
 	fileModTime, err := d.modTime(filename)
 	if err == nil {
 		d.lastLoadedModTime[filename] = fileModTime
@@ -161,6 +229,11 @@ func (d *DiskCache) State(filename string) FileState {
 	return state
 }
 
+// ModTime returns the time the file |filename| was saved.
+func (d *DiskCache) ModTime(filename string) (time.Time, error) {
+	return d.modTime(filename)
+}
+
 func (d *DiskCache) modTime(filename string) (time.Time, error) {
 	var fileInfo os.FileInfo
 	fileInfo, err := os.Stat(d.cacheDirPath(filename))
@@ -172,6 +245,177 @@ func (d *DiskCache) modTime(filename string) (time.Time, error) {
 	return fileInfo.ModTime(), nil
 }
 
+// Prune removes files from the cache directory, as per MaxAge and MaxSize.
+//
+// Only files written by the cache are considered (see isCacheFile()), so
+// other files in the directory are left alone.
+//
+// Files older than MaxAge are removed first, then the oldest files until the
+// total size is within MaxSize.
+//
+// Returns the number of files removed.
+func (d *DiskCache) Prune() (int, error) {
+	return d.prune("")
+}
+
+// prune implements Prune, never removing the file |keep| (e.g. the file just
+// saved).
+func (d *DiskCache) prune(keep string) (int, error) {
+	files, err := ioutil.ReadDir(d.Dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	var regular []os.FileInfo
+	var totalSize int64
+	for _, f := range files {
+		if f.Mode().IsRegular() && isCacheFile(f.Name()) {
+			regular = append(regular, f)
+			totalSize += f.Size()
+		}
+	}
+
+	// Oldest first.
+	sort.Slice(regular, func(i, j int) bool {
+		return regular[i].ModTime().Before(regular[j].ModTime())
+	})
+
+	var numRemoved int
+	var firstErr error
+	for _, f := range regular {
+		tooOld := d.MaxAge > 0 && time.Since(f.ModTime()) > d.MaxAge
+		tooBig := d.MaxSize > 0 && totalSize > d.MaxSize
+
+		if f.Name() == keep || !(tooOld || tooBig) {
+			continue
+		}
+
+		if err := os.Remove(d.cacheDirPath(f.Name())); err != nil && !os.IsNotExist(err) {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		delete(d.lastLoadedModTime, f.Name())
+		totalSize -= f.Size()
+		numRemoved++
+	}
+
+	return numRemoved, firstErr
+}
+
+// Purge removes all cached files from the cache directory.
+//
+// Other files (see isCacheFile()), and the directory itself, are kept.
+func (d *DiskCache) Purge() error {
+	files, err := ioutil.ReadDir(d.Dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if !f.Mode().IsRegular() || !isCacheFile(f.Name()) {
+			continue
+		}
+
+		if err := os.Remove(d.cacheDirPath(f.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	d.lastLoadedModTime = make(map[string]time.Time)
+
+	return nil
+}
+
+// registryFilenames are the Service Registry filenames the bootstrap client
+// saves, see isCacheFile().
+var registryFilenames = []string{"asn.json", "dns.json", "ipv4.json", "ipv6.json", "object-tags.json"}
+
+// isCacheFile returns true if |filename| is a file written by the cache: a
+// Service Registry file (e.g. dns.json, or 012def_dns.json for a custom
+// bootstrap service), its validators (dns.json.validators), or a temporary
+// file left by an interrupted writeFile() (dns.json.tmp123456).
+//
+// The cache directory may be user supplied (e.g. $HOME by mistake), so other
+// files (even e.g. package.json) must never be removed.
+func isCacheFile(filename string) bool {
+	// Temporary file?
+	if i := strings.LastIndex(filename, ".tmp"); i != -1 {
+		if !isDigits(filename[i+len(".tmp"):]) {
+			return false
+		}
+		filename = filename[:i]
+	}
+
+	filename = strings.TrimSuffix(filename, ".validators")
+
+	// Namespace prefix?
+	if i := strings.IndexByte(filename, '_'); i == 6 && isHex(filename[:i]) {
+		filename = filename[i+1:]
+	}
+
+	for _, f := range registryFilenames {
+		if filename == f {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDigits returns true if |s| consists of decimal digits only.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isHex returns true if |s| consists of lowercase hexadecimal digits only.
+func isHex(s string) bool {
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (d *DiskCache) cacheDirPath(filename string) string {
 	return filepath.Join(d.Dir, filename)
 }
+
+// writeFile writes |data| to a temporary file, then renames it to |filename|.
+func (d *DiskCache) writeFile(filename string, data []byte) error {
+	f, err := ioutil.TempFile(d.Dir, filename+".tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0664)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), d.cacheDirPath(filename))
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+	}
+
+	return err
+}
@@ -102,3 +102,112 @@ func TestDiskCache(t *testing.T) {
 	}
 }
 
+
+func TestDiskCacheDir(t *testing.T) {
+	userCacheDir := t.TempDir()
+	legacyDir := filepath.Join(t.TempDir(), ".openrdap")
+	dir := filepath.Join(userCacheDir, "openrdap")
+
+	if d := chooseCacheDir(userCacheDir, legacyDir); d != dir {
+		t.Errorf("Expected %s, got %s", dir, d)
+	}
+
+	if d := chooseCacheDir("", legacyDir); d != legacyDir {
+		t.Errorf("Expected fallback to %s, got %s", legacyDir, d)
+	}
+
+	// An existing legacy cache is kept.
+	if err := os.Mkdir(legacyDir, 0775); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := chooseCacheDir(userCacheDir, legacyDir); d != legacyDir {
+		t.Errorf("Expected existing %s, got %s", legacyDir, d)
+	}
+
+	// Unless the new cache also exists.
+	m := NewDiskCache()
+	m.Dir = dir
+
+	if created, err := m.InitDir(); !created || err != nil {
+		t.Fatalf("InitDir() failed: %v %s", created, err)
+	}
+
+	if d := chooseCacheDir(userCacheDir, legacyDir); d != dir {
+		t.Errorf("Expected %s, got %s", dir, d)
+	}
+}
+
+func TestDiskCachePrune(t *testing.T) {
+	d := NewDiskCache()
+	d.Dir = t.TempDir()
+
+	for i, filename := range []string{"asn.json", "dns.json", "ipv4.json"} {
+		if err := d.Save(filename, bytes.Repeat([]byte("x"), 100)); err != nil {
+			t.Fatalf("Save() error: %s", err)
+		}
+
+		modTime := time.Now().Add(time.Duration(i-3) * time.Hour)
+		os.Chtimes(filepath.Join(d.Dir, filename), modTime, modTime)
+	}
+
+	// asn.json is 3 hours old.
+	d.MaxAge = 150 * time.Minute
+	if n, err := d.Prune(); err != nil || n != 1 {
+		t.Errorf("Expected 1 file pruned by age, got %d, %v", n, err)
+	} else if d.State("asn.json") != Absent || d.State("dns.json") == Absent {
+		t.Errorf("Expected only asn.json pruned by age")
+	}
+
+	// Saving prunes the oldest files, but never the file saved.
+	d.MaxAge = 0
+	d.MaxSize = 250
+	if err := d.Save("ipv6.json", bytes.Repeat([]byte("x"), 100)); err != nil {
+		t.Fatalf("Save() error: %s", err)
+	} else if d.State("dns.json") != Absent || d.State("ipv4.json") == Absent || d.State("ipv6.json") == Absent {
+		t.Errorf("Expected only dns.json pruned by size")
+	}
+
+	if err := d.Purge(); err != nil {
+		t.Fatalf("Purge() error: %s", err)
+	}
+
+	files, _ := ioutil.ReadDir(d.Dir)
+	if len(files) != 0 {
+		t.Errorf("Expected no files after Purge(), got %d", len(files))
+	}
+}
+
+func TestDiskCacheKeepsOtherFiles(t *testing.T) {
+	d := NewDiskCache()
+	d.Dir = t.TempDir()
+
+	// e.g. --cache-dir pointed at $HOME by mistake.
+	old := time.Now().Add(-time.Hour)
+	for _, filename := range []string{"notes.txt", ".bashrc", "photo.jpg", "foo.json", "package.json", "dns.json.bak", "abc_dns.json"} {
+		path := filepath.Join(d.Dir, filename)
+		ioutil.WriteFile(path, bytes.Repeat([]byte("x"), 1000), 0644)
+		os.Chtimes(path, old, old)
+	}
+
+	for _, filename := range []string{"dns.json", "012def_dns.json.validators", "asn.json.tmp123"} {
+		ioutil.WriteFile(filepath.Join(d.Dir, filename), []byte("x"), 0644)
+		os.Chtimes(filepath.Join(d.Dir, filename), old, old)
+	}
+
+	d.MaxAge = time.Minute
+	d.MaxSize = 1
+	if n, err := d.Prune(); err != nil || n != 3 {
+		t.Errorf("Expected 3 cache files pruned, got %d, %v", n, err)
+	}
+
+	d.Save("ipv4.json", []byte("x"))
+	if err := d.Purge(); err != nil {
+		t.Fatalf("Purge() error: %s", err)
+	}
+
+	files, _ := ioutil.ReadDir(d.Dir)
+	if len(files) != 7 {
+		t.Errorf("Expected the 7 other files kept, got %d", len(files))
+	}
+}
@@ -60,6 +60,17 @@ func (m *MemoryCache) Load(filename string) ([]byte, error) {
 	return result, nil
 }
 
+// ModTime returns the time the file |filename| was saved.
+func (m *MemoryCache) ModTime(filename string) (time.Time, error) {
+	mtime, ok := m.mtime[filename]
+
+	if !ok {
+		return time.Time{}, fmt.Errorf("File %s not in cache", filename)
+	}
+
+	return mtime, nil
+}
+
 // State returns the cache state of the file |filename|.
 //
 // The returned state is one of: Absent, Good, Expired.
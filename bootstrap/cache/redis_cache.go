@@ -0,0 +1,336 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRedisKeyPrefix = "openrdap:bootstrap:"
+)
+
+// A RedisCache caches Service Registry files in a Redis server.
+//
+// This lets a fleet of stateless processes share one bootstrap cache, so only
+// one of them needs to download each Service Registry file per Timeout:
+//
+//	b := &bootstrap.Client{
+//	  Cache: cache.NewRedisCache("redis.internal:6379"),
+//	}
+//
+// Each file is stored as a Redis hash, with fields "data" (the file) and
+// "mtime" (the save time, in Unix nanoseconds). Keys don't expire in Redis:
+// expired files can still be Load()'ed, as per the other caches.
+//
+// As with DiskCache, files saved by other processes are reported as
+// ShouldReload.
+type RedisCache struct {
+	// Duration files are stored before they're considered expired.
+	//
+	// The default is 24 hours.
+	Timeout time.Duration
+
+	// Address of the Redis server, e.g. "localhost:6379".
+	Addr string
+
+	// Optional password (Redis AUTH), and database number (SELECT).
+	Password string
+	DB       int
+
+	// Key prefix. The default is "openrdap:bootstrap:".
+	KeyPrefix string
+
+	// Timeout for connecting to, and each command sent to, the Redis server.
+	//
+	// The default is 5 seconds.
+	NetTimeout time.Duration
+
+	mu                sync.Mutex
+	conn              net.Conn
+	reader            *bufio.Reader
+	lastLoadedModTime map[string]time.Time
+}
+
+// NewRedisCache creates a new RedisCache, using the Redis server at |addr|.
+//
+// The connection is established on first use.
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{
+		Timeout:           time.Hour * 24,
+		Addr:              addr,
+		KeyPrefix:         defaultRedisKeyPrefix,
+		NetTimeout:        5 * time.Second,
+		lastLoadedModTime: make(map[string]time.Time),
+	}
+}
+
+// SetTimeout sets the duration each Service Registry file can be stored before
+// its State() is Expired.
+func (r *RedisCache) SetTimeout(timeout time.Duration) {
+	r.Timeout = timeout
+}
+
+// Save saves the file |filename| with |data| to Redis.
+func (r *RedisCache) Save(filename string, data []byte) error {
+	mtime := time.Now()
+
+	_, err := r.do("HSET", r.key(filename), "data", string(data), "mtime", strconv.FormatInt(mtime.UnixNano(), 10))
+	if err != nil {
+		return fmt.Errorf("File %s failed to save to Redis: %s", filename, err)
+	}
+
+	r.mu.Lock()
+	r.lastLoadedModTime[filename] = mtime
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Load loads the file |filename| from Redis.
+//
+// Since Service Registry files do not change much, the file is returned even
+// if its State() is Expired.
+//
+// An error is returned if the file is not in Redis.
+func (r *RedisCache) Load(filename string) ([]byte, error) {
+	reply, err := r.do("HMGET", r.key(filename), "data", "mtime")
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load %s: %s", filename, err)
+	}
+
+	fields, ok := reply.([]interface{})
+	if !ok || len(fields) != 2 || fields[0] == nil || fields[1] == nil {
+		return nil, fmt.Errorf("File %s not in cache", filename)
+	}
+
+	mtime, err := parseRedisModTime(fields[1])
+	if err != nil {
+		return nil, fmt.Errorf("Unable to load %s: %s", filename, err)
+	}
+
+	r.mu.Lock()
+	r.lastLoadedModTime[filename] = mtime
+	r.mu.Unlock()
+
+	return []byte(fields[0].(string)), nil
+}
+
+// State returns the cache state of the file |filename|.
+//
+// The returned state is one of: Absent, Good, ShouldReload, Expired. Redis
+// errors are reported as Absent.
+func (r *RedisCache) State(filename string) FileState {
+	fileModTime, err := r.ModTime(filename)
+	if err != nil {
+		return Absent
+	}
+
+	if !fileModTime.After(time.Now().Add(-r.Timeout)) {
+		return Expired
+	}
+
+	r.mu.Lock()
+	lastLoadedModTime, haveLoaded := r.lastLoadedModTime[filename]
+	r.mu.Unlock()
+
+	if haveLoaded && !fileModTime.After(lastLoadedModTime) {
+		return Good
+	}
+
+	return ShouldReload
+}
+
+// ModTime returns the time the file |filename| was saved.
+func (r *RedisCache) ModTime(filename string) (time.Time, error) {
+	reply, err := r.do("HGET", r.key(filename), "mtime")
+	if err != nil {
+		return time.Time{}, err
+	} else if reply == nil {
+		return time.Time{}, fmt.Errorf("File %s not in cache", filename)
+	}
+
+	return parseRedisModTime(reply)
+}
+
+// Close closes the connection to the Redis server, if open.
+func (r *RedisCache) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		return nil
+	}
+
+	err := r.conn.Close()
+	r.conn = nil
+
+	return err
+}
+
+func (r *RedisCache) key(filename string) string {
+	return r.KeyPrefix + filename
+}
+
+// do sends the command |args| to Redis, and returns the reply.
+//
+// The connection is (re)established as needed. Replies are decoded as
+// string, int64, nil, or []interface{}. Redis error replies are returned as
+// errors.
+func (r *RedisCache) do(args ...string) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn == nil {
+		if err := r.connect(); err != nil {
+			return nil, err
+		}
+	}
+
+	reply, err := r.roundTrip(args)
+
+	// Drop the connection on network errors, so the next command reconnects.
+	var redisErr redisError
+	if err != nil && !errors.As(err, &redisErr) {
+		r.conn.Close()
+		r.conn = nil
+	}
+
+	return reply, err
+}
+
+// connect connects to the Redis server, and authenticates/selects the
+// database as configured.
+func (r *RedisCache) connect() error {
+	conn, err := net.DialTimeout("tcp", r.Addr, r.NetTimeout)
+	if err != nil {
+		return err
+	}
+
+	r.conn = conn
+	r.reader = bufio.NewReader(conn)
+
+	var setup [][]string
+	if r.Password != "" {
+		setup = append(setup, []string{"AUTH", r.Password})
+	}
+	if r.DB != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.DB)})
+	}
+
+	for _, args := range setup {
+		if _, err := r.roundTrip(args); err != nil {
+			conn.Close()
+			r.conn = nil
+
+			return fmt.Errorf("Redis %s failed: %s", args[0], err)
+		}
+	}
+
+	return nil
+}
+
+func (r *RedisCache) roundTrip(args []string) (interface{}, error) {
+	if r.NetTimeout > 0 {
+		r.conn.SetDeadline(time.Now().Add(r.NetTimeout))
+	}
+
+	if _, err := r.conn.Write(encodeRedisCommand(args)); err != nil {
+		return nil, err
+	}
+
+	return readRedisReply(r.reader)
+}
+
+// redisError is an error reply from the Redis server.
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// encodeRedisCommand encodes |args| as a RESP array of bulk strings.
+func encodeRedisCommand(args []string) []byte {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+
+	for _, a := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(a))+"\r\n"...)
+		buf = append(buf, a...)
+		buf = append(buf, "\r\n"...)
+	}
+
+	return buf
+}
+
+// readRedisReply reads a single RESP reply from |r|.
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	} else if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed Redis reply %q", line)
+	}
+
+	kind, body := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, redisError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		} else if n < 0 {
+			return nil, nil
+		}
+
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+
+		return string(data[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil {
+			return nil, err
+		} else if n < 0 {
+			return nil, nil
+		}
+
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	default:
+		return nil, fmt.Errorf("malformed Redis reply %q", line)
+	}
+}
+
+func parseRedisModTime(v interface{}) (time.Time, error) {
+	s, _ := v.(string)
+
+	ns, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("bad mtime %q", s)
+	}
+
+	return time.Unix(0, ns), nil
+}
@@ -0,0 +1,209 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a minimal Redis server, supporting the commands used by
+// RedisCache.
+type fakeRedis struct {
+	listener net.Listener
+	password string
+
+	mu     sync.Mutex
+	hashes map[string]map[string]string
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &fakeRedis{
+		listener: l,
+		password: password,
+		hashes:   map[string]map[string]string{},
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go f.serve(conn)
+		}
+	}()
+
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	authed := f.password == ""
+
+	for {
+		reply, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+
+		items := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			args[i] = item.(string)
+		}
+
+		conn.Write(f.handle(args, &authed))
+	}
+}
+
+func (f *fakeRedis) handle(args []string, authed *bool) []byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	bulk := func(s string, ok bool) string {
+		if !ok {
+			return "$-1\r\n"
+		}
+		return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+	}
+
+	if args[0] == "AUTH" {
+		if args[1] != f.password {
+			return []byte("-WRONGPASS invalid password\r\n")
+		}
+
+		*authed = true
+		return []byte("+OK\r\n")
+	} else if !*authed {
+		return []byte("-NOAUTH Authentication required\r\n")
+	}
+
+	switch args[0] {
+	case "SELECT":
+		return []byte("+OK\r\n")
+	case "HSET":
+		h := f.hashes[args[1]]
+		if h == nil {
+			h = map[string]string{}
+			f.hashes[args[1]] = h
+		}
+
+		for i := 2; i+1 < len(args); i += 2 {
+			h[args[i]] = args[i+1]
+		}
+
+		return []byte(":" + strconv.Itoa((len(args)-2)/2) + "\r\n")
+	case "HGET":
+		v, ok := f.hashes[args[1]][args[2]]
+		return []byte(bulk(v, ok))
+	case "HMGET":
+		out := "*" + strconv.Itoa(len(args)-2) + "\r\n"
+		for _, field := range args[2:] {
+			v, ok := f.hashes[args[1]][field]
+			out += bulk(v, ok)
+		}
+
+		return []byte(out)
+	default:
+		return []byte("-ERR unknown command\r\n")
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+	defer server.listener.Close()
+
+	m1 := NewRedisCache(server.listener.Addr().String())
+	m1.Password = "secret"
+	m1.DB = 2
+	defer m1.Close()
+
+	m2 := NewRedisCache(server.listener.Addr().String())
+	m2.Password = "secret"
+	defer m2.Close()
+
+	asn1 := []byte("file 1\r\n$3\r\n")
+	asn2 := []byte("file 2")
+
+	if m1.State("asn.json") != Absent {
+		t.Fatalf("asn.json expected absent in m1")
+	}
+
+	if _, err := m1.Load("asn.json"); err == nil {
+		t.Fatalf("Load of absent file unexpectedly succeeded")
+	}
+
+	if err := m1.Save("asn.json", asn1); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+
+	if m1.State("asn.json") != Good {
+		t.Fatalf("asn.json expected good in m1")
+	} else if m2.State("asn.json") != ShouldReload {
+		t.Fatalf("asn.json expected shouldreload in m2")
+	}
+
+	loaded, err := m2.Load("asn.json")
+	if err != nil {
+		t.Fatal(err)
+	} else if !bytes.Equal(loaded, asn1) {
+		t.Fatalf("loaded(%q) != asn1(%q)", loaded, asn1)
+	} else if m2.State("asn.json") != Good {
+		t.Fatalf("asn.json expected good in m2")
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if err := m2.Save("asn.json", asn2); err != nil {
+		t.Fatalf("Save failed: %s", err)
+	}
+
+	if m1.State("asn.json") != ShouldReload {
+		t.Fatalf("asn.json expected shouldreload in m1")
+	}
+
+	m1.SetTimeout(0)
+	if m1.State("asn.json") != Expired {
+		t.Fatalf("asn.json expected expired in m1")
+	}
+
+	if loaded, err := m1.Load("asn.json"); err != nil || !bytes.Equal(loaded, asn2) {
+		t.Fatalf("Load of expired file failed: %q %v", loaded, err)
+	}
+}
+
+func TestRedisCacheErrors(t *testing.T) {
+	server := newFakeRedis(t, "secret")
+
+	m := NewRedisCache(server.listener.Addr().String())
+	m.Password = "wrong"
+
+	if err := m.Save("asn.json", []byte("x")); err == nil {
+		t.Errorf("Save with wrong password unexpectedly succeeded")
+	}
+
+	server.listener.Close()
+
+	m = NewRedisCache(server.listener.Addr().String())
+	m.NetTimeout = time.Second
+
+	if m.State("asn.json") != Absent {
+		t.Errorf("Expected Absent with the Redis server down")
+	}
+}
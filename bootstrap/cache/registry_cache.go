@@ -4,7 +4,8 @@
 
 // Package cache implements RDAP Service Registry file caching.
 //
-// There are two separate implementations: MemoryCache and DiskCache.
+// There are four implementations: MemoryCache, DiskCache, RedisCache, and
+// BoltCache.
 package cache
 
 import "time"
@@ -49,3 +50,17 @@ type RegistryCache interface {
 
 	SetTimeout(timeout time.Duration)
 }
+
+// A ModTimeCache is a RegistryCache which can report when each file was
+// saved.
+//
+// MemoryCache, DiskCache, RedisCache, and BoltCache all implement
+// ModTimeCache.
+type ModTimeCache interface {
+	RegistryCache
+
+	// ModTime returns the time the file |filename| was saved.
+	//
+	// An error is returned if the file is not in the cache.
+	ModTime(filename string) (time.Time, error)
+}
@@ -54,6 +54,7 @@
 // requests are:
 //   - Download()            - force download one of Service Registry file.
 //   - DownloadWithContext() - force download one of Service Registry file.
+//   - DownloadAll()         - force download all of the Service Registry files, concurrently.
 //   - Lookup()              - download one Service Registry file if missing, or if the cached file is over (by default) 24 hours old.
 //
 // Lookup() is intended for repeated usage: A long lived bootstrap.Client will
@@ -61,10 +62,23 @@
 // of the number of calls made to Lookup(). You can still refresh them manually
 // using Download() if required.
 //
+// Downloads of cached Service Registry files are conditional (using the
+// ETag/Last-Modified headers of the previous download). If the file hasn't
+// changed, the server replies 304 Not Modified, and the cached copy is
+// refreshed instead, making periodic refreshes almost free.
+//
+// If the Service Registry files are also available from mirrors, set
+// Client.Mirrors. A failed download is retried from each mirror in turn.
+//
+// For high-assurance environments, set Client.Verifier to check downloaded
+// Service Registry files against pinned SHA-256 digests (SHA256Manifest), or
+// detached signatures (SignatureVerifier), before they're trusted.
+//
 // By default, Service Registry files are cached in memory. bootstrap.Client
 // also supports caching the Service Registry files on disk. The default cache
-// location is
-// $HOME/.openrdap/.
+// location is the openrdap directory in the user cache directory (e.g.
+// $XDG_CACHE_HOME/openrdap/ on Linux), or $HOME/.openrdap/ if that already
+// exists.
 //
 // Disk cache usage:
 //
@@ -79,10 +93,18 @@
 //
 //   dsr2 := b.DNS()  // Loads dns.json from disk cache.
 //
-// This package also implements the experimental Service Provider registry. Due
-// to the experimental nature, no Service Registry file exists on data.iana.org
-// yet, additionally the filename isn't known. The current filename used is
-// serviceprovider-draft-03.json.
+// Snapshots of the Service Registry files are built into this package. They
+// can optionally be used if a file cannot be downloaded, and is not cached
+// (see Client.EnableEmbedded).
+//
+// To use your own copies of the Service Registry files (e.g. mirrored
+// internally) instead of downloading them, see LoadFromFile() and
+// LoadFromReader().
+//
+// This package also implements the Object Tags registry (object-tags.json),
+// for bootstrapping entity handles such as "12345-ARIN", as defined in
+// https://tools.ietf.org/html/rfc8521. This was previously known as the
+// Service Provider registry.
 //
 // RDAP bootstrapping is defined in https://tools.ietf.org/html/rfc7484.
 package bootstrap
@@ -91,13 +113,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/openrdap/rdap/bootstrap/cache"
+	"github.com/openrdap/rdap/internal/httpbody"
 )
 
 // A RegistryType represents a bootstrap registry type.
@@ -109,6 +133,10 @@ const (
 	IPv6
 	ASN
 	ServiceProvider
+
+	// ObjectTags is the RFC 8521 Object Tags registry (object-tags.json),
+	// used to bootstrap entity handles. It is an alias of ServiceProvider.
+	ObjectTags = ServiceProvider
 )
 
 func (r RegistryType) String() string {
@@ -134,18 +162,130 @@ const (
 
 	// Default cache timeout of Service Registries.
 	DefaultCacheTimeout = time.Hour * 24
+
+	// Default maximum size of a downloaded Service Registry file (after
+	// decompression).
+	DefaultMaxFileSize = 16 * 1024 * 1024
 )
 
 // Client implements an RDAP bootstrap client.
+//
+// A Client is safe for concurrent use by multiple goroutines, once its
+// fields are set.
 type Client struct {
 	HTTP    *http.Client        // HTTP client.
 	BaseURL *url.URL            // Base URL of the Service Registry files. Default is DefaultBaseURL.
 	Cache   cache.RegistryCache // Service Registry cache. Default is a MemoryCache.
 
+	// Mirrors are alternate base URLs of the Service Registry files, tried in
+	// order when a download from the BaseURL fails. Services which failed
+	// recently are tried last, see MirrorHealth().
+	//
+	// Mirrors must serve the same files as the BaseURL: the files are cached
+	// under the BaseURL's Namespace().
+	Mirrors []*url.URL
+
+	// Verifier optionally checks the integrity of each downloaded Service
+	// Registry file (e.g. SHA256Manifest, or SignatureVerifier), before it's
+	// used or cached. A file failing verification is treated as a failed
+	// download, returning a *VerificationError. Lookup then returns the
+	// error, without falling back to a stale cached copy (see MaxStaleness).
+	//
+	// Files loaded with LoadFromFile() are not verified. The embedded
+	// snapshots can't be verified, so aren't used when a Verifier is set
+	// (see EnableEmbedded).
+	Verifier Verifier
+
 	// Optional callback function for verbose messages.
 	Verbose func(text string)
 
+	// MaxStaleness enables falling back to an expired cached copy of a
+	// Service Registry file when downloading a fresh copy fails (e.g. the
+	// network is unreachable). The fallback Answer has Stale set.
+	//
+	// The cached copy is used if it was downloaded no more than MaxStaleness
+	// ago. The Cache must implement cache.ModTimeCache.
+	//
+	// The default (zero) disables the fallback, the download error is
+	// returned instead.
+	MaxStaleness time.Duration
+
+	// Offline forbids network access. Lookups use only the Cache (even if
+	// the cached file has expired, in which case the Answer has Stale set).
+	//
+	// If a Service Registry file is not cached, Lookup returns an
+	// *OfflineError.
+	Offline bool
+
+	// EnableEmbedded enables the fallback to the embedded snapshots of the
+	// Service Registry files.
+	//
+	// If enabled, when a Service Registry file cannot be downloaded, and no
+	// copy is cached, the snapshot built into this package is used instead
+	// (see EmbeddedFile()). The fallback Answer has Embedded set. The
+	// download is retried with a backoff, first after EmbeddedRetryMin, then
+	// doubling up to EmbeddedRetryMax, so Lookups on a restricted network
+	// don't each wait for the download to time out.
+	//
+	// The snapshots are only as current as this package's release, and are
+	// not verified. The default (false) returns the download error instead.
+	EnableEmbedded bool
+
+	// MaxFileSize is the maximum size in bytes of a downloaded Service
+	// Registry file, after decompression. A larger download fails. Default
+	// (zero) is DefaultMaxFileSize.
+	MaxFileSize int64
+
+	// mu guards the Registries, the Cache, and the fields below, so the
+	// Client can be used by several goroutines at once. Network transfers
+	// are made with mu held, so concurrent lookups wait for a download in
+	// progress rather than starting their own.
+	mu sync.Mutex
+
 	registries map[RegistryType]Registry
+	embedded   map[RegistryType]*embeddedState
+	loaded     map[RegistryType]bool
+
+	// Verbose callback of the Question being looked up, see verbose().
+	questionVerbose func(text string)
+
+	overrides          map[RegistryType]map[string][]string
+	overrideRegistries map[RegistryType]Registry
+
+	mirrorMu     sync.Mutex
+	mirrorHealth map[string]*MirrorHealth
+
+	statsMu sync.Mutex
+	stats   ClientStats
+}
+
+// OfflineError is returned by Lookup in Offline mode, when the Service
+// Registry file is not available from the Cache.
+type OfflineError struct {
+	Registry RegistryType
+}
+
+func (o *OfflineError) Error() string {
+	return fmt.Sprintf("Offline mode, %s Service Registry file not cached", o.Registry)
+}
+
+// DownloadError is returned when a Service Registry file cannot be
+// downloaded.
+//
+// Use errors.As() on Err to check for network errors, e.g. *net.OpError.
+type DownloadError struct {
+	Registry RegistryType
+	URL      string
+	Err      error
+}
+
+func (d *DownloadError) Error() string {
+	return fmt.Sprintf("Error downloading %s Service Registry file %s: %s", d.Registry, d.URL, d.Err)
+}
+
+// Unwrap returns the underlying error.
+func (d *DownloadError) Unwrap() error {
+	return d.Err
 }
 
 // A Registry implements bootstrap lookups.
@@ -154,6 +294,7 @@ type Registry interface {
 	File() *File
 }
 
+// init sets the Client's defaults. c.mu must be held.
 func (c *Client) init() {
 	if c.HTTP == nil {
 		c.HTTP = &http.Client{}
@@ -168,6 +309,19 @@ func (c *Client) init() {
 		c.registries = make(map[RegistryType]Registry)
 	}
 
+	if c.embedded == nil {
+		c.embedded = make(map[RegistryType]*embeddedState)
+	}
+
+	if c.loaded == nil {
+		c.loaded = make(map[RegistryType]bool)
+	}
+
+	if c.overrides == nil {
+		c.overrides = make(map[RegistryType]map[string][]string)
+		c.overrideRegistries = make(map[RegistryType]Registry)
+	}
+
 	if c.BaseURL == nil {
 		c.BaseURL, _ = url.Parse(DefaultBaseURL)
 	}
@@ -184,12 +338,20 @@ func (c *Client) Download(registry RegistryType) error {
 //
 // On success, the relevant Registry is refreshed. Use the matching accessor (ASN(), DNS(), IPv4(), or IPv6()) to access it.
 func (c *Client) DownloadWithContext(ctx context.Context, registry RegistryType) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.downloadRegistry(ctx, registry)
+}
+
+// downloadRegistry implements DownloadWithContext(). c.mu must be held.
+func (c *Client) downloadRegistry(ctx context.Context, registry RegistryType) error {
 	c.init()
 
 	var json []byte
 	var s Registry
 
-	json, s, err := c.download(ctx, registry)
+	json, s, v, err := c.download(ctx, registry, c.loadCachedCopy(registry))
 
 	if err != nil {
 		return err
@@ -200,59 +362,222 @@ func (c *Client) DownloadWithContext(ctx context.Context, registry RegistryType)
 		return err
 	}
 
+	if v != nil || c.loadValidators(registry) != nil {
+		c.saveValidators(registry, v)
+	}
+
 	c.registries[registry] = s
+	delete(c.embedded, registry)
+	delete(c.loaded, registry)
 
 	return nil
 
 }
 
-func (c *Client) download(ctx context.Context, registry RegistryType) ([]byte, Registry, error) {
-	u, err := url.Parse(registry.Filename())
-	if err != nil {
-		return nil, nil, err
+// DownloadAll downloads all of the bootstrap registry files ({asn,dns,ipv4,
+// ipv6,object-tags}.json) concurrently, with context |ctx|. e.g. for cache
+// warm-up jobs, or container start-up hooks.
+//
+// The files are swapped in together: if any download fails, its error is
+// returned, and the Client and Cache are unchanged.
+func (c *Client) DownloadAll(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+
+	type result struct {
+		json []byte
+		s    Registry
+		v    *validators
+		err  error
 	}
 
-	baseURL := new(url.URL)
-	*baseURL = *c.BaseURL
+	registries := []RegistryType{ASN, DNS, IPv4, IPv6, ServiceProvider}
+	results := make([]result, len(registries))
 
-	if baseURL.Path != "" && baseURL.Path[len(baseURL.Path)-1] != '/' {
-		baseURL.Path += "/"
+	// Caches aren't safe for concurrent use, so the cached copies are read
+	// here, and only the HTTP requests run concurrently.
+	cached := make([]*cachedCopy, len(registries))
+	for i, registry := range registries {
+		cached[i] = c.loadCachedCopy(registry)
+	}
+
+	var wg sync.WaitGroup
+	for i, registry := range registries {
+		wg.Add(1)
+
+		go func(i int, registry RegistryType) {
+			defer wg.Done()
+
+			r := &results[i]
+			r.json, r.s, r.v, r.err = c.download(ctx, registry, cached[i])
+		}(i, registry)
 	}
+	wg.Wait()
 
-	var fetchURL *url.URL = baseURL.ResolveReference(u)
+	for _, r := range results {
+		if r.err != nil {
+			return r.err
+		}
+	}
+
+	for i, registry := range registries {
+		if err := c.Cache.Save(c.filenameFor(registry), results[i].json); err != nil {
+			return err
+		}
+	}
+
+	for i, registry := range registries {
+		r := results[i]
+
+		if r.v != nil || c.loadValidators(registry) != nil {
+			c.saveValidators(registry, r.v)
+		}
+
+		c.registries[registry] = r.s
+		delete(c.embedded, registry)
+		delete(c.loaded, registry)
+	}
+
+	return nil
+}
+
+// download downloads the Service Registry file |registry|, from the BaseURL
+// or one of the Mirrors. If |cached| is non-nil, the download is
+// conditional, see downloadFrom().
+//
+// Each is tried in turn (see baseURLs()) until the download succeeds. The
+// first error is returned if they all fail.
+func (c *Client) download(ctx context.Context, registry RegistryType, cached *cachedCopy) ([]byte, Registry, *validators, error) {
+	json, s, v, err := c.downloadMirrors(ctx, registry, cached)
+	c.recordDownloadResult(registry, err)
+
+	return json, s, v, err
+}
+
+// downloadMirrors implements download.
+func (c *Client) downloadMirrors(ctx context.Context, registry RegistryType, cached *cachedCopy) ([]byte, Registry, *validators, error) {
+	var firstErr error
+
+	for i, baseURL := range c.baseURLs() {
+		if i > 0 {
+			if ctx.Err() != nil {
+				break
+			}
+
+			c.verbose(fmt.Sprintf("  bootstrap: Download failed (%s), trying mirror %s", firstErr, baseURL))
+		}
+
+		json, s, v, err := c.downloadFrom(ctx, baseURL, registry, cached)
+		c.recordDownload(baseURL, err)
+
+		if err == nil {
+			return json, s, v, nil
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return nil, nil, nil, firstErr
+}
+
+// maxFileSize returns the maximum size of a downloaded Service Registry file.
+func (c *Client) maxFileSize() int64 {
+	if c.MaxFileSize > 0 {
+		return c.MaxFileSize
+	}
+
+	return DefaultMaxFileSize
+}
+
+// downloadFrom downloads the Service Registry file |registry| from the
+// bootstrap service |baseURL|.
+//
+// If |cached| is non-nil, the download is conditional (using the ETag and
+// Last-Modified values of the cached copy's download). A 304 Not Modified
+// response returns the cached copy.
+//
+// The Cache isn't accessed, so downloads can run concurrently.
+//
+// Returns the file, its Registry, and the validators for the next
+// conditional download (nil if the server sent none).
+func (c *Client) downloadFrom(ctx context.Context, baseURL *url.URL, registry RegistryType, cached *cachedCopy) ([]byte, Registry, *validators, error) {
+	var fetchURL *url.URL = urlFor(baseURL, registry)
 	req, err := http.NewRequest("GET", fetchURL.String(), nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	req = req.WithContext(ctx)
+	req.Header.Set("Accept-Encoding", httpbody.AcceptEncoding)
+
+	// Conditional download?
+	var v *validators
+	if cached != nil {
+		v = cached.v
+		v.addHeaders(req)
+	}
 
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, &DownloadError{Registry: registry, URL: fetchURL.String(), Err: err}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != 200 {
-		return nil, nil, fmt.Errorf("Server returned non-200 status code: %s", resp.Status)
+	var json []byte
+
+	if resp.StatusCode == http.StatusNotModified && v != nil {
+		c.verbose(fmt.Sprintf("  bootstrap: %s not modified, refreshing cached copy", registry.Filename()))
+
+		json = cached.json
+	} else if resp.StatusCode != 200 {
+		return nil, nil, nil, &DownloadError{
+			Registry: registry,
+			URL:      fetchURL.String(),
+			Err:      fmt.Errorf("Server returned non-200 status code: %s", resp.Status),
+		}
+	} else {
+		json, err = httpbody.ReadBody(resp, c.maxFileSize())
+		if err != nil {
+			return nil, nil, nil, &DownloadError{Registry: registry, URL: fetchURL.String(), Err: err}
+		}
+
+		v = newValidators(resp)
 	}
 
-	json, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, nil, err
+	if c.Verifier != nil {
+		if err := c.Verifier.Verify(ctx, registry, fetchURL.String(), json); err != nil {
+			return nil, nil, nil, &VerificationError{Registry: registry, URL: fetchURL.String(), Err: err}
+		}
 	}
 
 	var s Registry
 	s, err = newRegistry(registry, json)
 
 	if err != nil {
-		return json, nil, err
+		return json, nil, nil, err
+	}
+
+	return json, s, v, nil
+}
+
+// urlFor returns the download URL of the Service Registry file |registry|,
+// from the bootstrap service |base|.
+func urlFor(base *url.URL, registry RegistryType) *url.URL {
+	u := &url.URL{Path: registry.Filename()}
+
+	baseURL := new(url.URL)
+	*baseURL = *base
+
+	if baseURL.Path != "" && baseURL.Path[len(baseURL.Path)-1] != '/' {
+		baseURL.Path += "/"
 	}
 
-	return json, s, nil
+	return baseURL.ResolveReference(u)
 }
 
 func (c *Client) freshenFromCache(registry RegistryType) {
-	if c.Cache.State(c.filenameFor(registry)) == cache.ShouldReload {
+	if !c.loaded[registry] && c.Cache.State(c.filenameFor(registry)) == cache.ShouldReload {
 		c.reloadFromCache(registry)
 	}
 }
@@ -272,10 +597,41 @@ func (c *Client) reloadFromCache(registry RegistryType) error {
 	}
 
 	c.registries[registry] = s
+	delete(c.embedded, registry)
 
 	return nil
 }
 
+// useStale prepares a stale cached copy of the Service Registry file
+// |registry| for use, as per MaxStaleness.
+//
+// Returns an error if no suitable copy is available.
+func (c *Client) useStale(registry RegistryType) error {
+	if c.MaxStaleness <= 0 {
+		return fmt.Errorf("MaxStaleness not set")
+	}
+
+	mc, ok := c.Cache.(cache.ModTimeCache)
+	if !ok {
+		return fmt.Errorf("cache does not support ModTime")
+	}
+
+	modTime, err := mc.ModTime(c.filenameFor(registry))
+	if err != nil {
+		return err
+	}
+
+	if age := time.Since(modTime); age > c.MaxStaleness {
+		return fmt.Errorf("cached copy is %s old, exceeds MaxStaleness", age.Round(time.Second))
+	}
+
+	if c.registries[registry] != nil {
+		return nil
+	}
+
+	return c.reloadFromCache(registry)
+}
+
 func newRegistry(registry RegistryType, json []byte) (Registry, error) {
 	var s Registry
 	var err error
@@ -299,53 +655,138 @@ func newRegistry(registry RegistryType, json []byte) (Registry, error) {
 }
 
 // Lookup returns the RDAP base URLs for the bootstrap question |question|.
+//
+// Lookup is safe for concurrent use.
 func (c *Client) Lookup(question *Question) (*Answer, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.questionVerbose = question.Verbose
+	defer func() {
+		c.questionVerbose = nil
+	}()
+
+	return c.lookup(question)
+}
+
+// lookup implements Lookup(). c.mu must be held.
+func (c *Client) lookup(question *Question) (answer *Answer, err error) {
 	c.init()
-	if c.Verbose == nil {
-		c.Verbose = func(text string) {}
-	}
 
-	c.Verbose("  bootstrap: Looking up...")
-	c.Verbose(fmt.Sprintf("  bootstrap: Question type : %s", question.RegistryType))
-	c.Verbose(fmt.Sprintf("  bootstrap: Question query: %s", question.Query))
+	var downloadAttempted bool
+	defer func() {
+		c.recordLookup(downloadAttempted, answer, err)
+	}()
+
+	c.verbose("  bootstrap: Looking up...")
+	c.verbose(fmt.Sprintf("  bootstrap: Question type : %s", question.RegistryType))
+	c.verbose(fmt.Sprintf("  bootstrap: Question query: %s", question.Query))
+
+	if answer := c.lookupOverride(question); answer != nil {
+		c.verbose(fmt.Sprintf("  bootstrap: Matching override '%s'", answer.Entry))
+
+		for i, url := range answer.URLs {
+			c.verbose(fmt.Sprintf("  bootstrap: Service URL #%d: '%s'", i+1, url))
+		}
+
+		return answer, nil
+	}
 
 	registry := question.RegistryType
 
 	var state cache.FileState = c.Cache.State(c.filenameFor(registry))
-	c.Verbose(fmt.Sprintf("  bootstrap: Cache state: %s: %s", c.filenameFor(registry), state))
+	c.verbose(fmt.Sprintf("  bootstrap: Cache state: %s: %s", c.filenameFor(registry), state))
 
 	var forceDownload bool
-	if state == cache.ShouldReload {
+	var downloaded bool
+	var stale bool
+	var embedded bool
+	if state == cache.ShouldReload && !c.loaded[registry] {
 		if err := c.reloadFromCache(registry); err != nil {
 			forceDownload = true
 
-			c.Verbose(fmt.Sprintf("  bootstrap: Cache load error (%s), downloading...", err))
+			c.verbose(fmt.Sprintf("  bootstrap: Cache load error (%s), downloading...", err))
 		}
 	}
 
-	if c.registries[registry] == nil || forceDownload {
-		c.Verbose(fmt.Sprintf("  bootstrap: Downloading %s", registry.Filename()))
+	if c.loaded[registry] {
+		c.verbose("  bootstrap: Using loaded Service Registry file")
+	} else if c.Offline || question.Offline {
+		if c.registries[registry] == nil || forceDownload || state == cache.Expired {
+			if err := c.reloadFromCache(registry); err != nil && c.registries[registry] == nil {
+				c.verbose(fmt.Sprintf("  bootstrap: Offline, cache load error (%s)", err))
+				return nil, &OfflineError{Registry: registry}
+			}
+		}
 
-		err := c.DownloadWithContext(question.Context(), registry)
-		if err != nil {
+		stale = state == cache.Expired
+		if stale {
+			c.verbose("  bootstrap: Offline, using expired cached Service Registry file")
+		} else {
+			c.verbose("  bootstrap: Offline, using cached Service Registry file")
+		}
+	} else if e := c.embedded[registry]; e != nil && !forceDownload && !e.retryDue() {
+		c.verbose(fmt.Sprintf("  bootstrap: Using embedded copy, download retry at %s", e.retryAt.Format(time.RFC3339)))
+		embedded = true
+	} else if c.registries[registry] == nil || forceDownload || state == cache.Expired || c.embedded[registry] != nil {
+		c.verbose(fmt.Sprintf("  bootstrap: Downloading %s", registry.Filename()))
+		downloadAttempted = true
+
+		// A file failing verification may have been tampered with, so
+		// there's no fallback to unverified (embedded) or older copies.
+		var verificationErr *VerificationError
+
+		err := c.downloadRegistry(question.Context(), registry)
+		if errors.As(err, &verificationErr) {
+			c.verbose(fmt.Sprintf("  bootstrap: Download failed verification (%s)", err))
 			return nil, err
+		} else if err != nil && state == cache.Absent {
+			if embeddedErr := c.useEmbedded(registry); embeddedErr != nil {
+				c.verbose(fmt.Sprintf("  bootstrap: No embedded copy available (%s)", embeddedErr))
+				return nil, err
+			}
+
+			c.verbose(fmt.Sprintf("  bootstrap: Download failed (%s), using embedded copy", err))
+			embedded = true
+		} else if err != nil {
+			if staleErr := c.useStale(registry); staleErr != nil {
+				c.verbose(fmt.Sprintf("  bootstrap: No stale copy available (%s)", staleErr))
+				return nil, err
+			}
+
+			c.verbose(fmt.Sprintf("  bootstrap: Download failed (%s), using stale cached copy", err))
+			stale = true
+		} else {
+			downloaded = true
 		}
 	} else {
-		c.Verbose("  bootstrap: Using cached Service Registry file")
+		c.verbose("  bootstrap: Using cached Service Registry file")
 	}
 
-	answer, err := c.registries[registry].Lookup(question)
+	answer, err = c.registries[registry].Lookup(question)
 
 	if answer != nil {
-		c.Verbose(fmt.Sprintf("  bootstrap: Looked up '%s'", answer.Query))
+		answer.Downloaded = downloaded
+		answer.Stale = stale
+		answer.Embedded = embedded
+		answer.Registry = registry
+
+		if file := c.registries[registry].File(); file != nil {
+			answer.Description = file.Description
+			answer.Publication = file.Publication
+			answer.Version = file.Version
+		}
+
+		c.verbose(fmt.Sprintf("  bootstrap: Looked up '%s'", answer.Query))
+		c.verbose(fmt.Sprintf("  bootstrap: Service Registry file publication %s, version %s", answer.Publication, answer.Version))
 		if answer.Entry != "" {
-			c.Verbose(fmt.Sprintf("  bootstrap: Matching entry '%s'", answer.Entry))
+			c.verbose(fmt.Sprintf("  bootstrap: Matching entry '%s'", answer.Entry))
 		} else {
-			c.Verbose(fmt.Sprintf("  bootstrap: No match"))
+			c.verbose(fmt.Sprintf("  bootstrap: No match"))
 		}
 
 		for i, url := range answer.URLs {
-			c.Verbose(fmt.Sprintf("  bootstrap: Service URL #%d: '%s'", i+1, url))
+			c.verbose(fmt.Sprintf("  bootstrap: Service URL #%d: '%s'", i+1, url))
 		}
 	}
 
@@ -356,22 +797,15 @@ func (c *Client) Lookup(question *Question) (*Answer, error) {
 //
 // This function never initiates a network transfer.
 func (c *Client) ASN() *ASNRegistry {
-	c.init()
-	c.freshenFromCache(ServiceProvider)
-
-	s, _ := c.registries[ASN].(*ASNRegistry)
+	s, _ := c.Registry(ASN).(*ASNRegistry)
 	return s
 }
 
-//
 // DNS returns the current DNS Registry (or nil if the registry file hasn't been Download()ed).
 //
 // This function never initiates a network transfer.
 func (c *Client) DNS() *DNSRegistry {
-	c.init()
-	c.freshenFromCache(ServiceProvider)
-
-	s, _ := c.registries[DNS].(*DNSRegistry)
+	s, _ := c.Registry(DNS).(*DNSRegistry)
 	return s
 }
 
@@ -379,10 +813,7 @@ func (c *Client) DNS() *DNSRegistry {
 //
 // This function never initiates a network transfer.
 func (c *Client) IPv4() *NetRegistry {
-	c.init()
-	c.freshenFromCache(ServiceProvider)
-
-	s, _ := c.registries[IPv4].(*NetRegistry)
+	s, _ := c.Registry(IPv4).(*NetRegistry)
 	return s
 }
 
@@ -390,10 +821,7 @@ func (c *Client) IPv4() *NetRegistry {
 //
 // This function never initiates a network transfer.
 func (c *Client) IPv6() *NetRegistry {
-	c.init()
-	c.freshenFromCache(ServiceProvider)
-
-	s, _ := c.registries[IPv6].(*NetRegistry)
+	s, _ := c.Registry(IPv6).(*NetRegistry)
 	return s
 }
 
@@ -401,35 +829,135 @@ func (c *Client) IPv6() *NetRegistry {
 //
 // This function never initiates a network transfer.
 func (c *Client) ServiceProvider() *ServiceProviderRegistry {
+	s, _ := c.Registry(ServiceProvider).(*ServiceProviderRegistry)
+	return s
+}
+
+// Registry returns the current Registry of type |registry| (or nil if the
+// registry file hasn't been Download()ed). e.g. c.Registry(bootstrap.DNS) is
+// the same Registry as c.DNS().
+//
+// A newer copy saved in the Cache (e.g. by another process) is loaded first.
+//
+// This function never initiates a network transfer.
+func (c *Client) Registry(registry RegistryType) Registry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.registry(registry)
+}
+
+// registry implements Registry(). c.mu must be held.
+func (c *Client) registry(registry RegistryType) Registry {
 	c.init()
-	c.freshenFromCache(ServiceProvider)
+	c.freshenFromCache(registry)
 
-	s, _ := c.registries[ServiceProvider].(*ServiceProviderRegistry)
-	return s
+	return c.registries[registry]
+}
+
+// Refresh brings the Registry of type |registry| up to date, as Lookup()
+// would: the Service Registry file is downloaded if it's not cached, or the
+// cached copy has expired. Otherwise the cached copy is used.
+//
+// Files loaded with LoadFromFile() are left as-is. In Offline mode, only the
+// Cache is used, and an *OfflineError is returned if the file isn't cached.
+func (c *Client) Refresh(registry RegistryType) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refresh(registry)
+}
+
+// refresh implements Refresh(). c.mu must be held.
+func (c *Client) refresh(registry RegistryType) error {
+	c.init()
+
+	if c.loaded[registry] {
+		return nil
+	}
+
+	state := c.Cache.State(c.filenameFor(registry))
+
+	if c.Offline {
+		if state == cache.Absent || c.reloadFromCache(registry) != nil {
+			return &OfflineError{Registry: registry}
+		}
+
+		return nil
+	}
+
+	if state == cache.Expired || state == cache.Absent || c.embedded[registry] != nil {
+		return c.downloadRegistry(context.Background(), registry)
+	}
+
+	if state == cache.ShouldReload || c.registries[registry] == nil {
+		if err := c.reloadFromCache(registry); err != nil {
+			return c.downloadRegistry(context.Background(), registry)
+		}
+	}
+
+	return nil
+}
+
+// verbose prints the verbose message |text|, to the Question's Verbose
+// callback during a Lookup(), otherwise to the Client's. c.mu must be held.
+func (c *Client) verbose(text string) {
+	if c.questionVerbose != nil {
+		c.questionVerbose(text)
+	} else if c.Verbose != nil {
+		c.Verbose(text)
+	}
 }
 
-// fileFor returns a filename to save the bootstrap registry file |r| as.
+// filenameFor returns a filename to save the bootstrap registry file |r| as.
 //
 // For the official IANA bootstrap service, this is the exact filename, e.g.
 // dns.json.
 //
-// For custom bootstrap services, a 6 character hash of the bootstrap service
-// URL is prepended to the filename (e.g. 012def_dns.json), to prevent mixing
-// them up.
+// For custom bootstrap services, the Namespace() is prepended to the filename
+// (e.g. 012def_dns.json), to prevent mixing them up.
 func (c *Client) filenameFor(r RegistryType) string {
 	filename := r.Filename()
 
-	if c.BaseURL.String() != DefaultBaseURL {
-		hasher := sha256.New()
-		hasher.Write([]byte(c.BaseURL.String()))
-		sha256Hash := hex.EncodeToString(hasher.Sum(nil))
-
-		filename = sha256Hash[0:6] + "_" + filename
+	if namespace := c.namespace(); namespace != "" {
+		filename = namespace + "_" + filename
 	}
 
 	return filename
 }
 
+// Namespace returns a short identifier of the bootstrap service (BaseURL),
+// for keeping data from different bootstrap services apart, e.g. when
+// switching between IANA and test.rdap.net.
+//
+// This is empty string for the official IANA bootstrap service, and a 6
+// character hash of the BaseURL otherwise. A missing trailing slash is
+// ignored, so "https://data.iana.org/rdap" is the IANA service too.
+func (c *Client) Namespace() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.namespace()
+}
+
+// namespace implements Namespace(). c.mu must be held.
+func (c *Client) namespace() string {
+	baseURL := DefaultBaseURL
+	if c.BaseURL != nil {
+		baseURL = c.BaseURL.String()
+	}
+
+	if baseURL == DefaultBaseURL || baseURL+"/" == DefaultBaseURL {
+		return ""
+	}
+
+	hasher := sha256.New()
+	hasher.Write([]byte(baseURL))
+	sha256Hash := hex.EncodeToString(hasher.Sum(nil))
+
+	return sha256Hash[0:6]
+}
+
 // Filename returns the JSON document filename: One of {asn,dns,ipv4,ipv6,object-tags}.json.
 func (r RegistryType) Filename() string {
 	switch r {
@@ -5,8 +5,26 @@
 package bootstrap
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/openrdap/rdap/bootstrap/cache"
+	"github.com/openrdap/rdap/internal/httpbody"
 	"github.com/openrdap/rdap/test"
 )
 
@@ -126,3 +144,825 @@ func TestLookupWithDownloadError(t *testing.T) {
 
 	t.Logf("Error was: %s", err)
 }
+
+func TestLookupEmbedded(t *testing.T) {
+	test.Start(test.BootstrapHTTPError)
+
+	c := &Client{}
+
+	question := &Question{RegistryType: DNS, Query: "example.cz"}
+
+	// The embedded snapshots are opt-in.
+	if _, err := c.Lookup(question); err == nil {
+		t.Fatalf("Expected download error without EnableEmbedded")
+	}
+
+	c = &Client{EnableEmbedded: true}
+
+	answer, err := c.Lookup(question)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if !answer.Embedded || answer.Downloaded || answer.Entry != "cz" || answer.Publication == "" {
+		t.Errorf("Expected embedded answer, got %+v", answer)
+	}
+
+	// The download isn't retried until the backoff expires.
+	answer, err = c.Lookup(question)
+	if err != nil || !answer.Embedded {
+		t.Fatalf("Expected embedded answer, got %+v, %v", answer, err)
+	} else if failures := c.Stats().DownloadFailures[DNS]; failures != 1 {
+		t.Errorf("Expected 1 download failure during backoff, got %d", failures)
+	}
+
+	// A failed retry doubles the backoff.
+	c.embedded[DNS].retryAt = time.Time{}
+	if answer, err = c.Lookup(question); err != nil || !answer.Embedded {
+		t.Fatalf("Expected embedded answer, got %+v, %v", answer, err)
+	} else if delay := c.embedded[DNS].delay; delay != 2*EmbeddedRetryMin {
+		t.Errorf("Expected backoff %s, got %s", 2*EmbeddedRetryMin, delay)
+	}
+
+	test.Finish()
+
+	// Once due, the download is retried, and replaces the embedded copy.
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c.embedded[DNS].retryAt = time.Time{}
+	answer, err = c.Lookup(question)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if answer.Embedded || !answer.Downloaded {
+		t.Errorf("Expected downloaded answer, got %+v", answer)
+	}
+}
+
+func TestEmbeddedFile(t *testing.T) {
+	for _, registry := range []RegistryType{DNS, IPv4, IPv6, ASN, ServiceProvider} {
+		f, err := EmbeddedFile(registry)
+		if err != nil {
+			t.Errorf("EmbeddedFile(%s) error: %s", registry, err)
+		} else if len(f.Entries) == 0 {
+			t.Errorf("EmbeddedFile(%s) has no entries", registry)
+		}
+	}
+}
+
+func TestLookupStale(t *testing.T) {
+	test.Start(test.Bootstrap)
+
+	mc := cache.NewMemoryCache()
+	c := &Client{Cache: mc}
+
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	}
+
+	test.Finish()
+
+	test.Start(test.BootstrapHTTPError)
+	defer test.Finish()
+
+	// Expire the cached file.
+	mc.SetTimeout(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	question := &Question{RegistryType: DNS, Query: "example.cz"}
+
+	_, err := c.Lookup(question)
+
+	var downloadErr *DownloadError
+	if !errors.As(err, &downloadErr) || downloadErr.Registry != DNS {
+		t.Fatalf("Expected DownloadError, got %v", err)
+	}
+
+	c.MaxStaleness = time.Hour
+
+	answer, err := c.Lookup(question)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if !answer.Stale || answer.Downloaded || len(answer.URLs) == 0 {
+		t.Errorf("Expected stale answer, got %+v", answer)
+	}
+
+	c.MaxStaleness = time.Nanosecond
+
+	if _, err := c.Lookup(question); err == nil {
+		t.Errorf("Expected error with cached copy older than MaxStaleness")
+	}
+}
+
+func TestLookupOffline(t *testing.T) {
+	test.Start(test.Bootstrap)
+
+	mc := cache.NewMemoryCache()
+	online := &Client{Cache: mc}
+
+	if err := online.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	}
+
+	test.Finish()
+
+	// Any download would now fail.
+	test.Start(test.BootstrapHTTPError)
+	defer test.Finish()
+
+	c := &Client{Cache: mc, Offline: true}
+
+	answer, err := c.Lookup(&Question{RegistryType: DNS, Query: "example.cz"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if answer.Stale || answer.Downloaded || len(answer.URLs) == 0 {
+		t.Errorf("Unexpected answer %+v", answer)
+	}
+
+	mc.SetTimeout(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	answer, err = c.Lookup(&Question{RegistryType: DNS, Query: "example.cz"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if !answer.Stale {
+		t.Errorf("Expected stale answer")
+	}
+
+	_, err = c.Lookup(&Question{RegistryType: ASN, Query: "as1768"})
+
+	var offlineErr *OfflineError
+	if !errors.As(err, &offlineErr) || offlineErr.Registry != ASN {
+		t.Errorf("Expected OfflineError, got %v", err)
+	}
+}
+
+func TestRegistryAccessorsFromCache(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	mc := cache.NewMemoryCache()
+
+	c := &Client{Cache: mc}
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	}
+
+	// DNS() should load the file another Client downloaded to the Cache.
+	c2 := &Client{Cache: &reloadCache{mc}}
+	if c2.DNS() == nil {
+		t.Errorf("DNS() not loaded from cache")
+	}
+}
+
+// reloadCache reports every cached file as ShouldReload.
+type reloadCache struct {
+	*cache.MemoryCache
+}
+
+func (r *reloadCache) State(filename string) cache.FileState {
+	if r.MemoryCache.State(filename) == cache.Absent {
+		return cache.Absent
+	}
+
+	return cache.ShouldReload
+}
+
+func TestLoadFromFile(t *testing.T) {
+	// Any download would fail.
+	test.Start(test.BootstrapHTTPError)
+	defer test.Finish()
+
+	path := filepath.Join(t.TempDir(), "dns.json")
+	if err := ioutil.WriteFile(path, test.LoadFile("bootstrap/dns.json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Client{}
+
+	if err := c.LoadFromFile(DNS, path); err != nil {
+		t.Fatalf("LoadFromFile() error: %s", err)
+	}
+
+	if err := c.LoadFromReader(IPv4, bytes.NewReader(test.LoadFile("bootstrap/ipv4.json"))); err != nil {
+		t.Fatalf("LoadFromReader() error: %s", err)
+	}
+
+	for _, q := range []*Question{
+		{RegistryType: DNS, Query: "example.cz"},
+		{RegistryType: IPv4, Query: "41.0.0.1"},
+	} {
+		answer, err := c.Lookup(q)
+		if err != nil {
+			t.Errorf("Lookup(%s) error: %s", q.Query, err)
+		} else if answer.Downloaded || len(answer.URLs) == 0 {
+			t.Errorf("Lookup(%s) unexpected answer %+v", q.Query, answer)
+		}
+	}
+
+	if c.DNS() == nil {
+		t.Errorf("DNS() returned nil for a loaded registry")
+	}
+
+	if err := c.LoadFromReader(ASN, bytes.NewReader([]byte("{"))); err == nil {
+		t.Errorf("Expected error loading malformed registry")
+	}
+}
+
+func TestLookupOverride(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+
+	if err := c.AddOverride(DNS, "internal.corp", "https://rdap.corp.example/"); err != nil {
+		t.Fatalf("AddOverride() error: %s", err)
+	}
+
+	if err := c.AddOverride(IPv4, "10.0.0.0/8", "https://rdap.corp.example/"); err != nil {
+		t.Fatalf("AddOverride() error: %s", err)
+	}
+
+	if err := c.AddOverride(IPv4, "2001:db8::/32", "https://rdap.corp.example/"); err == nil {
+		t.Errorf("Expected error adding IPv6 network to IPv4 overrides")
+	}
+
+	if err := c.AddOverride(ASN, "not-an-asn", "https://rdap.corp.example/"); err == nil {
+		t.Errorf("Expected error adding bad AS number range")
+	}
+
+	tests := []struct {
+		Question *Question
+		Override bool
+		URL      string
+	}{
+		{&Question{RegistryType: DNS, Query: "host.INTERNAL.corp"}, true, "https://rdap.corp.example/"},
+		{&Question{RegistryType: DNS, Query: "example.cz"}, false, "https://rdap.nic.cz"},
+		{&Question{RegistryType: IPv4, Query: "10.1.2.3"}, true, "https://rdap.corp.example/"},
+		{&Question{RegistryType: IPv4, Query: "41.0.0.1"}, false, "https://rdap.afrinic.net/rdap/"},
+	}
+
+	for _, test := range tests {
+		answer, err := c.Lookup(test.Question)
+		if err != nil {
+			t.Errorf("Lookup(%s) error: %s", test.Question.Query, err)
+		} else if answer.Override != test.Override || len(answer.URLs) == 0 || answer.URLs[0].String() != test.URL {
+			t.Errorf("Lookup(%s) unexpected answer %+v", test.Question.Query, answer)
+		}
+	}
+}
+
+func TestLookupRegistryMetadata(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+
+	answer, err := c.Lookup(&Question{RegistryType: DNS, Query: "example.cz"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if answer.Registry != DNS ||
+		answer.Description != "RDAP bootstrap file for Domain Name System registrations" ||
+		answer.Publication != "2017-03-15T21:26:24Z" ||
+		answer.Version != "1.0" ||
+		answer.Entry != "cz" {
+		t.Errorf("Unexpected answer metadata %+v", answer)
+	}
+}
+
+func TestDownloadConditional(t *testing.T) {
+	dns := test.LoadFile("bootstrap/dns.json")
+
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Write(dns)
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	mc := cache.NewMemoryCache()
+	c := &Client{BaseURL: baseURL, Cache: mc}
+
+	for i := 0; i < 2; i++ {
+		if err := c.Download(DNS); err != nil {
+			t.Fatalf("Download() #%d error: %s", i+1, err)
+		}
+	}
+
+	if requests != 2 || notModified != 1 {
+		t.Fatalf("Expected 2 requests, 1 not modified, got %d, %d", requests, notModified)
+	}
+
+	if c.DNS() == nil {
+		t.Errorf("DNS() nil after 304 Not Modified")
+	}
+
+	// The refresh resets the cached file's expiry.
+	mc.SetTimeout(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	answer, err := c.Lookup(&Question{RegistryType: DNS, Query: "example.br"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if !answer.Downloaded || len(answer.URLs) == 0 || notModified != 2 {
+		t.Errorf("Expected refreshed answer, got %+v", answer)
+	}
+}
+
+func TestDownloadCompressed(t *testing.T) {
+	dns := test.LoadFile("bootstrap/dns.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != httpbody.AcceptEncoding {
+			http.Error(w, "expected Accept-Encoding", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+
+		gw := gzip.NewWriter(w)
+		gw.Write(dns)
+		gw.Close()
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	c := &Client{
+		BaseURL: baseURL,
+		HTTP:    &http.Client{Transport: &http.Transport{DisableCompression: true}},
+	}
+
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	} else if c.DNS() == nil {
+		t.Errorf("DNS() nil after compressed download")
+	}
+}
+
+func TestDownloadMaxFileSize(t *testing.T) {
+	dns := test.LoadFile("bootstrap/dns.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+
+		gw := gzip.NewWriter(w)
+		gw.Write(dns)
+		gw.Close()
+	}))
+	defer server.Close()
+
+	baseURL, _ := url.Parse(server.URL)
+	c := &Client{
+		BaseURL:     baseURL,
+		MaxFileSize: int64(len(dns) - 1),
+	}
+
+	err := c.Download(DNS)
+	if _, ok := err.(*DownloadError); !ok {
+		t.Fatalf("Expected *DownloadError, got %v", err)
+	}
+
+	c.MaxFileSize = int64(len(dns))
+	if err := c.Download(DNS); err != nil {
+		t.Errorf("Download() error: %s", err)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	tests := []struct {
+		BaseURL string
+		Default bool
+	}{
+		{"", true},
+		{"https://data.iana.org/rdap/", true},
+		{"https://data.iana.org/rdap", true},
+		{"https://test.rdap.net/rdap", false},
+	}
+
+	for _, test := range tests {
+		c := &Client{}
+		if test.BaseURL != "" {
+			c.BaseURL, _ = url.Parse(test.BaseURL)
+		}
+
+		namespace := c.Namespace()
+		if (namespace == "") != test.Default {
+			t.Errorf("%q: unexpected namespace %q", test.BaseURL, namespace)
+			continue
+		}
+
+		expected := "dns.json"
+		if !test.Default {
+			expected = namespace + "_dns.json"
+		}
+
+		if filename := c.filenameFor(DNS); filename != expected {
+			t.Errorf("%q: expected filename %q, got %q", test.BaseURL, expected, filename)
+		}
+	}
+}
+
+func TestStatuses(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	mc := cache.NewMemoryCache()
+
+	c := &Client{Cache: mc}
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	}
+
+	// Another Client, which hasn't loaded anything yet.
+	c2 := &Client{Cache: mc}
+
+	statuses := c2.Statuses()
+	if len(statuses) != 5 {
+		t.Fatalf("Expected 5 statuses, got %d", len(statuses))
+	}
+
+	for _, s := range statuses {
+		if s.Registry != DNS {
+			if s.Cached || s.Available || s.Entries != 0 {
+				t.Errorf("%s: unexpected status %+v", s.Registry, s)
+			}
+			continue
+		}
+
+		if !s.Cached || !s.Fresh || s.ModTime.IsZero() {
+			t.Errorf("dns: expected cached and fresh, got %+v", s)
+		} else if s.Size != len(c.DNS().File().JSON) || s.Entries != len(c.DNS().File().Entries) {
+			t.Errorf("dns: unexpected size %d or entries %d", s.Size, s.Entries)
+		} else if s.SourceURL != "https://data.iana.org/rdap/dns.json" {
+			t.Errorf("dns: unexpected source URL %s", s.SourceURL)
+		}
+	}
+
+	if c2.registries[DNS] != nil {
+		t.Errorf("Statuses() shouldn't load files for lookups")
+	}
+}
+
+func TestDownloadAll(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+	if err := c.DownloadAll(context.Background()); err != nil {
+		t.Fatalf("DownloadAll() error: %s", err)
+	}
+
+	if c.ASN() == nil || c.DNS() == nil || c.IPv4() == nil || c.IPv6() == nil || c.ServiceProvider() == nil {
+		t.Errorf("DownloadAll() didn't load every registry")
+	}
+}
+
+func TestDownloadAllConditional(t *testing.T) {
+	var notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write(test.LoadFile("bootstrap" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	diskCache := cache.NewDiskCache()
+	diskCache.Dir = t.TempDir()
+
+	c := &Client{Cache: diskCache}
+	c.BaseURL, _ = url.Parse(server.URL)
+
+	// The second DownloadAll() makes conditional downloads, reading the
+	// cached copies (run with -race).
+	for i := 0; i < 2; i++ {
+		if err := c.DownloadAll(context.Background()); err != nil {
+			t.Fatalf("DownloadAll() #%d error: %s", i+1, err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&notModified); n != 5 {
+		t.Errorf("Expected 5 not modified responses, got %d", n)
+	}
+
+	if c.DNS() == nil {
+		t.Errorf("DownloadAll() didn't load the DNS registry")
+	}
+}
+
+func TestDownloadAllFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ipv6.json" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write(test.LoadFile("bootstrap" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	c := &Client{}
+	c.BaseURL, _ = url.Parse(server.URL)
+
+	err := c.DownloadAll(context.Background())
+
+	var downloadErr *DownloadError
+	if !errors.As(err, &downloadErr) || downloadErr.Registry != IPv6 {
+		t.Fatalf("Expected IPv6 DownloadError, got %v", err)
+	}
+
+	if c.registries[DNS] != nil || c.Cache.State(c.filenameFor(DNS)) != cache.Absent {
+		t.Errorf("DownloadAll() failure shouldn't change the Client or Cache")
+	}
+}
+
+func TestDownloadMirrors(t *testing.T) {
+	var primaryRequests int
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(test.LoadFile("bootstrap" + r.URL.Path))
+	}))
+	defer mirror.Close()
+
+	c := &Client{}
+	c.BaseURL, _ = url.Parse(primary.URL)
+	mirrorURL, _ := url.Parse(mirror.URL)
+	c.Mirrors = []*url.URL{mirrorURL}
+
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	} else if c.DNS() == nil {
+		t.Fatalf("DNS registry not loaded")
+	}
+
+	health := c.MirrorHealth()
+	if len(health) != 2 {
+		t.Fatalf("Expected 2 MirrorHealth, got %d", len(health))
+	} else if health[0].Healthy() || health[0].ConsecutiveFailures != 1 || health[0].LastError == nil {
+		t.Errorf("Unexpected primary health %+v", health[0])
+	} else if !health[1].Healthy() || health[1].LastSuccess.IsZero() {
+		t.Errorf("Unexpected mirror health %+v", health[1])
+	}
+
+	// The failing primary is now tried last.
+	if err := c.Download(ASN); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	} else if primaryRequests != 1 {
+		t.Errorf("Expected the unhealthy primary to be skipped, got %d requests", primaryRequests)
+	}
+
+	// All failing: the first error is returned.
+	mirror.Close()
+
+	var downloadErr *DownloadError
+	if err := c.Download(IPv4); !errors.As(err, &downloadErr) || downloadErr.URL != mirror.URL+"/ipv4.json" {
+		t.Errorf("Expected mirror DownloadError, got %v", err)
+	}
+}
+
+func TestDownloadVerified(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	dns := test.Get("https://data.iana.org/rdap/dns.json")
+	digest := sha256.Sum256(dns)
+
+	manifest, err := ParseSHA256Manifest([]byte("# Pinned digests\n" +
+		hex.EncodeToString(digest[:]) + "  mirror/dns.json\n" +
+		strings.Repeat("0", 64) + " *asn.json\n"))
+	if err != nil {
+		t.Fatalf("ParseSHA256Manifest() error: %s", err)
+	}
+
+	c := &Client{Verifier: manifest}
+
+	if err := c.Download(DNS); err != nil {
+		t.Errorf("Download(DNS) error: %s", err)
+	}
+
+	var verificationErr *VerificationError
+	if err := c.Download(ASN); !errors.As(err, &verificationErr) {
+		t.Errorf("Expected ASN VerificationError, got %v", err)
+	} else if c.Cache.State(c.filenameFor(ASN)) != cache.Absent {
+		t.Errorf("Unverified file was cached")
+	}
+
+	if err := c.Download(IPv4); !errors.As(err, &verificationErr) {
+		t.Errorf("Expected IPv4 VerificationError (not in manifest), got %v", err)
+	}
+
+	if _, err := ParseSHA256Manifest([]byte("abc dns.json\n")); err == nil {
+		t.Errorf("Expected error for invalid digest")
+	}
+}
+
+func TestLookupVerifiedNoFallback(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	manifest, err := ParseSHA256Manifest([]byte(strings.Repeat("0", 64) + "  dns.json\n"))
+	if err != nil {
+		t.Fatalf("ParseSHA256Manifest() error: %s", err)
+	}
+
+	question := &Question{RegistryType: DNS, Query: "example.cz"}
+	var verificationErr *VerificationError
+
+	// Not cached: the embedded snapshot isn't used.
+	c := &Client{Verifier: manifest, EnableEmbedded: true}
+
+	if answer, err := c.Lookup(question); !errors.As(err, &verificationErr) {
+		t.Errorf("Expected VerificationError, got %+v, %v", answer, err)
+	}
+
+	// Expired cached copy: the stale copy isn't used.
+	mc := cache.NewMemoryCache()
+	c = &Client{Cache: mc, MaxStaleness: time.Hour}
+
+	if err := c.Download(DNS); err != nil {
+		t.Fatalf("Download() error: %s", err)
+	}
+
+	mc.SetTimeout(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	c.Verifier = manifest
+	if answer, err := c.Lookup(question); !errors.As(err, &verificationErr) {
+		t.Errorf("Expected VerificationError, got %+v, %v", answer, err)
+	}
+}
+
+func TestDownloadSigned(t *testing.T) {
+	publicKey, privateKey, _ := ed25519.GenerateKey(nil)
+	dns := test.LoadFile("bootstrap/dns.json")
+
+	var badSignature bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dns.json":
+			w.Write(dns)
+		case "/dns.json.sig":
+			sig := ed25519.Sign(privateKey, dns)
+			if badSignature {
+				sig[0] ^= 0xff
+			}
+
+			w.Write([]byte(base64.StdEncoding.EncodeToString(sig)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c := &Client{Verifier: &SignatureVerifier{PublicKey: publicKey}}
+	c.BaseURL, _ = url.Parse(server.URL)
+
+	if err := c.Download(DNS); err != nil {
+		t.Errorf("Download() error: %s", err)
+	}
+
+	badSignature = true
+
+	var verificationErr *VerificationError
+	if err := c.Download(DNS); !errors.As(err, &verificationErr) {
+		t.Errorf("Expected VerificationError, got %v", err)
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+
+	for _, query := range []string{"example.cz", "example.br", "example.invalid"} {
+		question := &Question{RegistryType: DNS, Query: query}
+		if _, err := c.Lookup(question); err != nil {
+			t.Fatalf("Lookup(%s) error: %s", query, err)
+		}
+	}
+
+	if _, err := c.Lookup(&Question{RegistryType: ASN, Query: "not-a-number"}); err == nil {
+		t.Fatalf("Expected Lookup error")
+	}
+
+	s := c.Stats()
+
+	if s.Lookups != 4 || s.CacheMisses != 2 || s.CacheHits != 2 || s.NoMatch != 1 || s.Errors != 1 {
+		t.Errorf("Unexpected lookup stats %+v", s)
+	} else if s.Downloads[DNS] != 1 || s.Downloads[ASN] != 1 || len(s.DownloadFailures) != 0 {
+		t.Errorf("Unexpected download stats %+v", s)
+	}
+}
+
+func TestRegistryAndRefresh(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+
+	for _, registry := range []RegistryType{ASN, DNS, IPv4, IPv6, ServiceProvider} {
+		if c.Registry(registry) != nil {
+			t.Errorf("%s: expected nil Registry before Refresh()", registry)
+		}
+
+		if err := c.Refresh(registry); err != nil {
+			t.Fatalf("%s: Refresh() error: %s", registry, err)
+		}
+
+		if c.Registry(registry) == nil {
+			t.Errorf("%s: expected Registry after Refresh()", registry)
+		}
+	}
+
+	if c.Registry(IPv6) != Registry(c.IPv6()) || c.Registry(ServiceProvider) != Registry(c.ServiceProvider()) {
+		t.Errorf("Registry() doesn't match the typed accessors")
+	}
+
+	// A fresh cached copy is used as-is.
+	if err := c.Refresh(DNS); err != nil {
+		t.Errorf("Refresh() error: %s", err)
+	} else if c.Stats().Downloads[DNS] != 1 {
+		t.Errorf("Expected a single DNS download, got %d", c.Stats().Downloads[DNS])
+	}
+
+	// Each accessor loads its own file, saved to the Cache by another Client.
+	c2 := &Client{Cache: &reloadCache{c.Cache.(*cache.MemoryCache)}}
+	if c2.ASN() == nil || c2.DNS() == nil || c2.IPv4() == nil || c2.IPv6() == nil || c2.ServiceProvider() == nil {
+		t.Errorf("Accessors didn't load from the Cache")
+	}
+
+	offline := &Client{Offline: true}
+	var offlineErr *OfflineError
+	if err := offline.Refresh(DNS); !errors.As(err, &offlineErr) {
+		t.Errorf("Expected OfflineError, got %v", err)
+	}
+}
+
+func TestServer(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	server := &Server{Client: &Client{}}
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, req)
+
+		return w
+	}
+
+	w := get("/rdap/dns.json", nil)
+	if w.Code != 200 || !bytes.Equal(w.Body.Bytes(), test.Get("https://data.iana.org/rdap/dns.json")) {
+		t.Fatalf("Unexpected response %d %q", w.Code, w.Body.String())
+	}
+
+	etag := w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") == "" {
+		t.Errorf("Missing validators %v", w.Header())
+	}
+
+	if w := get("/rdap/dns.json", http.Header{"If-None-Match": {etag}}); w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 Not Modified, got %d", w.Code)
+	}
+
+	if w := get("/rdap/example.json", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+
+	// A Client using the Server as its bootstrap service.
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	// A new Transport bypasses httpmock.
+	c := &Client{HTTP: &http.Client{Transport: &http.Transport{}}}
+	c.BaseURL, _ = url.Parse(httpServer.URL)
+
+	answer, err := c.Lookup(&Question{RegistryType: ASN, Query: "as1768"})
+	if err != nil || len(answer.URLs) != 1 || !answer.Downloaded {
+		t.Errorf("Lookup via Server failed: %v", err)
+	}
+}
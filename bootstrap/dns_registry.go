@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"net/url"
 	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
 )
 
 type DNSRegistry struct {
@@ -35,9 +38,13 @@ func NewDNSRegistry(json []byte) (*DNSRegistry, error) {
 }
 
 // Lookup returns the RDAP base URLs for the domain name question |question|.
+//
+// Internationalized domain names are converted to their ASCII (punycode)
+// form before matching, e.g. "пример.рф" is looked up as "xn--e1afmkfd.xn--p1ai".
 func (d *DNSRegistry) Lookup(question *Question) (*Answer, error) {
 	input := question.Query
 	input = strings.TrimSuffix(input, ".")
+	input = toASCIIDomain(input)
 	input = strings.ToLower(input)
 	fqdn := input
 
@@ -73,7 +80,31 @@ func (d *DNSRegistry) Lookup(question *Question) (*Answer, error) {
 	}, nil
 }
 
+// toASCIIDomain returns the domain name |name| with any Unicode labels
+// converted to punycode.
+//
+// ASCII names, and names which can't be converted, are returned unchanged.
+func toASCIIDomain(name string) string {
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			if ascii, err := idna.Lookup.ToASCII(name); err == nil {
+				return ascii
+			}
+
+			break
+		}
+	}
+
+	return name
+}
+
 // File returns a struct describing the registry's JSON document.
 func (d *DNSRegistry) File() *File {
 	return d.file
 }
+
+// EntriesFor returns the domain names (e.g. TLDs) served by the RDAP base URL
+// |baseURL|. See File.EntriesFor.
+func (d *DNSRegistry) EntriesFor(baseURL string) []string {
+	return d.file.EntriesFor(baseURL)
+}
@@ -95,3 +95,47 @@ func TestNetRegistryLookupsDNS(t *testing.T) {
 
 	runRegistryTests(t, tests, d)
 }
+
+func TestNetRegistryLookupsDNSIDN(t *testing.T) {
+	d, err := NewDNSRegistry([]byte(`{
+		"version": "1.0",
+		"publication": "2024-01-01T00:00:00Z",
+		"services": [
+			[["xn--p1ai"], ["https://rdap.example.rf/"]],
+			[["xn--mxtq1m.com"], ["https://rdap.example.com/idn/"]]
+		]
+	}`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []registryTest{
+		{
+			"пример.рф",
+			false,
+			"xn--p1ai",
+			[]string{"https://rdap.example.rf/"},
+		},
+		{
+			"ПРИМЕР.РФ.",
+			false,
+			"xn--p1ai",
+			[]string{"https://rdap.example.rf/"},
+		},
+		{
+			"xn--e1afmkfd.xn--p1ai",
+			false,
+			"xn--p1ai",
+			[]string{"https://rdap.example.rf/"},
+		},
+		{
+			"www.政府.com",
+			false,
+			"xn--mxtq1m.com",
+			[]string{"https://rdap.example.com/idn/"},
+		},
+	}
+
+	runRegistryTests(t, tests, d)
+}
@@ -0,0 +1,92 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"embed"
+	"fmt"
+	"time"
+)
+
+//go:generate go run embedded/refresh.go
+
+// embeddedFiles contains snapshots of the Service Registry files from
+// https://data.iana.org/rdap/, used when a file cannot be downloaded and is
+// not cached. See Client.EnableEmbedded.
+//
+// The snapshots should be refreshed before each release, by running
+// "go generate" in this directory. This downloads the current files, checks
+// they parse, and replaces embedded/*.json.
+//
+//go:embed embedded/*.json
+var embeddedFiles embed.FS
+
+const (
+	// EmbeddedRetryMin is the initial delay before retrying the download of
+	// a Service Registry file, while the embedded snapshot is in use.
+	EmbeddedRetryMin = time.Minute
+
+	// EmbeddedRetryMax is the maximum delay between download retries.
+	EmbeddedRetryMax = time.Hour
+)
+
+// embeddedState records a Service Registry file's embedded snapshot being in
+// use, and when to next retry the download.
+type embeddedState struct {
+	retryAt time.Time
+	delay   time.Duration
+}
+
+// retryDue returns true if the download should be retried.
+func (e *embeddedState) retryDue() bool {
+	return !time.Now().Before(e.retryAt)
+}
+
+// EmbeddedFile returns the embedded snapshot of the Service Registry file
+// |registry|.
+func EmbeddedFile(registry RegistryType) (*File, error) {
+	json, err := embeddedFiles.ReadFile("embedded/" + registry.Filename())
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFile(json)
+}
+
+// useEmbedded loads the embedded snapshot of the Service Registry file
+// |registry|.
+func (c *Client) useEmbedded(registry RegistryType) error {
+	if !c.EnableEmbedded {
+		return fmt.Errorf("embedded copies not enabled")
+	} else if c.Verifier != nil {
+		return fmt.Errorf("embedded copies can't be verified")
+	}
+
+	json, err := embeddedFiles.ReadFile("embedded/" + registry.Filename())
+	if err != nil {
+		return err
+	}
+
+	s, err := newRegistry(registry, json)
+	if err != nil {
+		return err
+	}
+
+	delay := EmbeddedRetryMin
+	if prev := c.embedded[registry]; prev != nil {
+		delay = prev.delay * 2
+		if delay > EmbeddedRetryMax {
+			delay = EmbeddedRetryMax
+		}
+	}
+
+	c.registries[registry] = s
+	c.embedded[registry] = &embeddedState{
+		retryAt: time.Now().Add(delay),
+		delay:   delay,
+	}
+
+	return nil
+}
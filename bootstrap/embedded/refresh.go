@@ -0,0 +1,72 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+//go:build ignore
+
+// refresh downloads the current Service Registry files from data.iana.org,
+// replacing the embedded snapshots. Run via "go generate" in the bootstrap
+// directory.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const baseURL = "https://data.iana.org/rdap/"
+
+func main() {
+	client := &http.Client{Timeout: 60 * time.Second}
+
+	for _, name := range []string{"asn.json", "dns.json", "ipv4.json", "ipv6.json", "object-tags.json"} {
+		if err := refresh(client, name); err != nil {
+			fmt.Fprintf(os.Stderr, "refresh: %s: %s\n", name, err)
+			os.Exit(1)
+		}
+	}
+}
+
+func refresh(client *http.Client, name string) error {
+	resp, err := client.Get(baseURL + name)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16*1024*1024))
+	if err != nil {
+		return err
+	}
+
+	var file struct {
+		Publication string
+		Services    [][][]string
+	}
+
+	if err := json.Unmarshal(body, &file); err != nil {
+		return err
+	} else if file.Publication == "" || len(file.Services) == 0 {
+		return fmt.Errorf("not a Service Registry file")
+	}
+
+	filename := filepath.Join("embedded", name)
+	tmp := filename + ".tmp"
+
+	if err := os.WriteFile(tmp, body, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s: publication %s, %d services\n", name, file.Publication, len(file.Services))
+
+	return os.Rename(tmp, filename)
+}
@@ -7,7 +7,10 @@ package bootstrap
 import (
 	"encoding/json"
 	"errors"
+	"net/netip"
 	"net/url"
+	"sort"
+	"strings"
 )
 
 // File represents a bootstrap registry file (i.e. {asn,dns,ipv4,ipv6}.json).
@@ -29,6 +32,8 @@ type File struct {
 }
 
 // NewFile constructs a File from a bootstrap registry file.
+//
+// Malformed entries and URLs are skipped. To check a file, see ValidateFile().
 func NewFile(jsonDocument []byte) (*File, error) {
 	var doc struct {
 		Description string
@@ -90,3 +95,75 @@ func NewFile(jsonDocument []byte) (*File, error) {
 
 	return f, nil
 }
+
+// EntriesFor returns the service entries (e.g. TLDs, IP networks, or AS
+// number ranges) served by the RDAP base URL |baseURL|.
+//
+// This is the reverse of a lookup, e.g. to list the IP networks served by
+// "https://rdap.arin.net/registry/". The URL's scheme must match, while the
+// hostname's case and a trailing slash are ignored.
+//
+// The entries are sorted in their natural order (by address for IP networks,
+// numerically for AS number ranges, alphabetically otherwise).
+func (f *File) EntriesFor(baseURL string) []string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+
+	key := baseURLKey(u)
+
+	var entries []string
+	for entry, urls := range f.Entries {
+		for _, entryURL := range urls {
+			if baseURLKey(entryURL) == key {
+				entries = append(entries, entry)
+				break
+			}
+		}
+	}
+
+	sortEntries(entries)
+
+	return entries
+}
+
+// baseURLKey returns the RDAP base URL |u| in a form suitable for comparison.
+func baseURLKey(u *url.URL) string {
+	return strings.ToLower(u.Scheme) + "://" + strings.ToLower(u.Host) + strings.TrimSuffix(u.Path, "/")
+}
+
+// sortEntries sorts the service |entries| in their natural order.
+func sortEntries(entries []string) {
+	allPrefixes, allASNs := true, true
+	for _, e := range entries {
+		if _, err := netip.ParsePrefix(e); err != nil {
+			allPrefixes = false
+		}
+
+		if _, _, err := parseASNRange(e); err != nil {
+			allASNs = false
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		switch {
+		case allPrefixes:
+			a, _ := netip.ParsePrefix(entries[i])
+			b, _ := netip.ParsePrefix(entries[j])
+
+			if c := a.Addr().Compare(b.Addr()); c != 0 {
+				return c < 0
+			}
+
+			return a.Bits() < b.Bits()
+		case allASNs:
+			a, _, _ := parseASNRange(entries[i])
+			b, _, _ := parseASNRange(entries[j])
+
+			return a < b
+		default:
+			return entries[i] < entries[j]
+		}
+	})
+}
@@ -5,6 +5,7 @@
 package bootstrap
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/openrdap/rdap/test"
@@ -88,3 +89,118 @@ func TestParseBadURL(t *testing.T) {
 		t.Fatalf("Expected 3 entries, got %d: %v\n", len(r.Entries), r)
 	}
 }
+
+func TestFileEntriesFor(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	ipv4, err := NewFile(test.Get("https://data.iana.org/rdap/ipv4.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries := ipv4.EntriesFor("https://RDAP.arin.net/registry/")
+	if len(entries) == 0 || entries[0] != "3.0.0.0/8" || entries[1] != "4.0.0.0/8" || entries[len(entries)-1] != "216.0.0.0/8" {
+		t.Errorf("Unexpected IPv4 entries %v", entries)
+	}
+
+	asn, err := NewFile(test.Get("https://data.iana.org/rdap/asn.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries = asn.EntriesFor("https://rdap.arin.net/registry")
+	if len(entries) < 3 || entries[0] != "1-6" || entries[1] != "8-27" || entries[2] != "29-136" {
+		t.Errorf("Unexpected ASN entries %v", entries[:3])
+	}
+
+	dns, err := NewFile(test.Get("https://data.iana.org/rdap/dns.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if entries := dns.EntriesFor("https://rdap.nic.cz/"); len(entries) != 1 || entries[0] != "cz" {
+		t.Errorf("Unexpected DNS entries %v", entries)
+	}
+
+	for _, u := range []string{"http://rdap.nic.cz", "https://rdap.nic.cz/other", "https://unknown.example"} {
+		if entries := dns.EntriesFor(u); len(entries) != 0 {
+			t.Errorf("Unexpected DNS entries for %s: %v", u, entries)
+		}
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	for _, registry := range []RegistryType{ASN, DNS, IPv4, IPv6, ServiceProvider} {
+		jsonDocument := test.Get("https://data.iana.org/rdap/" + registry.Filename())
+
+		// The test snapshot predates RFC 9224's trailing slash requirement.
+		for _, p := range ValidateFile(registry, jsonDocument) {
+			if p.Message != "base URL must end with a slash" {
+				t.Errorf("%s: unexpected problem %s", registry, p)
+			}
+		}
+	}
+
+	tests := []struct {
+		Registry RegistryType
+		JSON     string
+		Expected []string
+	}{
+		{
+			DNS,
+			`{"services": []}`,
+			[]string{"missing version", "missing publication"},
+		},
+		{
+			DNS,
+			`{"version": "2.0", "publication": "yesterday", "services": [[["cz", "CZ"], ["https://rdap.nic.cz"]], [["cz"], []], [["sk"]]]}`,
+			[]string{
+				`"2.0": unsupported version, expected "1.0"`,
+				`"yesterday": publication is not an RFC 3339 date`,
+				`services[0]: "CZ": domain name must be lower case`,
+				`services[0]: "https://rdap.nic.cz": base URL must end with a slash`,
+				`services[1]: no URLs`,
+				`services[1]: "cz": duplicate entry, also in services[0]`,
+				`services[2]: service must be an array of 2 string arrays`,
+			},
+		},
+		{
+			ASN,
+			`{"version": "1.0", "publication": "2024-01-01T00:00:00Z", "services": [[["1-100", "50-150", "200-199", "x"], ["rdap.example/"]]]}`,
+			[]string{
+				`services[0]: "50-150": overlaps "1-100" in services[0]`,
+				`services[0]: "200-199": AS number range is reversed`,
+				`services[0]: "x": not an AS number or range (e.g. 1-100)`,
+				`services[0]: "rdap.example/": not an absolute http/https URL`,
+			},
+		},
+		{
+			IPv4,
+			`{"version": "1.0", "publication": "2024-01-01T00:00:00Z", "services": [[["2001:db8::/32", "192.0.2.1/24", "192.0.2.0/24"], ["https://rdap.example/"]]]}`,
+			[]string{
+				`services[0]: "2001:db8::/32": wrong address family for ipv4.json`,
+				`services[0]: "192.0.2.1/24": IP network has host bits set`,
+			},
+		},
+		{
+			DNS,
+			`[`,
+			[]string{"invalid JSON document: unexpected end of JSON input"},
+		},
+	}
+
+	for i, test := range tests {
+		var problems []string
+		for _, p := range ValidateFile(test.Registry, []byte(test.JSON)) {
+			problems = append(problems, p.Error())
+		}
+
+		if strings.Join(problems, "\n") != strings.Join(test.Expected, "\n") {
+			t.Errorf("#%d: expected problems:\n%s\ngot:\n%s", i, strings.Join(test.Expected, "\n"), strings.Join(problems, "\n"))
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// LoadFromFile loads the Service Registry file |registry| from the local file
+// |path|, e.g. a snapshot mirrored internally.
+//
+// See LoadFromReader().
+func (c *Client) LoadFromFile(registry RegistryType, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return c.LoadFromReader(registry, f)
+}
+
+// LoadFromReader loads the Service Registry file |registry| from |r|.
+//
+// A loaded Service Registry file is used by Lookup() as-is: it is never
+// expired, downloaded, or reloaded from the Cache (and is not saved to the
+// Cache). An explicit Download() replaces it.
+func (c *Client) LoadFromReader(registry RegistryType, r io.Reader) error {
+	json, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	s, err := newRegistry(registry, json)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	c.registries[registry] = s
+	c.loaded[registry] = true
+	delete(c.embedded, registry)
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"net/url"
+	"time"
+)
+
+// mirrorRetryInterval is how long a failing bootstrap service is tried last,
+// after its most recent failure.
+const mirrorRetryInterval = 5 * time.Minute
+
+// MirrorHealth describes the recent download history of a bootstrap service
+// (the BaseURL, or one of the Mirrors), see Client.MirrorHealth().
+type MirrorHealth struct {
+	// Base URL of the bootstrap service.
+	URL string
+
+	// Number of failed downloads since the last successful one.
+	ConsecutiveFailures int
+
+	// Last download error, nil if none.
+	LastError error
+
+	// When the last download succeeded and failed. Zero if never.
+	LastSuccess time.Time
+	LastFailure time.Time
+}
+
+// Healthy returns true if the bootstrap service has no recent failures.
+func (m MirrorHealth) Healthy() bool {
+	return m.ConsecutiveFailures == 0 || time.Since(m.LastFailure) > mirrorRetryInterval
+}
+
+// MirrorHealth returns the health of the BaseURL, followed by each of the
+// Mirrors.
+func (c *Client) MirrorHealth() []MirrorHealth {
+	c.mu.Lock()
+	c.init()
+	c.mu.Unlock()
+
+	c.mirrorMu.Lock()
+	defer c.mirrorMu.Unlock()
+
+	var result []MirrorHealth
+	for _, u := range append([]*url.URL{c.BaseURL}, c.Mirrors...) {
+		h := MirrorHealth{URL: u.String()}
+		if m := c.mirrorHealth[h.URL]; m != nil {
+			h = *m
+		}
+
+		result = append(result, h)
+	}
+
+	return result
+}
+
+// baseURLs returns the BaseURL and Mirrors, in the order to try them: the
+// configured order, except services with recent failures are tried last.
+func (c *Client) baseURLs() []*url.URL {
+	var healthy []*url.URL
+	var unhealthy []*url.URL
+
+	c.mirrorMu.Lock()
+	defer c.mirrorMu.Unlock()
+
+	for _, u := range append([]*url.URL{c.BaseURL}, c.Mirrors...) {
+		if m := c.mirrorHealth[u.String()]; m != nil && !m.Healthy() {
+			unhealthy = append(unhealthy, u)
+		} else {
+			healthy = append(healthy, u)
+		}
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// recordDownload records the result |err| of a download from the bootstrap
+// service |baseURL|.
+func (c *Client) recordDownload(baseURL *url.URL, err error) {
+	c.mirrorMu.Lock()
+	defer c.mirrorMu.Unlock()
+
+	if c.mirrorHealth == nil {
+		c.mirrorHealth = make(map[string]*MirrorHealth)
+	}
+
+	m := c.mirrorHealth[baseURL.String()]
+	if m == nil {
+		m = &MirrorHealth{URL: baseURL.String()}
+		c.mirrorHealth[m.URL] = m
+	}
+
+	if err != nil {
+		m.ConsecutiveFailures++
+		m.LastError = err
+		m.LastFailure = time.Now()
+	} else {
+		m.ConsecutiveFailures = 0
+		m.LastSuccess = time.Now()
+	}
+}
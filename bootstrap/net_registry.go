@@ -5,18 +5,17 @@
 package bootstrap
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"net"
+	"net/netip"
 	"net/url"
-	"sort"
 	"strings"
 )
 
 type NetRegistry struct {
-	// Map of netmask size (0-32 for IPv4, 0-128 for IPv6) to list of NetEntries.
-	networks map[int][]netEntry
+	// Longest-prefix-match trie of the networks.
+	root *netTrieNode
 
 	numIPBytes int // Length in bytes of each IP address (4 for IPv4, 16 for IPv6).
 
@@ -25,22 +24,18 @@ type NetRegistry struct {
 
 // A netEntry is a network and its RDAP base URLs.
 type netEntry struct {
-	Net  *net.IPNet
-	URLs []*url.URL
+	Prefix netip.Prefix
+	URLs   []*url.URL
 }
 
-type netEntrySorter []netEntry
+// A netTrieNode is a node of a binary (radix 2) trie of networks. The node at
+// depth N represents the network whose first N address bits are the path to
+// it from the root.
+type netTrieNode struct {
+	children [2]*netTrieNode
 
-func (a netEntrySorter) Len() int {
-	return len(a)
-}
-
-func (a netEntrySorter) Swap(i int, j int) {
-	a[i], a[j] = a[j], a[i]
-}
-
-func (a netEntrySorter) Less(i int, j int) bool {
-	return bytes.Compare(a[i].Net.IP, a[j].Net.IP) <= 0
+	// The network at this node, nil if none.
+	entry *netEntry
 }
 
 // NewNetRegistry creates a NetRegistry from an IPv4 or IPv6 registry JSON document. ipVersion must be 4 or 6.
@@ -59,7 +54,7 @@ func NewNetRegistry(json []byte, ipVersion int) (*NetRegistry, error) {
 	}
 
 	n := &NetRegistry{
-		networks:   map[int][]netEntry{},
+		root:       &netTrieNode{},
 		numIPBytes: numIPBytesForVersion(ipVersion),
 		file:       registry,
 	}
@@ -67,28 +62,43 @@ func NewNetRegistry(json []byte, ipVersion int) (*NetRegistry, error) {
 	var cidr string
 	var urls []*url.URL
 	for cidr, urls = range registry.Entries {
-		_, ipNet, err := net.ParseCIDR(cidr)
+		prefix, err := netip.ParsePrefix(cidr)
 
 		if err != nil {
 			continue
-		} else if len(ipNet.IP) != n.numIPBytes {
+		} else if prefix.Addr().Is4() != (ipVersion == 4) {
 			continue
 		}
 
-		size, _ := ipNet.Mask.Size()
-		n.networks[size] = append(n.networks[size], netEntry{Net: ipNet, URLs: urls})
+		n.insert(&netEntry{Prefix: prefix.Masked(), URLs: urls})
 	}
 
-	for _, nets := range n.networks {
-		sort.Sort(netEntrySorter(nets))
+	return n, nil
+}
+
+// insert adds the network |e| to the trie.
+func (n *NetRegistry) insert(e *netEntry) {
+	addr := e.Prefix.Addr().AsSlice()
+
+	node := n.root
+	for i := 0; i < e.Prefix.Bits(); i++ {
+		bit := addressBit(addr, i)
+
+		if node.children[bit] == nil {
+			node.children[bit] = &netTrieNode{}
+		}
+
+		node = node.children[bit]
 	}
 
-	return n, nil
+	node.entry = e
 }
 
 // Lookup returns the RDAP base URLs for the IP address or CIDR range question |Question|.
 //
 // Example queries are: "192.0.2.0", "192.0.2.0/25". "2001:db8::", "2001::db8::/62".
+//
+// The most specific network containing the whole query is matched.
 func (n *NetRegistry) Lookup(question *Question) (*Answer, error) {
 	input := question.Query
 
@@ -97,48 +107,51 @@ func (n *NetRegistry) Lookup(question *Question) (*Answer, error) {
 		input = fmt.Sprintf("%s/%d", input, n.numIPBytes*8)
 	}
 
-	_, lookupNet, err := net.ParseCIDR(input)
+	lookupPrefix, err := netip.ParsePrefix(input)
 
 	if err != nil {
 		return nil, err
 	}
 
-	if len(lookupNet.IP) != n.numIPBytes {
+	if lookupPrefix.Addr().Is4() != (n.numIPBytes == net.IPv4len) {
 		return nil, errors.New("Lookup address has wrong IP protocol")
 	}
 
-	lookupMask, _ := lookupNet.Mask.Size()
+	addr := lookupPrefix.Masked().Addr().AsSlice()
 
-	var bestEntry string
-	var bestURLs []*url.URL
-	var bestMask int
+	// Walk the trie along the query's address bits, remembering the last
+	// (i.e. most specific) network seen.
+	var best *netEntry
 
-	var mask int
-	var nets []netEntry
-	for mask, nets = range n.networks {
-		if mask < bestMask || mask > lookupMask {
-			continue
+	node := n.root
+	for i := 0; node != nil; i++ {
+		if node.entry != nil {
+			best = node.entry
 		}
 
-		index := sort.Search(len(nets), func(i int) bool {
-			net := nets[i].Net
-			return net.Contains(lookupNet.IP) || bytes.Compare(net.IP, lookupNet.IP) >= 0
-		})
-
-		if index == len(nets) || !nets[index].Net.Contains(lookupNet.IP) {
-			continue
+		if i == lookupPrefix.Bits() {
+			break
 		}
 
-		bestEntry = nets[index].Net.String()
-		bestMask = mask
-		bestURLs = nets[index].URLs
+		node = node.children[addressBit(addr, i)]
 	}
 
-	return &Answer{
+	answer := &Answer{
 		Query: input,
-		Entry: bestEntry,
-		URLs:  bestURLs,
-	}, nil
+	}
+
+	if best != nil {
+		answer.Entry = best.Prefix.String()
+		answer.URLs = best.URLs
+	}
+
+	return answer, nil
+}
+
+// addressBit returns bit |i| of the address |addr|, counting from the most
+// significant bit.
+func addressBit(addr []byte, i int) int {
+	return int(addr[i/8]>>(7-uint(i%8))) & 1
 }
 
 func numIPBytesForVersion(ipVersion int) int {
@@ -160,3 +173,9 @@ func numIPBytesForVersion(ipVersion int) int {
 func (n *NetRegistry) File() *File {
 	return n.file
 }
+
+// EntriesFor returns the IP networks served by the RDAP base URL
+// |baseURL|. See File.EntriesFor.
+func (n *NetRegistry) EntriesFor(baseURL string) []string {
+	return n.file.EntriesFor(baseURL)
+}
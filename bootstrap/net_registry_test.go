@@ -5,6 +5,8 @@
 package bootstrap
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/openrdap/rdap/test"
@@ -113,3 +115,89 @@ func TestNetRegistryLookupsIPv6(t *testing.T) {
 
 	runRegistryTests(t, tests, n)
 }
+
+// benchmarkNetRegistry benchmarks lookups of |queries| in the registry
+// |json|.
+func benchmarkNetRegistry(b *testing.B, json []byte, ipVersion int, queries []string) {
+	n, err := NewNetRegistry(json, ipVersion)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	questions := make([]*Question, len(queries))
+	for i, q := range queries {
+		questions[i] = &Question{Query: q}
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := n.Lookup(questions[i%len(questions)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// syntheticNetRegistry returns an IPv6 registry JSON document with |n|
+// /32 networks.
+func syntheticNetRegistry(n int) ([]byte, []string) {
+	var services []string
+	var queries []string
+
+	for i := 0; i < n; i++ {
+		services = append(services, fmt.Sprintf(`[["2001:%x::/32"], ["https://rdap%d.example/"]]`, i, i%8))
+		queries = append(queries, fmt.Sprintf("2001:%x::1", i))
+	}
+
+	return []byte(`{"services": [` + strings.Join(services, ",") + `]}`), queries
+}
+
+func BenchmarkNetRegistryLookupIPv4(b *testing.B) {
+	benchmarkNetRegistry(b, test.LoadFile("bootstrap/ipv4.json"), 4,
+		[]string{"41.0.0.1", "192.0.2.0/24", "255.0.0.0", "1.1.1.1"})
+}
+
+func BenchmarkNetRegistryLookupIPv6(b *testing.B) {
+	benchmarkNetRegistry(b, test.LoadFile("bootstrap/ipv6.json"), 6,
+		[]string{"2001:db8::1", "2c00::/12", "2001:200::1"})
+}
+
+func BenchmarkNetRegistryLookupLarge(b *testing.B) {
+	json, queries := syntheticNetRegistry(8192)
+	benchmarkNetRegistry(b, json, 6, queries)
+}
+
+func TestNetRegistryMostSpecific(t *testing.T) {
+	n, err := NewNetRegistry([]byte(`{"services": [
+		[["10.0.0.0/8"], ["https://a.example/"]],
+		[["10.1.0.0/16"], ["https://b.example/"]],
+		[["10.1.2.3/24"], ["https://c.example/"]],
+		[["2001:db8::/32"], ["https://ipv6.example/"]]
+	]}`), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"10.9.9.9":    "10.0.0.0/8",
+		"10.1.9.9":    "10.1.0.0/16",
+		"10.1.2.200":  "10.1.2.0/24",
+		"10.1.2.0/24": "10.1.2.0/24",
+		"10.1.0.0/15": "10.0.0.0/8",
+		"0.0.0.0/0":   "",
+		"11.0.0.0":    "",
+	}
+
+	for query, entry := range tests {
+		answer, err := n.Lookup(&Question{Query: query})
+		if err != nil {
+			t.Errorf("Lookup(%s) error: %s", query, err)
+		} else if answer.Entry != entry {
+			t.Errorf("Lookup(%s) expected entry %q, got %q", query, entry, answer.Entry)
+		}
+	}
+
+	if _, err := n.Lookup(&Question{Query: "2001:db8::1"}); err == nil {
+		t.Errorf("Expected error looking up IPv6 address in IPv4 registry")
+	}
+}
@@ -0,0 +1,132 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+)
+
+// AddOverride adds a custom Service Registry entry, mapping |entry| to the
+// RDAP base URLs |urls|. e.g. to use an internal RDAP server for a private
+// TLD:
+//
+//	b.AddOverride(bootstrap.DNS, "internal.corp", "https://rdap.corp.example/")
+//
+// |entry| uses the Service Registry file's format: a domain name (suffix), an
+// IP network in CIDR format ("10.0.0.0/8"), an AS number range
+// ("64512-65534"), or an object tag.
+//
+// Overrides are merged over the IANA data: Lookup() tries them first, and
+// only uses the Service Registry file if no override matches. Matching
+// follows the usual rules (e.g. the longest matching domain name suffix, or
+// the most specific IP network), applied to the overrides alone. Questions
+// answered by an override don't require the Service Registry file, and the
+// Answer has Override set.
+//
+// Adding an entry again replaces its URLs.
+func (c *Client) AddOverride(registry RegistryType, entry string, urls ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+
+	if len(urls) == 0 {
+		return fmt.Errorf("no RDAP base URLs for override '%s'", entry)
+	}
+
+	for _, u := range urls {
+		if _, err := url.Parse(u); err != nil {
+			return err
+		}
+	}
+
+	switch registry {
+	case ASN:
+		if _, _, err := parseASNRange(entry); err != nil {
+			return fmt.Errorf("bad AS number range override '%s': %s", entry, err)
+		}
+	case IPv4, IPv6:
+		ipVersion := 4
+		if registry == IPv6 {
+			ipVersion = 6
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return fmt.Errorf("bad IP network override '%s': %s", entry, err)
+		} else if len(ipNet.IP) != numIPBytesForVersion(ipVersion) {
+			return fmt.Errorf("override '%s' is not an %s network", entry, registry)
+		}
+	}
+
+	entries := map[string][]string{}
+	for e, u := range c.overrides[registry] {
+		entries[e] = u
+	}
+	entries[entry] = urls
+
+	s, err := newOverrideRegistry(registry, entries)
+	if err != nil {
+		return err
+	}
+
+	c.overrides[registry] = entries
+	c.overrideRegistries[registry] = s
+
+	return nil
+}
+
+// lookupOverride returns the Answer to |question| from the overrides, or nil
+// if no override matches.
+func (c *Client) lookupOverride(question *Question) *Answer {
+	s := c.overrideRegistries[question.RegistryType]
+	if s == nil {
+		return nil
+	}
+
+	answer, err := s.Lookup(question)
+	if err != nil || answer == nil || len(answer.URLs) == 0 {
+		return nil
+	}
+
+	answer.Registry = question.RegistryType
+	answer.Description = s.File().Description
+	answer.Override = true
+
+	return answer
+}
+
+// newOverrideRegistry creates a Registry containing the override |entries|.
+func newOverrideRegistry(registry RegistryType, entries map[string][]string) (Registry, error) {
+	var keys []string
+	for e := range entries {
+		keys = append(keys, e)
+	}
+	sort.Strings(keys)
+
+	var services [][][]string
+	for _, e := range keys {
+		if registry == ServiceProvider {
+			// object-tags.json has an additional (contact) column.
+			services = append(services, [][]string{{}, {e}, entries[e]})
+		} else {
+			services = append(services, [][]string{{e}, entries[e]})
+		}
+	}
+
+	doc, err := json.Marshal(map[string]interface{}{
+		"description": "Overrides",
+		"services":    services,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRegistry(registry, doc)
+}
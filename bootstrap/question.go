@@ -19,6 +19,14 @@ type Question struct {
 	// Query text.
 	Query string
 
+	// Optional callback function for verbose messages about this Question's
+	// lookup. The default is the Client's Verbose.
+	Verbose func(text string)
+
+	// Offline forbids network access for this Question's lookup, as if the
+	// Client's Offline was set.
+	Offline bool
+
 	ctx context.Context
 }
 
@@ -0,0 +1,101 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"path"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap/cache"
+)
+
+// Server is an http.Handler serving a Client's Service Registry files
+// ({asn,dns,ipv4,ipv6,object-tags}.json), i.e. a minimal bootstrap mirror.
+//
+// An internal fleet can then use one host as its bootstrap service (see
+// Client.BaseURL), instead of each node downloading from IANA.
+//
+// Each file is refreshed as required (see Client.Refresh()), so is
+// downloaded at most once per cache timeout. If a refresh fails, the
+// previous copy continues to be served. Responses include ETag and
+// Last-Modified headers, so clients' conditional downloads work.
+//
+// Example usage:
+//
+//	server := &bootstrap.Server{Client: &bootstrap.Client{}}
+//	http.ListenAndServe(":8080", server)
+type Server struct {
+	// Client to serve the Service Registry files of.
+	Client *Client
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	registry, ok := registryForFilename(path.Base(r.URL.Path))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	jsonDocument, modTime := s.file(registry)
+	if jsonDocument == nil {
+		http.Error(w, registry.Filename()+" not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	digest := sha256.Sum256(jsonDocument)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(digest[0:16])+`"`)
+
+	http.ServeContent(w, r, registry.Filename(), modTime, bytes.NewReader(jsonDocument))
+}
+
+// file returns the current Service Registry file |registry|, and when it
+// was saved (zero if unknown). Returns nil if the file isn't available.
+func (s *Server) file(registry RegistryType) ([]byte, time.Time) {
+	c := s.Client
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.refresh(registry); err != nil {
+		c.verbose("  bootstrap: Server refresh failed: " + err.Error())
+	}
+
+	r := c.registry(registry)
+	if r == nil || r.File() == nil {
+		return nil, time.Time{}
+	}
+
+	var modTime time.Time
+	if mc, ok := c.Cache.(cache.ModTimeCache); ok && !c.loaded[registry] {
+		modTime, _ = mc.ModTime(c.filenameFor(registry))
+	}
+
+	return r.File().JSON, modTime
+}
+
+// registryForFilename returns the RegistryType of the Service Registry file
+// named |filename|, e.g. "dns.json".
+func registryForFilename(filename string) (RegistryType, bool) {
+	for _, r := range []RegistryType{ASN, DNS, IPv4, IPv6, ServiceProvider} {
+		if r.Filename() == filename {
+			return r, true
+		}
+	}
+
+	return 0, false
+}
@@ -10,6 +10,8 @@ import (
 	"strings"
 )
 
+// ServiceProviderRegistry implements bootstrap lookups of entity handles,
+// using the RFC 8521 Object Tags registry (object-tags.json).
 type ServiceProviderRegistry struct {
 	// Map of service tag (e.g. "VRSN") to RDAP base URLs.
 	services map[string][]*url.URL
@@ -62,6 +64,16 @@ func (s *ServiceProviderRegistry) Lookup(question *Question) (*Answer, error) {
 
 	urls, ok := s.services[service]
 
+	// Fall back to a case insensitive match, e.g. "12345-arin".
+	if !ok {
+		for tag, tagURLs := range s.services {
+			if strings.EqualFold(tag, service) {
+				service, urls, ok = tag, tagURLs, true
+				break
+			}
+		}
+	}
+
 	if !ok {
 		service = ""
 	}
@@ -77,3 +89,9 @@ func (s *ServiceProviderRegistry) Lookup(question *Question) (*Answer, error) {
 func (s *ServiceProviderRegistry) File() *File {
 	return s.file
 }
+
+// EntriesFor returns the object tags served by the RDAP base URL
+// |baseURL|. See File.EntriesFor.
+func (s *ServiceProviderRegistry) EntriesFor(baseURL string) []string {
+	return s.file.EntriesFor(baseURL)
+}
@@ -54,6 +54,12 @@ func TestServiceProviderRegistryLookups(t *testing.T) {
 			"FRNIC",
 			[]string{"https://rdap.nic.fr/"},
 		},
+		{
+			"12345-frnic",
+			false,
+			"FRNIC",
+			[]string{"https://rdap.nic.fr/"},
+		},
 	}
 
 	runRegistryTests(t, tests, s)
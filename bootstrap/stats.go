@@ -0,0 +1,83 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+// ClientStats are counters of a Client's activity, e.g. for capacity
+// monitoring. See Client.Stats().
+type ClientStats struct {
+	// Number of Lookup() calls.
+	Lookups int64
+
+	// Lookups answered without a download attempt (from memory, the Cache,
+	// a loaded file, or an override), and lookups which attempted a download.
+	CacheHits   int64
+	CacheMisses int64
+
+	// Lookups which found no RDAP service, and lookups which failed.
+	NoMatch int64
+	Errors  int64
+
+	// Successful and failed downloads, per Service Registry file. A download
+	// failing over to a mirror counts once.
+	Downloads        map[RegistryType]int64
+	DownloadFailures map[RegistryType]int64
+}
+
+// Stats returns a snapshot of the Client's counters.
+func (c *Client) Stats() ClientStats {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	s := c.stats
+	s.Downloads = make(map[RegistryType]int64)
+	s.DownloadFailures = make(map[RegistryType]int64)
+
+	for r, n := range c.stats.Downloads {
+		s.Downloads[r] = n
+	}
+	for r, n := range c.stats.DownloadFailures {
+		s.DownloadFailures[r] = n
+	}
+
+	return s
+}
+
+// recordLookup counts a Lookup() call, with result |answer|, |err|.
+// |downloadAttempted| is true if a download was needed.
+func (c *Client) recordLookup(downloadAttempted bool, answer *Answer, err error) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	c.stats.Lookups++
+
+	if downloadAttempted {
+		c.stats.CacheMisses++
+	} else {
+		c.stats.CacheHits++
+	}
+
+	if err != nil {
+		c.stats.Errors++
+	} else if answer == nil || len(answer.URLs) == 0 {
+		c.stats.NoMatch++
+	}
+}
+
+// recordDownloadResult counts a download of |registry|, with result |err|.
+func (c *Client) recordDownloadResult(registry RegistryType, err error) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	if c.stats.Downloads == nil {
+		c.stats.Downloads = make(map[RegistryType]int64)
+		c.stats.DownloadFailures = make(map[RegistryType]int64)
+	}
+
+	if err != nil {
+		c.stats.DownloadFailures[registry]++
+	} else {
+		c.stats.Downloads[registry]++
+	}
+}
@@ -0,0 +1,120 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap/cache"
+)
+
+// RegistryStatus describes the availability of a Service Registry file, see
+// Client.Status().
+type RegistryStatus struct {
+	Registry RegistryType
+
+	// Available is true if a copy of the file is available for lookups
+	// (downloaded, cached, loaded from a file, or embedded).
+	Available bool
+
+	// Fresh is true if the copy is within the Cache timeout, or was loaded
+	// from a file. Stale and embedded copies are not fresh.
+	Fresh bool
+
+	// Embedded is true if the embedded snapshot is in use.
+	Embedded bool
+
+	// Loaded is true if the file was loaded with LoadFromFile() or
+	// LoadFromReader().
+	Loaded bool
+
+	// The file's publication date and version, if available.
+	Publication string
+	Version     string
+
+	// Cached is true if the file is in the Cache.
+	Cached bool
+
+	// When the cached file was saved, and its age. Zero if not cached, or
+	// the Cache doesn't implement cache.ModTimeCache.
+	ModTime time.Time
+	Age     time.Duration
+
+	// Size of the file in bytes, and its number of entries (e.g. TLDs in
+	// dns.json). Zero if not available.
+	Size    int
+	Entries int
+
+	// URL the file is downloaded from.
+	SourceURL string
+}
+
+// Status returns the availability of the Service Registry file |registry|,
+// e.g. for health checks.
+//
+// This function never initiates a network transfer.
+func (c *Client) Status(registry RegistryType) *RegistryStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.init()
+	c.freshenFromCache(registry)
+
+	s := &RegistryStatus{
+		Registry:  registry,
+		Embedded:  c.embedded[registry] != nil,
+		Loaded:    c.loaded[registry],
+		SourceURL: urlFor(c.BaseURL, registry).String(),
+	}
+
+	filename := c.filenameFor(registry)
+	state := c.Cache.State(filename)
+
+	s.Cached = state != cache.Absent
+	if mc, ok := c.Cache.(cache.ModTimeCache); ok && s.Cached {
+		if modTime, err := mc.ModTime(filename); err == nil {
+			s.ModTime = modTime
+			s.Age = time.Since(modTime)
+		}
+	}
+
+	var file *File
+	if r := c.registries[registry]; r != nil {
+		s.Available = true
+		file = r.File()
+	} else if s.Cached {
+		s.Available = true
+
+		// Parse the cached copy, without using it for lookups.
+		if json, err := c.Cache.Load(filename); err == nil {
+			file, _ = NewFile(json)
+		}
+	}
+
+	if file != nil {
+		s.Publication = file.Publication
+		s.Version = file.Version
+		s.Size = len(file.JSON)
+		s.Entries = len(file.Entries)
+	}
+
+	s.Fresh = s.Loaded || (!s.Embedded && (state == cache.Good || state == cache.ShouldReload))
+
+	return s
+}
+
+// Statuses returns the Status() of each Service Registry file (asn, dns,
+// ipv4, ipv6, and object-tags), e.g. to see why a lookup used stale data.
+//
+// This function never initiates a network transfer.
+func (c *Client) Statuses() []*RegistryStatus {
+	var statuses []*RegistryStatus
+
+	for _, registry := range []RegistryType{ASN, DNS, IPv4, IPv6, ServiceProvider} {
+		statuses = append(statuses, c.Status(registry))
+	}
+
+	return statuses
+}
@@ -0,0 +1,144 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const (
+	// TLDListURL is the IANA list of all top-level domains.
+	TLDListURL = "https://data.iana.org/TLD/tlds-alpha-by-domain.txt"
+)
+
+// TLDCoverage reports which top-level domains have an RDAP service.
+type TLDCoverage struct {
+	// TLDs with an RDAP service, in lowercase A-label form, sorted.
+	Supported []string
+
+	// TLDs without an RDAP service (e.g. WHOIS only), in lowercase A-label
+	// form, sorted.
+	Unsupported []string
+}
+
+// IsSupported returns true if the TLD |tld| has an RDAP service.
+func (t *TLDCoverage) IsSupported(tld string) bool {
+	tld = strings.ToLower(strings.Trim(tld, "."))
+
+	i := sort.SearchStrings(t.Supported, tld)
+	return i < len(t.Supported) && t.Supported[i] == tld
+}
+
+// Coverage compares the list of TLDs |tlds| with the DNS registry, and
+// reports which TLDs have an RDAP service.
+//
+// A TLD is supported if the registry has an entry for it.
+func (d *DNSRegistry) Coverage(tlds []string) *TLDCoverage {
+	t := &TLDCoverage{}
+
+	for _, tld := range tlds {
+		tld = strings.ToLower(strings.Trim(tld, "."))
+		if tld == "" {
+			continue
+		}
+
+		if _, ok := d.dns[tld]; ok {
+			t.Supported = append(t.Supported, tld)
+		} else {
+			t.Unsupported = append(t.Unsupported, tld)
+		}
+	}
+
+	sort.Strings(t.Supported)
+	sort.Strings(t.Unsupported)
+
+	return t
+}
+
+// ParseTLDList parses the IANA TLD list (tlds-alpha-by-domain.txt) from |r|.
+//
+// Returns the TLDs in lowercase.
+func ParseTLDList(r io.Reader) ([]string, error) {
+	var tlds []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		tlds = append(tlds, strings.ToLower(line))
+	}
+
+	return tlds, scanner.Err()
+}
+
+// DownloadTLDList downloads and parses the IANA TLD list from TLDListURL.
+func (c *Client) DownloadTLDList(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	c.init()
+	c.mu.Unlock()
+
+	req, err := http.NewRequest("GET", TLDListURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error downloading TLD list %s: %s", TLDListURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Error downloading TLD list %s: Server returned non-200 status code: %s", TLDListURL, resp.Status)
+	}
+
+	return ParseTLDList(resp.Body)
+}
+
+// TLDCoverage reports which top-level domains have an RDAP service, e.g. to
+// decide when to fall back to WHOIS.
+//
+// The IANA TLD list is downloaded, and compared with the DNS Service Registry
+// file (which is downloaded if not already available). TLDs covered by an
+// AddOverride() entry are also considered supported.
+func (c *Client) TLDCoverage(ctx context.Context) (*TLDCoverage, error) {
+	tlds, err := c.DownloadTLDList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.registry(DNS) == nil {
+		if err := c.downloadRegistry(ctx, DNS); err != nil {
+			return nil, err
+		}
+	}
+
+	t := c.registries[DNS].(*DNSRegistry).Coverage(tlds)
+
+	if overrides, ok := c.overrideRegistries[DNS].(*DNSRegistry); ok && len(t.Unsupported) > 0 {
+		o := overrides.Coverage(t.Unsupported)
+
+		t.Supported = append(t.Supported, o.Supported...)
+		t.Unsupported = o.Unsupported
+
+		sort.Strings(t.Supported)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,64 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestParseTLDList(t *testing.T) {
+	tlds, err := ParseTLDList(strings.NewReader("# Version 1\nCOM\n\nXN--P1AI\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(tlds, []string{"com", "xn--p1ai"}) {
+		t.Errorf("Unexpected TLDs %v", tlds)
+	}
+}
+
+func TestTLDCoverage(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	c := &Client{}
+
+	if err := c.AddOverride(DNS, "uk", "https://rdap.example.uk/"); err != nil {
+		t.Fatal(err)
+	}
+
+	coverage, err := c.TLDCoverage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(coverage.Supported, []string{"ar", "br", "cz", "uk"}) {
+		t.Errorf("Unexpected supported TLDs %v", coverage.Supported)
+	}
+
+	if !reflect.DeepEqual(coverage.Unsupported, []string{"com", "xn--p1ai"}) {
+		t.Errorf("Unexpected unsupported TLDs %v", coverage.Unsupported)
+	}
+
+	if !coverage.IsSupported("CZ.") || coverage.IsSupported("com") {
+		t.Errorf("Unexpected IsSupported() result")
+	}
+}
+
+func TestTLDCoverageDownloadError(t *testing.T) {
+	test.Start(test.BootstrapHTTPError)
+	defer test.Finish()
+
+	c := &Client{}
+
+	if _, err := c.TLDCoverage(context.Background()); err == nil {
+		t.Errorf("Unexpected success")
+	}
+}
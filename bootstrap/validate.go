@@ -0,0 +1,230 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationProblem is a problem found by ValidateFile().
+type ValidationProblem struct {
+	// Index of the service in the services array. -1 for problems with the
+	// document as a whole.
+	Service int
+
+	// The entry (e.g. TLD, IP network, AS number range) or URL with the
+	// problem. Empty string if not applicable.
+	Value string
+
+	// Description of the problem.
+	Message string
+}
+
+func (v ValidationProblem) Error() string {
+	var where string
+	if v.Service >= 0 {
+		where = fmt.Sprintf("services[%d]: ", v.Service)
+	}
+
+	if v.Value != "" {
+		return fmt.Sprintf("%s%q: %s", where, v.Value, v.Message)
+	}
+
+	return where + v.Message
+}
+
+// ValidateFile checks the Service Registry file |jsonDocument|, of type
+// |registry|, against RFC 9224 (formerly RFC 7484), and RFC 8521 for
+// object-tags.json.
+//
+// NewFile() is lenient: unparsable URLs are ignored, and overlapping entries
+// silently replace each other. ValidateFile instead reports every problem
+// found, e.g. for mirror operators to lint their files. The checks are:
+//
+//   - The document is a JSON object, with version "1.0", an RFC 3339
+//     publication date, and a services array.
+//   - Each service has the right number of string arrays (three for
+//     object-tags.json, two otherwise), and at least one entry and URL.
+//   - Entries are valid for the registry type: lower case domain names, IP
+//     networks of the right address family, AS numbers and ranges, or object
+//     tags.
+//   - Entries don't overlap: no duplicates, and no overlapping AS number
+//     ranges.
+//   - URLs are absolute http/https URLs, ending in a slash (a requirement
+//     added by RFC 9224).
+//
+// Returns nil if the file is valid.
+func ValidateFile(registry RegistryType, jsonDocument []byte) []ValidationProblem {
+	var problems []ValidationProblem
+	addProblem := func(service int, value string, format string, args ...interface{}) {
+		problems = append(problems, ValidationProblem{
+			Service: service,
+			Value:   value,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	var doc struct {
+		Version     *string
+		Publication *string
+		Services    *[]json.RawMessage
+	}
+
+	if err := json.Unmarshal(jsonDocument, &doc); err != nil {
+		addProblem(-1, "", "invalid JSON document: %s", err)
+		return problems
+	}
+
+	if doc.Version == nil {
+		addProblem(-1, "", "missing version")
+	} else if *doc.Version != "1.0" {
+		addProblem(-1, *doc.Version, "unsupported version, expected \"1.0\"")
+	}
+
+	if doc.Publication == nil {
+		addProblem(-1, "", "missing publication")
+	} else if _, err := time.Parse(time.RFC3339, *doc.Publication); err != nil {
+		addProblem(-1, *doc.Publication, "publication is not an RFC 3339 date")
+	}
+
+	if doc.Services == nil {
+		addProblem(-1, "", "missing services array")
+		return problems
+	}
+
+	numArrays := 2
+	if registry == ServiceProvider {
+		numArrays = 3
+	}
+
+	seen := make(map[string]int)
+	type asnRange struct {
+		min, max uint32
+		service  int
+		entry    string
+	}
+	var asnRanges []asnRange
+
+	for i, rawService := range *doc.Services {
+		var service [][]string
+		if err := json.Unmarshal(rawService, &service); err != nil || len(service) != numArrays {
+			addProblem(i, "", "service must be an array of %d string arrays", numArrays)
+			continue
+		}
+
+		entries := service[numArrays-2]
+		urls := service[numArrays-1]
+
+		if registry == ServiceProvider && len(service[0]) == 0 {
+			addProblem(i, "", "no contact information")
+		}
+
+		if len(entries) == 0 {
+			addProblem(i, "", "no entries")
+		}
+
+		if len(urls) == 0 {
+			addProblem(i, "", "no URLs")
+		}
+
+		for _, entry := range entries {
+			if msg := validateEntry(registry, entry); msg != "" {
+				addProblem(i, entry, "%s", msg)
+				continue
+			}
+
+			key := entry
+			if registry == IPv4 || registry == IPv6 {
+				prefix, _ := netip.ParsePrefix(entry)
+				key = prefix.Masked().String()
+			} else if registry == DNS || registry == ServiceProvider {
+				key = strings.ToLower(entry)
+			}
+
+			if other, ok := seen[key]; ok {
+				addProblem(i, entry, "duplicate entry, also in services[%d]", other)
+				continue
+			}
+			seen[key] = i
+
+			if registry == ASN {
+				min, max, _ := parseASNRange(entry)
+
+				for _, r := range asnRanges {
+					if min <= r.max && r.min <= max {
+						addProblem(i, entry, "overlaps %q in services[%d]", r.entry, r.service)
+					}
+				}
+
+				asnRanges = append(asnRanges, asnRange{min, max, i, entry})
+			}
+		}
+
+		for _, rawURL := range urls {
+			u, err := url.Parse(rawURL)
+
+			if err != nil {
+				addProblem(i, rawURL, "unparsable URL: %s", err)
+			} else if u.Scheme != "http" && u.Scheme != "https" || u.Host == "" {
+				addProblem(i, rawURL, "not an absolute http/https URL")
+			} else if !strings.HasSuffix(u.Path, "/") {
+				addProblem(i, rawURL, "base URL must end with a slash")
+			}
+		}
+	}
+
+	return problems
+}
+
+// validateEntry checks the service entry |entry| is valid for the registry
+// type |registry|.
+//
+// Returns a description of the problem, or empty string if valid.
+func validateEntry(registry RegistryType, entry string) string {
+	switch registry {
+	case ASN:
+		first, last, ok := strings.Cut(entry, "-")
+		if !ok {
+			last = first
+		}
+
+		min, minErr := strconv.ParseUint(first, 10, 32)
+		max, maxErr := strconv.ParseUint(last, 10, 32)
+		if minErr != nil || maxErr != nil {
+			return "not an AS number or range (e.g. 1-100)"
+		} else if min > max {
+			return "AS number range is reversed"
+		}
+	case DNS:
+		if entry == "" {
+			return "empty domain name"
+		} else if entry != strings.ToLower(entry) {
+			return "domain name must be lower case"
+		} else if strings.HasPrefix(entry, ".") || strings.HasSuffix(entry, ".") || strings.Contains(entry, "..") {
+			return "malformed domain name"
+		}
+	case IPv4, IPv6:
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return "not an IP network (e.g. 192.0.2.0/24)"
+		} else if prefix.Addr().Is4() != (registry == IPv4) {
+			return fmt.Sprintf("wrong address family for %s", registry.Filename())
+		} else if prefix.Masked() != prefix {
+			return "IP network has host bits set"
+		}
+	case ServiceProvider:
+		if entry == "" || strings.ContainsAny(entry, "- ") {
+			return "malformed object tag"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// validators are the HTTP cache validators (ETag and Last-Modified) of a
+// downloaded Service Registry file.
+//
+// They're saved to the Cache alongside the file (as e.g.
+// dns.json.validators), so the next download can be a conditional request.
+// If the file hasn't changed, the server replies 304 Not Modified with no
+// body, and the cached copy is refreshed instead.
+type validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// newValidators returns the validators of the HTTP response |resp|, or nil
+// if it has none.
+func newValidators(resp *http.Response) *validators {
+	v := &validators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+
+	if v.ETag == "" && v.LastModified == "" {
+		return nil
+	}
+
+	return v
+}
+
+// addHeaders makes |req| a conditional request.
+func (v *validators) addHeaders(req *http.Request) {
+	if v.ETag != "" {
+		req.Header.Set("If-None-Match", v.ETag)
+	}
+
+	if v.LastModified != "" {
+		req.Header.Set("If-Modified-Since", v.LastModified)
+	}
+}
+
+func (c *Client) validatorsFilenameFor(registry RegistryType) string {
+	return c.filenameFor(registry) + ".validators"
+}
+
+// loadValidators returns the saved validators for the Service Registry file
+// |registry|, or nil if there are none.
+func (c *Client) loadValidators(registry RegistryType) *validators {
+	data, err := c.Cache.Load(c.validatorsFilenameFor(registry))
+	if err != nil {
+		return nil
+	}
+
+	v := &validators{}
+	if err := json.Unmarshal(data, v); err != nil || (v.ETag == "" && v.LastModified == "") {
+		return nil
+	}
+
+	return v
+}
+
+// cachedCopy is a cached Service Registry file, and the validators of its
+// download, for a conditional download.
+type cachedCopy struct {
+	json []byte
+	v    *validators
+}
+
+// loadCachedCopy returns the cached copy of the Service Registry file
+// |registry| and its validators, or nil if either is missing.
+func (c *Client) loadCachedCopy(registry RegistryType) *cachedCopy {
+	v := c.loadValidators(registry)
+	if v == nil {
+		return nil
+	}
+
+	json, err := c.Cache.Load(c.filenameFor(registry))
+	if err != nil {
+		return nil
+	}
+
+	return &cachedCopy{json: json, v: v}
+}
+
+// saveValidators saves the validators |v| for the Service Registry file
+// |registry|. A nil |v| clears any previously saved validators.
+//
+// Errors are ignored, the next download just won't be conditional.
+func (c *Client) saveValidators(registry RegistryType, v *validators) {
+	if v == nil {
+		v = &validators{}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	c.Cache.Save(c.validatorsFilenameFor(registry), data)
+}
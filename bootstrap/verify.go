@@ -0,0 +1,161 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package bootstrap
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// A Verifier checks the integrity of downloaded Service Registry files, see
+// Client.Verifier.
+type Verifier interface {
+	// Verify returns an error unless |jsonDocument|, the Service Registry
+	// file |registry| downloaded from |fileURL|, is trusted.
+	Verify(ctx context.Context, registry RegistryType, fileURL string, jsonDocument []byte) error
+}
+
+// VerificationError is returned when a downloaded Service Registry file fails
+// verification by the Client's Verifier.
+type VerificationError struct {
+	Registry RegistryType
+	URL      string
+	Err      error
+}
+
+func (v *VerificationError) Error() string {
+	return fmt.Sprintf("Verification of %s Service Registry file %s failed: %s", v.Registry, v.URL, v.Err)
+}
+
+// Unwrap returns the underlying error.
+func (v *VerificationError) Unwrap() error {
+	return v.Err
+}
+
+// SHA256Manifest is a Verifier which checks files against pinned SHA-256
+// digests. It maps filenames (e.g. "dns.json") to hex encoded digests.
+//
+// Files not in the manifest fail verification.
+type SHA256Manifest map[string]string
+
+// ParseSHA256Manifest parses a manifest in sha256sum format, e.g.:
+//
+//	2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae  dns.json
+//
+// Only the base name of each file is used.
+func ParseSHA256Manifest(data []byte) (SHA256Manifest, error) {
+	m := SHA256Manifest{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected DIGEST FILENAME", lineNum)
+		}
+
+		digest, filename := strings.ToLower(fields[0]), strings.TrimPrefix(fields[1], "*")
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != sha256.Size*2 {
+			return nil, fmt.Errorf("line %d: invalid SHA-256 digest", lineNum)
+		}
+
+		m[filename[strings.LastIndex(filename, "/")+1:]] = digest
+	}
+
+	return m, scanner.Err()
+}
+
+// Verify implements Verifier.
+func (m SHA256Manifest) Verify(ctx context.Context, registry RegistryType, fileURL string, jsonDocument []byte) error {
+	expected, ok := m[registry.Filename()]
+	if !ok {
+		return fmt.Errorf("%s not in SHA-256 manifest", registry.Filename())
+	}
+
+	digest := sha256.Sum256(jsonDocument)
+	if hex.EncodeToString(digest[:]) != strings.ToLower(expected) {
+		return errors.New("SHA-256 digest mismatch")
+	}
+
+	return nil
+}
+
+// SignatureVerifier is a Verifier which checks detached Ed25519 signatures.
+//
+// The signature of each file is downloaded from the file's URL plus
+// SignatureSuffix (e.g. https://mirror.example/rdap/dns.json.sig). It may be
+// raw (64 bytes) or base64 encoded.
+type SignatureVerifier struct {
+	// Public key the files are signed with.
+	PublicKey ed25519.PublicKey
+
+	// Suffix of the signature URLs. Default is ".sig".
+	SignatureSuffix string
+
+	// HTTP client. Default is http.DefaultClient.
+	HTTP *http.Client
+}
+
+// Verify implements Verifier.
+func (s *SignatureVerifier) Verify(ctx context.Context, registry RegistryType, fileURL string, jsonDocument []byte) error {
+	suffix := s.SignatureSuffix
+	if suffix == "" {
+		suffix = ".sig"
+	}
+
+	httpClient := s.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("GET", fileURL+suffix, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("signature download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("signature download failed: %s", resp.Status)
+	}
+
+	sig, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("signature download failed: %w", err)
+	}
+
+	if len(sig) != ed25519.SignatureSize {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+		if err != nil || len(decoded) != ed25519.SignatureSize {
+			return errors.New("malformed signature")
+		}
+
+		sig = decoded
+	}
+
+	if !ed25519.Verify(s.PublicKey, jsonDocument, sig) {
+		return errors.New("bad signature")
+	}
+
+	return nil
+}
@@ -6,18 +6,19 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/openrdap/rdap/bootstrap"
 	"github.com/openrdap/rdap/bootstrap/cache"
+	"github.com/openrdap/rdap/internal/autnum"
 	"github.com/openrdap/rdap/sandbox"
 
 	"golang.org/x/crypto/pkcs12"
@@ -26,7 +27,7 @@ import (
 )
 
 var (
-	version   = "OpenRDAP v0.9.1"
+	version   = "OpenRDAP v" + libraryVersion
 	usageText = version + `
 (www.openrdap.org)
 
@@ -43,20 +44,70 @@ Usage: rdap [OPTIONS] DOMAIN|IP|ASN|ENTITY|NAMESERVER|RDAP-URL
 
 Options:
   -h, --help          Show help message.
-  -V, --version       Print version and quit.
+  -V, --version       Print version and quit. Use --json for JSON output,
+                      including build metadata (commit, date).
   -v, --verbose       Print verbose messages on STDERR.
+  -q, --quiet         Print only errors on STDERR (no warnings).
 
   -T, --timeout=SECS  Timeout after SECS seconds (default: 30).
+      --query-timeout=SECS
+                      Timeout of each RDAP server query in SECS seconds,
+                      counted within --timeout (default: no separate
+                      timeout). See also --bs-timeout.
   -k, --insecure      Disable SSL certificate verification.
+      --tls-fallback  On an SSL certificate error, try the next RDAP server
+                      (if any) instead of stopping.
+      --strict        Reject responses which don't match the query (wrong
+                      object class, domain name, handle, or address range).
+      --archive-dir=DIR
+                      Save every raw RDAP response (with its URL and
+                      timestamp) under DIR, as an evidentiary trail.
+      --metrics-file=FILE
+                      Write query/HTTP/bootstrap counters and durations to
+                      FILE in OpenMetrics text format, at the end of the run.
+      --manifest=FILE Write a JSON run manifest to FILE: run ID, start/end
+                      time, version, arguments (secrets redacted), and
+                      SHA-256 digests of the query, output, and responses.
 
 Output Options:
       --text          Output RDAP, plain text "tree" format (default).
   -w, --whois         Output WHOIS style (domain queries only).
   -j, --json          Output JSON, pretty-printed format.
+      --json-keys=STYLE
+                      Convert the --json output's keys to a single naming
+                      convention: snake (e.g. object_class_name) or camel
+                      (e.g. objectClassName). Default: as-is.
   -r, --raw           Output the raw server response.
+      --extract=PATH  Output only the values at the JSON PATH, one per line.
+                      e.g. '.events[?eventAction=="expiration"].eventDate'
+                      Supports .field, [N], [*], [?field=="value"].
+      --graph=FORMAT  Output the graph of object relationships (domain,
+                      entities, nameservers, networks, links), for
+                      visualization. FORMAT is dot (Graphviz) or json.
 
 Advanced options (query):
   -s  --server=URL    RDAP server to query.
+  -l  --lang=LANG     Preferred response language, e.g. ja. Can be repeated.
+                      Multilingual notices and remarks are printed in the
+                      preferred language only.
+      --host-param=HOST:KEY=VALUE
+                      Add the query parameter KEY=VALUE to every request to
+                      HOST, e.g. an API key. Not shown in verbose output.
+                      Can be repeated.
+      --tag=KEY=VALUE Attach metadata to the query, e.g. --tag case=1234.
+                      Printed in verbose and --lookup-only --json output.
+                      Can be repeated.
+      --dns-precheck  For domain queries, check the domain's NS records
+                      exist in the DNS first. Clearly nonexistent domains
+                      are reported without querying RDAP.
+      --infer-roles   Guess the roles of contacts which have none (from
+                      their vCard, remarks, and position). Inferred roles
+                      are marked "(inferred)".
+      --related       Follow "related" links to the registrar's RDAP server,
+                      and also print its response (gTLD domains only).
+  -f  --fetch=ROLE    Fetch full contact information for ROLE, when only a
+                      link is provided. e.g. registrant, administrative.
+                      Use "all" for all roles. Can be repeated.
   -t  --type=TYPE     RDAP query type. Normally auto-detected. The types are:
                       - ip
                       - domain
@@ -72,16 +123,74 @@ Advanced options (query):
                       - nameserver-search-by-ip
                       - entity-search
                       - entity-search-by-handle
+                      - ip-search
+                      - ip-search-by-handle
+                      - autnum-search
+                      - autnum-search-by-handle
                       The servers for domain, ip, autnum, url queries can be
                       determined automatically. Otherwise, the RDAP server
                       (--server=URL) must be specified.
+      --sort=PROPERTY[:a|:d]
+                      Sort search results by PROPERTY (e.g. name,
+                      registrationDate:d), if the server supports sorting.
+                      Can be repeated.
+      --count         Request the total number of search results.
+      --cursor=CURSOR Fetch the search results page CURSOR, as given by
+                      the previous page's "Next Page" link.
+      --field-set=NAME
+                      Request the search results field set NAME (e.g. id,
+                      brief, full), if the server supports field sets.
 
 Advanced options (bootstrapping):
       --cache-dir=DIR Bootstrap cache directory to use. Specify empty string
                       to disable bootstrap caching. The directory is created
-                      automatically as needed. (default: $HOME/.openrdap).
+                      automatically as needed. (default: openrdap in the
+                      user cache dir, e.g. $XDG_CACHE_HOME/openrdap, or
+                      $HOME/.openrdap if that exists).
+      --cache-db=FILE Use the single-file database FILE as the bootstrap cache,
+                      instead of --cache-dir. Safe for concurrent use by
+                      multiple processes.
+      --cache-db-ttl=SECS
+                      Also cache RDAP responses in the --cache-db database,
+                      for SECS seconds (default: 0, disabled).
       --bs-url=URL    Bootstrap service URL (default: https://data.iana.org/rdap)
+      --bs-mirror=URL Alternate bootstrap service URL, tried if a download
+                      from --bs-url fails. Can be specified multiple times.
       --bs-ttl=SECS   Bootstrap cache time in seconds (default: 3600)
+      --offline       Use only the bootstrap cache, never the network. Useful
+                      with --lookup-only.
+      --bs-max-stale=SECS
+                      If a bootstrap download fails, use an expired cached
+                      copy up to SECS seconds old (default: 0, disabled).
+      --bs-file=FILE  Use a local bootstrap file instead of downloading it,
+                      e.g. an internal mirror. The registry type is taken
+                      from the filename ({asn,dns,ipv4,ipv6,object-tags}.json).
+                      Can be specified multiple times.
+      --bs-sha256=FILE
+                      Verify downloaded bootstrap files against the SHA-256
+                      digests in FILE (sha256sum format). Files failing
+                      verification are not used.
+      --bs-override=TYPE:ENTRY=URL
+                      Use the RDAP server URL for ENTRY, instead of the
+                      bootstrap data. TYPE is one of dns, ipv4, ipv6, asn,
+                      or serviceprovider. e.g.
+                      --bs-override=dns:internal.corp=https://rdap.corp/
+                      Can be specified multiple times.
+      --bs-embedded   If a bootstrap download fails and nothing is cached,
+                      use the built-in bootstrap files (which may be out of
+                      date) instead of failing.
+      --lookup-only   Print the RDAP service URLs for the query, one per
+                      line, without querying them. Use --json for JSON output.
+      --bs-timeout=SECS
+                      Bootstrap download timeout in seconds, counted within
+                      --timeout (default: no separate timeout).
+      --bs-status     Print the status of each bootstrap file (cached, age,
+                      size, number of entries, and source URL), without
+                      downloading anything, and exit. No query is required.
+                      Use --json for JSON output.
+      --bs-serve=ADDR Serve the bootstrap files over HTTP on ADDR (e.g.
+                      :8080), as a bootstrap mirror for other hosts'
+                      --bs-url. Runs until interrupted. No query is required.
 
 Advanced options (authentication):
   -P, --p12=cert.p12[:password] Use client certificate & private key (PKCS#12 format)
@@ -108,11 +217,14 @@ type CLIOptions struct {
 // RunCLI runs the OpenRDAP command line client.
 //
 // |args| are the command line arguments to use (normally os.Args[1:]).
-// |stdout| and |stderr| are the io.Writers for STDOUT/STDERR.
+// |stdout| and |stderr| are the io.Writers for STDOUT/STDERR. Only the
+// output (e.g. the response, or --help message) is written to |stdout|, all
+// diagnostics are written to |stderr|. This keeps the machine-readable output
+// formats (--json, --raw, --extract, etc) safe to use in pipelines.
 // |options| specifies extra options.
 //
 // Returns the program exit code.
-func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOptions) int {
+func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOptions) (exitCode int) {
 	// For duration timer (in --verbose output).
 	start := time.Now()
 
@@ -134,20 +246,50 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 
 	// Command line options.
 	verboseFlag := app.Flag("verbose", "").Short('v').Bool()
+	quietFlag := app.Flag("quiet", "").Short('q').Bool()
 	versionFlag := app.Flag("version", "").Short('V').Bool()
 	timeoutFlag := app.Flag("timeout", "").Short('T').Default("30").Uint16()
+	queryTimeoutFlag := app.Flag("query-timeout", "").Default("0").Uint16()
 	insecureFlag := app.Flag("insecure", "").Short('k').Bool()
+	tlsFallbackFlag := app.Flag("tls-fallback", "").Bool()
+	strictFlag := app.Flag("strict", "").Bool()
+	metricsFileFlag := app.Flag("metrics-file", "").String()
+	manifestFlag := app.Flag("manifest", "").String()
+	archiveDirFlag := app.Flag("archive-dir", "").String()
 
 	queryType := app.Flag("type", "").Short('t').String()
 	fetchRolesFlag := app.Flag("fetch", "").Short('f').Strings()
+	relatedFlag := app.Flag("related", "").Bool()
+	inferRolesFlag := app.Flag("infer-roles", "").Bool()
+	dnsPrecheckFlag := app.Flag("dns-precheck", "").Bool()
 	serverFlag := app.Flag("server", "").Short('s').String()
+	langFlag := app.Flag("lang", "").Short('l').Strings()
+	tagFlag := app.Flag("tag", "").StringMap()
+	sortFlag := app.Flag("sort", "").Strings()
+	countFlag := app.Flag("count", "").Bool()
+	cursorFlag := app.Flag("cursor", "").String()
+	fieldSetFlag := app.Flag("field-set", "").String()
+	hostParamFlag := app.Flag("host-param", "").Strings()
 
 	experimentalFlag := app.Flag("experimental", "").Short('e').Bool()
 	experimentsFlag := app.Flag("exp", "").Strings()
 
 	cacheDirFlag := app.Flag("cache-dir", "").Default("default").String()
+	cacheDBFlag := app.Flag("cache-db", "").String()
+	cacheDBTTLFlag := app.Flag("cache-db-ttl", "").Default("0").Uint32()
 	bootstrapURLFlag := app.Flag("bs-url", "").Default("default").String()
+	bootstrapMirrorFlag := app.Flag("bs-mirror", "").Strings()
 	bootstrapTimeoutFlag := app.Flag("bs-ttl", "").Default("3600").Uint32()
+	bootstrapMaxStaleFlag := app.Flag("bs-max-stale", "").Default("0").Uint32()
+	bootstrapFileFlag := app.Flag("bs-file", "").Strings()
+	bootstrapSHA256Flag := app.Flag("bs-sha256", "").String()
+	bootstrapOverrideFlag := app.Flag("bs-override", "").Strings()
+	bootstrapEmbeddedFlag := app.Flag("bs-embedded", "").Bool()
+	offlineFlag := app.Flag("offline", "").Bool()
+	bootstrapDownloadTimeoutFlag := app.Flag("bs-timeout", "").Default("0").Uint16()
+	lookupOnlyFlag := app.Flag("lookup-only", "").Bool()
+	bootstrapStatusFlag := app.Flag("bs-status", "").Bool()
+	bootstrapServeFlag := app.Flag("bs-serve", "").String()
 
 	clientP12FilenameAndPassword := app.Flag("p12", "").Short('P').String()
 	clientCertFilename := app.Flag("cert", "").Short('C').String()
@@ -156,7 +298,10 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 	outputFormatText := app.Flag("text", "").Bool()
 	outputFormatWhois := app.Flag("whois", "").Short('w').Bool()
 	outputFormatJSON := app.Flag("json", "").Short('j').Bool()
+	jsonKeysFlag := app.Flag("json-keys", "").Default("as-is").Enum("as-is", "snake", "camel")
 	outputFormatRaw := app.Flag("raw", "").Short('r').Bool()
+	extractFlag := app.Flag("extract", "").String()
+	graphFlag := app.Flag("graph", "").Enum("dot", "json")
 
 	// Command line query (any remaining non-option arguments).
 	queryArgs := app.Arg("", "").Strings()
@@ -174,10 +319,21 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 
 	// Print version string?
 	if *versionFlag {
-		fmt.Fprintln(stdout, version)
+		if *outputFormatJSON {
+			out, _ := json.MarshalIndent(Build(), "", "  ")
+			fmt.Fprintf(stdout, "%s\n", out)
+		} else {
+			fmt.Fprintln(stdout, version)
+		}
+
 		return 0
 	}
 
+	if *verboseFlag && *quietFlag {
+		printError(stderr, "Error: Can't use both --verbose and --quiet together")
+		return 1
+	}
+
 	var verbose func(text string)
 	if *verboseFlag {
 		verbose = func(text string) {
@@ -188,6 +344,13 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		}
 	}
 
+	// Warnings are printed unless --quiet.
+	warn := func(text string) {
+		if !*quietFlag {
+			printError(stderr, "Warning: "+text)
+		}
+	}
+
 	verbose(version)
 	verbose("")
 
@@ -211,6 +374,11 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		}
 	}
 
+	// The object_tag experiment is now always enabled, and kept as an alias.
+	if experiments["object_tag"] {
+		verbose("rdap: The object_tag experiment is now always enabled (RFC 8521)")
+	}
+
 	// Enable the -e selection of experiments?
 	if *experimentalFlag {
 		verbose("rdap: Enabled -e/--experiments: test_rdap_net")
@@ -223,8 +391,8 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 	}
 
 	// Exactly one argument is required (i.e. the domain/ip/url/etc), unless
-	// we're making a help query.
-	if *queryType != "help" && len(*queryArgs) == 0 {
+	// we're making a help query, or printing/serving the bootstrap files.
+	if *queryType != "help" && !*bootstrapStatusFlag && *bootstrapServeFlag == "" && len(*queryArgs) == 0 {
 		printError(stderr, fmt.Sprintf("Error: %s\n\n%s", "Query object required, e.g. rdap example.cz", usageText))
 		return 1
 	}
@@ -245,15 +413,13 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 	case "domain", "dns":
 		req = NewDomainRequest(queryText)
 	case "autnum", "as", "asn":
-		autnum := strings.ToUpper(queryText)
-		autnum = strings.TrimPrefix(autnum, "AS")
-		result, err := strconv.ParseUint(autnum, 10, 32)
+		result, err := autnum.Parse(queryText)
 
 		if err != nil {
 			printError(stderr, fmt.Sprintf("Invalid ASN '%s'", queryText))
 			return 1
 		}
-		req = NewAutnumRequest(uint32(result))
+		req = NewAutnumRequest(result)
 	case "ip":
 		ip := net.ParseIP(queryText)
 		if ip == nil {
@@ -286,6 +452,14 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		req = NewRequest(NameserverSearchRequest, queryText)
 	case "nameserver-search-by-ip":
 		req = NewRequest(NameserverSearchByNameserverIPRequest, queryText)
+	case "ip-search":
+		req = NewRequest(IPSearchRequest, queryText)
+	case "ip-search-by-handle":
+		req = NewRequest(IPSearchByHandleRequest, queryText)
+	case "autnum-search":
+		req = NewRequest(AutnumSearchRequest, queryText)
+	case "autnum-search-by-handle":
+		req = NewRequest(AutnumSearchByHandleRequest, queryText)
 	default:
 		printError(stderr, fmt.Sprintf("Unknown query type '%s'", *queryType))
 		return 1
@@ -322,8 +496,23 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 
 	bs := &bootstrap.Client{}
 
+	// Database cache?
+	var db *cache.BoltCache
+	if *cacheDBFlag != "" {
+		if options.Sandbox {
+			verbose(fmt.Sprintf("rdap: Ignored --cache-db option (sandbox mode enabled)"))
+		} else {
+			db = cache.NewBoltCache(*cacheDBFlag)
+			bs.Cache = db
+
+			verbose(fmt.Sprintf("rdap: Using database cache (%s)", *cacheDBFlag))
+		}
+	}
+
 	// Custom bootstrap cache type/directory?
-	if *cacheDirFlag == "" {
+	if db != nil {
+		// Already set.
+	} else if *cacheDirFlag == "" {
 		// Disk cache disabled, use memory cache.
 		bs.Cache = cache.NewMemoryCache()
 
@@ -373,6 +562,19 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		verbose(fmt.Sprintf("rdap: Bootstrap URL is default '%s'", bootstrap.DefaultBaseURL))
 	}
 
+	// Bootstrap mirrors?
+	for _, m := range *bootstrapMirrorFlag {
+		mirrorURL, err := url.Parse(m)
+		if err != nil || mirrorURL.Scheme == "" {
+			printError(stderr, fmt.Sprintf("Error: --bs-mirror: invalid URL '%s'", m))
+			return 1
+		}
+
+		bs.Mirrors = append(bs.Mirrors, mirrorURL)
+
+		verbose(fmt.Sprintf("rdap: Bootstrap mirror '%s'", mirrorURL))
+	}
+
 	// Custom bootstrap cache timeout?
 	if bootstrapTimeoutFlag != nil {
 		bs.Cache.SetTimeout(time.Duration(*bootstrapTimeoutFlag) * time.Second)
@@ -380,6 +582,92 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		verbose(fmt.Sprintf("rdap: Bootstrap cache TTL set to %d seconds", *bootstrapTimeoutFlag))
 	}
 
+	// Offline mode?
+	if *offlineFlag {
+		bs.Offline = true
+
+		verbose("rdap: Offline mode enabled")
+	}
+
+	// Stale bootstrap fallback?
+	if *bootstrapMaxStaleFlag > 0 {
+		bs.MaxStaleness = time.Duration(*bootstrapMaxStaleFlag) * time.Second
+
+		verbose(fmt.Sprintf("rdap: Bootstrap max staleness set to %d seconds", *bootstrapMaxStaleFlag))
+	}
+
+	// Local bootstrap files?
+	for _, path := range *bootstrapFileFlag {
+		if options.Sandbox {
+			verbose("rdap: Ignored --bs-file option (sandbox mode enabled)")
+			break
+		}
+
+		registry, ok := bootstrapTypeForFilename(path)
+		if !ok {
+			printError(stderr, fmt.Sprintf("Error: --bs-file: unknown bootstrap file type '%s'", path))
+			return 1
+		}
+
+		if err := bs.LoadFromFile(registry, path); err != nil {
+			printError(stderr, fmt.Sprintf("Error: --bs-file: %s", err))
+			return 1
+		}
+
+		verbose(fmt.Sprintf("rdap: Loaded %s bootstrap file %s", registry, path))
+	}
+
+	// Verify bootstrap downloads?
+	if *bootstrapSHA256Flag != "" {
+		if options.Sandbox {
+			verbose("rdap: Ignored --bs-sha256 option (sandbox mode enabled)")
+		} else {
+			data, err := ioutil.ReadFile(*bootstrapSHA256Flag)
+			if err != nil {
+				printError(stderr, fmt.Sprintf("Error: --bs-sha256: %s", err))
+				return 1
+			}
+
+			manifest, err := bootstrap.ParseSHA256Manifest(data)
+			if err != nil {
+				printError(stderr, fmt.Sprintf("Error: --bs-sha256: %s", err))
+				return 1
+			}
+
+			bs.Verifier = manifest
+
+			verbose(fmt.Sprintf("rdap: Verifying bootstrap downloads against %s", *bootstrapSHA256Flag))
+		}
+	}
+
+	// Bootstrap overrides?
+	for _, o := range *bootstrapOverrideFlag {
+		registry, entry, rdapURL, ok := parseBootstrapOverride(o)
+		if !ok {
+			printError(stderr, fmt.Sprintf("Error: --bs-override must be TYPE:ENTRY=URL, got '%s'", o))
+			return 1
+		}
+
+		if err := bs.AddOverride(registry, entry, rdapURL); err != nil {
+			printError(stderr, fmt.Sprintf("Error: --bs-override: %s", err))
+			return 1
+		}
+
+		verbose(fmt.Sprintf("rdap: Bootstrap override %s %s => %s", registry, entry, rdapURL))
+	}
+
+	// Built-in bootstrap fallback enabled?
+	if *bootstrapEmbeddedFlag {
+		bs.EnableEmbedded = true
+
+		verbose("rdap: Built-in bootstrap files enabled")
+	}
+
+	// Bootstrap status only?
+	if *bootstrapStatusFlag {
+		return runBootstrapStatus(bs, stdout, *outputFormatJSON)
+	}
+
 	var clientCert tls.Certificate
 	if *clientCertFilename != "" || *clientKeyFilename != "" {
 		if *clientP12FilenameAndPassword != "" {
@@ -471,18 +759,174 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		Transport: transport,
 	}
 
+	// Serve the bootstrap files?
+	if *bootstrapServeFlag != "" {
+		if options.Sandbox {
+			printError(stderr, "Error: --bs-serve is not available in sandbox mode")
+			return 1
+		}
+
+		bs.Verbose = verbose
+		verbose(fmt.Sprintf("rdap: Serving bootstrap files on %s", *bootstrapServeFlag))
+
+		err := http.ListenAndServe(*bootstrapServeFlag, &bootstrap.Server{Client: bs})
+		printError(stderr, fmt.Sprintf("Error: --bs-serve: %s", err))
+		return 1
+	}
+
 	client := &Client{
 		HTTP:      httpClient,
 		Bootstrap: bs,
 
-		Verbose:   verbose,
-		UserAgent: version,
+		Verbose: verbose,
+
+		FallbackOnTLSError: *tlsFallbackFlag,
+		FollowRelated:      *relatedFlag,
+		InferRoles:         *inferRolesFlag,
+		Offline:            *offlineFlag,
+		Strict:             *strictFlag,
+	}
+
+	if db != nil && *cacheDBTTLFlag > 0 {
+		client.ResponseStore = db
+		client.ResponseStoreTTL = time.Duration(*cacheDBTTLFlag) * time.Second
+
+		verbose(fmt.Sprintf("rdap: Caching responses for %s", client.ResponseStoreTTL))
+	}
+
+	if *dnsPrecheckFlag {
+		client.DNSPrecheck = &ResolverPrecheck{}
+
+		verbose("rdap: DNS pre-check enabled for domain queries")
+	}
+
+	// Archive responses?
+	if *archiveDirFlag != "" {
+		if options.Sandbox {
+			verbose(fmt.Sprintf("rdap: Ignored --archive-dir option (sandbox mode enabled)"))
+		} else {
+			client.Archive = NewArchive(*archiveDirFlag)
+
+			verbose(fmt.Sprintf("rdap: Archiving responses to %s", *archiveDirFlag))
+		}
+	}
+
+	// Write metrics at the end of the run?
+	if *metricsFileFlag != "" {
+		if options.Sandbox {
+			verbose(fmt.Sprintf("rdap: Ignored --metrics-file option (sandbox mode enabled)"))
+		} else {
+			metrics := newCLIMetrics()
+			client.Metrics = metrics
+
+			defer func() {
+				var out bytes.Buffer
+				if _, err := metrics.WriteTo(&out); err != nil {
+					printError(stderr, fmt.Sprintf("Error writing metrics: %s", err))
+				} else if err := ioutil.WriteFile(*metricsFileFlag, out.Bytes(), 0644); err != nil {
+					printError(stderr, fmt.Sprintf("Error writing metrics file: %s", err))
+				} else {
+					verbose(fmt.Sprintf("rdap: Wrote metrics to %s", *metricsFileFlag))
+				}
+			}()
+		}
+	}
+
+	// Write a run manifest at the end of the run?
+	var manifest *cliManifest
+	if *manifestFlag != "" {
+		if options.Sandbox {
+			verbose(fmt.Sprintf("rdap: Ignored --manifest option (sandbox mode enabled)"))
+		} else {
+			manifest = newCLIManifest(args, start)
+			manifest.SetInput(strings.Join(*queryArgs, " "))
+			stdout = manifest.Output(stdout)
+
+			defer func() {
+				var out bytes.Buffer
+				manifest.WriteTo(&out, exitCode)
+
+				if err := ioutil.WriteFile(*manifestFlag, out.Bytes(), 0644); err != nil {
+					printError(stderr, fmt.Sprintf("Error writing manifest file: %s", err))
+				} else {
+					verbose(fmt.Sprintf("rdap: Wrote run manifest to %s", *manifestFlag))
+				}
+			}()
+		}
+	}
+
+	// Per-host query parameters?
+	for _, hp := range *hostParamFlag {
+		host, key, value, ok := parseHostParam(hp)
+		if !ok {
+			printError(stderr, fmt.Sprintf("Error: --host-param must be HOST:KEY=VALUE, got '%s'", hp))
+			return 1
+		}
+
+		if client.HostParams == nil {
+			client.HostParams = map[string]url.Values{}
+		}
+		if client.HostParams[host] == nil {
+			client.HostParams[host] = url.Values{}
+		}
+		client.HostParams[host].Add(key, value)
+
+		verbose(fmt.Sprintf("rdap: Adding query parameter '%s' for %s", key, host))
+	}
+
+	// Separate bootstrap download timeout?
+	if *bootstrapDownloadTimeoutFlag > 0 {
+		client.BootstrapTimeout = time.Duration(*bootstrapDownloadTimeoutFlag) * time.Second
+
+		verbose(fmt.Sprintf("rdap: Bootstrap timeout is %d seconds", *bootstrapDownloadTimeoutFlag))
+	}
+
+	// Separate RDAP server query timeout?
+	if *queryTimeoutFlag > 0 {
+		client.QueryTimeout = time.Duration(*queryTimeoutFlag) * time.Second
+
+		verbose(fmt.Sprintf("rdap: Query timeout is %d seconds", *queryTimeoutFlag))
 	}
 
 	if *insecureFlag {
 		verbose(fmt.Sprintf("rdap: SSL certificate validation disabled"))
 	}
 
+	// Preferred response languages?
+	if len(*langFlag) > 0 {
+		req.Languages = *langFlag
+
+		verbose(fmt.Sprintf("rdap: Preferred languages %v", req.Languages))
+	}
+
+	// Query metadata?
+	if len(*tagFlag) > 0 {
+		req.Tags = *tagFlag
+
+		verbose(fmt.Sprintf("rdap: Tags %v", req.Tags))
+	}
+
+	// Search result sorting and paging?
+	req.Sort = *sortFlag
+	req.Count = *countFlag
+	req.Cursor = *cursorFlag
+	req.FieldSet = *fieldSetFlag
+
+	// Additional contact information fetches?
+	if len(*fetchRolesFlag) > 0 {
+		req.FetchRoles = *fetchRolesFlag
+
+		verbose(fmt.Sprintf("rdap: Fetching contact roles %v", req.FetchRoles))
+	}
+
+	// Check the --extract path before running the query.
+	if *extractFlag != "" {
+		if _, err := parseJSONPath(*extractFlag); err != nil {
+			printError(stderr, fmt.Sprintf("Error: --extract: %s", err))
+			return 1
+		}
+	}
+
 	// Set the request timeout.
 	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Duration(*timeoutFlag)*time.Second)
 	defer cancelFunc()
@@ -490,13 +934,32 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 
 	verbose(fmt.Sprintf("rdap: Timeout is %d seconds", *timeoutFlag))
 
+	// Bootstrap lookup only?
+	if *lookupOnlyFlag {
+		return runLookupOnly(client, req, stdout, stderr, *outputFormatJSON)
+	}
+
 	// Run the request.
 	var resp *Response
 	resp, err = client.Do(req)
 
+	if manifest != nil && resp != nil {
+		manifest.AddResponse(resp)
+	}
+
 	verbose("")
 	verbose(fmt.Sprintf("rdap: Finished in %s", time.Since(start)))
 
+	if resp != nil && resp.Object != nil {
+		verbose(fmt.Sprintf("rdap: Fetched %s", resp.Stats()))
+	}
+
+	if resp != nil {
+		for _, w := range resp.Warnings {
+			warn(w)
+		}
+	}
+
 	if err != nil {
 		printError(stderr, fmt.Sprintf("Error: %s", err))
 		return 1
@@ -507,6 +970,41 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		fmt.Fprintln(stderr, "")
 	}
 
+	// Extract values only?
+	if *extractFlag != "" {
+		values, err := extractJSONPath(resp.objectBody(), *extractFlag)
+		if err != nil {
+			printError(stderr, fmt.Sprintf("Error: --extract: %s", err))
+			return 1
+		}
+
+		for _, v := range values {
+			fmt.Fprintln(stdout, formatExtracted(v))
+		}
+
+		return 0
+	}
+
+	// Output the relationship graph only?
+	if *graphFlag != "" {
+		g := NewGraph()
+		for r := resp; r != nil; r = r.Related {
+			g.Add(r.Object)
+		}
+
+		write := g.WriteDOT
+		if *graphFlag == "json" {
+			write = g.WriteJSON
+		}
+
+		if err := write(stdout); err != nil {
+			printError(stderr, fmt.Sprintf("Error: %s", err))
+			return 1
+		}
+
+		return 0
+	}
+
 	// Output formatting.
 	if !(*outputFormatText || *outputFormatWhois || *outputFormatJSON || *outputFormatRaw) {
 		*outputFormatText = true
@@ -518,8 +1016,27 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 			Writer: stdout,
 
 			BriefLinks: true,
+			Languages:  *langFlag,
+		}
+		if err := printer.SafePrint(resp.Object); err != nil {
+			printError(stderr, fmt.Sprintf("Error: %s", err))
+
+			var panicErr *PanicError
+			if errors.As(err, &panicErr) {
+				verbose(fmt.Sprintf("rdap: Stack trace:\n%s", panicErr.Stack))
+			}
+
+			return 1
+		}
+
+		if resp.Related != nil {
+			fmt.Fprintf(stdout, "\n# Related response from %s\n\n", resp.Related.HTTP[0].URL)
+
+			if err := printer.SafePrint(resp.Related.Object); err != nil {
+				printError(stderr, fmt.Sprintf("Error: %s", err))
+				return 1
+			}
 		}
-		printer.Print(resp.Object)
 	}
 
 	// Print the raw response out?
@@ -529,8 +1046,26 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 
 	// Print the response, JSON pretty-printed?
 	if *outputFormatJSON {
+		body := resp.HTTP[0].Body
+
+		keyStyle := map[string]KeyStyle{
+			"as-is": KeyStyleAsIs,
+			"snake": KeyStyleSnakeCase,
+			"camel": KeyStyleCamelCase,
+		}[*jsonKeysFlag]
+
+		if keyStyle != KeyStyleAsIs {
+			var err error
+			if body, err = ConvertJSONKeys(body, keyStyle); err != nil {
+				printError(stderr, fmt.Sprintf("Error: --json-keys: %s", err))
+				return 1
+			}
+
+			verbose(fmt.Sprintf("rdap: Converted JSON keys to %s case", *jsonKeysFlag))
+		}
+
 		var out bytes.Buffer
-		json.Indent(&out, resp.HTTP[0].Body, "", "  ")
+		json.Indent(&out, body, "", "  ")
 		out.WriteTo(stdout)
 	}
 
@@ -545,11 +1080,210 @@ func RunCLI(args []string, stdout io.Writer, stderr io.Writer, options CLIOption
 		}
 	}
 
-	_ = fetchRolesFlag
+	return 0
+}
+
+// parseHostParam parses a --host-param value, HOST:KEY=VALUE.
+func parseHostParam(hp string) (host string, key string, value string, ok bool) {
+	hostKey, value, ok := strings.Cut(hp, "=")
+	if !ok {
+		return "", "", "", false
+	}
+
+	i := strings.LastIndex(hostKey, ":")
+	if i <= 0 || i == len(hostKey)-1 {
+		return "", "", "", false
+	}
+
+	return strings.ToLower(hostKey[:i]), hostKey[i+1:], value, true
+}
+
+// runLookupOnly prints the RDAP service URLs for |req|, as determined by
+// bootstrapping, without running the query.
+//
+// Returns the program exit code.
+func runLookupOnly(client *Client, req *Request, stdout io.Writer, stderr io.Writer, jsonOutput bool) int {
+	type lookupResult struct {
+		Query       string            `json:"query"`
+		Registry    string            `json:"registry,omitempty"`
+		Description string            `json:"description,omitempty"`
+		Publication string            `json:"publication,omitempty"`
+		Version     string            `json:"version,omitempty"`
+		Entry       string            `json:"entry,omitempty"`
+		URLs        []string          `json:"urls"`
+		Tags        map[string]string `json:"tags,omitempty"`
+	}
+
+	result := lookupResult{
+		Query: req.Query,
+		URLs:  []string{},
+		Tags:  req.Tags,
+	}
+
+	if req.Server != nil {
+		// The server is already known (--server, or a full RDAP URL).
+		result.URLs = append(result.URLs, req.Server.String())
+	} else {
+		registry := bootstrapTypeFor(req)
+		if registry == nil {
+			printError(stderr, fmt.Sprintf("Error: query type '%s' cannot be bootstrapped", req.Type))
+			return 1
+		}
+
+		client.Bootstrap.Verbose = client.Verbose
+
+		question := &bootstrap.Question{
+			RegistryType: *registry,
+			Query:        req.Query,
+		}
+		question = question.WithContext(req.Context())
+
+		answer, err := client.Bootstrap.Lookup(question)
+		if err != nil {
+			printError(stderr, fmt.Sprintf("Error: %s", err))
+			return 1
+		}
+
+		result.Registry = registry.String()
+		result.Description = answer.Description
+		result.Publication = answer.Publication
+		result.Version = answer.Version
+		result.Entry = answer.Entry
+
+		for _, u := range answer.URLs {
+			result.URLs = append(result.URLs, u.String())
+		}
+	}
+
+	if jsonOutput {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Fprintf(stdout, "%s\n", out)
+	} else {
+		for _, u := range result.URLs {
+			fmt.Fprintln(stdout, u)
+		}
+	}
+
+	if len(result.URLs) == 0 {
+		printError(stderr, fmt.Sprintf("Error: No RDAP servers found for '%s'", req.Query))
+		return 1
+	}
 
 	return 0
 }
 
+// runBootstrapStatus prints the status of each bootstrap file in |bs|, e.g.
+// to see why a lookup used stale data. Nothing is downloaded.
+//
+// Returns the program exit code.
+func runBootstrapStatus(bs *bootstrap.Client, stdout io.Writer, jsonOutput bool) int {
+	type registryStatus struct {
+		Registry    string  `json:"registry"`
+		Available   bool    `json:"available"`
+		Fresh       bool    `json:"fresh"`
+		Cached      bool    `json:"cached"`
+		Embedded    bool    `json:"embedded"`
+		Loaded      bool    `json:"loaded"`
+		ModTime     string  `json:"mod_time,omitempty"`
+		AgeSeconds  float64 `json:"age_seconds,omitempty"`
+		Size        int     `json:"size"`
+		Entries     int     `json:"entries"`
+		Publication string  `json:"publication,omitempty"`
+		Version     string  `json:"version,omitempty"`
+		SourceURL   string  `json:"source_url"`
+	}
+
+	var results []registryStatus
+	for _, s := range bs.Statuses() {
+		r := registryStatus{
+			Registry:    s.Registry.String(),
+			Available:   s.Available,
+			Fresh:       s.Fresh,
+			Cached:      s.Cached,
+			Embedded:    s.Embedded,
+			Loaded:      s.Loaded,
+			AgeSeconds:  s.Age.Round(time.Second).Seconds(),
+			Size:        s.Size,
+			Entries:     s.Entries,
+			Publication: s.Publication,
+			Version:     s.Version,
+			SourceURL:   s.SourceURL,
+		}
+
+		if !s.ModTime.IsZero() {
+			r.ModTime = s.ModTime.UTC().Format(time.RFC3339)
+		}
+
+		results = append(results, r)
+	}
+
+	if jsonOutput {
+		out, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Fprintf(stdout, "%s\n", out)
+		return 0
+	}
+
+	for _, r := range results {
+		var state string
+		switch {
+		case r.Loaded:
+			state = "loaded"
+		case r.Cached && r.Fresh:
+			state = "cached"
+		case r.Cached:
+			state = "stale"
+		case r.Embedded:
+			state = "embedded"
+		default:
+			state = "absent"
+		}
+
+		age := "-"
+		if r.ModTime != "" {
+			age = (time.Duration(r.AgeSeconds) * time.Second).String()
+		}
+
+		fmt.Fprintf(stdout, "%-15s %-8s age=%-10s size=%-8d entries=%-6d %s\n",
+			r.Registry, state, age, r.Size, r.Entries, r.SourceURL)
+	}
+
+	return 0
+}
+
+// bootstrapTypeForFilename returns the bootstrap registry type of the file
+// |path|, based on its name, e.g. "/mirror/dns.json".
+func bootstrapTypeForFilename(path string) (bootstrap.RegistryType, bool) {
+	for _, r := range []bootstrap.RegistryType{bootstrap.ASN, bootstrap.DNS, bootstrap.IPv4, bootstrap.IPv6, bootstrap.ServiceProvider} {
+		if strings.HasSuffix(path, r.Filename()) {
+			return r, true
+		}
+	}
+
+	return 0, false
+}
+
+// parseBootstrapOverride parses a --bs-override value |o|, in the format
+// TYPE:ENTRY=URL.
+func parseBootstrapOverride(o string) (registry bootstrap.RegistryType, entry string, rdapURL string, ok bool) {
+	typeName, rest, found := strings.Cut(o, ":")
+	if !found {
+		return 0, "", "", false
+	}
+
+	entry, rdapURL, found = strings.Cut(rest, "=")
+	if !found || entry == "" || rdapURL == "" {
+		return 0, "", "", false
+	}
+
+	for _, r := range []bootstrap.RegistryType{bootstrap.ASN, bootstrap.DNS, bootstrap.IPv4, bootstrap.IPv6, bootstrap.ServiceProvider} {
+		if strings.EqualFold(typeName, r.String()) {
+			return r, entry, rdapURL, true
+		}
+	}
+
+	return 0, "", "", false
+}
+
 func safePrint(v string) string {
 	removeBadChars := func(r rune) rune {
 		switch {
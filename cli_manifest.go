@@ -0,0 +1,154 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"strings"
+	"time"
+)
+
+// cliManifest is the run manifest written by the --manifest option.
+//
+// It records what was run, when, and digests of the output, so results can
+// be reproduced and audited (e.g. for research datasets).
+type cliManifest struct {
+	RunID        string                `json:"run_id"`
+	Version      string                `json:"version"`
+	StartTime    time.Time             `json:"start_time"`
+	EndTime      time.Time             `json:"end_time"`
+	Args         []string              `json:"args"`
+	InputSHA256  string                `json:"input_sha256"`
+	ExitCode     int                   `json:"exit_code"`
+	OutputSHA256 string                `json:"output_sha256"`
+	Responses    []cliManifestResponse `json:"responses,omitempty"`
+
+	// True if the RDAP query was skipped by --dns-precheck.
+	DNSPrecheckSkipped bool `json:"dns_precheck_skipped,omitempty"`
+
+	output hash.Hash
+}
+
+// cliManifestResponse records a single RDAP response received during the run.
+type cliManifestResponse struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	BodySHA256 string `json:"body_sha256"`
+}
+
+// newCLIManifest creates a cliManifest for a run started at |start| with the
+// command line arguments |args|.
+//
+// Secrets (--host-param values, --p12 passwords) are redacted from |args|.
+func newCLIManifest(args []string, start time.Time) *cliManifest {
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return &cliManifest{
+		RunID:     hex.EncodeToString(id),
+		Version:   version,
+		StartTime: start.UTC(),
+		Args:      redactCLIArgs(args),
+		output:    sha256.New(),
+	}
+}
+
+// Output wraps |w| so everything written to it is included in the manifest's
+// output digest.
+func (m *cliManifest) Output(w io.Writer) io.Writer {
+	return io.MultiWriter(w, m.output)
+}
+
+// SetInput records the digest of the query input |input|.
+func (m *cliManifest) SetInput(input string) {
+	sum := sha256.Sum256([]byte(input))
+	m.InputSHA256 = hex.EncodeToString(sum[:])
+}
+
+// AddResponse records the HTTP responses of the RDAP Response |resp|,
+// including any Related response.
+func (m *cliManifest) AddResponse(resp *Response) {
+	if resp != nil && resp.DNSPrecheckSkipped {
+		m.DNSPrecheckSkipped = true
+	}
+
+	for ; resp != nil; resp = resp.Related {
+		for _, h := range resp.HTTP {
+			if h.Response == nil {
+				continue
+			}
+
+			sum := sha256.Sum256(h.Body)
+			m.Responses = append(m.Responses, cliManifestResponse{
+				URL:        h.URL,
+				StatusCode: h.Response.StatusCode,
+				BodySHA256: hex.EncodeToString(sum[:]),
+			})
+		}
+	}
+}
+
+// WriteTo finishes the manifest with the run's |exitCode|, and writes it to
+// |w| as JSON.
+func (m *cliManifest) WriteTo(w io.Writer, exitCode int) error {
+	m.EndTime = time.Now().UTC()
+	m.ExitCode = exitCode
+	m.OutputSHA256 = hex.EncodeToString(m.output.Sum(nil))
+
+	out, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(out, '\n'))
+
+	return err
+}
+
+// redactCLIArgs returns a copy of |args| with secret option values replaced.
+func redactCLIArgs(args []string) []string {
+	result := make([]string, len(args))
+
+	redactNext := ""
+	for i, arg := range args {
+		switch {
+		case redactNext != "":
+			arg = redactCLIValue(redactNext, arg)
+			redactNext = ""
+		case arg == "--host-param" || arg == "--p12" || arg == "-P":
+			redactNext = arg
+		case strings.HasPrefix(arg, "--host-param="):
+			arg = "--host-param=" + redactCLIValue("--host-param", strings.TrimPrefix(arg, "--host-param="))
+		case strings.HasPrefix(arg, "--p12="):
+			arg = "--p12=" + redactCLIValue("--p12", strings.TrimPrefix(arg, "--p12="))
+		}
+
+		result[i] = arg
+	}
+
+	return result
+}
+
+// redactCLIValue redacts the secret part of the value |v| of the option
+// |flag|.
+func redactCLIValue(flag string, v string) string {
+	switch flag {
+	case "--host-param":
+		if i := strings.Index(v, "="); i != -1 {
+			return v[:i+1] + "REDACTED"
+		}
+	default:
+		if i := strings.Index(v, ":"); i != -1 {
+			return v[:i+1] + "REDACTED"
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,132 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+)
+
+// cliMetrics implements Metrics for the --metrics-file option.
+//
+// It records counters and total durations, which are written in the
+// OpenMetrics text format (https://openmetrics.io) by WriteTo().
+type cliMetrics struct {
+	mu sync.Mutex
+
+	queries   map[string]*cliMetric
+	http      map[string]*cliMetric
+	bootstrap map[string]*cliMetric
+}
+
+// cliMetric is a count of events and their total duration, with a set of
+// labels.
+type cliMetric struct {
+	labels   string
+	count    uint64
+	duration time.Duration
+}
+
+func newCLIMetrics() *cliMetrics {
+	return &cliMetrics{
+		queries:   map[string]*cliMetric{},
+		http:      map[string]*cliMetric{},
+		bootstrap: map[string]*cliMetric{},
+	}
+}
+
+func (m *cliMetrics) ObserveQuery(requestType RequestType, status string, duration time.Duration) {
+	m.observe(m.queries, duration, "type", requestType.String(), "status", status)
+}
+
+func (m *cliMetrics) ObserveHTTP(server string, statusCode int, duration time.Duration) {
+	m.observe(m.http, duration, "server", server, "code", strconv.Itoa(statusCode))
+}
+
+func (m *cliMetrics) ObserveBootstrap(registry bootstrap.RegistryType, cacheHit bool, duration time.Duration) {
+	m.observe(m.bootstrap, duration, "registry", registry.String(), "cache_hit", strconv.FormatBool(cacheHit))
+}
+
+// observe records an event of |duration| in |metrics|, with the label
+// names/values |labels|.
+func (m *cliMetrics) observe(metrics map[string]*cliMetric, duration time.Duration, labels ...string) {
+	var pairs []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], escapeLabelValue(labels[i+1])))
+	}
+	key := strings.Join(pairs, ",")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metric, ok := metrics[key]
+	if !ok {
+		metric = &cliMetric{labels: key}
+		metrics[key] = metric
+	}
+
+	metric.count++
+	metric.duration += duration
+}
+
+// WriteTo writes the metrics in OpenMetrics text format to |w|.
+func (m *cliMetrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+
+	writeFamily := func(name string, help string, metrics map[string]*cliMetric) {
+		// The counter family is |name|, its samples |name|_total.
+		fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		fmt.Fprintf(&b, "# HELP %s %s.\n", name, help)
+		for _, metric := range sortedCLIMetrics(metrics) {
+			fmt.Fprintf(&b, "%s_total{%s} %d\n", name, metric.labels, metric.count)
+		}
+
+		fmt.Fprintf(&b, "# TYPE %s_duration_seconds summary\n", name)
+		fmt.Fprintf(&b, "# UNIT %s_duration_seconds seconds\n", name)
+		fmt.Fprintf(&b, "# HELP %s_duration_seconds %s duration.\n", name, help)
+		for _, metric := range sortedCLIMetrics(metrics) {
+			fmt.Fprintf(&b, "%s_duration_seconds_sum{%s} %g\n", name, metric.labels, metric.duration.Seconds())
+			fmt.Fprintf(&b, "%s_duration_seconds_count{%s} %d\n", name, metric.labels, metric.count)
+		}
+	}
+
+	writeFamily("rdap_queries", "RDAP queries", m.queries)
+	writeFamily("rdap_http_requests", "HTTP requests to RDAP servers", m.http)
+	writeFamily("rdap_bootstrap_lookups", "Bootstrap lookups", m.bootstrap)
+	b.WriteString("# EOF\n")
+
+	n, err := io.WriteString(w, b.String())
+
+	return int64(n), err
+}
+
+// escapeLabelValue escapes the label value |value| as per the OpenMetrics
+// text format, which allows only \\, \", and \n escapes.
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func sortedCLIMetrics(metrics map[string]*cliMetric) []*cliMetric {
+	var result []*cliMetric
+	for _, metric := range metrics {
+		result = append(result, metric)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].labels < result[j].labels
+	})
+
+	return result
+}
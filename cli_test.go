@@ -0,0 +1,403 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func runCLITest(args ...string) (int, string, string) {
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+
+	exitCode := RunCLI(args, &stdout, &stderr, CLIOptions{})
+
+	return exitCode, stdout.String(), stderr.String()
+}
+
+// newCLITestServer returns a HTTP server serving the test/testdata/ files
+// |files| (map of URL path => filename).
+//
+// The CLI uses its own http.Transport, so httpmock can't be used.
+func newCLITestServer(files map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filename, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Write(test.LoadFile(filename))
+	}))
+}
+
+func TestCLILookupOnly(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/dns.json": "bootstrap/dns.json",
+	})
+	defer server.Close()
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--bs-url="+server.URL, "--lookup-only", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if strings.TrimSpace(stdout) != "https://rdap.nic.cz" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+
+	exitCode, stdout, stderr = runCLITest("--cache-dir=", "--bs-url="+server.URL, "--lookup-only", "--json", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if !strings.Contains(stdout, `"registry": "dns"`) || !strings.Contains(stdout, `"publication": "2017-03-15T21:26:24Z"`) || !strings.Contains(stdout, `"https://rdap.nic.cz"`) {
+		t.Errorf("Unexpected JSON output %q", stdout)
+	}
+}
+
+func TestCLIMetricsFile(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	metricsFile := filepath.Join(t.TempDir(), "rdap.prom")
+
+	exitCode, _, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--metrics-file="+metricsFile, "example.cz")
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	}
+
+	metrics, err := ioutil.ReadFile(metricsFile)
+	if err != nil {
+		t.Fatalf("Metrics file not written: %s", err)
+	}
+
+	for _, expected := range []string{
+		"# TYPE rdap_queries counter\n",
+		`rdap_queries_total{type="domain",status="success"} 1`,
+		`rdap_http_requests_total{server="` + strings.TrimPrefix(server.URL, "http://") + `",code="200"} 1`,
+		"# EOF\n",
+	} {
+		if !strings.Contains(string(metrics), expected) {
+			t.Errorf("Metrics file missing %q:\n%s", expected, metrics)
+		}
+	}
+}
+
+func TestCLIMetricsEscaping(t *testing.T) {
+	m := newCLIMetrics()
+	m.ObserveHTTP("bücher.example\\\"\n", 200, time.Second)
+
+	var out bytes.Buffer
+	if _, err := m.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `rdap_http_requests_total{server="bücher.example\\\"\n",code="200"} 1`
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Metrics missing %q:\n%s", expected, out.String())
+	}
+}
+
+func TestCLIManifest(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--manifest="+manifestFile,
+		"--host-param=127.0.0.1:apikey=secret", "example.cz")
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	}
+
+	data, err := ioutil.ReadFile(manifestFile)
+	if err != nil {
+		t.Fatalf("Manifest file not written: %s", err)
+	}
+
+	var manifest cliManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Bad manifest: %s", err)
+	}
+
+	outputSum := sha256.Sum256([]byte(stdout))
+	inputSum := sha256.Sum256([]byte("example.cz"))
+
+	if manifest.RunID == "" || manifest.ExitCode != 0 || manifest.EndTime.Before(manifest.StartTime) {
+		t.Errorf("Unexpected manifest %s", data)
+	} else if manifest.OutputSHA256 != hex.EncodeToString(outputSum[:]) {
+		t.Errorf("Manifest output digest mismatch")
+	} else if manifest.InputSHA256 != hex.EncodeToString(inputSum[:]) {
+		t.Errorf("Manifest input digest mismatch")
+	} else if len(manifest.Responses) != 1 || manifest.Responses[0].StatusCode != 200 {
+		t.Errorf("Unexpected manifest responses %+v", manifest.Responses)
+	}
+
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Manifest contains a secret:\n%s", data)
+	}
+}
+
+func TestCLIExtract(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--extract=.ldhName", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if stdout != "example.cz\n" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
+
+func TestCLIGraph(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--graph=dot", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if !strings.Contains(stdout, `"domain:example.cz" -> "nameserver:ns.pipni.cz" [label="nameserver"];`) {
+		t.Errorf("Unexpected output %s", stdout)
+	}
+
+	exitCode, _, _ = runCLITest("--cache-dir=", "--server="+server.URL, "--graph=svg", "example.cz")
+	if exitCode != 1 {
+		t.Errorf("Expected --graph=svg to fail, got exit code %d", exitCode)
+	}
+}
+
+func TestCLIOutputStreams(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/dns.json":          "bootstrap/dns.json",
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	body := string(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json"))
+
+	tests := []struct {
+		Args  []string
+		Check func(stdout string) bool
+	}{
+		{[]string{"--json"}, func(stdout string) bool { return json.Valid([]byte(stdout)) }},
+		{[]string{"--raw"}, func(stdout string) bool { return stdout == body }},
+		{[]string{"--graph=json"}, func(stdout string) bool { return json.Valid([]byte(stdout)) }},
+		{[]string{"--extract=.ldhName"}, func(stdout string) bool { return stdout == "example.cz\n" }},
+	}
+
+	for _, test := range tests {
+		args := append([]string{"--cache-dir=", "--server=" + server.URL, "--verbose"}, test.Args...)
+		args = append(args, "example.cz")
+
+		exitCode, stdout, stderr := runCLITest(args...)
+
+		if exitCode != 0 {
+			t.Errorf("%v: unexpected exit code %d, stderr=%s", test.Args, exitCode, stderr)
+		} else if !test.Check(stdout) {
+			t.Errorf("%v: stdout contains more than the output: %q", test.Args, stdout)
+		} else if !strings.Contains(stderr, "# rdap: Finished in") {
+			t.Errorf("%v: verbose messages not on stderr: %q", test.Args, stderr)
+		}
+	}
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--bs-url="+server.URL, "--verbose", "--lookup-only", "--json", "example.cz")
+	if exitCode != 0 {
+		t.Errorf("--lookup-only: unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if !json.Valid([]byte(stdout)) {
+		t.Errorf("--lookup-only: stdout contains more than the output: %q", stdout)
+	}
+}
+
+func TestCLIQuiet(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--quiet", "--extract=.ldhName", "example.cz")
+	if exitCode != 0 || stdout != "example.cz\n" || stderr != "" {
+		t.Errorf("Unexpected result %d %q %q", exitCode, stdout, stderr)
+	}
+
+	// Errors are still printed.
+	exitCode, stdout, stderr = runCLITest("--cache-dir=", "--server="+server.URL, "--quiet", "non-existent.cz")
+	if exitCode != 1 || stdout != "" || !strings.Contains(stderr, "Error:") {
+		t.Errorf("Unexpected result %d %q %q", exitCode, stdout, stderr)
+	}
+
+	exitCode, _, _ = runCLITest("--quiet", "--verbose", "example.cz")
+	if exitCode != 1 {
+		t.Errorf("Expected --quiet --verbose to fail, got exit code %d", exitCode)
+	}
+}
+
+func TestCLICacheDB(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+
+	dbFile := filepath.Join(t.TempDir(), "rdap.db")
+	args := []string{"--cache-db=" + dbFile, "--cache-db-ttl=60", "--server=" + server.URL, "--extract=.ldhName", "example.cz"}
+
+	exitCode, stdout, stderr := runCLITest(args...)
+	if exitCode != 0 || stdout != "example.cz\n" {
+		t.Fatalf("Unexpected result %d %q, stderr=%s", exitCode, stdout, stderr)
+	}
+
+	// The second run is answered from the database.
+	server.Close()
+
+	exitCode, stdout, stderr = runCLITest(args...)
+	if exitCode != 0 || stdout != "example.cz\n" {
+		t.Fatalf("Unexpected result %d %q, stderr=%s", exitCode, stdout, stderr)
+	}
+}
+
+func TestParseHostParam(t *testing.T) {
+	host, key, value, ok := parseHostParam("RDAP.example.net:apikey=a=b")
+	if !ok || host != "rdap.example.net" || key != "apikey" || value != "a=b" {
+		t.Errorf("Unexpected result %s %s %s %v", host, key, value, ok)
+	}
+
+	for _, bad := range []string{"apikey=x", ":apikey=x", "rdap.example.net:=x", "rdap.example.net:apikey"} {
+		if _, _, _, ok := parseHostParam(bad); ok {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestCLIBootstrapFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "dns.json")
+	if err := ioutil.WriteFile(path, test.LoadFile("bootstrap/dns.json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The bootstrap URL is unreachable, so the local file must be used.
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--bs-url=http://127.0.0.1:1", "--bs-file="+path, "--lookup-only", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if strings.TrimSpace(stdout) != "https://rdap.nic.cz" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+
+	exitCode, _, stderr = runCLITest("--bs-file="+filepath.Join(dir, "unknown.json"), "example.cz")
+	if exitCode != 1 || !strings.Contains(stderr, "unknown bootstrap file type") {
+		t.Errorf("Expected error for unknown file type, got %d %q", exitCode, stderr)
+	}
+}
+
+func TestCLIBootstrapOverride(t *testing.T) {
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--bs-url=http://127.0.0.1:1",
+		"--bs-override=dns:internal.corp=https://rdap.corp.example/", "--lookup-only", "host.internal.corp")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if strings.TrimSpace(stdout) != "https://rdap.corp.example/" {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+
+	for _, bad := range []string{"internal.corp=https://rdap.corp.example/", "dns:internal.corp", "xyz:a=https://b/"} {
+		if _, _, _, ok := parseBootstrapOverride(bad); ok {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestCLIBootstrapStatus(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/dns.json": "bootstrap/dns.json",
+	})
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	exitCode, _, stderr := runCLITest("--cache-dir="+dir, "--bs-url="+server.URL, "--lookup-only", "example.cz")
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	}
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir="+dir, "--bs-url="+server.URL, "--bs-status")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %q", stdout)
+	}
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "dns ") {
+			if !strings.Contains(line, "cached") || !strings.Contains(line, server.URL+"/dns.json") {
+				t.Errorf("Unexpected dns status %q", line)
+			}
+		} else if !strings.Contains(line, "absent") {
+			t.Errorf("Unexpected status %q", line)
+		}
+	}
+
+	exitCode, stdout, stderr = runCLITest("--cache-dir="+dir, "--bs-url="+server.URL, "--bs-status", "--json")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if !strings.Contains(stdout, `"registry": "dns"`) || !strings.Contains(stdout, `"cached": true`) {
+		t.Errorf("Unexpected JSON output %q", stdout)
+	}
+}
+
+func TestCLIJSONKeys(t *testing.T) {
+	server := newCLITestServer(map[string]string{
+		"/domain/example.cz": "rdap/rdap.nic.cz/domain-example.cz.json",
+	})
+	defer server.Close()
+
+	exitCode, stdout, stderr := runCLITest("--cache-dir=", "--server="+server.URL, "--json", "--json-keys=snake", "example.cz")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d, stderr=%s", exitCode, stderr)
+	} else if !strings.Contains(stdout, `"object_class_name": "domain"`) || strings.Contains(stdout, `"objectClassName"`) {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+
+	exitCode, _, _ = runCLITest("--cache-dir=", "--server="+server.URL, "--json", "--json-keys=kebab", "example.cz")
+	if exitCode != 1 {
+		t.Errorf("Expected exit code 1 for an unknown key style, got %d", exitCode)
+	}
+}
+
+func TestCLIVersionJSON(t *testing.T) {
+	exitCode, stdout, _ := runCLITest("--version", "--json")
+
+	if exitCode != 0 {
+		t.Fatalf("Unexpected exit code %d", exitCode)
+	} else if !strings.Contains(stdout, `"version": "`+Version()+`"`) || !strings.Contains(stdout, `"go_version"`) {
+		t.Errorf("Unexpected output %q", stdout)
+	}
+}
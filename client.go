@@ -6,13 +6,19 @@ package rdap
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/internal/httpbody"
 )
 
 // Client implements an RDAP client.
@@ -77,16 +83,250 @@ type Client struct {
 	Bootstrap *bootstrap.Client
 
 	// Optional callback function for verbose messages.
+	//
+	// For structured log events, use Logger instead.
 	Verbose func(text string)
 
+	// Optional structured logger.
+	Logger Logger
+
+	// FallbackOnTLSError enables trying the next RDAP server URL (if any)
+	// after a TLS certificate verification failure.
+	//
+	// By default, a certificate failure stops the request with a
+	// TLSCertificateError. When enabled, a warning is recorded in
+	// Response.Warnings instead.
+	FallbackOnTLSError bool
+
+	// FollowRelated enables following the rel="related" links of Domain
+	// responses.
+	//
+	// gTLD registry responses typically link to the sponsoring registrar's
+	// RDAP server, which often holds the actual contact data. The registrar's
+	// Domain response is stored in Response.Related.
+	FollowRelated bool
+
+	// InferRoles enables guessing the roles of entities which have none,
+	// using heuristics such as the vCard kind, remark titles, and position in
+	// the response. See InferRoles().
+	//
+	// Inferred roles are used by FetchRoles and FollowRelated, and are
+	// marked, see Entity.RolesInferred().
+	InferRoles bool
+
+	// Strict enables checking that each response actually answers the
+	// Request: the object's class must match the Request type, and its
+	// identity (e.g. ldhName, handle, or address range) the query.
+	//
+	// Mismatched responses are rejected with a WrongResponseType
+	// *ClientError describing the mismatch.
+	Strict bool
+
+	// LenientDecoding enables decoding responses from sloppy servers which
+	// would otherwise fail to decode, see LenientDecoding().
+	//
+	// The decoding problems are recorded in Response.Warnings.
+	LenientDecoding bool
+
+	// InferObjectClass enables decoding responses with a missing or unusual
+	// objectClassName, see InferObjectClass().
+	InferObjectClass bool
+
+	// Resource limits for responses, e.g. DefaultDecoderLimits, see
+	// LimitDecoding(). The default is no limits.
+	//
+	// Responses larger than DecoderLimits.MaxBodySize are abandoned without
+	// being read in full.
+	DecoderLimits DecoderLimits
+
+	// Offline forbids network access. Only ObjectCache hits are returned,
+	// other Requests fail with an OfflineError.
+	//
+	// The Client's bootstrap lookups are made offline too (see
+	// bootstrap.Question.Offline), so use only the bootstrap Cache. The
+	// Bootstrap client itself isn't modified.
+	Offline bool
+
+	// Default list of supported RDAP extensions, for Requests which don't
+	// specify any Extensions.
+	Extensions []string
+
+	// Default list of preferred response languages, for Requests which don't
+	// specify any Languages. e.g. []string{"ja", "en"}.
+	Languages []string
+
+	// User-Agent header to send. Default is DefaultUserAgent().
 	UserAgent string
 
+	// Optional hook to reorder (or filter) the bootstrapped RDAP base URLs
+	// before querying. By default, the bootstrap file's order is used.
+	//
+	// e.g. to prefer https, then a regional mirror:
+	//
+	//	client.OrderURLs = rdap.ChainURLOrders(rdap.PreferHTTPS, rdap.PreferHosts("rdap.example.eu"))
+	//
+	// Not applied to Requests with an explicit Server.
+	OrderURLs URLOrderFunc
+
+	// Optional query parameters to add to every request to a host, e.g. an
+	// API key required by a commercial RDAP gateway. Keyed by lowercase
+	// hostname:
+	//
+	//	client.HostParams = map[string]url.Values{
+	//	  "rdap.example.net": {"apikey": {"secret"}},
+	//	}
+	//
+	// The parameters are added to the HTTP request only, and are kept out of
+	// log messages, HTTPResponse.URL, and the Archive.
+	HostParams map[string]url.Values
+
+	// Optional DNS existence check for domain queries.
+	//
+	// Before querying a domain, DNSPrecheck is asked whether the domain
+	// exists. If it clearly doesn't, the RDAP query is skipped, saving
+	// registry rate limit quota: an ObjectDoesNotExist *ClientError is
+	// returned, with Response.DNSPrecheckSkipped set. If the check fails, the
+	// RDAP query proceeds as normal.
+	//
+	// e.g. client.DNSPrecheck = &rdap.ResolverPrecheck{}
+	//
+	// Not used in Offline mode, or for Requests with an explicit Server.
+	DNSPrecheck DNSPrecheck
+
+	// Optional policy restricting which RDAP servers may be contacted.
+	HostPolicy *HostPolicy
+
+	// Optional on-disk archive of every raw RDAP response.
+	Archive *Archive
+
+	// Optional cache of decoded RDAP responses.
+	ObjectCache *ObjectCache
+
+	// Optional persistent cache of RDAP responses, e.g. a cache.BoltCache
+	// shared between processes.
+	//
+	// Successful responses are saved for ResponseStoreTTL, and later
+	// Requests are answered from the store, with Response.Cached set.
+	// Requests with FetchRoles aren't stored.
+	ResponseStore ResponseStore
+
+	// Duration responses are saved in the ResponseStore. The default is 1
+	// hour.
+	ResponseStoreTTL time.Duration
+
+	// Optional instrumentation hooks, e.g. for Prometheus.
+	Metrics Metrics
+
+	// Optional tracing hooks, e.g. for OpenTelemetry.
+	Tracer Tracer
+
+	// Default maximum duration of the bootstrap phase, for Requests which
+	// don't specify a BootstrapTimeout. The default is no separate timeout.
+	BootstrapTimeout time.Duration
+
+	// Default maximum duration of each RDAP server query, for Requests which
+	// don't specify a QueryTimeout. The default is no separate timeout.
+	QueryTimeout time.Duration
+
+	// Default number of RDAP server URLs to query concurrently, for Requests
+	// which don't specify RaceServers.
+	//
+	// Some registries publish several equivalent RDAP base URLs. With
+	// RaceServers >= 2, the first RaceServers URLs are queried at once, the
+	// first useful response is used, and the slower queries are cancelled.
+	// The default (0 or 1) queries the URLs one at a time, in order.
+	RaceServers int
+
 	// Service Provider support is now always enabled.
 	// This field is ignored.
 	ServiceProviderExperiment bool
+
+	// Number of Do() calls in progress, see InFlight().
+	inFlight int64
+
+	// Guards the default HTTP and Bootstrap clients' creation, see init().
+	initMu sync.Mutex
+}
+
+// init creates the default HTTP and Bootstrap clients, if not set.
+func (c *Client) init() {
+	c.initMu.Lock()
+	defer c.initMu.Unlock()
+
+	if c.HTTP == nil {
+		c.HTTP = &http.Client{}
+	}
+
+	if c.Bootstrap == nil {
+		c.Bootstrap = &bootstrap.Client{}
+	}
 }
 
-func (c *Client) Do(req *Request) (*Response, error) {
+// InFlight returns the number of Do() calls in progress, i.e. the Client's
+// backlog of queries. See HealthHandler.MaxBacklog.
+func (c *Client) InFlight() int64 {
+	return atomic.LoadInt64(&c.inFlight)
+}
+
+// Do executes the RDAP Request |req|.
+func (c *Client) Do(req *Request) (resp *Response, err error) {
+	start := time.Now()
+
+	atomic.AddInt64(&c.inFlight, 1)
+	defer atomic.AddInt64(&c.inFlight, -1)
+
+	if req != nil {
+		attributes := map[string]string{
+			"rdap.type":  req.Type.String(),
+			"rdap.query": req.Query,
+		}
+		for k, v := range req.Tags {
+			attributes["rdap.tag."+k] = v
+		}
+
+		ctx, span := c.startSpan(req.Context(), "rdap.query", attributes)
+		req = req.WithContext(ctx)
+		defer func() {
+			span.End(err)
+		}()
+	}
+
+	resp, err = c.do(req)
+
+	if c.Metrics != nil && req != nil {
+		c.Metrics.ObserveQuery(req.Type, queryStatus(err), time.Since(start))
+	}
+
+	return resp, err
+}
+
+// newDecoder returns a Decoder for the RDAP response |body|, with the
+// Client's decoding options (LenientDecoding, InferObjectClass, and
+// DecoderLimits).
+//
+// Every response the Client decodes goes through this, including those
+// fetched by following server supplied links.
+func (c *Client) newDecoder(body []byte) *Decoder {
+	var decoderOptions []DecoderOption
+	if c.LenientDecoding {
+		decoderOptions = append(decoderOptions, LenientDecoding())
+	}
+	if c.InferObjectClass {
+		decoderOptions = append(decoderOptions, InferObjectClass())
+	}
+	decoderOptions = append(decoderOptions, LimitDecoding(c.DecoderLimits))
+
+	return NewDecoder(body, decoderOptions...)
+}
+
+// do executes |req|, see Do().
+//
+// The Request's Timeout bounds the whole request (the bootstrap phase, and
+// every RDAP server query). Within it, the bootstrap phase is bounded by the
+// BootstrapTimeout, and each RDAP server query by the QueryTimeout (see
+// bootstrapTimeoutFor() and queryTimeoutFor()), so a slow bootstrap download
+// doesn't eat into the query's budget.
+func (c *Client) do(req *Request) (*Response, error) {
 	// Response struct.
 	resp := &Response{}
 
@@ -98,35 +338,90 @@ func (c *Client) Do(req *Request) (*Response, error) {
 		}
 	}
 
-	// Init HTTP client?
-	if c.HTTP == nil {
-		c.HTTP = &http.Client{}
+	// Copy the Request's tags.
+	if len(req.Tags) > 0 {
+		resp.Tags = make(map[string]string, len(req.Tags))
+		for k, v := range req.Tags {
+			resp.Tags[k] = v
+		}
 	}
 
-	// Init Bootstrap client?
-	if c.Bootstrap == nil {
-		c.Bootstrap = &bootstrap.Client{}
+	// Cached response?
+	var cacheKey string
+	if c.ObjectCache != nil || c.ResponseStore != nil {
+		cacheKey = c.objectCacheKey(req)
 	}
 
-	// Init Verbose callback?
-	if c.Verbose == nil {
-		c.Verbose = func(text string) {}
+	if c.ObjectCache != nil {
+		if cached, ok := c.ObjectCache.get(cacheKey); ok {
+			c.verbose(fmt.Sprintf("client: Object cache hit for %s query '%s'", req.Type, req.Query))
+
+			cachedResp := *cached
+			cachedResp.Tags = resp.Tags
+			cachedResp.Cached = true
+
+			return &cachedResp, nil
+		}
 	}
 
-	c.Verbose("")
-	c.Verbose(fmt.Sprintf("client: Running..."))
-	c.Verbose(fmt.Sprintf("client: Request type  : %s", req.Type))
-	c.Verbose(fmt.Sprintf("client: Request query : %s", req.Query))
+	useResponseStore := c.ResponseStore != nil && len(req.FetchRoles) == 0
+
+	if useResponseStore {
+		if stored := c.loadStoredResponse(cacheKey); stored != nil {
+			c.verbose(fmt.Sprintf("client: Response store hit for %s query '%s'", req.Type, req.Query))
+
+			stored.Tags = resp.Tags
+
+			if c.ObjectCache != nil {
+				c.ObjectCache.put(cacheKey, stored)
+			}
+
+			return stored, nil
+		}
+	}
+
+	// Apply the overall request timeout?
+	if req.Timeout > 0 {
+		ctx, cancelFunc := context.WithTimeout(req.Context(), req.Timeout)
+		defer cancelFunc()
+
+		req = req.WithContext(ctx)
+	}
+
+	c.init()
+
+	c.verbose("")
+	c.verbose(fmt.Sprintf("client: Running..."))
+	c.verbose(fmt.Sprintf("client: Request type  : %s", req.Type))
+	c.verbose(fmt.Sprintf("client: Request query : %s", req.Query))
+
+	// DNS existence pre-check?
+	if c.DNSPrecheck != nil && req.Type == DomainRequest && req.Server == nil && !c.Offline {
+		exists, err := c.DNSPrecheck.DomainExists(req.Context(), req.Query)
+
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: DNS pre-check for '%s' failed (%s), continuing", req.Query, err))
+		} else if !exists {
+			c.verbose(fmt.Sprintf("client: DNS pre-check: '%s' does not exist, skipping RDAP query", req.Query))
+
+			resp.DNSPrecheckSkipped = true
+
+			return resp, &ClientError{
+				Type: ObjectDoesNotExist,
+				Text: fmt.Sprintf("DNS pre-check: domain '%s' does not exist, RDAP query skipped", req.Query),
+			}
+		}
+	}
 
 	var reqs []*Request
 
 	// Need to bootstrap the query?
 	if req.Server != nil {
-		c.Verbose(fmt.Sprintf("client: Request URL   : %s", req.URL()))
+		c.verbose(fmt.Sprintf("client: Request URL   : %s", req.URL()))
 
 		reqs = []*Request{req}
 	} else if req.Server == nil {
-		c.Verbose("client: Request URL   : TBD, bootstrap required")
+		c.verbose("client: Request URL   : TBD, bootstrap required")
 
 		var bootstrapType *bootstrap.RegistryType = bootstrapTypeFor(req)
 
@@ -139,28 +434,100 @@ func (c *Client) Do(req *Request) (*Response, error) {
 			}
 		}
 
-		origBootstrapVerbose := c.Bootstrap.Verbose
-		c.Bootstrap.Verbose = c.Verbose
-		defer func() {
-			c.Bootstrap.Verbose = origBootstrapVerbose
-		}()
-
 		question := &bootstrap.Question{
 			RegistryType: *bootstrapType,
 			Query:        req.Query,
+			Verbose:      c.verbose,
+			Offline:      c.Offline,
 		}
-		question = question.WithContext(req.Context())
+		bootstrapCtx := req.Context()
+		if timeout := c.bootstrapTimeoutFor(req); timeout > 0 {
+			var cancelFunc context.CancelFunc
+			bootstrapCtx, cancelFunc = context.WithTimeout(bootstrapCtx, timeout)
+			defer cancelFunc()
+
+			c.verbose(fmt.Sprintf("client: Bootstrap timeout is %s", timeout))
+		}
+
+		bootstrapCtx, span := c.startSpan(bootstrapCtx, "rdap.bootstrap", map[string]string{
+			"rdap.registry": bootstrapType.String(),
+		})
+		question = question.WithContext(bootstrapCtx)
 
 		var answer *bootstrap.Answer
 		var err error
 
+		c.log(&LogEvent{
+			Type:    LogBootstrapStarted,
+			Level:   LogInfo,
+			Message: fmt.Sprintf("client: Bootstrapping %s query '%s'", bootstrapType, req.Query),
+			Fields: map[string]string{
+				"registry": bootstrapType.String(),
+				"query":    req.Query,
+			},
+		})
+
+		bootstrapStart := time.Now()
 		answer, err = c.Bootstrap.Lookup(question)
 		resp.BootstrapAnswer = answer
 
+		var offlineErr *bootstrap.OfflineError
+		if errors.As(err, &offlineErr) {
+			err = &ClientError{
+				Type: OfflineError,
+				Text: offlineErr.Error(),
+				Err:  offlineErr,
+			}
+		}
+
+		if answer != nil {
+			var urls []string
+			for _, u := range answer.URLs {
+				urls = append(urls, u.String())
+			}
+
+			c.log(&LogEvent{
+				Type:    LogBootstrapFinished,
+				Level:   LogInfo,
+				Message: fmt.Sprintf("client: Bootstrap found %d RDAP server(s)", len(answer.URLs)),
+				Fields: map[string]string{
+					"registry": bootstrapType.String(),
+					"entry":    answer.Entry,
+					"urls":     strings.Join(urls, " "),
+				},
+			})
+		}
+
+		span.End(err)
+
+		if c.Metrics != nil {
+			cacheHit := answer != nil && !answer.Downloaded
+			c.Metrics.ObserveBootstrap(*bootstrapType, cacheHit, time.Since(bootstrapStart))
+		}
+
 		if err != nil {
 			return resp, err
 		}
 
+		var warning string
+		if answer.Stale {
+			warning = fmt.Sprintf("Bootstrap %s Service Registry file has expired, used a stale cached copy",
+				bootstrapType)
+		} else if answer.Embedded {
+			warning = fmt.Sprintf("Bootstrap %s Service Registry file could not be downloaded, used the built-in copy published %s",
+				bootstrapType, answer.Publication)
+		}
+
+		if warning != "" {
+			resp.Warnings = append(resp.Warnings, warning)
+
+			c.log(&LogEvent{
+				Type:    LogMessage,
+				Level:   LogWarn,
+				Message: "client: WARNING: " + warning,
+			})
+		}
+
 		// No URLs to query?
 		if len(answer.URLs) == 0 {
 			return resp, &ClientError{
@@ -169,70 +536,256 @@ func (c *Client) Do(req *Request) (*Response, error) {
 			}
 		}
 
-		for _, u := range answer.URLs {
-			reqs = append(reqs, req.WithServer(u))
+		urls := answer.URLs
+		if c.OrderURLs != nil {
+			urls = c.OrderURLs(req, append([]*url.URL{}, urls...))
+
+			if len(urls) == 0 {
+				return resp, &ClientError{
+					Type: NoWorkingServers,
+					Text: fmt.Sprintf("All RDAP servers for '%s' were removed by OrderURLs", question.Query),
+				}
+			}
+		}
+
+		for _, u := range urls {
+			r := req.WithServer(u)
+			r.bootstrapAnswer = answer
+			reqs = append(reqs, r)
 		}
 	}
 
 	for i, r := range reqs {
-		c.Verbose(fmt.Sprintf("client: RDAP URL #%d is %s", i, r.URL()))
+		c.verbose(fmt.Sprintf("client: RDAP URL #%d is %s", i, r.URL()))
 	}
 
-	for _, r := range reqs {
-		c.Verbose(fmt.Sprintf("client: GET %s", r.URL()))
+	// Remove any RDAP URLs not permitted by the HostPolicy.
+	if c.HostPolicy != nil {
+		var allowed []*Request
+		var policyErr error
+
+		for _, r := range reqs {
+			if err := c.HostPolicy.Check(r.URL()); err != nil {
+				c.verbose(fmt.Sprintf("client: Skipping %s: %s", r.URL(), err))
+				policyErr = err
+				continue
+			}
 
-		httpResponse := c.get(r)
+			allowed = append(allowed, r)
+		}
+
+		if len(allowed) == 0 {
+			return resp, policyErr
+		}
+
+		reqs = allowed
+	}
+
+	// No network access allowed?
+	if c.Offline {
+		return resp, &ClientError{
+			Type: OfflineError,
+			Text: fmt.Sprintf("Offline mode, %s query '%s' not cached", req.Type, req.Query),
+			URL:  reqs[0].URL().String(),
+		}
+	}
+
+	// Race the first few RDAP servers?
+	var raced map[*Request]*HTTPResponse
+	if n := c.raceCount(req, len(reqs)); n >= 2 {
+		var ordered []*Request
+		raced, ordered = c.race(reqs[:n])
+		reqs = append(ordered, reqs[n:]...)
+	}
+
+	// The last RDAP error response received, if any.
+	var lastRDAPError *Error
+
+	for _, r := range reqs {
+		c.log(&LogEvent{
+			Type:    LogHTTPRequest,
+			Level:   LogInfo,
+			Message: fmt.Sprintf("client: GET %s", r.URL()),
+			Fields: map[string]string{
+				"url": r.URL().String(),
+			},
+		})
+
+		httpResponse, ok := raced[r]
+		if !ok {
+			httpResponse = c.get(r)
+		}
 		resp.HTTP = append(resp.HTTP, httpResponse)
 
+		if c.Metrics != nil {
+			statusCode := 0
+			if httpResponse.Response != nil {
+				statusCode = httpResponse.Response.StatusCode
+			}
+
+			c.Metrics.ObserveHTTP(r.URL().Host, statusCode, httpResponse.Duration)
+		}
+
 		if httpResponse.Error != nil {
-			c.Verbose(fmt.Sprintf("client: error: %s",
-				httpResponse.Error))
+			c.log(&LogEvent{
+				Type:    LogHTTPResponse,
+				Level:   LogWarn,
+				Message: fmt.Sprintf("client: error: %s", httpResponse.Error),
+				Fields: map[string]string{
+					"url":      httpResponse.URL,
+					"duration": httpResponse.Duration.String(),
+					"error":    httpResponse.Error.Error(),
+				},
+			})
 
 			if r.Context().Err() == context.DeadlineExceeded {
 				return resp, httpResponse.Error
 			}
 
+			if certErr := tlsCertificateError(httpResponse.Error); certErr != "" {
+				if !c.FallbackOnTLSError {
+					return resp, &ClientError{
+						Type: TLSCertificateError,
+						Text: fmt.Sprintf("TLS certificate error for %s: %s", httpResponse.URL, certErr),
+						Err:  httpResponse.Error,
+						URL:  httpResponse.URL,
+					}
+				}
+
+				warning := fmt.Sprintf("TLS certificate error for %s (%s), trying next RDAP server",
+					httpResponse.URL, certErr)
+				resp.Warnings = append(resp.Warnings, warning)
+
+				c.log(&LogEvent{
+					Type:    LogMessage,
+					Level:   LogWarn,
+					Message: "client: WARNING: " + warning,
+				})
+			}
+
 			// Continues to the next RDAP server.
 		} else {
 			hrr := httpResponse.Response
 
-			c.Verbose(fmt.Sprintf("client: status-code=%d, content-type=%s, length=%d bytes, duration=%s",
-				hrr.StatusCode,
-				hrr.Header.Get("Content-Type"),
-				len(httpResponse.Body),
-				httpResponse.Duration))
+			c.log(&LogEvent{
+				Type:  LogHTTPResponse,
+				Level: LogInfo,
+				Message: fmt.Sprintf("client: status-code=%d, content-type=%s, length=%d bytes, duration=%s",
+					hrr.StatusCode,
+					hrr.Header.Get("Content-Type"),
+					len(httpResponse.Body),
+					httpResponse.Duration),
+				Fields: map[string]string{
+					"url":          httpResponse.URL,
+					"status_code":  strconv.Itoa(hrr.StatusCode),
+					"content_type": hrr.Header.Get("Content-Type"),
+					"length":       strconv.Itoa(len(httpResponse.Body)),
+					"duration":     httpResponse.Duration.String(),
+				},
+			})
 
 			if len(httpResponse.Body) > 0 && hrr.StatusCode >= 200 && hrr.StatusCode <= 299 {
 				// Decode the response.
-				decoder := NewDecoder(httpResponse.Body)
+				_, span := c.startSpan(r.Context(), "rdap.decode", nil)
+				decoder := c.newDecoder(httpResponse.Body)
 
 				resp.Object, httpResponse.Error = decoder.Decode()
+				span.End(httpResponse.Error)
+
+				for _, w := range decoder.Warnings() {
+					resp.Warnings = append(resp.Warnings, "Decoding: "+w)
+				}
 
 				if httpResponse.Error != nil {
-					c.Verbose(fmt.Sprintf("client: Error decoding response: %s",
-						httpResponse.Error))
+					c.log(&LogEvent{
+						Type:    LogDecodeWarning,
+						Level:   LogWarn,
+						Message: fmt.Sprintf("client: Error decoding response: %s", httpResponse.Error),
+						Fields: map[string]string{
+							"url":   httpResponse.URL,
+							"error": httpResponse.Error.Error(),
+						},
+					})
 					continue
 				}
 
-				c.Verbose("client: Successfully decoded response")
+				c.verbose("client: Successfully decoded response")
+
+				if c.Strict {
+					if err := verifyResponse(req, resp.Object, httpResponse.URL); err != nil {
+						return resp, err
+					}
+				}
+
+				if c.HostPolicy != nil && req.Type == RawRequest {
+					if err := c.HostPolicy.CheckObject(resp.Object, httpResponse.URL); err != nil {
+						return resp, err
+					}
+				}
+
+				if n, ok := resp.Object.(*IPNetwork); ok {
+					upgradeLinks(n, httpResponse.URL)
+				}
+
+				if c.InferRoles {
+					if n := InferRoles(resp.Object); n > 0 {
+						c.verbose(fmt.Sprintf("client: Inferred roles of %d entities", n))
+					}
+				}
+
+				// Additional fetches for contact information.
+				if len(req.FetchRoles) > 0 {
+					c.fetchRoles(r, resp)
+				}
+
+				// Registrar chase.
+				if c.FollowRelated {
+					c.followRelated(r, resp)
+				}
+
+				if c.ObjectCache != nil {
+					c.ObjectCache.put(cacheKey, resp)
+				}
 
-				// Implement additional fetches here.
+				if useResponseStore {
+					c.storeResponse(cacheKey, resp, httpResponse.URL)
+				}
 
 				return resp, nil
-			} else if hrr.StatusCode == 404 {
-				return resp, &ClientError{
-					Type: ObjectDoesNotExist,
-					Text: fmt.Sprintf("RDAP server returned 404, object does not exist."),
+			} else if hrr.StatusCode >= 400 {
+				rdapError := c.decodeRDAPError(httpResponse.Body)
+
+				// Client errors are returned immediately, except rate
+				// limiting (429), where another server may succeed.
+				if hrr.StatusCode == 404 || (rdapError != nil && hrr.StatusCode < 500 && hrr.StatusCode != 429) {
+					return resp, clientErrorFromHTTPError(hrr.StatusCode, rdapError, httpResponse.URL)
+				} else if rdapError != nil {
+					lastRDAPError = rdapError
 				}
 			}
 		}
 	}
 
-	return resp, &ClientError{
+	noWorkingServers := &ClientError{
 		Type: NoWorkingServers,
 		Text: fmt.Sprintf("No RDAP servers responded successfully (tried %d server(s))",
 			len(reqs)),
 	}
+
+	// Include the last server's error.
+	if len(resp.HTTP) > 0 {
+		last := resp.HTTP[len(resp.HTTP)-1]
+
+		noWorkingServers.Err = last.Error
+		noWorkingServers.URL = last.URL
+		if last.Response != nil {
+			noWorkingServers.StatusCode = last.Response.StatusCode
+		}
+	}
+
+	noWorkingServers.RDAPError = lastRDAPError
+
+	return resp, noWorkingServers
 }
 
 func (c *Client) get(rdapReq *Request) *HTTPResponse {
@@ -243,27 +796,81 @@ func (c *Client) get(rdapReq *Request) *HTTPResponse {
 
 	start := time.Now()
 
+	ctx, span := c.startSpan(rdapReq.Context(), "rdap.http", map[string]string{
+		"http.url": httpResponse.URL,
+	})
+	defer func() {
+		if httpResponse.Response != nil {
+			span.SetAttribute("http.status_code", strconv.Itoa(httpResponse.Response.StatusCode))
+		}
+		span.End(httpResponse.Error)
+	}()
+
 	// Setup the HTTP request.
-	req, err := http.NewRequest("GET", httpResponse.URL, nil)
+	req, err := http.NewRequest("GET", c.urlWithHostParams(rdapReq.URL()).String(), nil)
 	if err != nil {
 		httpResponse.Error = err
 		httpResponse.Duration = time.Since(start)
 		return httpResponse
 	}
 
-	// Optionally add User-Agent header.
-	if c.UserAgent != "" {
-		req.Header.Add("User-Agent", c.UserAgent)
+	// User-Agent header.
+	userAgent := c.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
 	}
+	req.Header.Add("User-Agent", userAgent)
 
 	// HTTP Accept header.
-	req.Header.Add("Accept", "application/rdap+json, application/json")
+	extensions := rdapReq.Extensions
+	if len(extensions) == 0 {
+		extensions = c.Extensions
+	}
+	req.Header.Add("Accept", acceptHeader(extensions))
+
+	// Request a compressed response.
+	req.Header.Add("Accept-Encoding", httpbody.AcceptEncoding)
+
+	// Optionally add Accept-Language header.
+	languages := rdapReq.Languages
+	if len(languages) == 0 {
+		languages = c.Languages
+	}
+
+	if len(languages) > 0 {
+		req.Header.Add("Accept-Language", acceptLanguage(languages))
+	}
 
 	// Add context for timeout.
-	req = req.WithContext(rdapReq.Context())
+	if timeout := c.queryTimeoutFor(rdapReq); timeout > 0 {
+		var cancelFunc context.CancelFunc
+		ctx, cancelFunc = context.WithTimeout(ctx, timeout)
+		defer cancelFunc()
+	}
+	req = req.WithContext(ctx)
+
+	httpClient := c.HTTP
+
+	// Apply the HostPolicy to redirects too.
+	if c.HostPolicy != nil {
+		policyClient := *c.HTTP
+		policyClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if err := c.HostPolicy.checkRedirect(req, via); err != nil {
+				return err
+			}
+
+			if c.HTTP.CheckRedirect != nil {
+				return c.HTTP.CheckRedirect(req, via)
+			}
+
+			return nil
+		}
+
+		httpClient = &policyClient
+	}
 
 	// Make the HTTP request.
-	resp, err := c.HTTP.Do(req)
+	resp, err := httpClient.Do(req)
 	httpResponse.Response = resp
 
 	// Handle errors such as "remote doesn't speak HTTP"...
@@ -275,13 +882,99 @@ func (c *Client) get(rdapReq *Request) *HTTPResponse {
 	}
 
 	defer resp.Body.Close()
-	httpResponse.Body, httpResponse.Error = ioutil.ReadAll(resp.Body)
+	httpResponse.Body, httpResponse.Error = httpbody.ReadBody(resp, c.DecoderLimits.MaxBodySize)
 
 	httpResponse.Duration = time.Since(start)
 
+	// Archive the response?
+	if c.Archive != nil && httpResponse.Error == nil {
+		if err := c.Archive.Save(rdapReq, httpResponse); err != nil {
+			c.verbose(fmt.Sprintf("client: Error archiving response: %s", err))
+		}
+	}
+
 	return httpResponse
 }
 
+// urlWithHostParams returns |u| with the Client's HostParams for its host
+// added (if any).
+func (c *Client) urlWithHostParams(u *url.URL) *url.URL {
+	params := c.HostParams[strings.ToLower(u.Hostname())]
+	if len(params) == 0 {
+		return u
+	}
+
+	result := *u
+	query := result.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	result.RawQuery = query.Encode()
+
+	return &result
+}
+
+// tlsCertificateError returns a description of the TLS certificate error
+// within |err|, or empty string if |err| is not a certificate error.
+func tlsCertificateError(err error) string {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalid x509.CertificateInvalidError
+	var hostname x509.HostnameError
+
+	switch {
+	case errors.As(err, &unknownAuthority):
+		return unknownAuthority.Error()
+	case errors.As(err, &invalid):
+		return invalid.Error()
+	case errors.As(err, &hostname):
+		return hostname.Error()
+	}
+
+	return ""
+}
+
+// acceptLanguage returns an Accept-Language header value for the list of
+// |languages|, most preferred first. e.g. "ja, en;q=0.9".
+func acceptLanguage(languages []string) string {
+	var values []string
+
+	for i, l := range languages {
+		if i == 0 {
+			values = append(values, l)
+			continue
+		}
+
+		q := 10 - i
+		if q < 1 {
+			q = 1
+		}
+
+		values = append(values, fmt.Sprintf("%s;q=0.%d", l, q))
+	}
+
+	return strings.Join(values, ", ")
+}
+
+// bootstrapTimeoutFor returns the bootstrap phase timeout for |req|, or zero
+// for no separate timeout.
+func (c *Client) bootstrapTimeoutFor(req *Request) time.Duration {
+	if req.BootstrapTimeout > 0 {
+		return req.BootstrapTimeout
+	}
+
+	return c.BootstrapTimeout
+}
+
+// queryTimeoutFor returns the per-server query timeout for |req|, or zero for
+// no separate timeout.
+func (c *Client) queryTimeoutFor(req *Request) time.Duration {
+	if req.QueryTimeout > 0 {
+		return req.QueryTimeout
+	}
+
+	return c.QueryTimeout
+}
+
 // QueryDomain makes an RDAP request for the |domain|.
 //
 // Full contact information (where available) is provided. The timeout is 30s.
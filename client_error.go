@@ -5,7 +5,9 @@
 package rdap
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -21,33 +23,173 @@ const (
 	NoWorkingServers
 	ObjectDoesNotExist
 	RDAPServerError
+	HostNotAllowed
+	TLSCertificateError
+	OfflineError
 )
 
+// String returns the ClientErrorType as a string, e.g. "bootstrap-no-match".
+func (c ClientErrorType) String() string {
+	switch c {
+	case InputError:
+		return "input-error"
+	case BootstrapNotSupported:
+		return "bootstrap-not-supported"
+	case BootstrapNoMatch:
+		return "bootstrap-no-match"
+	case WrongResponseType:
+		return "wrong-response-type"
+	case NoWorkingServers:
+		return "no-working-servers"
+	case ObjectDoesNotExist:
+		return "object-does-not-exist"
+	case RDAPServerError:
+		return "rdap-server-error"
+	case HostNotAllowed:
+		return "host-not-allowed"
+	case TLSCertificateError:
+		return "tls-certificate-error"
+	case OfflineError:
+		return "offline"
+	default:
+		return "unknown"
+	}
+}
+
+// Sentinel errors, for use with errors.Is(). e.g.:
+//
+//	_, err := client.QueryDomain("example.cz")
+//	if errors.Is(err, rdap.ErrObjectDoesNotExist) {
+//	  ...
+//	}
+//
+// A ClientError matches the sentinel of its Type.
+var (
+	ErrInput                 = &ClientError{Type: InputError, Text: "input error"}
+	ErrBootstrapNotSupported = &ClientError{Type: BootstrapNotSupported, Text: "bootstrap not supported"}
+	ErrBootstrapNoMatch      = &ClientError{Type: BootstrapNoMatch, Text: "no bootstrap match"}
+	ErrWrongResponseType     = &ClientError{Type: WrongResponseType, Text: "wrong response type"}
+	ErrNoWorkingServers      = &ClientError{Type: NoWorkingServers, Text: "no working RDAP servers"}
+	ErrObjectDoesNotExist    = &ClientError{Type: ObjectDoesNotExist, Text: "object does not exist"}
+	ErrRDAPServerError       = &ClientError{Type: RDAPServerError, Text: "RDAP server error"}
+	ErrHostNotAllowed        = &ClientError{Type: HostNotAllowed, Text: "host not allowed"}
+	ErrTLSCertificate        = &ClientError{Type: TLSCertificateError, Text: "TLS certificate error"}
+	ErrOffline               = &ClientError{Type: OfflineError, Text: "offline"}
+)
+
+// ClientError is the error type returned by Client.
+//
+// Use errors.Is() with the sentinel errors above to check the Type, and
+// errors.As() to access the underlying cause (e.g. a *net.DNSError or
+// x509.UnknownAuthorityError).
 type ClientError struct {
 	Type ClientErrorType
 	Text string
+
+	// Underlying error, if any. e.g. the last HTTP error for
+	// NoWorkingServers.
+	Err error
+
+	// URL of the RDAP server the error relates to, if any.
+	URL string
+
+	// HTTP status code received, or zero if none.
+	StatusCode int
+
+	// The RDAP error response returned by the server, if any. Contains the
+	// server's explanation of the error (title, description, and notices).
+	RDAPError *Error
 }
 
 func (c ClientError) Error() string {
 	return c.Text
 }
 
+// Unwrap returns the underlying error, if any.
+func (c *ClientError) Unwrap() error {
+	return c.Err
+}
+
+// Is returns true if |target| is a *ClientError of the same Type, e.g. one of
+// the sentinel errors.
+func (c *ClientError) Is(target error) bool {
+	t, ok := target.(*ClientError)
+
+	return ok && t.Type == c.Type
+}
+
 func isClientError(t ClientErrorType, err error) bool {
-	if ce, ok := err.(*ClientError); ok {
-		if ce.Type == t {
-			return true
-		}
+	var ce *ClientError
+	if errors.As(err, &ce) {
+		return ce.Type == t
 	}
 
 	return false
 }
 
 func clientErrorFromRDAPError(e *Error) *ClientError {
+	statusCode := 0
+	if e.ErrorCode != nil {
+		statusCode = int(*e.ErrorCode)
+	}
+
 	return &ClientError{
-		Type: RDAPServerError,
+		Type:       RDAPServerError,
+		StatusCode: statusCode,
 		Text: fmt.Sprintf("Server returned error code %d, title='%s', description='%s'",
-			e.ErrorCode,
+			statusCode,
 			e.Title,
 			strings.Join(e.Description, " ")),
+		RDAPError: e,
 	}
 }
+
+// clientErrorFromHTTPError returns the ClientError for an HTTP error
+// |statusCode| from |url|, with the RDAP error response |e| (nil if none).
+func clientErrorFromHTTPError(statusCode int, e *Error, url string) *ClientError {
+	ce := &ClientError{
+		Type:       RDAPServerError,
+		StatusCode: statusCode,
+		URL:        url,
+		RDAPError:  e,
+		Text: fmt.Sprintf("RDAP server returned %d %s",
+			statusCode,
+			http.StatusText(statusCode)),
+	}
+
+	if statusCode == http.StatusNotFound {
+		ce.Type = ObjectDoesNotExist
+		ce.Text = "RDAP server returned 404, object does not exist."
+	}
+
+	if e != nil {
+		var explanation []string
+		if e.Title != "" {
+			explanation = append(explanation, e.Title)
+		}
+		explanation = append(explanation, e.Description...)
+
+		if len(explanation) > 0 {
+			ce.Text += " (" + strings.Join(explanation, " ") + ")"
+		}
+	}
+
+	return ce
+}
+
+// decodeRDAPError returns the RDAP error response in |body|, or nil if |body|
+// isn't one.
+func (c *Client) decodeRDAPError(body []byte) *Error {
+	if len(body) == 0 {
+		return nil
+	}
+
+	obj, err := c.newDecoder(body).Decode()
+	if err != nil {
+		return nil
+	}
+
+	e, _ := obj.(*Error)
+
+	return e
+}
@@ -5,9 +5,18 @@
 package rdap
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/openrdap/rdap/bootstrap"
 	"github.com/openrdap/rdap/test"
 )
 
@@ -101,3 +110,594 @@ func TestClientQueryDomainMalformed(t *testing.T) {
 // 2) bootstrap not supported
 // 3) bootstrap no match
 // test Help...
+
+func TestClientPhaseTimeouts(t *testing.T) {
+	client := &Client{
+		BootstrapTimeout: 5 * time.Second,
+		QueryTimeout:     20 * time.Second,
+	}
+
+	req := NewDomainRequest("example.cz")
+
+	if client.bootstrapTimeoutFor(req) != 5*time.Second {
+		t.Errorf("Expected Client default bootstrap timeout")
+	} else if client.queryTimeoutFor(req) != 20*time.Second {
+		t.Errorf("Expected Client default query timeout")
+	}
+
+	req.BootstrapTimeout = time.Second
+	req.QueryTimeout = 2 * time.Second
+
+	if client.bootstrapTimeoutFor(req) != time.Second {
+		t.Errorf("Expected Request bootstrap timeout override")
+	} else if client.queryTimeoutFor(req) != 2*time.Second {
+		t.Errorf("Expected Request query timeout override")
+	}
+}
+
+func TestClientPhaseTimeoutsSlowServers(t *testing.T) {
+	domainJSON := test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	var bootstrapDelay, queryDelay int64 // time.Duration, accessed atomically.
+	setDelays := func(bootstrap, query time.Duration) {
+		atomic.StoreInt64(&bootstrapDelay, int64(bootstrap))
+		atomic.StoreInt64(&queryDelay, int64(query))
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delay := time.Duration(atomic.LoadInt64(&queryDelay))
+		if r.URL.Path == "/dns.json" {
+			delay = time.Duration(atomic.LoadInt64(&bootstrapDelay))
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if r.URL.Path == "/dns.json" {
+			fmt.Fprintf(w, `{"version": "1.0", "services": [[["cz"], ["%s/"]]]}`, server.URL)
+			return
+		}
+
+		w.Write(domainJSON)
+	}))
+	defer server.Close()
+
+	bootstrapURL, _ := url.Parse(server.URL)
+
+	newClient := func() *Client {
+		return &Client{
+			Verbose:          verboseFunc(),
+			Bootstrap:        &bootstrap.Client{BaseURL: bootstrapURL},
+			BootstrapTimeout: 2 * time.Second,
+			QueryTimeout:     600 * time.Millisecond,
+		}
+	}
+
+	// A slow bootstrap download times out after the BootstrapTimeout, not the
+	// overall Timeout.
+	setDelays(5*time.Second, 0)
+
+	client := newClient()
+	client.BootstrapTimeout = 200 * time.Millisecond
+
+	req := NewDomainRequest("example.cz")
+	req.Timeout = 10 * time.Second
+
+	start := time.Now()
+	if _, err := client.Do(req); err == nil {
+		t.Errorf("Expected bootstrap timeout error")
+	} else if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Bootstrap timeout took %s", elapsed)
+	}
+
+	// The bootstrap phase doesn't use up the QueryTimeout: 400ms + 400ms is
+	// longer than the 600ms QueryTimeout, but each phase has its own budget.
+	setDelays(400*time.Millisecond, 400*time.Millisecond)
+
+	resp, err := newClient().Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	} else if _, ok := resp.Object.(*Domain); !ok {
+		t.Errorf("Expected Domain response")
+	}
+
+	// A slow RDAP server times out after the QueryTimeout.
+	setDelays(0, 5*time.Second)
+
+	start = time.Now()
+	if _, err := newClient().Do(NewDomainRequest("example.cz")); err == nil {
+		t.Errorf("Expected query timeout error")
+	} else if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Query timeout took %s", elapsed)
+	}
+}
+
+func TestClientFetchRoles(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	req := NewDomainRequest("fetch-roles.cz")
+	req.FetchRoles = []string{"registrant"}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	domain := resp.Object.(*Domain)
+
+	registrant := domain.Entities[0]
+	if registrant.VCard == nil || registrant.VCard.Name() != "Jan Novak" {
+		t.Errorf("Registrant contact information not fetched")
+	} else if len(registrant.Roles) != 1 || registrant.Roles[0] != "registrant" {
+		t.Errorf("Registrant roles not preserved: %v", registrant.Roles)
+	}
+
+	if domain.Entities[1].VCard != nil {
+		t.Errorf("Technical contact unexpectedly fetched")
+	}
+
+	if len(resp.HTTP) != 2 {
+		t.Errorf("Expected 2 HTTP requests, got %d", len(resp.HTTP))
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		Languages []string
+		Expected  string
+	}{
+		{[]string{"ja"}, "ja"},
+		{[]string{"ja", "en"}, "ja, en;q=0.9"},
+		{[]string{"ja", "en-GB", "en"}, "ja, en-GB;q=0.9, en;q=0.8"},
+	}
+
+	for _, test := range tests {
+		actual := acceptLanguage(test.Languages)
+
+		if actual != test.Expected {
+			t.Errorf("Languages %v: got %s, expected %s", test.Languages, actual, test.Expected)
+		}
+	}
+}
+
+func TestClientTLSFallback(t *testing.T) {
+	domainJSON := test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(domainJSON)
+	}))
+	defer tlsServer.Close()
+
+	var plainServer *httptest.Server
+	plainServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dns.json" {
+			fmt.Fprintf(w, `{"version": "1.0", "services": [[["cz"], ["%s/", "%s/"]]]}`,
+				tlsServer.URL, plainServer.URL)
+			return
+		}
+
+		w.Write(domainJSON)
+	}))
+	defer plainServer.Close()
+
+	bootstrapURL, _ := url.Parse(plainServer.URL)
+
+	for _, fallback := range []bool{false, true} {
+		client := &Client{
+			Verbose:            verboseFunc(),
+			Bootstrap:          &bootstrap.Client{BaseURL: bootstrapURL},
+			FallbackOnTLSError: fallback,
+		}
+
+		resp, err := client.Do(NewDomainRequest("example.cz"))
+
+		if !fallback {
+			if !isClientError(TLSCertificateError, err) {
+				t.Errorf("Expected TLSCertificateError, got %v", err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error with fallback: %s", err)
+		} else if len(resp.Warnings) != 1 {
+			t.Errorf("Expected 1 warning, got %v", resp.Warnings)
+		} else if _, ok := resp.Object.(*Domain); !ok {
+			t.Errorf("Expected Domain response")
+		}
+	}
+}
+
+func TestClientTags(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{}
+
+	req := NewDomainRequest("example.cz")
+	req.Tags = map[string]string{"case": "1234"}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if resp.Tags["case"] != "1234" {
+		t.Errorf("Response tags not set: %v", resp.Tags)
+	}
+
+	req.Tags["case"] = "5678"
+	if resp.Tags["case"] != "1234" {
+		t.Errorf("Response tags not copied")
+	}
+}
+
+func TestClientRaceServers(t *testing.T) {
+	domainJSON := test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+		w.Write(domainJSON)
+	}))
+	defer slowServer.Close()
+
+	var fastServer *httptest.Server
+	fastServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/dns.json" {
+			fmt.Fprintf(w, `{"version": "1.0", "services": [[["cz"], ["%s/", "%s/"]]]}`,
+				slowServer.URL, fastServer.URL)
+			return
+		}
+
+		w.Write(domainJSON)
+	}))
+	defer fastServer.Close()
+
+	bootstrapURL, _ := url.Parse(fastServer.URL)
+
+	client := &Client{
+		Verbose:     verboseFunc(),
+		Bootstrap:   &bootstrap.Client{BaseURL: bootstrapURL},
+		RaceServers: 2,
+	}
+
+	start := time.Now()
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if time.Since(start) > 2*time.Second {
+		t.Errorf("Race took too long: %s", time.Since(start))
+	}
+
+	if len(resp.HTTP) != 1 || !strings.HasPrefix(resp.HTTP[0].URL, fastServer.URL) {
+		t.Errorf("Expected a single response from the fast server")
+	} else if _, ok := resp.Object.(*Domain); !ok {
+		t.Errorf("Expected Domain response")
+	}
+}
+
+func TestClientFollowRelated(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose:       verboseFunc(),
+		FollowRelated: true,
+	}
+
+	resp, err := client.Do(NewDomainRequest("related.cz"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if resp.Related == nil {
+		t.Fatalf("Related response not fetched")
+	}
+
+	domain, ok := resp.Related.Object.(*Domain)
+	if !ok {
+		t.Fatalf("Expected related Domain response")
+	} else if len(domain.Entities) != 1 || domain.Entities[0].VCard.Name() != "Jan Novak" {
+		t.Errorf("Unexpected related Domain entities")
+	}
+
+	if resp.Related.HTTP[0].URL != "https://rdap.registrar.example/domain/related.cz" {
+		t.Errorf("Unexpected related URL %s", resp.Related.HTTP[0].URL)
+	}
+}
+
+func TestClientQueryDomainAtRegistrar(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	registry, registrar, err := client.QueryDomainAtRegistrar("related.cz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if registry.Handle != "related.cz" || len(registry.Entities) != 0 {
+		t.Errorf("Unexpected registry Domain")
+	} else if registrar == nil || len(registrar.Entities) != 1 {
+		t.Errorf("Unexpected registrar Domain")
+	}
+
+	_, registrar, err = client.QueryDomainAtRegistrar("example.cz")
+	if !isClientError(BootstrapNoMatch, err) || registrar != nil {
+		t.Errorf("Expected BootstrapNoMatch, got %v", err)
+	}
+}
+
+func TestDomainRegistrarRDAPURL(t *testing.T) {
+	d := &Domain{
+		LDHName: "example.com",
+		Entities: []Entity{
+			{
+				Roles: []string{"registrar"},
+				Links: []Link{
+					{Rel: "about", Href: "https://www.registrar.example/"},
+					{Rel: "related", Type: "application/rdap+json", Href: "https://rdap.registrar.example/"},
+				},
+			},
+		},
+	}
+
+	expected := "https://rdap.registrar.example/domain/example.com"
+	if actual := d.RegistrarRDAPURL(); actual != expected {
+		t.Errorf("Got %s, expected %s", actual, expected)
+	}
+
+	// A link to the registrar's own entity object isn't a base URL.
+	for _, href := range []string{
+		"https://rdap.registrar.example/entity/123",
+		"https://rdap.registrar.example/entity/123/",
+		"https://rdap.registrar.example/rdap?id=123",
+	} {
+		d.Entities[0].Links[1].Href = href
+
+		if actual := d.RegistrarRDAPURL(); actual != "" {
+			t.Errorf("%s: got %s, expected no URL", href, actual)
+		}
+	}
+
+	d.Entities[0].Links[1].Href = "https://registrar.example/rdap/"
+	if actual, expected := d.RegistrarRDAPURL(), "https://registrar.example/rdap/domain/example.com"; actual != expected {
+		t.Errorf("Got %s, expected %s", actual, expected)
+	}
+}
+
+func TestClientRDAPErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var status int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/domain/"), "%d", &status)
+
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"rdapConformance": ["rdap_level_0"], "errorCode": %d, "title": "Error %d", "description": ["Try again later."]}`, status, status)
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &Client{}
+
+	tests := []struct {
+		Status int
+		Type   ClientErrorType
+	}{
+		{404, ObjectDoesNotExist},
+		{422, RDAPServerError},
+		{429, NoWorkingServers},
+		{503, NoWorkingServers},
+	}
+
+	for _, test := range tests {
+		req := NewRequest(DomainRequest, fmt.Sprintf("%d", test.Status)).WithServer(serverURL)
+		_, err := client.Do(req)
+
+		var ce *ClientError
+		if !errors.As(err, &ce) || ce.Type != test.Type {
+			t.Errorf("HTTP %d: expected %s, got %v", test.Status, test.Type, err)
+			continue
+		}
+
+		if ce.StatusCode != test.Status {
+			t.Errorf("HTTP %d: got StatusCode %d", test.Status, ce.StatusCode)
+		}
+
+		if ce.RDAPError == nil || ce.RDAPError.Title != fmt.Sprintf("Error %d", test.Status) {
+			t.Errorf("HTTP %d: RDAPError not decoded: %+v", test.Status, ce.RDAPError)
+		}
+	}
+
+	_, err := client.Do(NewRequest(DomainRequest, "422").WithServer(serverURL))
+	if expected := "RDAP server returned 422 Unprocessable Entity (Error 422 Try again later.)"; err == nil || err.Error() != expected {
+		t.Errorf("Got error %v, expected %s", err, expected)
+	}
+}
+
+func TestClientErrorIs(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	_, err := client.QueryDomain("non-existent.cz")
+
+	if !errors.Is(err, ErrObjectDoesNotExist) {
+		t.Errorf("Expected ErrObjectDoesNotExist, got %v", err)
+	} else if errors.Is(err, ErrNoWorkingServers) {
+		t.Errorf("Unexpected match of ErrNoWorkingServers")
+	}
+
+	var ce *ClientError
+	if !errors.As(err, &ce) {
+		t.Fatalf("Expected ClientError")
+	} else if ce.StatusCode != 404 || ce.URL != "https://rdap.nic.cz/domain/non-existent.cz" {
+		t.Errorf("Unexpected StatusCode=%d URL=%s", ce.StatusCode, ce.URL)
+	}
+
+	_, err = client.QueryDomain("malformed.cz")
+
+	var syntaxErr *json.SyntaxError
+	if !errors.Is(err, ErrNoWorkingServers) {
+		t.Errorf("Expected ErrNoWorkingServers, got %v", err)
+	} else if !errors.As(err, &syntaxErr) {
+		t.Errorf("Expected wrapped JSON syntax error, got %#v", errors.Unwrap(err))
+	}
+}
+
+func TestClientOffline(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose:     verboseFunc(),
+		ObjectCache: NewObjectCache(10, 0, 0),
+	}
+
+	if _, err := client.Do(NewDomainRequest("example.cz")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	client.Offline = true
+
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Errorf("Unexpected error for cached query: %s", err)
+	} else if !resp.Cached {
+		t.Errorf("Expected cached response")
+	}
+
+	_, err = client.Do(NewDomainRequest("fetch-roles.cz"))
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected ErrOffline, got %v", err)
+	}
+
+	_, err = client.Do(NewAutnumRequest(1768))
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("Expected ErrOffline for uncached bootstrap, got %v", err)
+	}
+
+	// The shared bootstrap client isn't modified.
+	if client.Bootstrap.Offline {
+		t.Errorf("Bootstrap.Offline set by Do()")
+	}
+}
+
+func TestClientHostParams(t *testing.T) {
+	var gotQuery url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.Write(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json"))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	var messages []string
+	client := &Client{
+		Verbose: func(text string) {
+			messages = append(messages, text)
+		},
+		HostParams: map[string]url.Values{
+			serverURL.Hostname(): {"apikey": {"secret"}},
+		},
+	}
+
+	req := NewDomainRequest("example.cz").WithServer(serverURL)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if gotQuery.Get("apikey") != "secret" {
+		t.Errorf("apikey not sent, got query %v", gotQuery)
+	}
+
+	if strings.Contains(resp.HTTP[0].URL, "secret") {
+		t.Errorf("apikey leaked into HTTPResponse.URL")
+	}
+
+	for _, m := range messages {
+		if strings.Contains(m, "secret") {
+			t.Errorf("apikey leaked into log message %q", m)
+		}
+	}
+}
+
+func TestClientEmbeddedBootstrap(t *testing.T) {
+	test.Start(test.BootstrapHTTPError)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose:   verboseFunc(),
+		Bootstrap: &bootstrap.Client{EnableEmbedded: true},
+	}
+
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if !resp.BootstrapAnswer.Embedded || len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "built-in copy") {
+		t.Errorf("Expected built-in bootstrap warning, got %v", resp.Warnings)
+	}
+}
+
+func TestClientLenientDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objectClassName": "domian", "ldhName": "example.cz"}`))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	for _, lenient := range []bool{false, true} {
+		client := &Client{Verbose: verboseFunc(), LenientDecoding: lenient}
+
+		req := NewDomainRequest("example.cz")
+		req.Server = serverURL
+
+		resp, err := client.Do(req)
+
+		if !lenient {
+			if err == nil {
+				t.Errorf("Expected decode failure without LenientDecoding")
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Unexpected error: %s", err)
+		} else if len(resp.Warnings) != 1 {
+			t.Errorf("Expected 1 warning, got %v", resp.Warnings)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 
 package rdap
 
+import "time"
+
 // RDAP Conformance
 // Appears in topmost JSON objects only, embedded (no separate type):
 // Conformance []string `rdap:"rdapConformance"`
@@ -31,6 +33,7 @@ type Link struct {
 type Notice struct {
 	DecodeData *DecodeData
 
+	Common
 	Title       string
 	Type        string
 	Description []string
@@ -43,6 +46,7 @@ type Notice struct {
 type Remark struct {
 	DecodeData *DecodeData
 
+	Common
 	Title       string
 	Type        string
 	Description []string
@@ -65,6 +69,9 @@ type Event struct {
 	Actor  string `rdap:"eventActor"`
 	Date   string `rdap:"eventDate"`
 	Links  []Link
+
+	// Date parsed by ParseDate(). Zero if Date is missing or unparsable.
+	Time time.Time `rdap:"-"`
 }
 
 // Status indicates the state of a registered object.
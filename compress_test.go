@@ -0,0 +1,50 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/openrdap/rdap/internal/httpbody"
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientCompressedResponse(t *testing.T) {
+	body := test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept-Encoding") != httpbody.AcceptEncoding {
+			http.Error(w, "expected Accept-Encoding", http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+		gw := gzip.NewWriter(w)
+		gw.Write(body)
+		gw.Close()
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	// Automatic decompression disabled.
+	client := &Client{
+		HTTP: &http.Client{Transport: &http.Transport{DisableCompression: true}},
+	}
+
+	resp, err := client.Do(NewDomainRequest("example.cz").WithServer(serverURL))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if d, ok := resp.Object.(*Domain); !ok || d.LDHName != "example.cz" {
+		t.Errorf("Unexpected response %v", resp.Object)
+	} else if !bytes.Equal(resp.HTTP[0].Body, body) {
+		t.Errorf("Body not decompressed")
+	}
+}
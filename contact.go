@@ -0,0 +1,291 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"sort"
+	"strings"
+)
+
+// Contact is a simplified contact, see Entity.Contact() and e.g.
+// Domain.Registrant().
+type Contact struct {
+	Name         string
+	Organization string
+	Email        string
+	Phone        string
+
+	// Postal address on one line, e.g. "1 Main St, Springfield, 12345, US".
+	Address string
+
+	// The Entity the contact details are from.
+	Entity *Entity
+}
+
+// Contact returns the Entity's contact details, resolved on a best-effort
+// basis from the first available source of each field:
+//
+//   - The jCard (VCard).
+//   - A JSContact card (the "jscontact_card" member, from the RDAP JSContact
+//     extension).
+//   - Remarks with "Key: value" lines, e.g. "Email: abuse@example.net".
+//
+// Fields are left empty if not found, e.g. when the Entity has no vCard.
+func (e *Entity) Contact() *Contact {
+	c := &Contact{Entity: e}
+
+	if v := e.VCard; v != nil {
+		c.Name = v.Name()
+		c.Organization = v.Org()
+		c.Email = v.Email()
+		c.Phone = v.Tel()
+		c.Address = v.preferredAddress().String()
+	}
+
+	if e.DecodeData != nil {
+		if card, ok := e.DecodeData.Value("jscontact_card").(map[string]interface{}); ok {
+			c.fillFrom(jsContactDetails(card))
+		}
+	}
+
+	for _, r := range e.Remarks {
+		c.fillFrom(remarkDetails(r))
+	}
+
+	return c
+}
+
+// fillFrom sets c's empty fields from |other|.
+func (c *Contact) fillFrom(other Contact) {
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&c.Name, other.Name},
+		{&c.Organization, other.Organization},
+		{&c.Email, other.Email},
+		{&c.Phone, other.Phone},
+		{&c.Address, other.Address},
+	} {
+		if *f.dst == "" {
+			*f.dst = f.src
+		}
+	}
+}
+
+// jsContactDetails returns the contact details of the JSContact card |card|
+// (https://tools.ietf.org/html/rfc9553).
+func jsContactDetails(card map[string]interface{}) Contact {
+	var c Contact
+
+	if name, ok := card["name"].(map[string]interface{}); ok {
+		c.Name, _ = name["full"].(string)
+		if c.Name == "" {
+			c.Name = jsContactComponents(name, " ")
+		}
+	}
+
+	for _, org := range jsContactEntries(card, "organizations") {
+		if c.Organization, _ = org["name"].(string); c.Organization != "" {
+			break
+		}
+	}
+
+	for _, email := range jsContactEntries(card, "emails") {
+		if c.Email, _ = email["address"].(string); c.Email != "" {
+			break
+		}
+	}
+
+	for _, phone := range jsContactEntries(card, "phones") {
+		// Voice numbers only, i.e. no features, or the "voice" feature.
+		if features, ok := phone["features"].(map[string]interface{}); ok && features["voice"] != true {
+			continue
+		}
+
+		if c.Phone, _ = phone["number"].(string); c.Phone != "" {
+			break
+		}
+	}
+
+	for _, address := range jsContactEntries(card, "addresses") {
+		c.Address, _ = address["full"].(string)
+		if c.Address == "" {
+			c.Address = jsContactComponents(address, ", ")
+		}
+
+		if c.Address != "" {
+			break
+		}
+	}
+
+	return c
+}
+
+// jsContactEntries returns the objects in the JSContact map |name| of |card|
+// (e.g. "emails"), ordered by key.
+func jsContactEntries(card map[string]interface{}, name string) []map[string]interface{} {
+	m, ok := card[name].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var entries []map[string]interface{}
+	for _, k := range keys {
+		if entry, ok := m[k].(map[string]interface{}); ok {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// jsContactComponents returns the values of |obj|'s JSContact "components",
+// joined by |sep|.
+func jsContactComponents(obj map[string]interface{}, sep string) string {
+	components, _ := obj["components"].([]interface{})
+
+	var values []string
+	for _, component := range components {
+		if c, ok := component.(map[string]interface{}); ok {
+			if value, ok := c["value"].(string); ok && value != "" && c["kind"] != "separator" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return strings.Join(values, sep)
+}
+
+// remarkDetails returns the contact details in the Remark |r|'s "Key: value"
+// description lines.
+func remarkDetails(r Remark) Contact {
+	var c Contact
+
+	for _, line := range r.Description {
+		key, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+		if !ok || value == "" {
+			continue
+		}
+
+		var dst *string
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "name", "contact", "person":
+			dst = &c.Name
+		case "organization", "organisation", "org":
+			dst = &c.Organization
+		case "email", "e-mail":
+			dst = &c.Email
+		case "phone", "telephone", "tel":
+			dst = &c.Phone
+		case "address":
+			dst = &c.Address
+		}
+
+		if dst != nil && *dst == "" {
+			*dst = value
+		}
+	}
+
+	return c
+}
+
+// findEntityWithRole returns the first Entity in |entities| with the role
+// |role|, or nil if none.
+//
+// Top level entities are checked first, then nested entities, e.g. the abuse
+// contact of a registrar.
+func findEntityWithRole(entities []Entity, role string) *Entity {
+	for i := range entities {
+		if hasRole(&entities[i], role) {
+			return &entities[i]
+		}
+	}
+
+	for i := range entities {
+		if e := findEntityWithRole(entities[i].Entities, role); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// contactWithRole returns the Contact of the first Entity in |entities| with
+// the role |role|, or nil if none.
+func contactWithRole(entities []Entity, role string) *Contact {
+	if e := findEntityWithRole(entities, role); e != nil {
+		return e.Contact()
+	}
+
+	return nil
+}
+
+// Registrant returns the Domain's registrant contact, or nil if none.
+func (d *Domain) Registrant() *Contact {
+	return contactWithRole(d.Entities, "registrant")
+}
+
+// Admin returns the Domain's administrative contact, or nil if none.
+func (d *Domain) Admin() *Contact {
+	return contactWithRole(d.Entities, "administrative")
+}
+
+// Tech returns the Domain's technical contact, or nil if none.
+func (d *Domain) Tech() *Contact {
+	return contactWithRole(d.Entities, "technical")
+}
+
+// Billing returns the Domain's billing contact, or nil if none.
+func (d *Domain) Billing() *Contact {
+	return contactWithRole(d.Entities, "billing")
+}
+
+// Registrant returns the IPNetwork's registrant contact, or nil if none.
+func (n *IPNetwork) Registrant() *Contact {
+	return contactWithRole(n.Entities, "registrant")
+}
+
+// Admin returns the IPNetwork's administrative contact, or nil if none.
+func (n *IPNetwork) Admin() *Contact {
+	return contactWithRole(n.Entities, "administrative")
+}
+
+// Tech returns the IPNetwork's technical contact, or nil if none.
+func (n *IPNetwork) Tech() *Contact {
+	return contactWithRole(n.Entities, "technical")
+}
+
+// Abuse returns the IPNetwork's abuse contact, or nil if none.
+func (n *IPNetwork) Abuse() *Contact {
+	return contactWithRole(n.Entities, "abuse")
+}
+
+// Registrant returns the Autnum's registrant contact, or nil if none.
+func (a *Autnum) Registrant() *Contact {
+	return contactWithRole(a.Entities, "registrant")
+}
+
+// Admin returns the Autnum's administrative contact, or nil if none.
+func (a *Autnum) Admin() *Contact {
+	return contactWithRole(a.Entities, "administrative")
+}
+
+// Tech returns the Autnum's technical contact, or nil if none.
+func (a *Autnum) Tech() *Contact {
+	return contactWithRole(a.Entities, "technical")
+}
+
+// Abuse returns the Autnum's abuse contact, or nil if none.
+func (a *Autnum) Abuse() *Contact {
+	return contactWithRole(a.Entities, "abuse")
+}
@@ -0,0 +1,112 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+func TestContactAccessors(t *testing.T) {
+	n := decodeTestObject(t, `{
+		"objectClassName": "ip network",
+		"entities": [
+			{
+				"objectClassName": "entity",
+				"handle": "EXAMPLE-ORG",
+				"roles": ["registrant"],
+				"vcardArray": ["vcard", [
+					["version", {}, "text", "4.0"],
+					["fn", {}, "text", "Example Org"],
+					["org", {}, "text", "Example Org Inc"],
+					["adr", {}, "text", ["", "Suite 1", "1 Main St", "Springfield", "", "12345", "US"]]
+				]],
+				"entities": [
+					{
+						"objectClassName": "entity",
+						"handle": "ABUSE-1",
+						"roles": ["abuse"],
+						"vcardArray": ["vcard", [
+							["version", {}, "text", "4.0"],
+							["fn", {}, "text", "Abuse Desk"],
+							["email", {}, "text", "abuse@example.net"],
+							["tel", {"type": "voice"}, "uri", "tel:+1-555-0100"]
+						]]
+					},
+					{
+						"objectClassName": "entity",
+						"handle": "TECH-1",
+						"roles": ["technical"],
+						"vcardArray": ["vcard", [
+							["version", {}, "text", "4.0"],
+							["fn", {}, "text", "Tech Team"],
+							["adr", {"label": "2 High St\nSpringfield"}, "text", ["", "", "", "", "", "", ""]]
+						]]
+					}
+				]
+			},
+			{"objectClassName": "entity", "handle": "TECH-2", "roles": ["technical"]}
+		]
+	}`).(*IPNetwork)
+
+	registrant := n.Registrant()
+	if registrant == nil || registrant.Name != "Example Org" || registrant.Organization != "Example Org Inc" ||
+		registrant.Address != "Suite 1, 1 Main St, Springfield, 12345, US" {
+		t.Errorf("Unexpected registrant %+v", registrant)
+	}
+
+	abuse := n.Abuse()
+	if abuse == nil || abuse.Email != "abuse@example.net" || abuse.Phone != "tel:+1-555-0100" || abuse.Entity.Handle != "ABUSE-1" {
+		t.Errorf("Unexpected abuse contact %+v", abuse)
+	}
+
+	// Top level entities take precedence over nested entities.
+	if tech := n.Tech(); tech == nil || tech.Entity.Handle != "TECH-2" || tech.Name != "" {
+		t.Errorf("Unexpected tech contact %+v", tech)
+	}
+
+	if tech := n.Entities[0].Entities[1].Contact(); tech.Address != "2 High St Springfield" {
+		t.Errorf("Unexpected address label %q", tech.Address)
+	}
+
+	if admin := n.Admin(); admin != nil {
+		t.Errorf("Unexpected admin contact %+v", admin)
+	}
+}
+
+func TestEntityContact(t *testing.T) {
+	e := decodeTestObject(t, `{
+		"objectClassName": "entity",
+		"handle": "JS-1",
+		"jscontact_card": {
+			"@type": "Card",
+			"version": "1.0",
+			"name": {"components": [{"kind": "given", "value": "Jane"}, {"kind": "surname", "value": "Doe"}]},
+			"organizations": {"org": {"name": "Example Ltd"}},
+			"phones": {
+				"fax": {"features": {"fax": true}, "number": "tel:+1-555-0199"},
+				"voice": {"features": {"voice": true}, "number": "tel:+1-555-0100"}
+			},
+			"addresses": {"addr": {"full": "1 Main St, Springfield"}}
+		},
+		"remarks": [
+			{"title": "Contact", "description": ["Email: jane@example.com", "Name: Ignored"]}
+		]
+	}`).(*Entity)
+
+	expected := Contact{
+		Name:         "Jane Doe",
+		Organization: "Example Ltd",
+		Email:        "jane@example.com",
+		Phone:        "tel:+1-555-0100",
+		Address:      "1 Main St, Springfield",
+		Entity:       e,
+	}
+
+	if c := e.Contact(); *c != expected {
+		t.Errorf("Got contact %+v, expected %+v", *c, expected)
+	}
+
+	if c := (&Entity{}).Contact(); c.Name != "" || c.Email != "" {
+		t.Errorf("Expected empty contact, got %+v", c)
+	}
+}
@@ -0,0 +1,149 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ExtensionFunc decodes the extension member |member| (e.g.
+// "regid_registrant") with the raw JSON value |value|, see
+// RegisterExtensionFunc().
+type ExtensionFunc func(member string, value json.RawMessage) (interface{}, error)
+
+// extensionDecoder decodes the members of a registered extension.
+type extensionDecoder struct {
+	prefix string
+
+	// Exactly one of these is set.
+	targetType reflect.Type
+	fn         ExtensionFunc
+}
+
+var (
+	extensionDecodersMu sync.RWMutex
+	extensionDecoders   []extensionDecoder
+)
+
+// RegisterExtension registers the Go type of |target| for decoding the
+// members of the RDAP extension |prefix|, e.g. for registry proprietary
+// extensions.
+//
+// Unknown members named |prefix| or starting with |prefix|+"_" (as per RFC
+// 9083 section 2.1) are decoded into a new value of |target|'s type, using the
+// usual decoding rules (including "rdap" struct tags, and a DecodeData
+// field). The result, a pointer, is attached to the containing object, see
+// DecodeData.Extension(). e.g.:
+//
+//	type RegIDInfo struct {
+//	  DecodeData *rdap.DecodeData
+//
+//	  ID   string `rdap:"id"`
+//	  Kind string
+//	}
+//
+//	rdap.RegisterExtension("regid", RegIDInfo{})
+//
+//	...
+//	info := domain.DecodeData.Extension("regid_info").(*RegIDInfo)
+//
+// |target|'s fields must be of types the Decoder supports: strings, numbers,
+// bools, and structs, pointers, slices, and maps of these. For anything else,
+// see RegisterExtensionFunc().
+//
+// Registration applies to every Decoder (and so every Client) created
+// afterwards. The members remain available as unknown fields.
+func RegisterExtension(prefix string, target interface{}) {
+	t := reflect.TypeOf(target)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	registerExtensionDecoder(extensionDecoder{prefix: prefix, targetType: t})
+}
+
+// RegisterExtensionFunc registers |fn| for decoding the members of the RDAP
+// extension |prefix|, as per RegisterExtension().
+//
+// |fn|'s result is attached to the containing object, see
+// DecodeData.Extension(). Errors are recorded as decode notes.
+func RegisterExtensionFunc(prefix string, fn ExtensionFunc) {
+	registerExtensionDecoder(extensionDecoder{prefix: prefix, fn: fn})
+}
+
+// UnregisterExtension removes the registration of the extension |prefix|.
+func UnregisterExtension(prefix string) {
+	extensionDecodersMu.Lock()
+	defer extensionDecodersMu.Unlock()
+
+	var result []extensionDecoder
+	for _, e := range extensionDecoders {
+		if e.prefix != prefix {
+			result = append(result, e)
+		}
+	}
+
+	extensionDecoders = result
+}
+
+func registerExtensionDecoder(e extensionDecoder) {
+	UnregisterExtension(e.prefix)
+
+	extensionDecodersMu.Lock()
+	defer extensionDecodersMu.Unlock()
+
+	extensionDecoders = append(extensionDecoders, e)
+}
+
+// registeredExtensionDecoders returns a snapshot of the registered
+// extension decoders.
+func registeredExtensionDecoders() []extensionDecoder {
+	extensionDecodersMu.RLock()
+	defer extensionDecodersMu.RUnlock()
+
+	return append([]extensionDecoder{}, extensionDecoders...)
+}
+
+// decodeExtension decodes the unknown member |name| with value |src| if it
+// belongs to a registered extension, attaching the result to |decodeData|.
+func (d *Decoder) decodeExtension(name string, src interface{}, decodeData *DecodeData) error {
+	for _, e := range d.extensions {
+		if name != e.prefix && !strings.HasPrefix(name, e.prefix+"_") {
+			continue
+		}
+
+		if e.fn != nil {
+			raw, err := json.Marshal(src)
+			if err != nil {
+				return err
+			}
+
+			result, err := e.fn(name, raw)
+			if err != nil {
+				d.addDecodeNote(decodeData, name, fmt.Sprintf("extension %s: %s", e.prefix, err))
+				return nil
+			}
+
+			decodeData.setExtension(name, result)
+			return nil
+		}
+
+		result := reflect.New(e.targetType)
+		success, err := d.decode(name, src, result.Elem(), decodeData)
+		if err != nil {
+			return err
+		} else if success {
+			decodeData.setExtension(name, result.Interface())
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type testRegIDInfo struct {
+	DecodeData *DecodeData
+
+	ID    string `rdap:"id"`
+	Kinds []string
+}
+
+func TestRegisterExtension(t *testing.T) {
+	RegisterExtension("regid", &testRegIDInfo{})
+	defer UnregisterExtension("regid")
+
+	RegisterExtensionFunc("platformNS", func(member string, value json.RawMessage) (interface{}, error) {
+		var names []string
+		if err := json.Unmarshal(value, &names); err != nil {
+			return nil, errors.New("expecting array of strings")
+		}
+
+		return names, nil
+	})
+	defer UnregisterExtension("platformNS")
+
+	d := decodeTestObject(t, `{
+		"objectClassName": "domain",
+		"ldhName": "example.com",
+		"regid_info": {"id": "R-1", "kinds": ["a", "b"], "extra": true},
+		"regidx_other": {"id": "not matched"},
+		"platformNS_nameservers": ["ns1.example.com", "ns2.example.com"],
+		"platformNS_bad": 42,
+		"entities": [
+			{"objectClassName": "entity", "handle": "X", "regid": {"id": "R-2"}}
+		]
+	}`).(*Domain)
+
+	info, ok := d.DecodeData.Extension("regid_info").(*testRegIDInfo)
+	if !ok || info.ID != "R-1" || len(info.Kinds) != 2 {
+		t.Fatalf("regid_info not decoded: %#v", d.DecodeData.Extension("regid_info"))
+	}
+
+	if len(info.DecodeData.UnknownFields()) != 1 {
+		t.Errorf("Expected unknown field in extension, got %v", info.DecodeData.UnknownFields())
+	}
+
+	if d.DecodeData.Extension("regidx_other") != nil {
+		t.Errorf("Unexpected decode of regidx_other")
+	}
+
+	if names, ok := d.DecodeData.Extension("platformNS_nameservers").([]string); !ok || len(names) != 2 {
+		t.Errorf("platformNS_nameservers not decoded: %v", d.DecodeData.Extension("platformNS_nameservers"))
+	}
+
+	if notes := d.DecodeData.Notes("platformNS_bad"); len(notes) != 1 || notes[0] != "extension platformNS: expecting array of strings" {
+		t.Errorf("Got notes %v", notes)
+	}
+
+	if info, ok := d.Entities[0].DecodeData.Extension("regid").(*testRegIDInfo); !ok || info.ID != "R-2" {
+		t.Errorf("Nested regid not decoded")
+	}
+
+	if len(d.DecodeData.Extensions()) != 2 {
+		t.Errorf("Got extensions %v", d.DecodeData.Extensions())
+	}
+
+	UnregisterExtension("regid")
+	d = decodeTestObject(t, `{"objectClassName": "domain", "regid_info": {"id": "R-1"}}`).(*Domain)
+	if d.DecodeData.Extension("regid_info") != nil {
+		t.Errorf("Extension decoded after UnregisterExtension")
+	}
+}
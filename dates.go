@@ -0,0 +1,79 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// dateFormats are the date formats accepted by ParseDate(), in order.
+//
+// RDAP requires RFC 3339, but real servers also omit the time zone, use a
+// space separator, or return just the date.
+var dateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// ParseDate parses the RDAP date |s|, e.g. an Event's eventDate.
+//
+// RFC 3339 dates (e.g. "2017-01-23T09:41:00Z") are expected, but several
+// variants used by real servers are also accepted. Dates without a time zone
+// are assumed to be UTC.
+func ParseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	for _, format := range dateFormats {
+		if t, err := time.Parse(format, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unparsable date %q", s)
+}
+
+// EventTime returns the time of the first event in |events| with the
+// eventAction |action| (e.g. "expiration"), case insensitively.
+//
+// Returns the zero time if there's no such event, or its date is unparsable.
+func EventTime(events []Event, action string) time.Time {
+	for _, e := range events {
+		if strings.EqualFold(e.Action, action) {
+			if !e.Time.IsZero() || e.Date == "" {
+				return e.Time
+			}
+
+			t, _ := ParseDate(e.Date)
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// RegistrationDate returns the time of the Domain's "registration" event,
+// or the zero time if unknown.
+func (d *Domain) RegistrationDate() time.Time {
+	return EventTime(d.Events, "registration")
+}
+
+// ExpirationDate returns the time of the Domain's "expiration" event, or the
+// zero time if unknown.
+func (d *Domain) ExpirationDate() time.Time {
+	return EventTime(d.Events, "expiration")
+}
+
+// LastChangedDate returns the time of the Domain's "last changed" event, or
+// the zero time if unknown.
+func (d *Domain) LastChangedDate() time.Time {
+	return EventTime(d.Events, "last changed")
+}
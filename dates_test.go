@@ -0,0 +1,63 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	expected := time.Date(2017, 1, 23, 9, 41, 0, 0, time.UTC)
+
+	for _, s := range []string{
+		"2017-01-23T09:41:00Z",
+		"2017-01-23T10:41:00+01:00",
+		"2017-01-23T09:41:00.000Z",
+		"2017-01-23T10:41:00+0100",
+		"2017-01-23T09:41:00",
+		"2017-01-23 09:41:00",
+		" 2017-01-23T09:41:00Z ",
+	} {
+		if actual, err := ParseDate(s); err != nil {
+			t.Errorf("ParseDate(%q) error: %s", s, err)
+		} else if !actual.Equal(expected) {
+			t.Errorf("ParseDate(%q) = %s, expected %s", s, actual, expected)
+		}
+	}
+
+	if actual, err := ParseDate("2017-01-23"); err != nil || !actual.Equal(time.Date(2017, 1, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseDate of a date only failed: %s, %v", actual, err)
+	}
+
+	if _, err := ParseDate("last tuesday"); err == nil {
+		t.Errorf("Expected error for unparsable date")
+	}
+}
+
+func TestDomainDates(t *testing.T) {
+	domain := decodeTestObject(t, `{
+		"objectClassName": "domain",
+		"events": [
+			{"eventAction": "registration", "eventDate": "1998-03-01T00:00:00Z"},
+			{"eventAction": "Expiration", "eventDate": "2030-03-01 00:00:00"},
+			{"eventAction": "last changed", "eventDate": "unknown"}
+		]
+	}`).(*Domain)
+
+	if !domain.Events[0].Time.Equal(time.Date(1998, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Event.Time not set: %s", domain.Events[0].Time)
+	} else if len(domain.Events[2].DecodeData.Notes("eventDate")) != 1 {
+		t.Errorf("Expected decode note for unparsable date")
+	}
+
+	if y := domain.RegistrationDate().Year(); y != 1998 {
+		t.Errorf("RegistrationDate year %d, expected 1998", y)
+	} else if y := domain.ExpirationDate().Year(); y != 2030 {
+		t.Errorf("ExpirationDate year %d, expected 2030", y)
+	} else if !domain.LastChangedDate().IsZero() {
+		t.Errorf("Expected zero LastChangedDate")
+	}
+}
@@ -4,6 +4,12 @@
 
 package rdap
 
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
 // DecodeData stores a snapshot of all fields in an RDAP object (in raw
 // interface{} form), at the time of decoding. This allows the values of unknown
 // fields to be retrieved.
@@ -21,6 +27,12 @@ type DecodeData struct {
 	values             map[string]interface{}
 	overrideKnownValue map[string]bool
 	notes              map[string][]string
+
+	// JSON Pointer of the object within the response, see Path().
+	path string
+
+	// Decoded extension members, see RegisterExtension().
+	extensions map[string]interface{}
 }
 
 // TODO (temporary, using for spew output)
@@ -51,10 +63,52 @@ func (r DecodeData) Notes(name string) []string {
 	return nil
 }
 
-//func (r DecodeData) OverrideValue(key string, value interface{}) {
-//	r.values[key] = value
-//	r.overrideKnownValue[key] = true
-//}
+// NewDecodeData returns an empty DecodeData, for manually constructed RDAP
+// structs which need SetValue().
+func NewDecodeData() *DecodeData {
+	r := &DecodeData{}
+	r.init()
+
+	return r
+}
+
+// SetValue sets the raw value of the field |name|, e.g. to add a registry
+// specific extension field to a response being re-encoded (see Encode()).
+//
+// |name| is the RDAP field name. If it's a known field (e.g. "port43"),
+// |value| overrides the Go field's value when encoding.
+//
+// |value| must be encodable by encoding/json.
+func (r *DecodeData) SetValue(name string, value interface{}) {
+	if r.values == nil {
+		r.init()
+	}
+
+	r.values[name] = value
+	r.overrideKnownValue[name] = true
+}
+
+// rawValue returns the raw value to encode for the known field |name|, in
+// place of the Go field's value |decoded|.
+//
+// This is the SetValue() override if any, otherwise the original value if
+// the field failed to decode (e.g. a string where an array was expected), so
+// it isn't silently dropped.
+func (r *DecodeData) rawValue(name string, decodedIsEmpty bool) (interface{}, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	value, exists := r.values[name]
+
+	if r.overrideKnownValue[name] {
+		return value, true
+	} else if exists && value != nil && decodedIsEmpty && len(r.notes[name]) > 0 {
+		return value, true
+	}
+
+	return nil, false
+}
 
 // Value returns the value of the field |name| as an interface{}.
 //
@@ -68,6 +122,103 @@ func (r DecodeData) Value(name string) interface{} {
 	return nil
 }
 
+// Path returns the JSON Pointer (RFC 6901) of the object within the RDAP
+// response, e.g. "/entities/0/entities/1". The top level object's path is
+// empty string.
+func (r DecodeData) Path() string {
+	return r.path
+}
+
+// FieldPath returns the JSON Pointer of the field |name| within the RDAP
+// response, e.g. "/entities/0/handle".
+func (r DecodeData) FieldPath(name string) string {
+	return r.path + jsonPointer([]string{name})
+}
+
+// RawValue returns the raw JSON of the field |name|, or nil if the field
+// isn't present.
+//
+// The raw JSON is re-encoded from the snapshot value, so object members are
+// in sorted order, and insignificant whitespace is removed.
+func (r DecodeData) RawValue(name string) json.RawMessage {
+	return r.LookupRaw(jsonPointer([]string{name}))
+}
+
+// Lookup returns the value at the JSON Pointer |pointer|, relative to the
+// object, e.g. "/fred_nsset/nameservers/0/ldhName". Useful for registry
+// specific extension members.
+//
+// The value is as per Value(). Returns false if there's no such value.
+func (r DecodeData) Lookup(pointer string) (interface{}, bool) {
+	if pointer == "" || pointer[0] != '/' {
+		return nil, false
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	value, exists := r.values[unescapeJSONPointer(tokens[0])]
+	if !exists {
+		return nil, false
+	}
+
+	for _, token := range tokens[1:] {
+		token = unescapeJSONPointer(token)
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if value, exists = v[token]; !exists {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// LookupRaw returns the raw JSON at the JSON Pointer |pointer| (see Lookup()
+// and RawValue()), or nil if there's no such value.
+func (r DecodeData) LookupRaw(pointer string) json.RawMessage {
+	value, ok := r.Lookup(pointer)
+	if !ok {
+		return nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+
+	return raw
+}
+
+// Extension returns the decoded value of the extension member |name| (e.g.
+// "regid_info"), or nil if there's none. See RegisterExtension().
+func (r DecodeData) Extension(name string) interface{} {
+	return r.extensions[name]
+}
+
+// Extensions returns the decoded values of all the object's extension
+// members, keyed by member name. See RegisterExtension().
+func (r DecodeData) Extensions() map[string]interface{} {
+	return r.extensions
+}
+
+func (r *DecodeData) setExtension(name string, value interface{}) {
+	if r.extensions == nil {
+		r.extensions = map[string]interface{}{}
+	}
+
+	r.extensions[name] = value
+}
+
 // Fields returns a list of all RDAP field names decoded.
 //
 // This includes both known/unknown fields.
@@ -103,3 +254,19 @@ func (r *DecodeData) init() {
 	r.overrideKnownValue = map[string]bool{}
 	r.notes = map[string][]string{}
 }
+
+// jsonPointer returns the JSON Pointer (RFC 6901) of the reference |tokens|.
+func jsonPointer(tokens []string) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString("/")
+		b.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(t))
+	}
+
+	return b.String()
+}
+
+// unescapeJSONPointer unescapes the JSON Pointer reference token |token|.
+func unescapeJSONPointer(token string) string {
+	return strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+}
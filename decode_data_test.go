@@ -0,0 +1,54 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+func TestDecodeDataPaths(t *testing.T) {
+	d := decodeTestObject(t, `{
+		"objectClassName": "domain",
+		"ldhName": "example.cz",
+		"entities": [
+			{"objectClassName": "entity", "handle": "A"},
+			{"objectClassName": "entity", "handle": "B", "entities": [{"objectClassName": "entity", "handle": "C"}]}
+		],
+		"example_ext": {"a/b": [{"id": 10}, {"id": 20}], "name": "x"}
+	}`).(*Domain)
+
+	c := d.Entities[1].Entities[0]
+	if p := c.DecodeData.Path(); p != "/entities/1/entities/0" {
+		t.Errorf("Got path %q", p)
+	}
+
+	if p := c.DecodeData.FieldPath("handle"); p != "/entities/1/entities/0/handle" {
+		t.Errorf("Got field path %q", p)
+	}
+
+	if p := d.DecodeData.Path(); p != "" {
+		t.Errorf("Got top level path %q", p)
+	}
+
+	if raw := string(d.DecodeData.RawValue("example_ext")); raw != `{"a/b":[{"id":10},{"id":20}],"name":"x"}` {
+		t.Errorf("Got raw value %s", raw)
+	}
+
+	if v, ok := d.DecodeData.Lookup("/example_ext/a~1b/1/id"); !ok || v != float64(20) {
+		t.Errorf("Lookup returned %v, %t", v, ok)
+	}
+
+	if raw := string(d.DecodeData.LookupRaw("/example_ext/a~1b/0")); raw != `{"id":10}` {
+		t.Errorf("LookupRaw returned %s", raw)
+	}
+
+	for _, pointer := range []string{"", "example_ext", "/missing", "/example_ext/a~1b/2", "/example_ext/name/0"} {
+		if _, ok := d.DecodeData.Lookup(pointer); ok {
+			t.Errorf("Unexpected Lookup(%q) success", pointer)
+		}
+	}
+
+	if d.DecodeData.RawValue("missing") != nil {
+		t.Errorf("Expected nil RawValue for missing field")
+	}
+}
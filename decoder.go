@@ -6,7 +6,9 @@ package rdap
 
 import (
 	"encoding/json"
+	"fmt"
 	"math"
+	"net/netip"
 	"reflect"
 	"strconv"
 	"strings"
@@ -47,9 +49,13 @@ import (
 //	&rdap.Entity{}                  - Responses with objectClassName="entity".
 //	&rdap.IPNetwork{}               - Responses with objectClassName="ip network".
 //	&rdap.Nameserver{}              - Responses with objectClassName="nameserver".
+//	&rdap.FredNSSet{}               - Responses with objectClassName="fred_nsset".
+//	&rdap.FredKeySet{}              - Responses with objectClassName="fred_keyset".
 //	&rdap.DomainSearchResults{}     - Responses with a domainSearchResults array.
 //	&rdap.EntitySearchResults{}     - Responses with a entitySearchResults array.
 //	&rdap.NameserverSearchResults{} - Responses with a nameserverSearchResults array.
+//	&rdap.IPNetworkSearchResults{}  - Responses with a networkSearchResults array.
+//	&rdap.AutnumSearchResults{}     - Responses with an autnumSearchResults array.
 //	&rdap.Help{}                    - All other valid JSON responses.
 //
 // Note that an RDAP server may return a different response type than expected.
@@ -59,9 +65,38 @@ import (
 //
 // Decoding is performed on a best-effort basis, with "minor error"s ignored.
 // This avoids minor errors rendering a response undecodable.
+//
+// IP network responses are normalized to smooth over known differences
+// between the RIRs (e.g. upper case status values), with decode notes
+// recording each change.
 type Decoder struct {
 	data   []byte
 	target interface{}
+
+	// Most recently decoded field name, for PanicError diagnostics.
+	lastKey string
+
+	// Validate against RFC 9083 first? See StrictDecoding().
+	strict bool
+
+	// Never abort decoding? See LenientDecoding().
+	lenient  bool
+	warnings []string
+
+	// Resource limits, see LimitDecoding(), and the current entity nesting
+	// depth.
+	limits      DecoderLimits
+	entityDepth int
+
+	// JSON Pointer reference tokens of the value being decoded, e.g.
+	// ["entities", "0"].
+	path []string
+
+	// Tolerate a missing/unusual objectClassName? See InferObjectClass().
+	inferClass bool
+
+	// Registered extension decoders, see RegisterExtension().
+	extensions []extensionDecoder
 }
 
 // DecoderOption sets a Decoder option.
@@ -76,12 +111,31 @@ func (d DecoderError) Error() string {
 	return d.text
 }
 
+// LenientDecoding returns a DecoderOption which makes decoding never fail
+// for a structurally bad (but syntactically valid JSON) response.
+//
+// Normally, an unrecognised objectClassName is a fatal error, and a jCard
+// with any invalid property is dropped entirely. With LenientDecoding:
+//
+//   - Objects with an unrecognised objectClassName are decoded as a Help.
+//   - Invalid jCard properties are skipped, keeping the rest of the jCard.
+//   - A field which fails to decode (including by panicking) is skipped.
+//
+// Every problem is recorded in the DecodeData notes as usual, and also
+// collected, see Decoder.Warnings().
+func LenientDecoding() DecoderOption {
+	return func(d *Decoder) {
+		d.lenient = true
+	}
+}
+
 // NewDecoder creates a new Decoder to decode the RDAP response |jsonBlob|.
 //
 // |opts| is an optional list of DecoderOptions.
 func NewDecoder(jsonBlob []byte, opts ...DecoderOption) *Decoder {
 	d := &Decoder{
-		data: jsonBlob,
+		data:       jsonBlob,
+		extensions: registeredExtensionDecoders(),
 	}
 
 	// Run the DecoderOption func()s.
@@ -103,9 +157,13 @@ func NewDecoder(jsonBlob []byte, opts ...DecoderOption) *Decoder {
 //	&rdap.Entity{}                  - Responses with objectClassName="entity".
 //	&rdap.IPNetwork{}               - Responses with objectClassName="ip network".
 //	&rdap.Nameserver{}              - Responses with objectClassName="nameserver".
+//	&rdap.FredNSSet{}               - Responses with objectClassName="fred_nsset".
+//	&rdap.FredKeySet{}              - Responses with objectClassName="fred_keyset".
 //	&rdap.DomainSearchResults{}     - Responses with a domainSearchResults array.
 //	&rdap.EntitySearchResults{}     - Responses with a entitySearchResults array.
 //	&rdap.NameserverSearchResults{} - Responses with a nameserverSearchResults array.
+//	&rdap.IPNetworkSearchResults{}  - Responses with a networkSearchResults array.
+//	&rdap.AutnumSearchResults{}     - Responses with an autnumSearchResults array.
 //	&rdap.Help{}                    - All other valid JSON responses.
 //
 // On serious errors (e.g. JSON syntax error) an error is returned. Otherwise,
@@ -115,9 +173,28 @@ func NewDecoder(jsonBlob []byte, opts ...DecoderOption) *Decoder {
 //
 // Minor error messages (e.g. type conversions, type errors) are embedded within
 // each result struct, see the DecodeData fields.
-func (d *Decoder) Decode() (interface{}, error) {
+//
+// With the StrictDecoding option, responses which don't conform to RFC 9083
+// are rejected with a *ValidationError instead. With the LenientDecoding
+// option, even structural errors (e.g. an unrecognised objectClassName) are
+// ignored. With the InferObjectClass option, a missing or unusual
+// objectClassName is tolerated. With the LimitDecoding option, responses
+// exceeding the resource limits are rejected.
+//
+// Should decoding panic, the panic is recovered and a *PanicError returned.
+func (d *Decoder) Decode() (result interface{}, err error) {
 	var s map[string]interface{}
-	var err error
+
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = newPanicError("decode", r, d.data, d.lastKey)
+		}
+	}()
+
+	if err = d.limits.checkLimits(d.data); err != nil {
+		return nil, err
+	}
 
 	// Unmarshal the JSON document.
 	err = json.Unmarshal(d.data, &s)
@@ -125,38 +202,70 @@ func (d *Decoder) Decode() (interface{}, error) {
 		return nil, err
 	}
 
+	d.warnings = nil
+	d.entityDepth = 0
+	d.path = nil
+
+	if d.strict {
+		violations, _ := ValidateResponse(d.data)
+		if len(violations) > 0 {
+			return nil, &ValidationError{Violations: violations}
+		}
+	}
+
 	// Decode the RDAP response.
-	var result interface{}
 	result, err = d.decodeTopLevel(s)
 
 	return result, err
 }
 
+// Warnings returns the problems encountered by the most recent Decode() call,
+// e.g. "port43: invalid JSON type, expecting string". Only collected with the
+// LenientDecoding option.
+func (d *Decoder) Warnings() []string {
+	return d.warnings
+}
+
 // decodeTopLevel decodes the top level object |src|.
 func (d *Decoder) decodeTopLevel(src map[string]interface{}) (interface{}, error) {
 	// Choose the target struct type.
+	var classNote string
+	var objectClassName string
+
 	if d.target != nil {
 		// Target already selected, e.g. tests use this.
 	} else if _, exists := src["errorCode"]; exists {
 		d.target = &Error{}
 	} else if o, exists := src["objectClassName"]; exists {
-		if objectClassName, ok := o.(string); ok {
-			switch objectClassName {
-			case "autnum":
-				d.target = &Autnum{}
-			case "domain":
-				d.target = &Domain{}
-			case "entity":
-				d.target = &Entity{}
-			case "ip network":
-				d.target = &IPNetwork{}
-			case "nameserver":
-				d.target = &Nameserver{}
-			default:
-				return nil, DecoderError{text: "objectClassName is not recognised"}
+		var ok bool
+		if objectClassName, ok = o.(string); ok {
+			if d.inferClass {
+				if normalized := normalizeObjectClassName(objectClassName); normalized != objectClassName {
+					classNote = "normalized objectClassName '" + objectClassName + "'"
+					objectClassName = normalized
+				}
 			}
-		} else {
+
+			d.target = objectOfClass(objectClassName)
+		}
+
+		if d.target == nil && d.inferClass {
+			if objectClassName = inferObjectClass(src); objectClassName != "" {
+				classNote = "objectClassName not recognised, inferred from structure"
+				d.target = objectOfClass(objectClassName)
+			}
+		}
+
+		if d.target != nil {
+			// Recognised.
+		} else if !ok && !d.lenient {
 			return nil, DecoderError{text: "objectClassName is not a string"}
+		} else if !ok {
+			d.addWarning("objectClassName", "not a string, decoding as help response")
+		} else if !d.lenient {
+			return nil, DecoderError{text: "objectClassName is not recognised"}
+		} else {
+			d.addWarning("objectClassName", "not recognised, decoding as help response")
 		}
 	} else if _, exists := src["domainSearchResults"]; exists {
 		d.target = &DomainSearchResults{}
@@ -164,6 +273,15 @@ func (d *Decoder) decodeTopLevel(src map[string]interface{}) (interface{}, error
 		d.target = &EntitySearchResults{}
 	} else if _, exists := src["nameserverSearchResults"]; exists {
 		d.target = &NameserverSearchResults{}
+	} else if _, exists := src["networkSearchResults"]; exists {
+		d.target = &IPNetworkSearchResults{}
+	} else if _, exists := src["autnumSearchResults"]; exists {
+		d.target = &AutnumSearchResults{}
+	} else if d.inferClass {
+		if objectClassName = inferObjectClass(src); objectClassName != "" {
+			classNote = "objectClassName missing, inferred from structure"
+			d.target = objectOfClass(objectClassName)
+		}
 	}
 
 	// Default to returning a Help{}.
@@ -180,6 +298,15 @@ func (d *Decoder) decodeTopLevel(src map[string]interface{}) (interface{}, error
 	// Decode the response into the result type.
 	_, err := d.decode("", src, result, nil)
 
+	if classNote != "" && err == nil {
+		d.setObjectClassName(result, objectClassName, classNote)
+	}
+
+	// Smooth over known RIR differences.
+	if n, ok := result.Interface().(*IPNetwork); ok && err == nil {
+		d.normalizeIPNetwork(n)
+	}
+
 	return result.Interface(), err
 
 }
@@ -197,6 +324,10 @@ func (d *Decoder) decode(keyName string, src interface{}, dst reflect.Value, dec
 	var success bool
 	var err error
 
+	if keyName != "" {
+		d.lastKey = keyName
+	}
+
 	// Choose and run the correct decoder for |dst|'s type.
 	switch dst.Kind() {
 	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -242,12 +373,15 @@ func (d *Decoder) decodeSlice(keyName string, src interface{}, dst reflect.Value
 	result := reflect.MakeSlice(dst.Type(), 0, len(srcSlice))
 
 	// Foreach value in the input slice...
-	for _, v := range srcSlice {
+	for i, v := range srcSlice {
 		// Construct a result value for it.
 		vdst := reflect.New(dst.Type().Elem())
 
 		// Decode into the result value.
+		depth := len(d.path)
+		d.path = append(d.path, strconv.Itoa(i))
 		success, err := d.decode(keyName, v, reflect.Indirect(vdst), decodeData)
+		d.path = d.path[:depth]
 
 		if err != nil {
 			return false, err
@@ -292,7 +426,10 @@ func (d *Decoder) decodeMap(keyName string, src interface{}, dst reflect.Value,
 		vdst := reflect.New(dst.Type().Elem())
 
 		// Decode into the result value.
+		depth := len(d.path)
+		d.path = append(d.path, k)
 		success, err := d.decode(keyName+":"+k, v, reflect.Indirect(vdst), decodeData)
+		d.path = d.path[:depth]
 
 		if err != nil {
 			return false, err
@@ -594,6 +731,12 @@ func (d *Decoder) decodeStruct(keyName string, src interface{}, dst reflect.Valu
 		return false, nil
 	}
 
+	leaveEntity, err := d.enterEntity(keyName, dst)
+	if err != nil {
+		return false, err
+	}
+	defer leaveEntity()
+
 	// Identify the fields in the struct we'll decode into.
 	// e.g. fields["port43"] => [some reflect.Value]
 	var fields map[string]reflect.Value
@@ -603,6 +746,8 @@ func (d *Decoder) decodeStruct(keyName string, src interface{}, dst reflect.Valu
 
 	// If the result struct has a DecodeData...
 	if myDecodeData != nil {
+		myDecodeData.path = jsonPointer(d.path)
+
 		// Save a snapshot of each field.
 		for name, value := range srcMap {
 			myDecodeData.values[name] = value
@@ -618,7 +763,30 @@ func (d *Decoder) decodeStruct(keyName string, src interface{}, dst reflect.Valu
 	for name, value := range srcMap {
 		// If there's a matching Go field, decode into it...
 		if _, ok := fields[name]; ok {
-			_, err := d.decode(name, value, fields[name], myDecodeData)
+			var err error
+
+			depth := len(d.path)
+			d.path = append(d.path, name)
+
+			if d.lenient {
+				err = d.decodeFieldLeniently(name, value, fields[name], myDecodeData)
+			} else {
+				_, err = d.decode(name, value, fields[name], myDecodeData)
+			}
+
+			d.path = d.path[:depth]
+
+			if err != nil {
+				return false, err
+			}
+		} else if myDecodeData != nil && len(d.extensions) > 0 {
+			// Otherwise, decode registered extension members.
+			depth := len(d.path)
+			d.path = append(d.path, name)
+
+			err := d.decodeExtension(name, value, myDecodeData)
+
+			d.path = d.path[:depth]
 
 			if err != nil {
 				return false, err
@@ -626,9 +794,74 @@ func (d *Decoder) decodeStruct(keyName string, src interface{}, dst reflect.Valu
 		}
 	}
 
+	if e, ok := dst.Addr().Interface().(*Event); ok && e.Date != "" {
+		var parseErr error
+		if e.Time, parseErr = ParseDate(e.Date); parseErr != nil {
+			d.addDecodeNote(myDecodeData, "eventDate", "unparsable date")
+		}
+	}
+
+	if n, ok := dst.Addr().Interface().(*IPNetwork); ok {
+		n.Start = d.parseAddr(myDecodeData, "startAddress", n.StartAddress)
+		n.End = d.parseAddr(myDecodeData, "endAddress", n.EndAddress)
+	}
+
+	if s, ok := dst.Addr().Interface().(*IPAddressSet); ok {
+		for _, a := range s.V4 {
+			if addr := d.parseAddr(myDecodeData, "v4", a); addr.IsValid() {
+				s.Addrs = append(s.Addrs, addr)
+			}
+		}
+
+		for _, a := range s.V6 {
+			if addr := d.parseAddr(myDecodeData, "v6", a); addr.IsValid() {
+				s.Addrs = append(s.Addrs, addr)
+			}
+		}
+	}
+
+	if h, ok := dst.Addr().Interface().(*Help); ok {
+		h.RateLimits = ParseRateLimits(h.Notices)
+	}
+
 	return true, err
 }
 
+// parseAddr returns the IP address |text| of the field |name|. Unparsable
+// addresses are noted, and returned as the zero netip.Addr.
+func (d *Decoder) parseAddr(decodeData *DecodeData, name string, text string) netip.Addr {
+	if text == "" {
+		return netip.Addr{}
+	}
+
+	addr, err := netip.ParseAddr(text)
+	if err != nil {
+		d.addDecodeNote(decodeData, name, fmt.Sprintf("unparsable address %q", text))
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
+
+// decodeFieldLeniently decodes the struct field |name| as per decode(), for
+// the LenientDecoding option.
+//
+// Errors and panics are noted and the field left unset, instead of aborting
+// the decode.
+func (d *Decoder) decodeFieldLeniently(name string, src interface{}, dst reflect.Value, decodeData *DecodeData) error {
+	defer func() {
+		if r := recover(); r != nil {
+			d.addDecodeNote(decodeData, name, fmt.Sprintf("decode failed: %v", r))
+		}
+	}()
+
+	if _, err := d.decode(name, src, dst, decodeData); err != nil {
+		d.addDecodeNote(decodeData, name, "decode failed: "+err.Error())
+	}
+
+	return nil
+}
+
 func (d *Decoder) chooseFields(v reflect.Value) (map[string]reflect.Value, *DecodeData) {
 	if v.Kind() != reflect.Struct {
 		panic("BUG: chooseFields called on non-struct")
@@ -716,8 +949,12 @@ func (d *Decoder) getFieldName(sf reflect.StructField) (string, bool) {
 		return "", false
 	}
 
-	// The "rdap" struct tag specifies a custom RDAP field name.
+	// The "rdap" struct tag specifies a custom RDAP field name, or "-" for a
+	// field which isn't decoded.
 	name := sf.Tag.Get("rdap")
+	if name == "-" {
+		return "", false
+	}
 
 	// Otherwise, the RDAP field name is the Go field name, with the first
 	// character lowercased.
@@ -741,7 +978,16 @@ func (d *Decoder) decodePtr(keyName string, src interface{}, dst reflect.Value,
 	if dst.Type().Elem().Name() == "VCard" {
 		vcard, vcardError := newVCardImpl(src, VCardOptions{})
 
-		if vcardError == nil {
+		if vcardError != nil && d.lenient {
+			// Keep the valid properties, if the jCard structure is intact.
+			d.addDecodeNote(decodeData, keyName, vcardError.Error())
+			vcard, vcardError = newVCardImpl(src, VCardOptions{IgnoreInvalidProperties: true})
+
+			if vcardError == nil {
+				dst.Set(reflect.ValueOf(vcard))
+				success = true
+			}
+		} else if vcardError == nil {
 			dst.Set(reflect.ValueOf(vcard))
 			success = true
 		} else {
@@ -761,6 +1007,8 @@ func (d *Decoder) decodePtr(keyName string, src interface{}, dst reflect.Value,
 
 // addDecodeNote adds a DecodeData note |msg| for the field |key|.
 func (d *Decoder) addDecodeNote(decodeData *DecodeData, key string, msg string) {
+	d.addWarning(key, msg)
+
 	if decodeData == nil {
 		return
 	}
@@ -771,3 +1019,10 @@ func (d *Decoder) addDecodeNote(decodeData *DecodeData, key string, msg string)
 
 	decodeData.notes[key] = append(decodeData.notes[key], msg)
 }
+
+// addWarning records the problem |msg| with the field |key|, for Warnings().
+func (d *Decoder) addWarning(key string, msg string) {
+	if d.lenient {
+		d.warnings = append(d.warnings, key+": "+msg)
+	}
+}
@@ -370,3 +370,105 @@ func runDecodeAndCompareTest(t *testing.T, target interface{}, jsonBlob string,
 			spew.Sdump(result))
 	}
 }
+
+func TestDecodeNoticeLang(t *testing.T) {
+	result, ok := runDecode(t, &Help{}, `
+	{
+		"lang": "ja",
+		"notices": [
+			{"lang": "en", "title": "Terms of Service"}
+		]
+	}
+	`)
+
+	if !ok {
+		return
+	}
+
+	h := result.(*Help)
+
+	if h.Lang != "ja" {
+		t.Errorf("Help lang not decoded")
+	} else if len(h.Notices) != 1 || h.Notices[0].Lang != "en" {
+		t.Errorf("Notice lang not decoded")
+	}
+}
+
+func TestDecodePanic(t *testing.T) {
+	type XYZ struct {
+		M map[int]string
+	}
+
+	jsonBlob := `{"m": {"1": "a"}}`
+
+	d := NewDecoder([]byte(jsonBlob))
+	d.target = &XYZ{}
+
+	result, err := d.Decode()
+
+	panicErr, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("Expected PanicError, got %v", err)
+	}
+
+	if result != nil {
+		t.Errorf("Unexpected result %v", result)
+	} else if panicErr.Op != "decode" || panicErr.Field != "m" {
+		t.Errorf("Unexpected PanicError op=%s field=%s", panicErr.Op, panicErr.Field)
+	} else if string(panicErr.Body) != jsonBlob || len(panicErr.Stack) == 0 {
+		t.Errorf("PanicError diagnostics missing")
+	}
+}
+
+func TestDecodeLenient(t *testing.T) {
+	jsonBlob := `{
+		"objectClassName": "domain",
+		"port43": ["whois.example.com"],
+		"entities": [{"objectClassName": "entity", "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn"]]]}]
+	}`
+
+	d := NewDecoder([]byte(jsonBlob), LenientDecoding())
+	result, err := d.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain := result.(*Domain)
+	if len(domain.Entities) != 1 || domain.Entities[0].VCard == nil || len(domain.Entities[0].VCard.Properties) != 1 {
+		t.Errorf("Valid jCard properties not kept: %v", domain.Entities)
+	} else if len(d.Warnings()) != 2 {
+		t.Errorf("Expected 2 warnings, got %v", d.Warnings())
+	}
+
+	// Without LenientDecoding, the whole jCard is dropped.
+	result, _ = NewDecoder([]byte(jsonBlob)).Decode()
+	if result.(*Domain).Entities[0].VCard != nil {
+		t.Errorf("Unexpected jCard without LenientDecoding")
+	}
+
+	jsonBlob = `{"objectClassName": "cat", "rdapConformance": ["rdap_level_0"]}`
+	if _, err := NewDecoder([]byte(jsonBlob)).Decode(); err == nil {
+		t.Errorf("Expected error for unknown objectClassName")
+	}
+
+	d = NewDecoder([]byte(jsonBlob), LenientDecoding())
+	if result, err := d.Decode(); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	} else if _, ok := result.(*Help); !ok || len(d.Warnings()) != 1 {
+		t.Errorf("Expected Help with 1 warning, got %v %v", result, d.Warnings())
+	}
+
+	type XYZ struct {
+		M map[int]string
+		S string
+	}
+
+	d = NewDecoder([]byte(`{"m": {"1": "a"}, "s": "ok"}`), LenientDecoding())
+	d.target = &XYZ{}
+
+	if result, err := d.Decode(); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	} else if x := result.(*XYZ); x.S != "ok" || len(d.Warnings()) != 1 {
+		t.Errorf("Panicking field not skipped: %v %v", x, d.Warnings())
+	}
+}
@@ -0,0 +1,53 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+)
+
+// DNSPrecheck checks whether a domain name exists in the DNS, before it's
+// queried over RDAP. See Client.DNSPrecheck.
+type DNSPrecheck interface {
+	// DomainExists returns false if the domain |name| clearly doesn't exist.
+	//
+	// Returns an error if the check couldn't be completed (e.g. a DNS
+	// timeout). The RDAP query then proceeds as normal.
+	DomainExists(ctx context.Context, name string) (bool, error)
+}
+
+// ResolverPrecheck is a DNSPrecheck which looks up the domain's NS records.
+//
+// A domain without NS records (i.e. NXDOMAIN, or no delegation) is treated as
+// nonexistent. Note registered domains which aren't delegated (e.g. those on
+// serverHold/clientHold) are also reported as nonexistent.
+type ResolverPrecheck struct {
+	// Resolver to use. If nil, net.DefaultResolver is used.
+	Resolver *net.Resolver
+}
+
+// DomainExists implements DNSPrecheck.
+func (r *ResolverPrecheck) DomainExists(ctx context.Context, name string) (bool, error) {
+	resolver := r.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	name = strings.TrimSuffix(name, ".") + "."
+
+	ns, err := resolver.LookupNS(ctx, name)
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return len(ns) > 0, nil
+}
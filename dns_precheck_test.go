@@ -0,0 +1,83 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+type testPrecheck struct {
+	exists map[string]bool
+	err    error
+
+	checked []string
+}
+
+func (p *testPrecheck) DomainExists(ctx context.Context, name string) (bool, error) {
+	p.checked = append(p.checked, name)
+
+	return p.exists[name], p.err
+}
+
+func TestClientDNSPrecheck(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	precheck := &testPrecheck{
+		exists: map[string]bool{"example.cz": true},
+	}
+
+	client := &Client{
+		Verbose:     verboseFunc(),
+		DNSPrecheck: precheck,
+	}
+
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Fatal(err)
+	} else if resp.DNSPrecheckSkipped {
+		t.Errorf("Unexpected DNSPrecheckSkipped")
+	}
+
+	resp, err = client.Do(NewDomainRequest("nonexistent.cz"))
+	if !errors.Is(err, ErrObjectDoesNotExist) {
+		t.Fatalf("Expected ErrObjectDoesNotExist, got %v", err)
+	} else if resp == nil || !resp.DNSPrecheckSkipped || len(resp.HTTP) != 0 {
+		t.Errorf("Expected the RDAP query to be skipped, got %+v", resp)
+	}
+
+	// Non-domain queries aren't checked.
+	if _, err := client.Do(NewIPNetRequest(parseTestCIDR("192.0.2.0/24"))); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+
+	if len(precheck.checked) != 2 {
+		t.Errorf("Unexpected DNS pre-checks %v", precheck.checked)
+	}
+}
+
+func TestClientDNSPrecheckError(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose:     verboseFunc(),
+		DNSPrecheck: &testPrecheck{err: errors.New("timeout")},
+	}
+
+	// A failed check doesn't prevent the RDAP query.
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Fatal(err)
+	} else if resp.DNSPrecheckSkipped {
+		t.Errorf("Unexpected DNSPrecheckSkipped")
+	}
+}
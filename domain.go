@@ -35,6 +35,13 @@ type Domain struct {
 	Port43    string
 	Events    []Event
 	Network   *IPNetwork
+
+	Redacted []Redaction
+
+	// FRED extension (fred_version_0) nameserver and DNSSEC key sets, used
+	// by CZ.NIC.
+	FredNSSet  *FredNSSet  `rdap:"fred_nsset"`
+	FredKeySet *FredKeySet `rdap:"fred_keyset"`
 }
 
 // Variant is a subfield of Domain.
@@ -56,7 +63,9 @@ type VariantName struct {
 	UnicodeName string
 }
 
-// SecureDNS is ia subfield of Domain.
+// SecureDNS is a subfield of Domain.
+//
+// See KeyData.DS() and Domain.VerifyDS() for validating the DS records.
 type SecureDNS struct {
 	DecodeData *DecodeData
 
@@ -82,6 +91,7 @@ type DSData struct {
 	Links  []Link
 }
 
+// KeyData is a subfield of Domain, a DNSKEY record.
 type KeyData struct {
 	DecodeData *DecodeData
 
@@ -0,0 +1,321 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// defaultObjectClassNames are the objectClassName values filled in by
+// Encode(), for objects without one.
+var defaultObjectClassNames = map[reflect.Type]string{
+	reflect.TypeOf(Autnum{}):     "autnum",
+	reflect.TypeOf(Domain{}):     "domain",
+	reflect.TypeOf(Entity{}):     "entity",
+	reflect.TypeOf(IPNetwork{}):  "ip network",
+	reflect.TypeOf(Nameserver{}): "nameserver",
+	reflect.TypeOf(FredNSSet{}):  "fred_nsset",
+	reflect.TypeOf(FredKeySet{}): "fred_keyset",
+}
+
+// Encode encodes the RDAP object |object| (e.g. a *Domain) as RDAP JSON,
+// the inverse of Decoder. This enables proxies, caches, and test servers to
+// be built on the rdap types.
+//
+// Members are named as per RFC 9083 (e.g. "ldhName", "vcardArray"), in
+// struct field order. Empty members are omitted. Missing objectClassName
+// values are filled in, as is a top level rdapConformance (of
+// "rdap_level_0"). VCards are encoded as jCards.
+//
+// Unknown members of decoded objects (see DecodeData.UnknownFields()) are
+// preserved, so extensions survive a decode/encode round trip. So are the
+// original values of members which failed to decode. Use
+// DecodeData.SetValue() to add or override members.
+//
+// A VCardProperty with an array Value is encoded as a single structured value
+// (e.g. an "adr"), so multi-valued jCard properties are not round tripped
+// exactly.
+func Encode(object RDAPObject) ([]byte, error) {
+	v := reflect.ValueOf(object)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("rdap: Encode requires a non-nil pointer to an RDAP object")
+	}
+
+	e := &encoder{}
+	if err := e.encodeStruct(v.Elem(), true); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// MarshalJSON encodes the Autnum as RDAP JSON, see Encode().
+func (a *Autnum) MarshalJSON() ([]byte, error) {
+	return Encode(a)
+}
+
+// MarshalJSON encodes the Domain as RDAP JSON, see Encode().
+func (d *Domain) MarshalJSON() ([]byte, error) {
+	return Encode(d)
+}
+
+// MarshalJSON encodes the Entity as RDAP JSON, see Encode().
+func (e *Entity) MarshalJSON() ([]byte, error) {
+	return Encode(e)
+}
+
+// MarshalJSON encodes the Error as RDAP JSON, see Encode().
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return Encode(e)
+}
+
+// MarshalJSON encodes the FredKeySet as RDAP JSON, see Encode().
+func (s *FredKeySet) MarshalJSON() ([]byte, error) {
+	return Encode(s)
+}
+
+// MarshalJSON encodes the FredNSSet as RDAP JSON, see Encode().
+func (s *FredNSSet) MarshalJSON() ([]byte, error) {
+	return Encode(s)
+}
+
+// MarshalJSON encodes the Help as RDAP JSON, see Encode().
+func (h *Help) MarshalJSON() ([]byte, error) {
+	return Encode(h)
+}
+
+// MarshalJSON encodes the IPNetwork as RDAP JSON, see Encode().
+func (n *IPNetwork) MarshalJSON() ([]byte, error) {
+	return Encode(n)
+}
+
+// MarshalJSON encodes the Nameserver as RDAP JSON, see Encode().
+func (n *Nameserver) MarshalJSON() ([]byte, error) {
+	return Encode(n)
+}
+
+// MarshalJSON encodes the VCard as a jCard.
+func (v *VCard) MarshalJSON() ([]byte, error) {
+	e := &encoder{}
+	if err := e.encodeVCard(v); err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+// encoder implements Encode().
+type encoder struct {
+	buf bytes.Buffer
+}
+
+// encodedField is a struct field to be encoded.
+type encodedField struct {
+	name  string
+	value reflect.Value
+
+	// Raw value (e.g. of an unknown field), used instead of value if hasRaw.
+	raw    interface{}
+	hasRaw bool
+}
+
+// encode writes the value |v| as JSON.
+func (e *encoder) encode(v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		} else if vcard, ok := v.Interface().(*VCard); ok {
+			return e.encodeVCard(vcard)
+		}
+
+		return e.encode(v.Elem())
+	case reflect.Struct:
+		return e.encodeStruct(v, false)
+	case reflect.Slice:
+		e.buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+
+			if err := e.encode(v.Index(i)); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte(']')
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		e.buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				e.buf.WriteByte(',')
+			}
+
+			e.writeRaw(k)
+			e.buf.WriteByte(':')
+
+			if err := e.encode(v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))); err != nil {
+				return err
+			}
+		}
+		e.buf.WriteByte('}')
+	default:
+		return e.writeRaw(v.Interface())
+	}
+
+	return nil
+}
+
+// encodeStruct writes the RDAP struct |v| as a JSON object. |topLevel| is
+// true for the topmost object.
+func (e *encoder) encodeStruct(v reflect.Value, topLevel bool) error {
+	fields, decodeData := e.fields(v)
+
+	known := map[string]bool{}
+
+	for i, f := range fields {
+		known[f.name] = true
+
+		if raw, ok := decodeData.rawValue(f.name, isEmptyValue(f.value)); ok {
+			fields[i].raw, fields[i].hasRaw = raw, true
+		} else if f.name == "objectClassName" && f.value.String() == "" {
+			if class, ok := defaultObjectClassNames[v.Type()]; ok {
+				fields[i].raw, fields[i].hasRaw = class, true
+			}
+		} else if f.name == "rdapConformance" && f.value.Len() == 0 && topLevel {
+			fields[i].raw, fields[i].hasRaw = []string{"rdap_level_0"}, true
+		}
+	}
+
+	if decodeData != nil {
+		unknown := decodeData.UnknownFields()
+		sort.Strings(unknown)
+
+		for _, name := range unknown {
+			if known[name] {
+				// Set by SetValue() on a manually constructed struct.
+				continue
+			}
+
+			fields = append(fields, encodedField{name: name, raw: decodeData.Value(name), hasRaw: true})
+		}
+	}
+
+	e.buf.WriteByte('{')
+
+	first := true
+	for _, f := range fields {
+		if !f.hasRaw && isEmptyValue(f.value) {
+			continue
+		}
+
+		if !first {
+			e.buf.WriteByte(',')
+		}
+		first = false
+
+		e.writeRaw(f.name)
+		e.buf.WriteByte(':')
+
+		var err error
+		if f.hasRaw {
+			err = e.writeRaw(f.raw)
+		} else {
+			err = e.encode(f.value)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	e.buf.WriteByte('}')
+
+	return nil
+}
+
+// fields returns the RDAP fields of the struct |v| in order, including those
+// of embedded structs (e.g. Common), and its DecodeData (nil if none).
+func (e *encoder) fields(v reflect.Value) ([]encodedField, *DecodeData) {
+	var fields []encodedField
+	var decodeData *DecodeData
+
+	d := &Decoder{}
+
+	vt := v.Type()
+	for i := 0; i < vt.NumField(); i++ {
+		structField := vt.Field(i)
+
+		if structField.Type == reflect.TypeOf(decodeData) {
+			decodeData = v.Field(i).Interface().(*DecodeData)
+		} else if structField.Anonymous {
+			subFields, subDecodeData := e.fields(v.Field(i))
+			fields = append(fields, subFields...)
+
+			if subDecodeData != nil {
+				decodeData = subDecodeData
+			}
+		} else if name, ok := d.getFieldName(structField); ok {
+			fields = append(fields, encodedField{name: name, value: v.Field(i)})
+		}
+	}
+
+	return fields, decodeData
+}
+
+// encodeVCard writes |v| as a jCard.
+func (e *encoder) encodeVCard(v *VCard) error {
+	properties := make([]interface{}, 0, len(v.Properties))
+
+	for _, p := range v.Properties {
+		parameters := map[string]interface{}{}
+		for k, values := range p.Parameters {
+			if len(values) == 1 {
+				parameters[k] = values[0]
+			} else {
+				parameters[k] = values
+			}
+		}
+
+		properties = append(properties, []interface{}{p.Name, parameters, p.Type, p.Value})
+	}
+
+	return e.writeRaw([]interface{}{"vcard", properties})
+}
+
+// writeRaw writes |v| using encoding/json.
+func (e *encoder) writeRaw(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	e.buf.Write(b)
+
+	return nil
+}
+
+// isEmptyValue returns true if the field value |v| should be omitted.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return v.Len() == 0
+	case reflect.Ptr, reflect.Interface:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
@@ -0,0 +1,119 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestEncode(t *testing.T) {
+	var startAutnum uint32 = 0
+
+	autnum := &Autnum{
+		Handle:      "AS0",
+		StartAutnum: &startAutnum,
+		Status:      []string{"active"},
+		Events:      []Event{{Action: "registration", Date: "2017-01-01T00:00:00Z"}},
+	}
+
+	out, err := Encode(autnum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"rdapConformance":["rdap_level_0"],"objectClassName":"autnum","handle":"AS0","startAutnum":0,"status":["active"],"events":[{"eventAction":"registration","eventDate":"2017-01-01T00:00:00Z"}]}`
+	if string(out) != expected {
+		t.Errorf("Got %s, expected %s", out, expected)
+	}
+
+	if _, err := Encode(Autnum{}); err == nil {
+		t.Errorf("Expected error encoding a non-pointer")
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, filename := range []string{
+		"rdap/rdap.nic.cz/domain-example.cz.json",
+		"rdap/rdap.arin.net/autnum-3356.json",
+	} {
+		original := test.LoadFile(filename)
+
+		obj, err := NewDecoder(original).Decode()
+		if err != nil {
+			t.Fatalf("%s: %s", filename, err)
+		}
+
+		encoded, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("%s: %s", filename, err)
+		}
+
+		if violations, _ := ValidateResponse(encoded); len(violations) != 0 {
+			t.Errorf("%s: encoded response has violations: %v", filename, violations)
+		}
+
+		var expected, actual interface{}
+		json.Unmarshal(original, &expected)
+		json.Unmarshal(encoded, &actual)
+
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: round trip mismatch:\n%s", filename, encoded)
+		}
+	}
+}
+
+func TestEncodePreservesUnknownFields(t *testing.T) {
+	jsonBlob := `{
+		"objectClassName": "domain",
+		"rdapConformance": ["rdap_level_0", "example_ext"],
+		"ldhName": "example.com",
+		"port43": ["whois.example.com"],
+		"example_ext_flag": true,
+		"entities": [{"objectClassName": "entity", "handle": "X", "example_ext_tags": ["a", "b"], "example_ext_null": null}]
+	}`
+
+	obj, err := NewDecoder([]byte(jsonBlob)).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := Encode(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected, actual interface{}
+	json.Unmarshal([]byte(jsonBlob), &expected)
+	json.Unmarshal(encoded, &actual)
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Round trip mismatch: %s", encoded)
+	}
+}
+
+func TestEncodeSetValue(t *testing.T) {
+	domain := &Domain{
+		DecodeData: NewDecodeData(),
+		LDHName:    "example.com",
+		Port43:     "whois.example.com",
+	}
+
+	domain.DecodeData.SetValue("example_ext_id", 42)
+	domain.DecodeData.SetValue("port43", "whois.example.net")
+
+	out, err := Encode(domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"rdapConformance":["rdap_level_0"],"objectClassName":"domain","ldhName":"example.com","port43":"whois.example.net","example_ext_id":42}`
+	if string(out) != expected {
+		t.Errorf("Got %s, expected %s", out, expected)
+	}
+}
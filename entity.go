@@ -28,4 +28,32 @@ type Entity struct {
 	Port43       string
 	Networks     []IPNetwork
 	Autnums      []Autnum
+	Redacted     []Redaction
+
+	rolesInferred bool
+}
+
+// ActorEvents returns the Entity's AsEventActor events, with each event's
+// Actor set to the Entity's Handle where not specified by the RDAP server.
+//
+// AsEventActor events are those in which the Entity itself is the actor, so
+// omit eventActor (RFC 9083 section 4.5).
+func (e *Entity) ActorEvents() []Event {
+	var events []Event
+
+	for _, ev := range e.AsEventActor {
+		if ev.Actor == "" {
+			ev.Actor = e.Handle
+		}
+
+		events = append(events, ev)
+	}
+
+	return events
+}
+
+// RolesInferred returns true if the Entity's Roles were inferred by
+// InferRoles(), rather than specified by the RDAP server.
+func (e *Entity) RolesInferred() bool {
+	return e.rolesInferred
 }
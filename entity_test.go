@@ -0,0 +1,57 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const testAsEventActorEntity = `{
+	"rdapConformance": ["rdap_level_0"],
+	"objectClassName": "entity",
+	"handle": "XXXX",
+	"asEventActor": [
+		{"eventAction": "last changed", "eventDate": "1990-12-31T23:59:59Z"}
+	]
+}`
+
+func TestEntityAsEventActor(t *testing.T) {
+	e := decodeTestObject(t, testAsEventActorEntity).(*Entity)
+
+	if len(e.AsEventActor) != 1 || e.AsEventActor[0].Action != "last changed" || e.AsEventActor[0].Time.Year() != 1990 {
+		t.Fatalf("AsEventActor decoded incorrectly: %+v", e.AsEventActor)
+	}
+
+	if events := e.ActorEvents(); len(events) != 1 || events[0].Actor != "XXXX" {
+		t.Errorf("ActorEvents() = %+v", events)
+	}
+
+	var buf bytes.Buffer
+	(&Printer{Writer: &buf}).Print(e)
+	if !strings.Contains(buf.String(), "AsEventActor:\n") || !strings.Contains(buf.String(), "Actor: XXXX\n") {
+		t.Errorf("AsEventActor not printed:\n%s", buf.String())
+	}
+
+	encoded, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(encoded), `"asEventActor":[{"eventAction":"last changed","eventDate":"1990-12-31T23:59:59Z"}]`) {
+		t.Errorf("AsEventActor not re-encoded: %s", encoded)
+	}
+
+	if violations, _ := ValidateResponse(encoded); len(violations) != 0 {
+		t.Errorf("Unexpected violations %v", violations)
+	}
+
+	withActor := strings.Replace(testAsEventActorEntity, `"eventAction"`, `"eventActor": "YYYY", "eventAction"`, 1)
+	if violations, _ := ValidateResponse([]byte(withActor)); len(violations) != 1 {
+		t.Errorf("Expected eventActor violation, got %v", violations)
+	}
+}
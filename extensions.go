@@ -0,0 +1,183 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "strings"
+
+// acceptHeader returns the HTTP Accept header value for a request advertising
+// the RDAP extensions |extensions|.
+//
+// The extensions are advertised using the "extensions" media type parameter,
+// as per the RDAP-X draft (draft-ietf-regext-rdap-x-media-type). e.g.:
+//
+//	application/rdap+json;extensions="rdap_level_0 redacted", application/json
+func acceptHeader(extensions []string) string {
+	if len(extensions) == 0 {
+		return "application/rdap+json, application/json"
+	}
+
+	return "application/rdap+json;extensions=\"" +
+		strings.Join(extensions, " ") +
+		"\", application/json"
+}
+
+// Conformance returns the rdapConformance values of the response Object.
+//
+// Returns nil if the Object is nil, or has no rdapConformance.
+func (r *Response) Conformance() []string {
+	return conformanceOf(r.Object)
+}
+
+// NegotiatedExtensions returns the RDAP extensions which were both requested
+// (see Request.Extensions) and are listed in the response's rdapConformance.
+//
+// The order of |requested| is preserved.
+func (r *Response) NegotiatedExtensions(requested []string) []string {
+	supported := map[string]bool{}
+	for _, c := range r.Conformance() {
+		supported[c] = true
+	}
+
+	var result []string
+	for _, e := range requested {
+		if supported[e] {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// conformanceOf returns the rdapConformance values of the topmost RDAP object
+// |obj|.
+func conformanceOf(obj RDAPObject) []string {
+	switch v := obj.(type) {
+	case *Domain:
+		return v.Conformance
+	case *Entity:
+		return v.Conformance
+	case *Nameserver:
+		return v.Conformance
+	case *Autnum:
+		return v.Conformance
+	case *IPNetwork:
+		return v.Conformance
+	case *FredNSSet:
+		return v.Conformance
+	case *FredKeySet:
+		return v.Conformance
+	case *Help:
+		return v.Conformance
+	case *Error:
+		return v.Conformance
+	case *DomainSearchResults:
+		return v.Conformance
+	case *EntitySearchResults:
+		return v.Conformance
+	case *NameserverSearchResults:
+		return v.Conformance
+	case *IPNetworkSearchResults:
+		return v.Conformance
+	case *AutnumSearchResults:
+		return v.Conformance
+	default:
+		return nil
+	}
+}
+
+// Capabilities is a typed interpretation of an rdapConformance array, see
+// ParseCapabilities().
+type Capabilities struct {
+	// rdap_level_0: RFC 9083.
+	Level0 bool
+
+	// redacted: RFC 9537 redaction, see Redaction.
+	RedactedSupported bool
+
+	// paging and sorting: RFC 8977, see PagingMetadata and SortingMetadata.
+	PagingSupported  bool
+	SortingSupported bool
+
+	// subsetting: RFC 8982 field sets, see SubsettingMetadata.
+	SubsettingSupported bool
+
+	// cidr0 and arin_originas0: IP network extensions, see IPNetwork.
+	Cidr0Supported         bool
+	ArinOriginAS0Supported bool
+
+	// fred: the FRED registry extensions, see FredNSSet and FredKeySet.
+	FredSupported bool
+
+	// jscontact: JSContact contact cards, see Entity.Contact().
+	JSContactSupported bool
+
+	// reverse_search: RFC 9536 reverse search.
+	ReverseSearchSupported bool
+
+	// rdap_objectTag_level_0: RFC 8521 object tagging.
+	ObjectTagSupported bool
+
+	// icann_rdap_response_profile_* and
+	// icann_rdap_technical_implementation_guide_*: the ICANN gTLD profile, see
+	// ValidateGTLDProfile().
+	ICANNProfile bool
+
+	// nro_rdap_profile_*: the NRO (RIR) RDAP profile.
+	NROProfile bool
+
+	// Identifiers not recognised by this package, e.g. registry proprietary
+	// extensions.
+	Unrecognized []string
+}
+
+// ParseCapabilities interprets the rdapConformance values |conformance|,
+// e.g. []string{"rdap_level_0", "redacted", "cidr0"}.
+//
+// Identifiers not recognised are listed in Capabilities.Unrecognized.
+func ParseCapabilities(conformance []string) Capabilities {
+	var c Capabilities
+
+	for _, id := range conformance {
+		switch {
+		case id == "rdap_level_0":
+			c.Level0 = true
+		case id == "redacted":
+			c.RedactedSupported = true
+		case id == "paging":
+			c.PagingSupported = true
+		case id == "sorting":
+			c.SortingSupported = true
+		case id == "subsetting":
+			c.SubsettingSupported = true
+		case id == "cidr0":
+			c.Cidr0Supported = true
+		case id == "arin_originas0":
+			c.ArinOriginAS0Supported = true
+		case id == "fred":
+			c.FredSupported = true
+		case id == "jscontact":
+			c.JSContactSupported = true
+		case id == "reverse_search":
+			c.ReverseSearchSupported = true
+		case id == "rdap_objectTag_level_0":
+			c.ObjectTagSupported = true
+		case strings.HasPrefix(id, "icann_rdap_response_profile_"),
+			strings.HasPrefix(id, "icann_rdap_technical_implementation_guide_"):
+			c.ICANNProfile = true
+		case strings.HasPrefix(id, "nro_rdap_profile_"):
+			c.NROProfile = true
+		default:
+			c.Unrecognized = append(c.Unrecognized, id)
+		}
+	}
+
+	return c
+}
+
+// Capabilities returns the Capabilities listed in the response's
+// rdapConformance, see ParseCapabilities().
+func (r *Response) Capabilities() Capabilities {
+	return ParseCapabilities(r.Conformance())
+}
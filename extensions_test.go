@@ -0,0 +1,62 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+func TestAcceptHeader(t *testing.T) {
+	if h := acceptHeader(nil); h != "application/rdap+json, application/json" {
+		t.Errorf("Unexpected default Accept header %s", h)
+	}
+
+	expected := `application/rdap+json;extensions="rdap_level_0 redacted", application/json`
+	if h := acceptHeader([]string{"rdap_level_0", "redacted"}); h != expected {
+		t.Errorf("Got Accept header %s, expected %s", h, expected)
+	}
+}
+
+func TestResponseNegotiatedExtensions(t *testing.T) {
+	resp := &Response{
+		Object: &Domain{
+			Conformance: []string{"rdap_level_0", "redacted", "icann_rdap_response_profile_0"},
+		},
+	}
+
+	result := resp.NegotiatedExtensions([]string{"jscontact", "redacted", "rdap_level_0"})
+
+	if len(result) != 2 || result[0] != "redacted" || result[1] != "rdap_level_0" {
+		t.Errorf("Unexpected negotiated extensions %v", result)
+	}
+
+	empty := &Response{}
+	if len(empty.NegotiatedExtensions([]string{"redacted"})) != 0 {
+		t.Errorf("Unexpected negotiated extensions for empty Response")
+	}
+}
+
+func TestParseCapabilities(t *testing.T) {
+	resp := &Response{
+		Object: &DomainSearchResults{
+			Conformance: []string{"rdap_level_0", "redacted", "paging", "cidr0", "icann_rdap_technical_implementation_guide_1", "nro_rdap_profile_0", "example_ext"},
+		},
+	}
+
+	c := resp.Capabilities()
+	if !c.Level0 || !c.RedactedSupported || !c.PagingSupported || !c.Cidr0Supported || !c.ICANNProfile || !c.NROProfile {
+		t.Errorf("Capabilities not recognised: %+v", c)
+	}
+
+	if c.SortingSupported || c.FredSupported || c.JSContactSupported {
+		t.Errorf("Unexpected capabilities: %+v", c)
+	}
+
+	if len(c.Unrecognized) != 1 || c.Unrecognized[0] != "example_ext" {
+		t.Errorf("Got Unrecognized %v", c.Unrecognized)
+	}
+
+	if c := (&Response{}).Capabilities(); c.Level0 || len(c.Unrecognized) != 0 {
+		t.Errorf("Unexpected capabilities for empty Response: %+v", c)
+	}
+}
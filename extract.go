@@ -0,0 +1,212 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// extractStep is a single step of a JSON path, see parseJSONPath().
+type extractStep struct {
+	// Object field name, for ".name" steps.
+	field string
+
+	// Array index, for "[N]" steps. -1 selects all elements ("[]" or "[*]").
+	index   int
+	isIndex bool
+
+	// Array filter, for "[?name==value]" and "[?name!=value]" steps.
+	filterField string
+	filterValue interface{}
+	filterNot   bool
+	isFilter    bool
+}
+
+// parseJSONPath parses the JSON path |path|.
+//
+// The supported syntax is a small subset of jq:
+//
+//	.name                  - Object field.
+//	[N]                    - Array element N.
+//	[] or [*]              - All array elements.
+//	[?name=="value"]       - Array elements whose field name equals the value
+//	                         (a JSON string, number, or bool). != is also
+//	                         supported.
+//
+// A field step applied to an array is applied to each element. e.g.
+// '.events[?eventAction=="expiration"].eventDate'.
+func parseJSONPath(path string) ([]extractStep, error) {
+	var steps []extractStep
+
+	p := strings.TrimSpace(path)
+	if p == "." {
+		return steps, nil
+	}
+
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			end := 1
+			for end < len(p) && p[end] != '.' && p[end] != '[' {
+				end++
+			}
+
+			name := p[1:end]
+			if name == "" {
+				return nil, fmt.Errorf("empty field name in '%s'", path)
+			}
+
+			steps = append(steps, extractStep{field: name})
+			p = p[end:]
+		case '[':
+			end := strings.Index(p, "]")
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated [ in '%s'", path)
+			}
+
+			step, err := parseJSONPathBracket(p[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%s in '%s'", err, path)
+			}
+
+			steps = append(steps, step)
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("expected . or [ at '%s'", p)
+		}
+	}
+
+	return steps, nil
+}
+
+// parseJSONPathBracket parses the contents |s| of a [...] step.
+func parseJSONPathBracket(s string) (extractStep, error) {
+	s = strings.TrimSpace(s)
+
+	if s == "" || s == "*" {
+		return extractStep{index: -1, isIndex: true}, nil
+	}
+
+	if !strings.HasPrefix(s, "?") {
+		index, err := strconv.Atoi(s)
+		if err != nil || index < 0 {
+			return extractStep{}, fmt.Errorf("bad array index '%s'", s)
+		}
+
+		return extractStep{index: index, isIndex: true}, nil
+	}
+
+	op := "=="
+	i := strings.Index(s, op)
+	if j := strings.Index(s, "!="); j != -1 && (i == -1 || j < i) {
+		op, i = "!=", j
+	}
+
+	if i == -1 {
+		return extractStep{}, fmt.Errorf("filter '%s' requires == or !=", s)
+	}
+
+	field := strings.TrimSpace(s[1:i])
+	field = strings.TrimPrefix(field, ".")
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(s[i+len(op):])), &value); err != nil {
+		return extractStep{}, fmt.Errorf("bad filter value in '%s'", s)
+	}
+
+	return extractStep{
+		filterField: field,
+		filterValue: value,
+		filterNot:   op == "!=",
+		isFilter:    true,
+	}, nil
+}
+
+// extractJSONPath returns the values within the JSON document |doc| matching
+// |path|, see parseJSONPath().
+func extractJSONPath(doc []byte, path string) ([]interface{}, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+
+	values := []interface{}{root}
+
+	for _, step := range steps {
+		var next []interface{}
+
+		for _, v := range values {
+			next = append(next, step.apply(v)...)
+		}
+
+		values = next
+	}
+
+	return values, nil
+}
+
+// apply returns the results of the step applied to |v|.
+func (s extractStep) apply(v interface{}) []interface{} {
+	switch {
+	case s.field != "":
+		switch t := v.(type) {
+		case map[string]interface{}:
+			if fv, ok := t[s.field]; ok {
+				return []interface{}{fv}
+			}
+		case []interface{}:
+			var result []interface{}
+			for _, e := range t {
+				result = append(result, s.apply(e)...)
+			}
+			return result
+		}
+	case s.isIndex:
+		if a, ok := v.([]interface{}); ok {
+			if s.index == -1 {
+				return a
+			} else if s.index < len(a) {
+				return []interface{}{a[s.index]}
+			}
+		}
+	case s.isFilter:
+		if a, ok := v.([]interface{}); ok {
+			var result []interface{}
+			for _, e := range a {
+				m, ok := e.(map[string]interface{})
+				if !ok {
+					continue
+				}
+
+				if (m[s.filterField] == s.filterValue) != s.filterNot {
+					result = append(result, e)
+				}
+			}
+			return result
+		}
+	}
+
+	return nil
+}
+
+// formatExtracted formats an extracted value for printing. Strings are
+// printed as-is, other values as JSON.
+func formatExtracted(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+
+	b, _ := json.Marshal(v)
+
+	return string(b)
+}
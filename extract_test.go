@@ -0,0 +1,63 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractJSONPath(t *testing.T) {
+	doc := []byte(`{
+		"ldhName": "example.cz",
+		"status": ["active", "locked"],
+		"secureDNS": {"delegationSigned": true},
+		"events": [
+			{"eventAction": "registration", "eventDate": "2000-01-01"},
+			{"eventAction": "expiration", "eventDate": "2030-01-01"}
+		]
+	}`)
+
+	tests := []struct {
+		Path     string
+		Expected string
+	}{
+		{".ldhName", "example.cz"},
+		{".status[1]", "locked"},
+		{".status[]", "active|locked"},
+		{".status[*]", "active|locked"},
+		{".secureDNS.delegationSigned", "true"},
+		{`.events[?eventAction=="expiration"].eventDate`, "2030-01-01"},
+		{`.events[?eventAction!="expiration"].eventDate`, "2000-01-01"},
+		{".events.eventAction", "registration|expiration"},
+		{".missing", ""},
+		{".status[5]", ""},
+	}
+
+	for _, test := range tests {
+		values, err := extractJSONPath(doc, test.Path)
+		if err != nil {
+			t.Errorf("Path %s: unexpected error %s", test.Path, err)
+			continue
+		}
+
+		var actual []string
+		for _, v := range values {
+			actual = append(actual, formatExtracted(v))
+		}
+
+		if strings.Join(actual, "|") != test.Expected {
+			t.Errorf("Path %s: got %v, expected %s", test.Path, actual, test.Expected)
+		}
+	}
+}
+
+func TestParseJSONPathErrors(t *testing.T) {
+	for _, path := range []string{"ldhName", ".status[", ".status[x]", "..a", `.events[?eventAction]`, `.events[?a==unquoted]`} {
+		if _, err := parseJSONPath(path); err == nil {
+			t.Errorf("Path %s: expected error", path)
+		}
+	}
+}
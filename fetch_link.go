@@ -0,0 +1,100 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// FetchLink fetches the RDAP object at |link|.Href, and verifies it is of the
+// type returned by a |want| query. e.g. DomainRequest expects a *Domain,
+// DomainSearchRequest expects a *DomainSearchResults. RawRequest accepts any
+// object.
+//
+// This simplifies following "self", "related", "up" (etc.) links safely:
+//
+//	resp, err := client.FetchLink(ctx, link, rdap.EntityRequest)
+//	if err == nil {
+//	  entity := resp.Object.(*rdap.Entity)
+//	}
+//
+// An RDAP error response is returned as a RDAPServerError ClientError, and
+// an unexpected object type as a WrongResponseType ClientError.
+func (c *Client) FetchLink(ctx context.Context, link Link, want RequestType) (*Response, error) {
+	if link.Href == "" {
+		return nil, &ClientError{
+			Type: InputError,
+			Text: fmt.Sprintf("Link (rel=%s) has no href", link.Rel),
+		}
+	}
+
+	u, err := url.Parse(link.Href)
+	if err != nil {
+		return nil, &ClientError{
+			Type: InputError,
+			Text: fmt.Sprintf("Bad link href '%s': %s", link.Href, err),
+			Err:  err,
+		}
+	}
+
+	resp, err := c.Do(NewRawRequest(u).WithContext(ctx))
+	if err != nil {
+		return resp, err
+	}
+
+	if respError, ok := resp.Object.(*Error); ok && want != RawRequest {
+		ce := clientErrorFromRDAPError(respError)
+		ce.URL = link.Href
+
+		return resp, ce
+	}
+
+	if !isObjectOfType(resp.Object, want) {
+		return resp, &ClientError{
+			Type: WrongResponseType,
+			Text: fmt.Sprintf("Link %s returned a %T, expected a %s response", link.Href, resp.Object, want),
+			URL:  link.Href,
+		}
+	}
+
+	return resp, nil
+}
+
+// isObjectOfType returns true if |obj| is the type of object returned by a
+// |requestType| query.
+func isObjectOfType(obj RDAPObject, requestType RequestType) bool {
+	var ok bool
+
+	switch requestType {
+	case AutnumRequest:
+		_, ok = obj.(*Autnum)
+	case DomainRequest:
+		_, ok = obj.(*Domain)
+	case EntityRequest:
+		_, ok = obj.(*Entity)
+	case HelpRequest:
+		_, ok = obj.(*Help)
+	case IPRequest:
+		_, ok = obj.(*IPNetwork)
+	case NameserverRequest:
+		_, ok = obj.(*Nameserver)
+	case DomainSearchRequest, DomainSearchByNameserverRequest, DomainSearchByNameserverIPRequest:
+		_, ok = obj.(*DomainSearchResults)
+	case NameserverSearchRequest, NameserverSearchByNameserverIPRequest:
+		_, ok = obj.(*NameserverSearchResults)
+	case EntitySearchRequest, EntitySearchByHandleRequest:
+		_, ok = obj.(*EntitySearchResults)
+	case IPSearchRequest, IPSearchByHandleRequest:
+		_, ok = obj.(*IPNetworkSearchResults)
+	case AutnumSearchRequest, AutnumSearchByHandleRequest:
+		_, ok = obj.(*AutnumSearchResults)
+	case RawRequest:
+		ok = obj != nil
+	}
+
+	return ok
+}
@@ -0,0 +1,46 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientFetchLink(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	link := Link{Rel: "self", Href: "https://rdap.nic.cz/entity/CZ-REGISTRANT"}
+
+	resp, err := client.FetchLink(context.Background(), link, EntityRequest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	} else if _, ok := resp.Object.(*Entity); !ok {
+		t.Errorf("Expected Entity")
+	}
+
+	_, err = client.FetchLink(context.Background(), link, DomainRequest)
+	if !errors.Is(err, ErrWrongResponseType) {
+		t.Errorf("Expected ErrWrongResponseType, got %v", err)
+	}
+
+	if _, err = client.FetchLink(context.Background(), link, RawRequest); err != nil {
+		t.Errorf("Unexpected error for RawRequest: %s", err)
+	}
+
+	_, err = client.FetchLink(context.Background(), Link{Rel: "up"}, IPRequest)
+	if !errors.Is(err, ErrInput) {
+		t.Errorf("Expected ErrInput, got %v", err)
+	}
+}
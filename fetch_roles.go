@@ -0,0 +1,135 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// fetchRoles implements Request.FetchRoles.
+//
+// For each contact Entity in |resp|.Object which has a role matching
+// |req|.FetchRoles, and no contact information (VCard), the Entity's "self"
+// link is fetched. The fetched Entity then replaces the original.
+//
+// Failed fetches are noted via Verbose and otherwise ignored, the original
+// Entity is left in place.
+func (c *Client) fetchRoles(req *Request, resp *Response) {
+	entities := entitiesOf(resp.Object)
+	if entities == nil {
+		return
+	}
+
+	for i := range *entities {
+		e := &(*entities)[i]
+
+		if e.VCard != nil || !hasFetchRole(e.Roles, req.FetchRoles) {
+			continue
+		}
+
+		selfURL := selfLink(e.Links)
+		if selfURL == "" {
+			c.verbose(fmt.Sprintf("client: Entity '%s' has no self link, not fetching", e.Handle))
+			continue
+		}
+
+		u, err := url.Parse(selfURL)
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: Entity '%s' has bad self link: %s", e.Handle, err))
+			continue
+		}
+
+		c.verbose(fmt.Sprintf("client: Fetching entity '%s' (roles %v)", e.Handle, e.Roles))
+
+		if c.HostPolicy != nil {
+			if err := c.HostPolicy.Check(u); err != nil {
+				c.verbose(fmt.Sprintf("client: Skipping entity fetch: %s", err))
+				continue
+			}
+		}
+
+		httpResponse := c.get(NewRawRequest(u).WithContext(req.Context()))
+		resp.HTTP = append(resp.HTTP, httpResponse)
+
+		if httpResponse.Error != nil {
+			c.verbose(fmt.Sprintf("client: Entity fetch error: %s", httpResponse.Error))
+			continue
+		} else if httpResponse.Response.StatusCode != 200 {
+			c.verbose(fmt.Sprintf("client: Entity fetch returned status-code=%d",
+				httpResponse.Response.StatusCode))
+			continue
+		}
+
+		obj, err := c.newDecoder(httpResponse.Body).Decode()
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: Error decoding entity: %s", err))
+			continue
+		}
+
+		fetched, ok := obj.(*Entity)
+		if !ok {
+			c.verbose("client: Entity fetch returned a non-Entity response")
+			continue
+		}
+
+		// The fetched Entity's roles are relative to the original object.
+		if len(fetched.Roles) == 0 {
+			fetched.Roles = e.Roles
+			fetched.rolesInferred = e.rolesInferred
+		}
+
+		*e = *fetched
+	}
+}
+
+// entitiesOf returns a pointer to the Entities of |obj|, or nil if |obj| has
+// no Entities field.
+func entitiesOf(obj RDAPObject) *[]Entity {
+	switch v := obj.(type) {
+	case *Domain:
+		return &v.Entities
+	case *Autnum:
+		return &v.Entities
+	case *IPNetwork:
+		return &v.Entities
+	case *Nameserver:
+		return &v.Entities
+	case *Entity:
+		return &v.Entities
+	default:
+		return nil
+	}
+}
+
+// hasFetchRole returns true if any of |roles| are in |fetchRoles| (or if
+// |fetchRoles| contains "all").
+func hasFetchRole(roles []string, fetchRoles []string) bool {
+	for _, f := range fetchRoles {
+		if f == "all" {
+			return true
+		}
+
+		for _, r := range roles {
+			if r == f {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// selfLink returns the href of the first "self" Link in |links|, or empty
+// string if none.
+func selfLink(links []Link) string {
+	for _, l := range links {
+		if l.Rel == "self" && l.Href != "" {
+			return l.Href
+		}
+	}
+
+	return ""
+}
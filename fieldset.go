@@ -0,0 +1,67 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+// Field sets defined by RFC 8982, for Request.FieldSet.
+//
+// Servers may support other field sets, see
+// SubsettingMetadata.AvailableFieldSets.
+const (
+	// Only the identifiers of each object, e.g. ldhName and handle.
+	FieldSetID = "id"
+
+	// The identifiers, status, and a few other key members.
+	FieldSetBrief = "brief"
+
+	// All available members (the server default).
+	FieldSetFull = "full"
+)
+
+// SubsettingMetadata describes the field set used in search results, as per
+// RFC 8982.
+//
+// https://tools.ietf.org/html/rfc8982#section-4
+type SubsettingMetadata struct {
+	DecodeData *DecodeData
+
+	// The field set used, e.g. "brief".
+	CurrentFieldSet string
+
+	// Field sets supported by the server.
+	AvailableFieldSets []AvailableFieldSet
+}
+
+// AvailableFieldSet is a field set supported by the server.
+type AvailableFieldSet struct {
+	DecodeData *DecodeData
+
+	Name        string
+	Description string
+
+	// True if this is the default field set.
+	Default *bool
+
+	// Links to the search results using this field set.
+	Links []Link
+}
+
+// subsettingMetadataOf returns the SubsettingMetadata of the search results
+// |obj|, or nil if none.
+func subsettingMetadataOf(obj RDAPObject) *SubsettingMetadata {
+	switch v := obj.(type) {
+	case *DomainSearchResults:
+		return v.SubsettingMetadata
+	case *NameserverSearchResults:
+		return v.SubsettingMetadata
+	case *EntitySearchResults:
+		return v.SubsettingMetadata
+	case *IPNetworkSearchResults:
+		return v.SubsettingMetadata
+	case *AutnumSearchResults:
+		return v.SubsettingMetadata
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSubsettingMetadata(t *testing.T) {
+	json := `{
+		"rdapConformance": ["rdap_level_0", "subsetting"],
+		"subsetting_metadata": {
+			"currentFieldSet": "brief",
+			"availableFieldSets": [
+				{"name": "id", "description": "Identifiers only", "default": false,
+				 "links": [{"value": "https://example.com/rdap/domains?name=example*.com", "rel": "alternate", "href": "https://example.com/rdap/domains?name=example*.com&fieldSet=id", "type": "application/rdap+json"}]},
+				{"name": "brief", "description": "Identifiers and status", "default": true}
+			]
+		},
+		"domainSearchResults": [
+			{"objectClassName": "domain", "ldhName": "example1.com"}
+		]
+	}`
+
+	sr := decodeTestObject(t, json).(*DomainSearchResults)
+
+	ssm := sr.SubsettingMetadata
+	if ssm == nil || ssm.CurrentFieldSet != FieldSetBrief || len(ssm.AvailableFieldSets) != 2 {
+		t.Fatalf("SubsettingMetadata decoded incorrectly: %+v", ssm)
+	}
+
+	if fs := ssm.AvailableFieldSets[1]; fs.Name != "brief" || fs.Default == nil || !*fs.Default {
+		t.Errorf("AvailableFieldSet decoded incorrectly: %+v", fs)
+	}
+
+	var buf bytes.Buffer
+	(&Printer{Writer: &buf}).Print(sr)
+	if !strings.Contains(buf.String(), "  Field Set: brief\n") {
+		t.Errorf("Field set not printed:\n%s", buf.String())
+	}
+}
+
+func TestRequestFieldSet(t *testing.T) {
+	server, _ := url.Parse("https://example.com/rdap/")
+
+	req := NewRequest(DomainSearchRequest, "example*.com")
+	req.Server = server
+	req.FieldSet = FieldSetID
+
+	expected := "https://example.com/rdap/domains?fieldSet=id&name=example%2A.com"
+	if u := req.URL().String(); u != expected {
+		t.Errorf("Got URL %s, expected %s", u, expected)
+	}
+}
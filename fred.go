@@ -0,0 +1,61 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+// FredNSSet is a set of nameservers, as per the FRED registry extension
+// (fred_version_0) used by CZ.NIC. Domains reference their nameservers via
+// an NSSET, see Domain.FredNSSet.
+//
+// FredNSSet is also a topmost RDAP response object, e.g. for
+// https://rdap.nic.cz/fred_nsset/NSS:PIPNI:1.
+type FredNSSet struct {
+	DecodeData *DecodeData
+
+	Common
+	Conformance     []string `rdap:"rdapConformance"`
+	ObjectClassName string
+	Notices         []Notice
+
+	Handle      string
+	Nameservers []Nameserver
+	Entities    []Entity
+	Status      []string
+	Remarks     []Remark
+	Links       []Link
+	Events      []Event
+}
+
+// FredKeySet is a set of DNSSEC keys, as per the FRED registry extension
+// (fred_version_0) used by CZ.NIC. Domains reference their keys via a
+// KEYSET, see Domain.FredKeySet.
+//
+// FredKeySet is also a topmost RDAP response object, e.g. for
+// https://rdap.nic.cz/fred_keyset/KEYSET-1.
+type FredKeySet struct {
+	DecodeData *DecodeData
+
+	Common
+	Conformance     []string `rdap:"rdapConformance"`
+	ObjectClassName string
+	Notices         []Notice
+
+	Handle   string
+	DNSKeys  []FredDNSKey `rdap:"dns_keys"`
+	Entities []Entity
+	Status   []string
+	Remarks  []Remark
+	Links    []Link
+	Events   []Event
+}
+
+// FredDNSKey is a DNSKEY record of a FredKeySet.
+type FredDNSKey struct {
+	DecodeData *DecodeData
+
+	Flags     *uint16
+	Protocol  *uint8
+	Algorithm *uint8 `rdap:"alg"`
+	PublicKey string `rdap:"public_key"`
+}
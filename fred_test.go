@@ -0,0 +1,59 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecodeFredNSSet(t *testing.T) {
+	domain := loadObject("rdap/rdap.nic.cz/domain-example.cz.json").(*Domain)
+
+	nsset := domain.FredNSSet
+	if nsset == nil {
+		t.Fatalf("FredNSSet not decoded")
+	} else if nsset.Handle != "NSS:PIPNI:1" || len(nsset.Nameservers) != 3 || nsset.Nameservers[0].LDHName != "ns2.pipni.cz" {
+		t.Errorf("FredNSSet decoded incorrectly: %+v", nsset)
+	}
+
+	for _, f := range domain.DecodeData.UnknownFields() {
+		if f == "fred_nsset" {
+			t.Errorf("fred_nsset still an unknown field")
+		}
+	}
+
+	var buf bytes.Buffer
+	printer := &Printer{Writer: &buf, BriefLinks: true}
+	printer.Print(domain)
+
+	if !strings.Contains(buf.String(), "  NSSET:\n    Handle: NSS:PIPNI:1\n") {
+		t.Errorf("NSSET not printed:\n%s", buf.String())
+	}
+}
+
+func TestDecodeFredKeySet(t *testing.T) {
+	obj := decodeTestObject(t, `{
+		"objectClassName": "fred_keyset",
+		"rdapConformance": ["rdap_level_0", "fred_version_0"],
+		"handle": "KEYSET-1",
+		"dns_keys": [{"flags": 257, "protocol": 3, "alg": 13, "public_key": "AwEAAQ=="}]
+	}`)
+
+	keyset, ok := obj.(*FredKeySet)
+	if !ok {
+		t.Fatalf("Expected *FredKeySet, got %T", obj)
+	}
+
+	if len(keyset.DNSKeys) != 1 {
+		t.Fatalf("Expected 1 DNS key, got %d", len(keyset.DNSKeys))
+	}
+
+	k := keyset.DNSKeys[0]
+	if *k.Flags != 257 || *k.Protocol != 3 || *k.Algorithm != 13 || k.PublicKey != "AwEAAQ==" {
+		t.Errorf("DNS key decoded incorrectly: %+v", k)
+	}
+}
@@ -0,0 +1,387 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Graph is the relationship graph of one or more RDAP objects.
+//
+// Each RDAP object (domain, entity, nameserver, IP network, autnum), IP
+// address and link is a GraphNode. Edges point from an object to the objects
+// it references, e.g. a domain to its nameservers and contacts.
+//
+// Nodes are identified by the object's identity (e.g. domain name, entity
+// handle), so adding several responses to one Graph merges shared objects.
+// Entities without an identity (no handle or name, or a redacted one) are
+// never merged. This shows infrastructure shared between domains:
+//
+//	g := rdap.NewGraph()
+//
+//	for _, domain := range domains {
+//	  resp, err := client.Do(rdap.NewDomainRequest(domain))
+//	  ...
+//	  g.Add(resp.Object)
+//	}
+//
+//	g.WriteDOT(os.Stdout)
+type Graph struct {
+	Nodes []*GraphNode `json:"nodes"`
+	Edges []*GraphEdge `json:"edges"`
+
+	nodes map[string]*GraphNode
+	edges map[GraphEdge]bool
+
+	// Number of topmost anonymous entities added, see addEntity().
+	anonymous int
+}
+
+// GraphNode is a node in a Graph.
+type GraphNode struct {
+	// Unique node ID, e.g. "domain:example.cz".
+	ID string `json:"id"`
+
+	// Node type: "domain", "entity", "nameserver", "ip network", "autnum",
+	// "ip address", or "link".
+	Type string `json:"type"`
+
+	// Human readable label, e.g. "example.cz".
+	Label string `json:"label"`
+}
+
+// GraphEdge is a directed edge in a Graph.
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	// Relationship, e.g. "nameserver", an entity role ("registrant"), or a
+	// link relation type ("related").
+	Label string `json:"label"`
+}
+
+// NewGraph creates an empty Graph.
+func NewGraph() *Graph {
+	return &Graph{
+		Nodes: []*GraphNode{},
+		Edges: []*GraphEdge{},
+
+		nodes: map[string]*GraphNode{},
+		edges: map[GraphEdge]bool{},
+	}
+}
+
+// Add adds the RDAP object |obj|, and all objects it references, to the graph.
+//
+// Search results add each result object. Other objects (e.g. Help, Error) are
+// ignored.
+func (g *Graph) Add(obj RDAPObject) {
+	switch o := obj.(type) {
+	case *Domain:
+		g.addDomain(o)
+	case *Entity:
+		g.addEntity(o, "")
+	case *Nameserver:
+		g.addNameserver(o)
+	case *IPNetwork:
+		g.addIPNetwork(o)
+	case *Autnum:
+		g.addAutnum(o)
+	case *DomainSearchResults:
+		for i := range o.Domains {
+			g.addDomain(&o.Domains[i])
+		}
+	case *EntitySearchResults:
+		for i := range o.Entities {
+			g.addEntity(&o.Entities[i], "")
+		}
+	case *NameserverSearchResults:
+		for i := range o.Nameservers {
+			g.addNameserver(&o.Nameservers[i])
+		}
+	case *IPNetworkSearchResults:
+		for i := range o.Networks {
+			g.addIPNetwork(&o.Networks[i])
+		}
+	case *AutnumSearchResults:
+		for i := range o.Autnums {
+			g.addAutnum(&o.Autnums[i])
+		}
+	}
+}
+
+func (g *Graph) addDomain(d *Domain) string {
+	name := indexName(firstNonEmpty(d.LDHName, d.UnicodeName))
+	id := g.addNode("domain", firstNonEmpty(name, d.Handle), firstNonEmpty(d.UnicodeName, d.LDHName, d.Handle))
+
+	for i := range d.Nameservers {
+		g.addEdge(id, g.addNameserver(&d.Nameservers[i]), "nameserver")
+	}
+
+	g.addEntities(id, d.Entities)
+
+	if d.Network != nil {
+		g.addEdge(id, g.addIPNetwork(d.Network), "network")
+	}
+
+	g.addLinks(id, d.Links)
+
+	return id
+}
+
+// addEntity adds the Entity |e|, returning its node ID. |scope| identifies
+// where |e| appears (its parent's node ID, roles, and index), and is empty for
+// a topmost Entity.
+//
+// Entities are identified by handle, or by name if they have no handle.
+// Entities with neither (or only redaction placeholders, e.g. "REDACTED FOR
+// PRIVACY") can't be told apart, so each has its own node, keyed by |scope|.
+// Otherwise unrelated domains would appear to share a contact.
+func (g *Graph) addEntity(e *Entity, scope string) string {
+	key := e.Handle
+	label := e.Handle
+
+	if isRedactedText(key) {
+		key = ""
+	}
+
+	if e.VCard != nil {
+		if name := e.VCard.Name(); name != "" {
+			if key == "" && !isRedactedText(name) {
+				key = "name:" + name
+			}
+			label = name
+		}
+	}
+
+	if key == "" {
+		if scope == "" {
+			g.anonymous++
+			scope = strconv.Itoa(g.anonymous)
+		}
+
+		key = "anonymous:" + scope
+	}
+
+	id := g.addNode("entity", key, label)
+
+	g.addEntities(id, e.Entities)
+
+	for i := range e.Networks {
+		g.addEdge(id, g.addIPNetwork(&e.Networks[i]), "network")
+	}
+
+	for i := range e.Autnums {
+		g.addEdge(id, g.addAutnum(&e.Autnums[i]), "autnum")
+	}
+
+	g.addLinks(id, e.Links)
+
+	return id
+}
+
+func (g *Graph) addNameserver(n *Nameserver) string {
+	name := indexName(firstNonEmpty(n.LDHName, n.UnicodeName))
+	id := g.addNode("nameserver", firstNonEmpty(name, n.Handle), firstNonEmpty(n.UnicodeName, n.LDHName, n.Handle))
+
+	if n.IPAddresses != nil {
+		for _, ips := range [][]string{n.IPAddresses.V4, n.IPAddresses.V6} {
+			for _, ip := range ips {
+				g.addEdge(id, g.addNode("ip address", strings.ToLower(ip), ip), "ip address")
+			}
+		}
+	}
+
+	g.addEntities(id, n.Entities)
+	g.addLinks(id, n.Links)
+
+	return id
+}
+
+func (g *Graph) addIPNetwork(n *IPNetwork) string {
+	key := n.Handle
+	label := n.Handle
+
+	if n.StartAddress != "" && n.EndAddress != "" {
+		key = n.StartAddress + "-" + n.EndAddress
+		label = key
+	}
+
+	if n.Name != "" {
+		label = fmt.Sprintf("%s (%s)", label, n.Name)
+	}
+
+	id := g.addNode("ip network", key, label)
+
+	g.addEntities(id, n.Entities)
+	g.addLinks(id, n.Links)
+
+	return id
+}
+
+func (g *Graph) addAutnum(a *Autnum) string {
+	key := a.Handle
+	label := a.Handle
+
+	if a.StartAutnum != nil {
+		key = "AS" + strconv.FormatUint(uint64(*a.StartAutnum), 10)
+		if a.EndAutnum != nil && *a.EndAutnum != *a.StartAutnum {
+			key += "-AS" + strconv.FormatUint(uint64(*a.EndAutnum), 10)
+		}
+		label = key
+	}
+
+	if a.Name != "" {
+		label = fmt.Sprintf("%s (%s)", label, a.Name)
+	}
+
+	id := g.addNode("autnum", key, label)
+
+	g.addEntities(id, a.Entities)
+	g.addLinks(id, a.Links)
+
+	return id
+}
+
+// addEntities adds |entities|, with an edge from |from| labelled with each
+// entity's roles.
+func (g *Graph) addEntities(from string, entities []Entity) {
+	for i := range entities {
+		e := &entities[i]
+
+		label := "entity"
+		if len(e.Roles) > 0 {
+			label = strings.Join(e.Roles, ",")
+		}
+
+		g.addEdge(from, g.addEntity(e, fmt.Sprintf("%s/%s/%d", from, label, i)), label)
+	}
+}
+
+// isRedactedText returns true if |s| is a redaction placeholder, e.g.
+// "REDACTED FOR PRIVACY", or "Data withheld".
+func isRedactedText(s string) bool {
+	s = strings.ToUpper(s)
+
+	for _, placeholder := range []string{"REDACTED", "PRIVACY", "WITHHELD", "NOT DISCLOSED"} {
+		if strings.Contains(s, placeholder) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addLinks adds |links|, with an edge from |from| labelled with each link's
+// relation type. "self" links are skipped, as they point at |from| itself.
+func (g *Graph) addLinks(from string, links []Link) {
+	for _, l := range links {
+		if l.Href == "" || l.Rel == "self" {
+			continue
+		}
+
+		g.addEdge(from, g.addNode("link", l.Href, l.Href), firstNonEmpty(l.Rel, "link"))
+	}
+}
+
+// addNode adds a node of type |nodeType|, returning its ID. If a node with the
+// same |key| already exists, it's reused.
+func (g *Graph) addNode(nodeType string, key string, label string) string {
+	id := nodeType + ":" + key
+
+	if _, ok := g.nodes[id]; !ok {
+		n := &GraphNode{
+			ID:    id,
+			Type:  nodeType,
+			Label: label,
+		}
+
+		g.nodes[id] = n
+		g.Nodes = append(g.Nodes, n)
+	}
+
+	return id
+}
+
+func (g *Graph) addEdge(from string, to string, label string) {
+	e := GraphEdge{From: from, To: to, Label: label}
+
+	if from == to || g.edges[e] {
+		return
+	}
+
+	g.edges[e] = true
+	g.Edges = append(g.Edges, &e)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format to |w|.
+//
+// Render using e.g. "dot -Tsvg graph.dot > graph.svg".
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph rdap {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(n.ID), dotQuote(n.Label), dotShape(n.Type))
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Label))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// WriteJSON writes the graph in JSON format to |w|, as {"nodes": [...],
+// "edges": [...]}.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	out, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(out, '\n'))
+
+	return err
+}
+
+// dotQuote returns |s| as a quoted DOT ID.
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+
+	return `"` + s + `"`
+}
+
+// dotShape returns the DOT node shape for a GraphNode of type |nodeType|.
+func dotShape(nodeType string) string {
+	switch nodeType {
+	case "domain":
+		return "box"
+	case "entity":
+		return "ellipse"
+	case "nameserver":
+		return "component"
+	case "ip network", "autnum":
+		return "box3d"
+	case "ip address":
+		return "plaintext"
+	default:
+		return "note"
+	}
+}
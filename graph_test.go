@@ -0,0 +1,146 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestGraph(t *testing.T) {
+	g := NewGraph()
+
+	for _, body := range []string{
+		`{
+			"objectClassName": "domain",
+			"ldhName": "EXAMPLE.net",
+			"nameservers": [{"objectClassName": "nameserver", "ldhName": "ns1.host.example",
+			                 "ipAddresses": {"v4": ["192.0.2.1"]}}],
+			"entities": [{"objectClassName": "entity", "handle": "R1", "roles": ["registrar"]}],
+			"links": [{"rel": "self", "href": "https://rdap.example/domain/example.net"},
+			          {"rel": "related", "href": "https://rdap.registrar.example/domain/example.net"}]
+		}`,
+		`{
+			"objectClassName": "domain",
+			"ldhName": "example.org",
+			"nameservers": [{"objectClassName": "nameserver", "ldhName": "NS1.host.example."}],
+			"entities": [{"objectClassName": "entity", "handle": "R1", "roles": ["registrar"]}]
+		}`,
+	} {
+		obj, err := NewDecoder([]byte(body)).Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		g.Add(obj)
+	}
+
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+
+	expected := "domain:example.net nameserver:ns1.host.example ip address:192.0.2.1 entity:R1 link:https://rdap.registrar.example/domain/example.net domain:example.org"
+	if strings.Join(ids, " ") != expected {
+		t.Errorf("Unexpected nodes %q", ids)
+	}
+
+	if len(g.Edges) != 6 {
+		t.Fatalf("Expected 6 edges, got %d", len(g.Edges))
+	}
+
+	if e := g.Edges[2]; e.From != "domain:example.net" || e.To != "entity:R1" || e.Label != "registrar" {
+		t.Errorf("Unexpected edge %+v", e)
+	}
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(dot.String(), "digraph rdap {\n") ||
+		!strings.Contains(dot.String(), `"domain:example.org" -> "nameserver:ns1.host.example" [label="nameserver"];`) {
+		t.Errorf("Unexpected DOT output:\n%s", dot.String())
+	}
+
+	var out bytes.Buffer
+	if err := g.WriteJSON(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Graph
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	} else if len(decoded.Nodes) != len(g.Nodes) || len(decoded.Edges) != len(g.Edges) {
+		t.Errorf("Unexpected JSON output:\n%s", out.String())
+	}
+}
+
+func TestGraphAnonymousEntities(t *testing.T) {
+	g := NewGraph()
+
+	for _, name := range []string{"example.net", "example.org"} {
+		body := `{
+			"objectClassName": "domain",
+			"ldhName": "` + name + `",
+			"entities": [
+				{"objectClassName": "entity", "roles": ["registrant"],
+				 "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "REDACTED FOR PRIVACY"]]]},
+				{"objectClassName": "entity", "roles": ["technical"]},
+				{"objectClassName": "entity", "roles": ["technical"]},
+				{"objectClassName": "entity", "handle": "R1", "roles": ["registrar"]}
+			]
+		}`
+
+		obj, err := NewDecoder([]byte(body)).Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		g.Add(obj)
+	}
+
+	// Each anonymous entity has its own node. Only the registrar is shared.
+	entities := map[string]int{}
+	for _, e := range g.Edges {
+		entities[e.To]++
+	}
+
+	if len(g.Nodes) != 9 || entities["entity:R1"] != 2 {
+		t.Errorf("Unexpected nodes %d %v", len(g.Nodes), entities)
+	}
+
+	for id, n := range entities {
+		if id != "entity:R1" && n != 1 {
+			t.Errorf("Anonymous entity %s shared by %d domains", id, n)
+		}
+	}
+}
+
+func TestDotQuote(t *testing.T) {
+	if q := dotQuote("a \"b\"\\"); q != `"a \"b\"\\"` {
+		t.Errorf("Unexpected %s", q)
+	}
+}
+
+func TestGraphDomainFixture(t *testing.T) {
+	obj, err := NewDecoder(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGraph()
+	g.Add(obj)
+
+	for _, id := range []string{"domain:example.cz", "nameserver:ns.pipni.cz", "entity:SB:EXAMPLE", "entity:REG-INTERNET-CZ"} {
+		if g.nodes[id] == nil {
+			t.Errorf("Missing node %s", id)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+)
+
+// HealthHandler is an http.Handler serving health check endpoints for
+// long-running services built on a Client, e.g. for Kubernetes probes:
+//
+//	/healthz - Liveness. Always returns 200 OK.
+//	/readyz  - Readiness. Returns 200 OK if the bootstrap Service Registry
+//	           files are available, all Probes succeed, and the query
+//	           backlog is within MaxBacklog, otherwise 503 Service
+//	           Unavailable.
+//
+// Both endpoints return a JSON status document, including the freshness of
+// each Service Registry file, the result of each probe, and the number of
+// queries in progress.
+//
+// Example usage:
+//
+//	health := &rdap.HealthHandler{
+//	  Client: client,
+//	  Probes: []*rdap.Request{rdap.NewHelpRequest().WithServer(serverURL)},
+//	}
+//
+//	mux.Handle("/healthz", health)
+//	mux.Handle("/readyz", health)
+type HealthHandler struct {
+	// Client to report on.
+	Client *Client
+
+	// Service Registry files required for readiness. Default is DNS, IPv4,
+	// IPv6, and ASN.
+	//
+	// The files must be available (e.g. downloaded in advance with
+	// Client.Bootstrap.Download()). Files past the cache timeout are reported
+	// as not fresh, but don't affect readiness, as the next Lookup refreshes
+	// them.
+	Registries []bootstrap.RegistryType
+
+	// Optional requests checking upstream RDAP server reachability.
+	//
+	// Each probe is run on each readiness check, so should be cheap (e.g. a
+	// help query). A probe succeeds if the server responds, even with an
+	// ObjectDoesNotExist error.
+	Probes []*Request
+
+	// Maximum duration of each probe. Default is 5 seconds.
+	ProbeTimeout time.Duration
+
+	// Maximum number of queries in progress on the Client (see
+	// Client.InFlight()) for readiness, e.g. to stop routing traffic to an
+	// overloaded instance. The default (zero) is unlimited.
+	MaxBacklog int64
+}
+
+// HealthStatus is the JSON document returned by HealthHandler.
+type HealthStatus struct {
+	Status    string                 `json:"status"`
+	Backlog   int64                  `json:"backlog"`
+	Bootstrap []HealthRegistryStatus `json:"bootstrap,omitempty"`
+	Upstream  []HealthUpstreamStatus `json:"upstream,omitempty"`
+}
+
+// HealthRegistryStatus reports the state of a Service Registry file.
+type HealthRegistryStatus struct {
+	Registry    string `json:"registry"`
+	Available   bool   `json:"available"`
+	Fresh       bool   `json:"fresh"`
+	Embedded    bool   `json:"embedded,omitempty"`
+	Publication string `json:"publication,omitempty"`
+}
+
+// HealthUpstreamStatus reports the result of a probe.
+type HealthUpstreamStatus struct {
+	Probe    string `json:"probe"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var status *HealthStatus
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/healthz"):
+		status = &HealthStatus{Status: "ok"}
+	case strings.HasSuffix(r.URL.Path, "/readyz"):
+		status = h.Ready(r.Context())
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	code := http.StatusOK
+	if status.Status != "ok" {
+		code = http.StatusServiceUnavailable
+	}
+
+	body, _ := json.MarshalIndent(status, "", "  ")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(append(body, '\n'))
+}
+
+// Ready runs the readiness checks, and returns the result. The Status is "ok"
+// if ready, otherwise "not ready".
+func (h *HealthHandler) Ready(ctx context.Context) *HealthStatus {
+	c := h.Client
+	c.init()
+
+	// Measured before the probes, which add to it.
+	status := &HealthStatus{Status: "ok", Backlog: c.InFlight()}
+	if h.MaxBacklog > 0 && status.Backlog > h.MaxBacklog {
+		status.Status = "not ready"
+	}
+
+	registries := h.Registries
+	if len(registries) == 0 {
+		registries = []bootstrap.RegistryType{bootstrap.DNS, bootstrap.IPv4, bootstrap.IPv6, bootstrap.ASN}
+	}
+
+	for _, registry := range registries {
+		s := c.Bootstrap.Status(registry)
+
+		status.Bootstrap = append(status.Bootstrap, HealthRegistryStatus{
+			Registry:    registry.String(),
+			Available:   s.Available,
+			Fresh:       s.Fresh,
+			Embedded:    s.Embedded,
+			Publication: s.Publication,
+		})
+
+		if !s.Available {
+			status.Status = "not ready"
+		}
+	}
+
+	timeout := h.ProbeTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	for _, probe := range h.Probes {
+		probeCtx, cancelFunc := context.WithTimeout(ctx, timeout)
+
+		start := time.Now()
+		_, err := c.Do(probe.WithContext(probeCtx))
+		cancelFunc()
+
+		name := fmt.Sprintf("%s %s", probe.Type, probe.Query)
+		if u := probe.URL(); u != nil {
+			name = u.String()
+		}
+
+		s := HealthUpstreamStatus{
+			Probe:    name,
+			OK:       err == nil || errors.Is(err, ErrObjectDoesNotExist),
+			Duration: time.Since(start).String(),
+		}
+
+		if !s.OK {
+			s.Error = err.Error()
+			status.Status = "not ready"
+		}
+
+		status.Upstream = append(status.Upstream, s)
+	}
+
+	return status
+}
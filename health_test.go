@@ -0,0 +1,155 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/test"
+)
+
+func TestHealthHandler(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	serverURL, _ := url.Parse("https://rdap.nic.cz")
+
+	health := &HealthHandler{
+		Client:     client,
+		Registries: []bootstrap.RegistryType{bootstrap.DNS},
+		Probes: []*Request{
+			NewDomainRequest("example.cz").WithServer(serverURL),
+			NewDomainRequest("non-existent.cz").WithServer(serverURL),
+		},
+	}
+
+	get := func(path string) (int, *HealthStatus) {
+		w := httptest.NewRecorder()
+		health.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		status := &HealthStatus{}
+		json.Unmarshal(w.Body.Bytes(), status)
+
+		return w.Code, status
+	}
+
+	if code, status := get("/healthz"); code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("/healthz: unexpected %d %+v", code, status)
+	}
+
+	// The DNS Service Registry file isn't available yet.
+	if code, status := get("/readyz"); code != http.StatusServiceUnavailable || status.Bootstrap[0].Available {
+		t.Errorf("/readyz: unexpected %d %+v", code, status)
+	}
+
+	if err := client.Bootstrap.Download(bootstrap.DNS); err != nil {
+		t.Fatal(err)
+	}
+
+	code, status := get("/readyz")
+	if code != http.StatusOK {
+		t.Errorf("/readyz: unexpected %d %+v", code, status)
+	} else if b := status.Bootstrap[0]; b.Registry != "dns" || !b.Available || !b.Fresh || b.Publication == "" {
+		t.Errorf("/readyz: unexpected bootstrap status %+v", b)
+	} else if len(status.Upstream) != 2 || !status.Upstream[0].OK || !status.Upstream[1].OK {
+		t.Errorf("/readyz: unexpected upstream status %+v", status.Upstream)
+	}
+
+	// Unreachable upstream.
+	unreachable, _ := url.Parse("https://rdap.unreachable.example")
+	health.Probes = []*Request{NewHelpRequest().WithServer(unreachable)}
+
+	if code, status := get("/readyz"); code != http.StatusServiceUnavailable || status.Upstream[0].OK || status.Upstream[0].Error == "" {
+		t.Errorf("/readyz: unexpected %d %+v", code, status)
+	}
+
+	if code, _ := get("/other"); code != http.StatusNotFound {
+		t.Errorf("/other: unexpected %d", code)
+	}
+}
+
+func TestHealthHandlerBacklog(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	client := &Client{}
+	health := &HealthHandler{
+		Client:     client,
+		Registries: []bootstrap.RegistryType{bootstrap.DNS},
+		MaxBacklog: 2,
+	}
+
+	// Concurrent readiness checks share the Client's bootstrap client (run
+	// with -race).
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			health.Ready(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if err := client.Bootstrap.Download(bootstrap.DNS); err != nil {
+		t.Fatal(err)
+	}
+
+	if status := health.Ready(context.Background()); status.Status != "ok" || status.Backlog != 0 {
+		t.Errorf("Unexpected status %+v", status)
+	}
+
+	atomic.StoreInt64(&client.inFlight, 3)
+	if status := health.Ready(context.Background()); status.Status != "not ready" || status.Backlog != 3 {
+		t.Errorf("Expected backlog of 3 to be not ready, got %+v", status)
+	}
+}
+
+func TestHealthHandlerConcurrentQueries(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{}
+	health := &HealthHandler{
+		Client:     client,
+		Registries: []bootstrap.RegistryType{bootstrap.DNS},
+	}
+
+	// Readiness checks share the bootstrap client with queries (run with
+	// -race).
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			health.Ready(context.Background())
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := client.Do(NewDomainRequest("example.cz")); err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if status := health.Ready(context.Background()); status.Status != "ok" {
+		t.Errorf("Unexpected status %+v", status)
+	}
+}
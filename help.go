@@ -4,6 +4,14 @@
 
 package rdap
 
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // Help represents a help response.
 //
 // Help is a topmost RDAP response object.
@@ -13,4 +21,107 @@ type Help struct {
 	Common
 	Conformance []string `rdap:"rdapConformance"`
 	Notices     []Notice
+
+	// Rate limits documented in the Notices, e.g. "10 queries per minute".
+	// Filled in by the decoder, see ParseRateLimits().
+	RateLimits []RateLimit `rdap:"-"`
+}
+
+// TermsOfService returns the help response's terms of service notice, or nil
+// if none.
+func (h *Help) TermsOfService() *Notice {
+	for i, n := range h.Notices {
+		title := strings.ToLower(n.Title)
+		if strings.Contains(title, "terms of service") || strings.Contains(title, "terms of use") {
+			return &h.Notices[i]
+		}
+	}
+
+	return nil
+}
+
+// RateLimit is a query rate limit documented by an RDAP server.
+type RateLimit struct {
+	// Maximum number of queries allowed per Period.
+	Queries uint64
+	Period  time.Duration
+
+	// The notice text the rate limit was parsed from.
+	Text string
+}
+
+// String returns the rate limit in the form "10 queries per minute".
+func (r RateLimit) String() string {
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+		{"second", time.Second},
+	}
+
+	for _, u := range units {
+		if r.Period%u.duration != 0 {
+			continue
+		}
+
+		if n := r.Period / u.duration; n != 1 {
+			return fmt.Sprintf("%d queries per %d %ss", r.Queries, n, u.name)
+		}
+
+		return fmt.Sprintf("%d queries per %s", r.Queries, u.name)
+	}
+
+	return fmt.Sprintf("%d queries per %s", r.Queries, r.Period)
+}
+
+// rateLimitRegexp matches rate limits such as "10 queries per minute",
+// "1,000 requests/day", and "5 lookups every 30 seconds".
+var rateLimitRegexp = regexp.MustCompile(`(?i)(\d[\d,]*)\s+(?:rdap\s+)?(?:queries|query|requests|request|lookups|lookup)\s*(?:per|every|each|a|an|/|in)\s*(\d+\s+)?(second|sec|minute|min|hour|hr|day)s?\b`)
+
+// ParseRateLimits returns the rate limits documented in |notices|.
+//
+// RDAP has no standard way to advertise rate limits, so they're found by
+// matching phrases such as "10 queries per minute" in the notice
+// descriptions.
+func ParseRateLimits(notices []Notice) []RateLimit {
+	var limits []RateLimit
+
+	for _, n := range notices {
+		for _, d := range n.Description {
+			for _, m := range rateLimitRegexp.FindAllStringSubmatch(d, -1) {
+				queries, err := strconv.ParseUint(strings.ReplaceAll(m[1], ",", ""), 10, 64)
+				if err != nil {
+					continue
+				}
+
+				count := int64(1)
+				if m[2] != "" {
+					count, _ = strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64)
+				}
+
+				var unit time.Duration
+				switch strings.ToLower(m[3]) {
+				case "second", "sec":
+					unit = time.Second
+				case "minute", "min":
+					unit = time.Minute
+				case "hour", "hr":
+					unit = time.Hour
+				case "day":
+					unit = 24 * time.Hour
+				}
+
+				limits = append(limits, RateLimit{
+					Queries: queries,
+					Period:  time.Duration(count) * unit,
+					Text:    m[0],
+				})
+			}
+		}
+	}
+
+	return limits
 }
@@ -0,0 +1,61 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeHelpRateLimits(t *testing.T) {
+	json := `{
+		"rdapConformance": ["rdap_level_0"],
+		"notices": [
+			{"title": "Terms of Service", "description": ["Use of this service is subject to our terms."]},
+			{"title": "Rate Limits", "description": [
+				"Clients are limited to 10 queries per minute, and 1,000 requests/day.",
+				"Searches are limited to 5 lookups every 30 seconds."
+			]}
+		]
+	}`
+
+	h := decodeTestObject(t, json).(*Help)
+
+	expected := []RateLimit{
+		{Queries: 10, Period: time.Minute, Text: "10 queries per minute"},
+		{Queries: 1000, Period: 24 * time.Hour, Text: "1,000 requests/day"},
+		{Queries: 5, Period: 30 * time.Second, Text: "5 lookups every 30 seconds"},
+	}
+
+	if len(h.RateLimits) != len(expected) {
+		t.Fatalf("Got rate limits %v, expected %v", h.RateLimits, expected)
+	}
+
+	for i, r := range h.RateLimits {
+		if r != expected[i] {
+			t.Errorf("Rate limit %d: got %+v, expected %+v", i, r, expected[i])
+		}
+	}
+
+	if s := h.RateLimits[2].String(); s != "5 queries per 30 seconds" {
+		t.Errorf("Unexpected String() %q", s)
+	}
+
+	if tos := h.TermsOfService(); tos == nil || tos.Title != "Terms of Service" {
+		t.Errorf("TermsOfService() = %v", tos)
+	}
+
+	var buf bytes.Buffer
+	(&Printer{Writer: &buf}).Print(h)
+	if !strings.Contains(buf.String(), "  Rate Limit: 1000 queries per day\n") {
+		t.Errorf("Rate limits not printed:\n%s", buf.String())
+	}
+
+	if _, err := Encode(h); err != nil {
+		t.Errorf("Encode failed: %s", err)
+	}
+}
@@ -0,0 +1,228 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// HostPolicy restricts which hosts a Client may contact.
+//
+// This is useful for services which pass user supplied URLs to
+// NewRawRequest(), to prevent them being used to make requests to internal
+// hosts.
+//
+// Example usage:
+//
+//	client := &rdap.Client{
+//	  HostPolicy: &rdap.HostPolicy{
+//	    AllowedDomains: []string{"nic.cz", "arin.net"},
+//	    DenyPrivateIPs: true,
+//	  },
+//	}
+//
+// The policy applies to RDAP server URLs (including HTTP redirects), not to
+// bootstrap downloads.
+type HostPolicy struct {
+	// Optional list of permitted domain names.
+	//
+	// A host is permitted if it equals, or is a subdomain of, one of the
+	// listed domain names. e.g. "nic.cz" permits "rdap.nic.cz".
+	//
+	// If empty, all hosts are permitted (subject to DeniedDomains and
+	// DenyPrivateIPs).
+	AllowedDomains []string
+
+	// Optional list of denied domain names.
+	//
+	// Matching is as per AllowedDomains. DeniedDomains takes precedence over
+	// AllowedDomains.
+	DeniedDomains []string
+
+	// DenyPrivateIPs denies IP address literal hosts in private (RFC 1918,
+	// RFC 4193), loopback, link-local, and unspecified address ranges.
+	DenyPrivateIPs bool
+
+	// Optional list of permitted object classes for RawRequest (URL query)
+	// responses.
+	//
+	// Object classes are objectClassName values ("domain", "ip network",
+	// "autnum", "entity", "nameserver", and the FRED extension's
+	// "fred_nsset" and "fred_keyset"), or "domainSearchResults",
+	// "entitySearchResults", "nameserverSearchResults",
+	// "networkSearchResults", "autnumSearchResults", or "help" for the other
+	// response types. e.g. []string{"domain", "ip network"}.
+	//
+	// Responses of other classes are rejected with a WrongResponseType
+	// *ClientError. RDAP Error responses are always permitted. If empty, all
+	// object classes are permitted.
+	RawObjectClasses []string
+
+	// Maximum number of HTTP redirects to follow per RDAP server query. 0
+	// means the default of 10, and a negative value disables redirects.
+	MaxRedirects int
+}
+
+// Check returns an error if the policy does not permit requests to |u|.
+//
+// Returns nil if the request is permitted.
+func (h *HostPolicy) Check(u *url.URL) error {
+	if u == nil {
+		return hostNotAllowedError(u, "", "no URL")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+
+	if host == "" {
+		return hostNotAllowedError(u, host, "empty hostname")
+	}
+
+	if h.DenyPrivateIPs {
+		if ip := net.ParseIP(host); ip != nil && isPrivateIP(ip) {
+			return hostNotAllowedError(u, host, "private IP address")
+		}
+	}
+
+	for _, d := range h.DeniedDomains {
+		if matchesDomain(host, d) {
+			return hostNotAllowedError(u, host, "denied domain")
+		}
+	}
+
+	if len(h.AllowedDomains) == 0 {
+		return nil
+	}
+
+	for _, d := range h.AllowedDomains {
+		if matchesDomain(host, d) {
+			return nil
+		}
+	}
+
+	return hostNotAllowedError(u, host, "not in allowed domains")
+}
+
+// CheckObject returns an error if the policy does not permit the RawRequest
+// response |obj|, see RawObjectClasses.
+//
+// Returns nil if the response is permitted.
+func (h *HostPolicy) CheckObject(obj RDAPObject, url string) error {
+	if len(h.RawObjectClasses) == 0 {
+		return nil
+	} else if _, ok := obj.(*Error); ok {
+		return nil
+	}
+
+	class := objectClassOf(obj)
+	for _, c := range h.RawObjectClasses {
+		if strings.EqualFold(c, class) {
+			return nil
+		}
+	}
+
+	return &ClientError{
+		Type: WrongResponseType,
+		Text: fmt.Sprintf("Response object class '%s' not permitted by HostPolicy", class),
+		URL:  url,
+	}
+}
+
+// checkRedirect returns an error if the policy does not permit a redirect
+// to |req|, after the previous requests |via|.
+func (h *HostPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	if err := h.Check(req.URL); err != nil {
+		return err
+	}
+
+	max := h.MaxRedirects
+	if max == 0 {
+		max = 10
+	} else if max < 0 {
+		max = 0
+	}
+
+	if len(via) > max {
+		return &ClientError{
+			Type: HostNotAllowed,
+			Text: fmt.Sprintf("Stopped after %d redirects, the HostPolicy maximum", max),
+			URL:  req.URL.String(),
+		}
+	}
+
+	return nil
+}
+
+// objectClassOf returns the object class name of the decoded response |obj|,
+// see HostPolicy.RawObjectClasses.
+func objectClassOf(obj RDAPObject) string {
+	switch obj.(type) {
+	case *Autnum:
+		return "autnum"
+	case *Domain:
+		return "domain"
+	case *Entity:
+		return "entity"
+	case *IPNetwork:
+		return "ip network"
+	case *Nameserver:
+		return "nameserver"
+	case *FredNSSet:
+		return "fred_nsset"
+	case *FredKeySet:
+		return "fred_keyset"
+	case *DomainSearchResults:
+		return "domainSearchResults"
+	case *EntitySearchResults:
+		return "entitySearchResults"
+	case *NameserverSearchResults:
+		return "nameserverSearchResults"
+	case *IPNetworkSearchResults:
+		return "networkSearchResults"
+	case *AutnumSearchResults:
+		return "autnumSearchResults"
+	case *Help:
+		return "help"
+	case *Error:
+		return "error"
+	default:
+		return fmt.Sprintf("%T", obj)
+	}
+}
+
+// matchesDomain returns true if |host| equals, or is a subdomain of, |domain|.
+func matchesDomain(host string, domain string) bool {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if domain == "" {
+		return false
+	}
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified()
+}
+
+func hostNotAllowedError(u *url.URL, host string, reason string) *ClientError {
+	ce := &ClientError{
+		Type: HostNotAllowed,
+		Text: fmt.Sprintf("Host '%s' not permitted by HostPolicy (%s)", host, reason),
+	}
+
+	if u != nil {
+		ce.URL = u.String()
+	}
+
+	return ce
+}
@@ -0,0 +1,163 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestHostPolicyCheck(t *testing.T) {
+	policy := &HostPolicy{
+		AllowedDomains: []string{"nic.cz", "arin.net", "192.168.1.1"},
+		DeniedDomains:  []string{"bad.nic.cz"},
+		DenyPrivateIPs: true,
+	}
+
+	tests := []struct {
+		URL     string
+		Allowed bool
+	}{
+		{"https://rdap.nic.cz/domain/example.cz", true},
+		{"https://nic.cz/", true},
+		{"https://RDAP.ARIN.NET./registry/ip/192.0.2.0", true},
+		{"https://evilnic.cz/", false},
+		{"https://bad.nic.cz/", false},
+		{"https://x.bad.nic.cz/", false},
+		{"https://example.com/", false},
+		{"http://192.168.1.1/", false},
+		{"http://127.0.0.1:8080/", false},
+		{"http://[::1]/", false},
+		{"http://10.0.0.1/", false},
+	}
+
+	for _, test := range tests {
+		u, _ := url.Parse(test.URL)
+		err := policy.Check(u)
+
+		if test.Allowed != (err == nil) {
+			t.Errorf("URL %s: expected allowed=%v, got err=%v", test.URL, test.Allowed, err)
+		}
+	}
+
+	open := &HostPolicy{DenyPrivateIPs: true}
+	u, _ := url.Parse("https://rdap.example/")
+	if err := open.Check(u); err != nil {
+		t.Errorf("Empty AllowedDomains unexpectedly denied: %s", err)
+	}
+}
+
+func TestClientHostPolicyRawRequest(t *testing.T) {
+	client := &Client{
+		Verbose: verboseFunc(),
+		HostPolicy: &HostPolicy{
+			DenyPrivateIPs: true,
+		},
+	}
+
+	u, _ := url.Parse("http://169.254.169.254/latest/meta-data")
+	_, err := client.Do(NewRawRequest(u))
+
+	if err == nil {
+		t.Errorf("Unexpected success")
+	} else if !isClientError(HostNotAllowed, err) {
+		t.Errorf("Unexpected err %s", err)
+	}
+}
+
+func TestClientHostPolicyRawObjectClasses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/domain/example.cz":
+			w.Write([]byte(`{"objectClassName": "domain", "ldhName": "example.cz"}`))
+		case "/entity/E1":
+			w.Write([]byte(`{"objectClassName": "entity", "handle": "E1"}`))
+		case "/fred_nsset/N1":
+			w.Write([]byte(`{"objectClassName": "fred_nsset", "handle": "N1"}`))
+		default:
+			w.Write([]byte(`{"notices": []}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+		HostPolicy: &HostPolicy{
+			RawObjectClasses: []string{"domain", "IP Network", "fred_nsset"},
+		},
+	}
+
+	for _, path := range []string{"/domain/example.cz", "/fred_nsset/N1"} {
+		u, _ := url.Parse(server.URL + path)
+		if _, err := client.Do(NewRawRequest(u)); err != nil {
+			t.Errorf("%s: unexpected error %s", path, err)
+		}
+	}
+
+	for _, path := range []string{"/entity/E1", "/help"} {
+		u, _ := url.Parse(server.URL + path)
+		_, err := client.Do(NewRawRequest(u))
+
+		if !errors.Is(err, ErrWrongResponseType) {
+			t.Errorf("%s: expected ErrWrongResponseType, got %v", path, err)
+		}
+	}
+
+	// Bootstrapped queries aren't restricted.
+	serverURL, _ := url.Parse(server.URL)
+	req := NewEntityRequest("E1").WithServer(serverURL)
+	if _, err := client.Do(req); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+}
+
+func TestClientHostPolicyMaxRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/redirect/"))
+		if n > 0 {
+			http.Redirect(w, r, "/redirect/"+strconv.Itoa(n-1), http.StatusFound)
+			return
+		}
+
+		w.Write([]byte(`{"objectClassName": "domain", "ldhName": "example.cz"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		MaxRedirects int
+		Redirects    int
+		OK           bool
+	}{
+		{0, 10, true},
+		{0, 11, false},
+		{2, 2, true},
+		{2, 3, false},
+		{-1, 0, true},
+		{-1, 1, false},
+	}
+
+	for _, test := range tests {
+		client := &Client{
+			Verbose: verboseFunc(),
+			HostPolicy: &HostPolicy{
+				MaxRedirects: test.MaxRedirects,
+			},
+		}
+
+		u, _ := url.Parse(server.URL + "/redirect/" + strconv.Itoa(test.Redirects))
+		resp, err := client.Do(NewRawRequest(u))
+
+		if test.OK != (err == nil) {
+			t.Errorf("MaxRedirects=%d, %d redirects: expected ok=%v, got err=%v", test.MaxRedirects, test.Redirects, test.OK, err)
+		} else if !test.OK && !strings.Contains(resp.HTTP[0].Error.Error(), "redirects") {
+			t.Errorf("Unexpected error %s", resp.HTTP[0].Error)
+		}
+	}
+}
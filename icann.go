@@ -0,0 +1,211 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Registrar returns the Domain's sponsoring registrar Entity (the first
+// Entity with the "registrar" role), or nil if none.
+func (d *Domain) Registrar() *Entity {
+	for i := range d.Entities {
+		if hasRole(&d.Entities[i], "registrar") {
+			return &d.Entities[i]
+		}
+	}
+
+	return nil
+}
+
+// RegistrarIANAID returns the IANA Registrar ID of the Domain's registrar,
+// as required by the ICANN gTLD RDAP Response Profile.
+//
+// Returns empty string if there's no registrar, or it has no IANA ID.
+func (d *Domain) RegistrarIANAID() string {
+	registrar := d.Registrar()
+	if registrar == nil {
+		return ""
+	}
+
+	for _, id := range registrar.PublicIDs {
+		if strings.EqualFold(id.Type, "IANA Registrar ID") {
+			return id.Identifier
+		}
+	}
+
+	return ""
+}
+
+// AbuseContact returns the Domain's registrar abuse contact, as required by
+// the ICANN gTLD RDAP Response Profile: an Entity with the "abuse" role
+// nested within the registrar Entity. A top level "abuse" Entity is used as a
+// fallback.
+//
+// Returns nil if there's no abuse contact.
+func (d *Domain) AbuseContact() *Contact {
+	var abuse *Entity
+
+	if registrar := d.Registrar(); registrar != nil {
+		for i := range registrar.Entities {
+			if hasRole(&registrar.Entities[i], "abuse") {
+				abuse = &registrar.Entities[i]
+				break
+			}
+		}
+	}
+
+	if abuse == nil {
+		for i := range d.Entities {
+			if hasRole(&d.Entities[i], "abuse") {
+				abuse = &d.Entities[i]
+				break
+			}
+		}
+	}
+
+	if abuse == nil {
+		return nil
+	}
+
+	return abuse.Contact()
+}
+
+// ValidateGTLDProfile checks the Domain response |d| against the ICANN gTLD
+// RDAP Response Profile, which applies to gTLD registries and registrars.
+//
+// The checks are:
+//
+//   - rdapConformance includes icann_rdap_response_profile_* and
+//     icann_rdap_technical_implementation_guide_*.
+//   - The domain has a handle, ldhName, and status.
+//   - The "Status Codes" and "RDDS Inaccuracy Complaint Form" notices are
+//     present.
+//   - The "registration", "expiration", and "last update of RDAP database"
+//     events are present.
+//   - A registrar Entity is present, with a name and an IANA Registrar ID.
+//   - The registrar has an abuse contact, with an email address and phone
+//     number.
+//
+// Returns the violations found, or nil if none.
+func ValidateGTLDProfile(d *Domain) []Violation {
+	var violations []Violation
+	add := func(path string, format string, args ...interface{}) {
+		violations = append(violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, prefix := range []string{"icann_rdap_response_profile_", "icann_rdap_technical_implementation_guide_"} {
+		found := false
+		for _, c := range d.Conformance {
+			if strings.HasPrefix(c, prefix) {
+				found = true
+			}
+		}
+
+		if !found {
+			add("$.rdapConformance", "missing %s*", prefix)
+		}
+	}
+
+	if d.Handle == "" {
+		add("$", "missing handle (Registry Domain ID)")
+	}
+
+	if d.LDHName == "" {
+		add("$", "missing ldhName")
+	}
+
+	if len(d.Status) == 0 {
+		add("$", "missing status")
+	}
+
+	for _, title := range []string{"Status Codes", "RDDS Inaccuracy Complaint Form"} {
+		found := false
+		for _, n := range d.Notices {
+			if strings.EqualFold(n.Title, title) {
+				found = true
+			}
+		}
+
+		if !found {
+			add("$.notices", "missing %q notice", title)
+		}
+	}
+
+	for _, action := range []string{"registration", "expiration", "last update of RDAP database"} {
+		found := false
+		for _, e := range d.Events {
+			if strings.EqualFold(e.Action, action) {
+				found = true
+			}
+		}
+
+		if !found {
+			add("$.events", "missing %q event", action)
+		}
+	}
+
+	registrarIndex := -1
+	for i := range d.Entities {
+		if hasRole(&d.Entities[i], "registrar") {
+			registrarIndex = i
+			break
+		}
+	}
+
+	if registrarIndex == -1 {
+		add("$.entities", "missing registrar entity")
+		return violations
+	}
+
+	path := fmt.Sprintf("$.entities[%d]", registrarIndex)
+	registrar := &d.Entities[registrarIndex]
+
+	if registrar.VCard == nil || registrar.VCard.Name() == "" {
+		add(path, "registrar has no name (vCard fn)")
+	}
+
+	if d.RegistrarIANAID() == "" {
+		add(path+".publicIds", "missing IANA Registrar ID")
+	}
+
+	abuse := d.AbuseContact()
+	if abuse == nil || abuse.Entity == nil || !isNestedIn(abuse.Entity, registrar) {
+		add(path+".entities", "missing registrar abuse contact entity")
+	} else {
+		if abuse.Email == "" {
+			add(path+".entities", "registrar abuse contact has no email")
+		}
+
+		if abuse.Phone == "" {
+			add(path+".entities", "registrar abuse contact has no phone number")
+		}
+	}
+
+	return violations
+}
+
+// hasRole returns true if the Entity |e| has the role |role|.
+func hasRole(e *Entity, role string) bool {
+	for _, r := range e.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNestedIn returns true if the Entity |e| is one of |parent|'s Entities.
+func isNestedIn(e *Entity, parent *Entity) bool {
+	for i := range parent.Entities {
+		if &parent.Entities[i] == e {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"testing"
+)
+
+const gTLDDomainJSON = `{
+	"objectClassName": "domain",
+	"rdapConformance": ["rdap_level_0", "icann_rdap_response_profile_0", "icann_rdap_technical_implementation_guide_0"],
+	"handle": "2336799_DOMAIN_COM-VRSN",
+	"ldhName": "EXAMPLE.COM",
+	"status": ["client delete prohibited"],
+	"notices": [
+		{"title": "Status Codes", "description": ["For more information on domain status codes, please visit https://icann.org/epp"]},
+		{"title": "RDDS Inaccuracy Complaint Form", "description": ["URL of the ICANN RDDS Inaccuracy Complaint Form: https://icann.org/wicf"]}
+	],
+	"events": [
+		{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2030-08-13T04:00:00Z"},
+		{"eventAction": "last update of RDAP database", "eventDate": "2024-01-01T00:00:00Z"}
+	],
+	"entities": [
+		{
+			"objectClassName": "entity",
+			"handle": "376",
+			"roles": ["registrar"],
+			"publicIds": [{"type": "IANA Registrar ID", "identifier": "376"}],
+			"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "RESERVED-Internet Assigned Numbers Authority"]]],
+			"entities": [
+				{
+					"objectClassName": "entity",
+					"roles": ["abuse"],
+					"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Abuse"], ["tel", {"type": "voice"}, "uri", "tel:+1.3104233200"], ["email", {}, "text", "abuse@example.net"]]]
+				}
+			]
+		}
+	]
+}`
+
+func TestGTLDProfileGetters(t *testing.T) {
+	d := decodeTestObject(t, gTLDDomainJSON).(*Domain)
+
+	if id := d.RegistrarIANAID(); id != "376" {
+		t.Errorf("RegistrarIANAID() = %q, expected 376", id)
+	}
+
+	abuse := d.AbuseContact()
+	if abuse == nil || abuse.Email != "abuse@example.net" || abuse.Phone != "tel:+1.3104233200" {
+		t.Errorf("AbuseContact() = %+v", abuse)
+	}
+
+	if violations := ValidateGTLDProfile(d); len(violations) != 0 {
+		t.Errorf("Unexpected violations: %v", violations)
+	}
+
+	w := (&Response{Object: d}).ToWhoisStyleResponse()
+	if !reflect.DeepEqual(w.Data["Registrar Abuse Contact Email"], []string{"abuse@example.net"}) {
+		t.Errorf("Whois style response missing abuse email: %v", w.Data)
+	}
+}
+
+func TestValidateGTLDProfile(t *testing.T) {
+	d := loadObject("rdap/rdap.nic.cz/domain-example.cz.json").(*Domain)
+
+	expected := []Violation{
+		{"$.rdapConformance", "missing icann_rdap_response_profile_*"},
+		{"$.rdapConformance", "missing icann_rdap_technical_implementation_guide_*"},
+		{"$.notices", `missing "Status Codes" notice`},
+		{"$.notices", `missing "RDDS Inaccuracy Complaint Form" notice`},
+		{"$.events", `missing "last update of RDAP database" event`},
+		{"$.entities[1]", "registrar has no name (vCard fn)"},
+		{"$.entities[1].publicIds", "missing IANA Registrar ID"},
+		{"$.entities[1].entities", "missing registrar abuse contact entity"},
+	}
+
+	if violations := ValidateGTLDProfile(d); !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Got violations %v, expected %v", violations, expected)
+	}
+}
@@ -0,0 +1,104 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ToUnicode returns the Domain's name in Unicode form, e.g. "bücher.example".
+//
+// This is the UnicodeName if present, otherwise the LDHName converted from
+// punycode.
+func (d *Domain) ToUnicode() (string, error) {
+	return idnToUnicode(d.LDHName, d.UnicodeName)
+}
+
+// ToASCII returns the Domain's name in LDH (punycode) form, e.g.
+// "xn--bcher-kva.example".
+//
+// This is the LDHName if present, otherwise the UnicodeName converted to
+// punycode.
+func (d *Domain) ToASCII() (string, error) {
+	return idnToASCII(d.LDHName, d.UnicodeName)
+}
+
+// ToUnicode returns the Nameserver's name in Unicode form, see
+// Domain.ToUnicode().
+func (n *Nameserver) ToUnicode() (string, error) {
+	return idnToUnicode(n.LDHName, n.UnicodeName)
+}
+
+// ToASCII returns the Nameserver's name in LDH (punycode) form, see
+// Domain.ToASCII().
+func (n *Nameserver) ToASCII() (string, error) {
+	return idnToASCII(n.LDHName, n.UnicodeName)
+}
+
+// ToUnicode returns the variant name in Unicode form, see
+// Domain.ToUnicode().
+func (v VariantName) ToUnicode() (string, error) {
+	return idnToUnicode(v.LDHName, v.UnicodeName)
+}
+
+// ToASCII returns the variant name in LDH (punycode) form, see
+// Domain.ToASCII().
+func (v VariantName) ToASCII() (string, error) {
+	return idnToASCII(v.LDHName, v.UnicodeName)
+}
+
+// HasRelation returns true if the Variant has the relation |relation|, e.g.
+// "registered" or "unregistered". Relations are compared case insensitively.
+func (v Variant) HasRelation(relation string) bool {
+	for _, r := range v.Relation {
+		if strings.EqualFold(r, relation) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// VariantNames returns the names of the Domain's variants with the relation
+// |relation| (e.g. "registered"), or all variant names if |relation| is empty
+// string.
+func (d *Domain) VariantNames(relation string) []VariantName {
+	var names []VariantName
+
+	for _, v := range d.Variants {
+		if relation == "" || v.HasRelation(relation) {
+			names = append(names, v.VariantNames...)
+		}
+	}
+
+	return names
+}
+
+// idnToUnicode returns |unicode|, or |ldh| converted to Unicode if
+// |unicode| is empty.
+func idnToUnicode(ldh string, unicode string) (string, error) {
+	if unicode != "" {
+		return unicode, nil
+	} else if ldh == "" {
+		return "", errors.New("no name")
+	}
+
+	return idna.Lookup.ToUnicode(ldh)
+}
+
+// idnToASCII returns |ldh|, or |unicode| converted to punycode if |ldh| is
+// empty.
+func idnToASCII(ldh string, unicode string) (string, error) {
+	if ldh != "" {
+		return ldh, nil
+	} else if unicode == "" {
+		return "", errors.New("no name")
+	}
+
+	return idna.Lookup.ToASCII(unicode)
+}
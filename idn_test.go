@@ -0,0 +1,54 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+func TestIDNConversion(t *testing.T) {
+	d := decodeTestObject(t, `{
+		"objectClassName": "domain",
+		"ldhName": "xn--bcher-kva.example",
+		"variants": [
+			{
+				"relation": ["registered", "conjoined"],
+				"idnTable": ".EXAMPLE German",
+				"variantNames": [{"ldhName": "xn--bcher-kva.example"}]
+			},
+			{
+				"relation": ["unregistered", "registration restricted"],
+				"idnTable": ".EXAMPLE German",
+				"variantNames": [{"unicodeName": "büсher.example"}, {"ldhName": "buecher.example"}]
+			}
+		],
+		"nameservers": [
+			{"objectClassName": "nameserver", "unicodeName": "ns1.bücher.example"}
+		]
+	}`).(*Domain)
+
+	if u, err := d.ToUnicode(); err != nil || u != "bücher.example" {
+		t.Errorf("Domain.ToUnicode() = %q, %v", u, err)
+	}
+
+	if a, err := d.ToASCII(); err != nil || a != "xn--bcher-kva.example" {
+		t.Errorf("Domain.ToASCII() = %q, %v", a, err)
+	}
+
+	if a, err := d.Nameservers[0].ToASCII(); err != nil || a != "ns1.xn--bcher-kva.example" {
+		t.Errorf("Nameserver.ToASCII() = %q, %v", a, err)
+	}
+
+	if len(d.VariantNames("")) != 3 || len(d.VariantNames("Registered")) != 1 {
+		t.Errorf("Unexpected variant names %v", d.VariantNames(""))
+	}
+
+	unregistered := d.VariantNames("unregistered")
+	if u, err := unregistered[1].ToUnicode(); err != nil || u != "buecher.example" {
+		t.Errorf("VariantName.ToUnicode() = %q, %v", u, err)
+	}
+
+	if _, err := (&Domain{}).ToASCII(); err == nil {
+		t.Errorf("Expected error for a Domain without a name")
+	}
+}
@@ -0,0 +1,194 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"strings"
+)
+
+// roleKeywords maps keywords found in remark titles and vCard role/title
+// values to RDAP entity roles (https://tools.ietf.org/html/rfc7483#section-10.2.4).
+var roleKeywords = []struct {
+	Keyword string
+	Role    string
+}{
+	{"registrant", "registrant"},
+	{"admin", "administrative"},
+	{"tech", "technical"},
+	{"abuse", "abuse"},
+	{"billing", "billing"},
+	{"registrar", "registrar"},
+	{"reseller", "reseller"},
+	{"sponsor", "sponsor"},
+	{"proxy", "proxy"},
+	{"notif", "notifications"},
+	{"noc", "noc"},
+}
+
+// domainRoleOrder is the conventional (WHOIS) order of a domain's contacts.
+var domainRoleOrder = []string{"registrant", "administrative", "technical", "billing"}
+
+// InferRoles infers the probable roles of the Entities in the RDAP object
+// |obj| (and their nested Entities) which have no roles.
+//
+// Some servers omit the roles array, which otherwise makes these entities
+// invisible to role based lookups (e.g. WHOIS style output, or
+// Request.FetchRoles). The heuristics are, in order:
+//
+//   - A remark title, or vCard "role"/"title" value, naming a role
+//     (e.g. "Abuse contact").
+//   - A publicId of type "IANA Registrar ID": registrar.
+//   - Nested inside a registrar entity: abuse (the gTLD registrar profile).
+//   - A vCard email address abuse@...: abuse.
+//   - A vCard kind of "org", for IP network and autnum objects: registrant.
+//   - Position in the document: if none of a domain's entities have roles,
+//     they're assumed to be in the conventional order of registrant,
+//     administrative, technical, billing.
+//
+// The inferred roles are marked, see Entity.RolesInferred(), and recorded
+// as "roles" decode notes.
+//
+// Returns the number of entities with inferred roles.
+func InferRoles(obj RDAPObject) int {
+	var n int
+
+	switch v := obj.(type) {
+	case *Domain:
+		n += inferEntityRoles(v.Entities, nil, true, false)
+	case *IPNetwork:
+		n += inferEntityRoles(v.Entities, nil, false, true)
+	case *Autnum:
+		n += inferEntityRoles(v.Entities, nil, false, true)
+	case *Nameserver:
+		n += inferEntityRoles(v.Entities, nil, false, false)
+	case *Entity:
+		n += inferEntityRoles(v.Entities, v.Roles, false, false)
+	case *DomainSearchResults:
+		for i := range v.Domains {
+			n += InferRoles(&v.Domains[i])
+		}
+	case *NameserverSearchResults:
+		for i := range v.Nameservers {
+			n += InferRoles(&v.Nameservers[i])
+		}
+	case *EntitySearchResults:
+		for i := range v.Entities {
+			n += inferEntityRoles(v.Entities[i].Entities, v.Entities[i].Roles, false, false)
+		}
+	case *IPNetworkSearchResults:
+		for i := range v.Networks {
+			n += InferRoles(&v.Networks[i])
+		}
+	case *AutnumSearchResults:
+		for i := range v.Autnums {
+			n += InferRoles(&v.Autnums[i])
+		}
+	}
+
+	return n
+}
+
+// inferEntityRoles infers the roles of |entities|, which are nested in an
+// object with roles |parentRoles|.
+//
+// |byPosition| enables the position heuristic (for domains), and |byKind|
+// the vCard kind heuristic (for IP networks and autnums).
+func inferEntityRoles(entities []Entity, parentRoles []string, byPosition bool, byKind bool) int {
+	var n int
+
+	anyRoles := false
+	for _, e := range entities {
+		if len(e.Roles) > 0 {
+			anyRoles = true
+		}
+	}
+
+	for i := range entities {
+		e := &entities[i]
+
+		if len(e.Roles) == 0 {
+			role, reason := inferRole(e, parentRoles, byKind)
+
+			if role == "" && byPosition && !anyRoles && i < len(domainRoleOrder) {
+				role, reason = domainRoleOrder[i], "position in document"
+			}
+
+			if role != "" {
+				e.Roles = []string{role}
+				e.rolesInferred = true
+				n++
+
+				if e.DecodeData != nil && e.DecodeData.notes != nil {
+					e.DecodeData.notes["roles"] = append(e.DecodeData.notes["roles"],
+						"inferred role '"+role+"' from "+reason)
+				}
+			}
+		}
+
+		n += inferEntityRoles(e.Entities, e.Roles, false, false)
+	}
+
+	return n
+}
+
+// inferRole returns the probable role of |e|, and the reason, or empty
+// strings if there's no clue.
+func inferRole(e *Entity, parentRoles []string, byKind bool) (role string, reason string) {
+	for _, r := range e.Remarks {
+		if role := roleFromText(r.Title); role != "" {
+			return role, "remark title"
+		}
+	}
+
+	if e.VCard != nil {
+		for _, name := range []string{"role", "title"} {
+			for _, p := range e.VCard.Get(name) {
+				for _, v := range p.Values() {
+					if role := roleFromText(v); role != "" {
+						return role, "vCard " + name
+					}
+				}
+			}
+		}
+	}
+
+	for _, p := range e.PublicIDs {
+		if strings.EqualFold(p.Type, "IANA Registrar ID") {
+			return "registrar", "publicId"
+		}
+	}
+
+	if hasFetchRole(parentRoles, []string{"registrar"}) {
+		return "abuse", "registrar nesting"
+	}
+
+	if e.VCard != nil {
+		if strings.HasPrefix(strings.ToLower(e.VCard.Email()), "abuse@") {
+			return "abuse", "vCard email"
+		}
+
+		if kind := e.VCard.GetFirst("kind"); byKind && kind != nil {
+			if values := kind.Values(); len(values) > 0 && strings.EqualFold(values[0], "org") {
+				return "registrant", "vCard kind"
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// roleFromText returns the role named in |text|, e.g. "Technical Contact",
+// or empty string if none.
+func roleFromText(text string) string {
+	text = strings.ToLower(text)
+
+	for _, k := range roleKeywords {
+		if strings.Contains(text, k.Keyword) {
+			return k.Role
+		}
+	}
+
+	return ""
+}
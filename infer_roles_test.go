@@ -0,0 +1,128 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInferRoles(t *testing.T) {
+	d := decodeTestObject(t, `
+	{
+	  "objectClassName": "domain",
+	  "ldhName": "example.com",
+	  "entities": [
+	    {
+	      "objectClassName": "entity",
+	      "handle": "REGISTRAR",
+	      "publicIds": [{"type": "IANA Registrar ID", "identifier": "9999"}],
+	      "entities": [
+	        {"objectClassName": "entity", "handle": "REGISTRAR-ABUSE"}
+	      ]
+	    },
+	    {
+	      "objectClassName": "entity",
+	      "handle": "TECH",
+	      "remarks": [{"title": "Technical contact", "description": ["x"]}]
+	    },
+	    {
+	      "objectClassName": "entity",
+	      "handle": "ABUSE",
+	      "vcardArray": ["vcard", [
+	        ["version", {}, "text", "4.0"],
+	        ["email", {}, "text", "Abuse@example.com"]
+	      ]]
+	    },
+	    {
+	      "objectClassName": "entity",
+	      "handle": "UNKNOWN"
+	    },
+	    {
+	      "objectClassName": "entity",
+	      "handle": "ADMIN",
+	      "roles": ["administrative"]
+	    }
+	  ]
+	}`).(*Domain)
+
+	if n := InferRoles(d); n != 4 {
+		t.Errorf("Expected 4 inferred roles, got %d", n)
+	}
+
+	expected := map[string][]string{
+		"REGISTRAR": {"registrar"},
+		"TECH":      {"technical"},
+		"ABUSE":     {"abuse"},
+		"UNKNOWN":   nil,
+		"ADMIN":     {"administrative"},
+	}
+
+	for _, e := range d.Entities {
+		if !reflect.DeepEqual(e.Roles, expected[e.Handle]) {
+			t.Errorf("%s: expected roles %v, got %v", e.Handle, expected[e.Handle], e.Roles)
+		} else if e.RolesInferred() != (e.Handle != "ADMIN" && e.Handle != "UNKNOWN") {
+			t.Errorf("%s: unexpected RolesInferred()", e.Handle)
+		}
+	}
+
+	nested := d.Entities[0].Entities[0]
+	if !reflect.DeepEqual(nested.Roles, []string{"abuse"}) || !nested.RolesInferred() {
+		t.Errorf("Expected nested registrar entity to be abuse, got %v", nested.Roles)
+	} else if len(nested.DecodeData.Notes("roles")) != 1 {
+		t.Errorf("Expected roles decode note, got %v", nested.DecodeData.Notes("roles"))
+	}
+}
+
+func TestInferRolesByPosition(t *testing.T) {
+	d := decodeTestObject(t, `
+	{
+	  "objectClassName": "domain",
+	  "ldhName": "example.com",
+	  "entities": [
+	    {"objectClassName": "entity", "handle": "A"},
+	    {"objectClassName": "entity", "handle": "B"}
+	  ]
+	}`).(*Domain)
+
+	InferRoles(d)
+
+	if d.Entities[0].Roles[0] != "registrant" || d.Entities[1].Roles[0] != "administrative" {
+		t.Errorf("Unexpected roles %v, %v", d.Entities[0].Roles, d.Entities[1].Roles)
+	}
+}
+
+func TestInferRolesByKind(t *testing.T) {
+	n := decodeTestObject(t, `
+	{
+	  "objectClassName": "ip network",
+	  "entities": [
+	    {
+	      "objectClassName": "entity",
+	      "handle": "ORG",
+	      "vcardArray": ["vcard", [
+	        ["version", {}, "text", "4.0"],
+	        ["kind", {}, "text", "org"]
+	      ]]
+	    }
+	  ]
+	}`).(*IPNetwork)
+
+	InferRoles(n)
+
+	if !reflect.DeepEqual(n.Entities[0].Roles, []string{"registrant"}) {
+		t.Errorf("Unexpected roles %v", n.Entities[0].Roles)
+	}
+}
+
+// decodeTestObject decodes the RDAP response |jsonBlob|.
+func decodeTestObject(t *testing.T, jsonBlob string) RDAPObject {
+	obj, err := NewDecoder([]byte(jsonBlob)).Decode()
+	if err != nil {
+		t.Fatalf("Decode error: %s", err)
+	}
+
+	return obj
+}
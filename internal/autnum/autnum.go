@@ -0,0 +1,42 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+// Package autnum parses AS numbers, for the rdap and bootstrap packages.
+package autnum
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parse parses the AS number |autnum|, in asplain (e.g. "AS65546") or asdot
+// (e.g. "AS1.10", https://tools.ietf.org/html/rfc5396) notation. The "AS"
+// prefix is optional, and case insensitive.
+func Parse(autnum string) (uint32, error) {
+	autnum = strings.ToUpper(autnum)
+	autnum = strings.TrimPrefix(autnum, "AS")
+
+	// asdot notation?
+	if high, low, ok := strings.Cut(autnum, "."); ok {
+		highValue, err := strconv.ParseUint(high, 10, 16)
+		if err != nil {
+			return 0, err
+		}
+
+		lowValue, err := strconv.ParseUint(low, 10, 16)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint32(highValue<<16 | lowValue), nil
+	}
+
+	result, err := strconv.ParseUint(autnum, 10, 32)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(result), nil
+}
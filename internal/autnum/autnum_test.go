@@ -0,0 +1,35 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package autnum
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected uint32
+		Error    bool
+	}{
+		{"AS1.10", 65546, false},
+		{"as0.65535", 65535, false},
+		{"65535.65535", 4294967295, false},
+		{"AS65546", 65546, false},
+		{"as65546", 65546, false},
+		{"4294967295", 4294967295, false},
+		{"AS4294967296", 0, true},
+		{"AS1.65536", 0, true},
+		{"AS1.", 0, true},
+		{"AS.1", 0, true},
+		{"ASAS1", 0, true},
+	}
+
+	for _, test := range tests {
+		result, err := Parse(test.Input)
+
+		if (err != nil) != test.Error || result != test.Expected {
+			t.Errorf("Parse(%q) = %d, %v, expected %d", test.Input, result, err, test.Expected)
+		}
+	}
+}
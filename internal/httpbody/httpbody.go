@@ -0,0 +1,109 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+// Package httpbody reads (and decompresses) HTTP response bodies, for the rdap
+// and bootstrap packages.
+package httpbody
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// AcceptEncoding is the Accept-Encoding header sent with RDAP requests and
+// Service Registry file downloads.
+//
+// Large responses (e.g. search results, ipv4.json) compress well. Since the
+// header is set explicitly, http.Transport never decompresses the response
+// itself (whether or not its DisableCompression option is set), see
+// ReadBody().
+const AcceptEncoding = "gzip, deflate"
+
+// ReadBody reads the body of |resp|, decompressing it according to its
+// Content-Encoding.
+//
+// Bodies larger than |maxSize| bytes (after decompression) are an error, so a
+// small compressed body can't expand without bound. Zero means no limit.
+//
+// As with http.Transport's automatic decompression, the Content-Encoding and
+// Content-Length headers are removed, and resp.Uncompressed is set.
+func ReadBody(resp *http.Response, maxSize int64) ([]byte, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var r io.Reader = resp.Body
+	switch encoding {
+	case "", "identity":
+		return ReadAllLimited(resp.Body, maxSize)
+	case "gzip", "x-gzip":
+		gr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+
+		r = gr
+	case "deflate":
+		// "deflate" should be zlib wrapped (RFC 7230), but some servers send
+		// raw deflate data.
+		br := bufio.NewReader(resp.Body)
+
+		if header, err := br.Peek(2); err == nil && isZlibHeader(header) {
+			zr, err := zlib.NewReader(br)
+			if err != nil {
+				return nil, err
+			}
+			defer zr.Close()
+
+			r = zr
+		} else {
+			fr := flate.NewReader(br)
+			defer fr.Close()
+
+			r = fr
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding '%s'", encoding)
+	}
+
+	body, err := ReadAllLimited(r, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	return body, nil
+}
+
+// isZlibHeader returns true if |header| is a valid zlib stream header.
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// ReadAllLimited reads |r| until EOF, failing if more than |maxSize| bytes
+// are read. Zero means no limit.
+func ReadAllLimited(r io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		return ioutil.ReadAll(r)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, err
+	} else if int64(len(body)) > maxSize {
+		return nil, fmt.Errorf("response body exceeds limit of %d bytes", maxSize)
+	}
+
+	return body, nil
+}
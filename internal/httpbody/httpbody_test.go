@@ -0,0 +1,88 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package httpbody
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+func compressTestBody(t *testing.T, encoding string, body []byte) []byte {
+	var b bytes.Buffer
+	var w io.WriteCloser
+
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&b)
+	case "deflate":
+		w = zlib.NewWriter(&b)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&b, flate.DefaultCompression)
+	default:
+		return body
+	}
+
+	w.Write(body)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestReadBody(t *testing.T) {
+	body := []byte(`{"objectClassName": "domain"}`)
+
+	for _, encoding := range []string{"", "gzip", "deflate", "raw-deflate"} {
+		header := encoding
+		if encoding == "raw-deflate" {
+			header = "deflate"
+		}
+
+		resp := &http.Response{
+			Header: http.Header{"Content-Encoding": {header}},
+			Body:   ioutil.NopCloser(bytes.NewReader(compressTestBody(t, encoding, body))),
+		}
+
+		result, err := ReadBody(resp, 0)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", encoding, err)
+		} else if !bytes.Equal(result, body) {
+			t.Errorf("%q: unexpected body %q", encoding, result)
+		} else if resp.Header.Get("Content-Encoding") != "" {
+			t.Errorf("%q: Content-Encoding header not removed", encoding)
+		}
+	}
+
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": {"br"}},
+		Body:   ioutil.NopCloser(bytes.NewReader(body)),
+	}
+	if _, err := ReadBody(resp, 0); err == nil {
+		t.Errorf("Expected error for unsupported Content-Encoding")
+	}
+}
+
+func TestReadBodyLimit(t *testing.T) {
+	// 64MiB of zeros compresses to ~64KiB.
+	body := make([]byte, 64*1024*1024)
+
+	for _, encoding := range []string{"", "gzip", "deflate"} {
+		resp := &http.Response{
+			Header: http.Header{"Content-Encoding": {encoding}},
+			Body:   ioutil.NopCloser(bytes.NewReader(compressTestBody(t, encoding, body))),
+		}
+
+		if _, err := ReadBody(resp, 1024*1024); err == nil {
+			t.Errorf("%q: expected error for body exceeding limit", encoding)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// IPOrigin describes who owns, and who announces, an IP address.
+//
+// See Client.IPOrigin().
+type IPOrigin struct {
+	// The IP network covering the address.
+	Network *IPNetwork
+
+	// AS numbers originating (announcing) the network, sorted. Empty if the
+	// registry doesn't publish origin AS information.
+	OriginASNs []uint32
+
+	// Autnum objects for the OriginASNs. Autnums which couldn't be fetched
+	// are absent.
+	Autnums map[uint32]*Autnum
+}
+
+// originASRemark matches origin AS statements in remarks, e.g.
+// "Origin AS: AS64496".
+var originASRemark = regexp.MustCompile(`(?i)\borigin(?:[ -]?AS)?\s*:\s*(?:AS\s?)?(\d+)|\borigin[ -]?AS\s+(?:AS\s?)?(\d+)`)
+
+// IPOrigin runs the IP Request |req|, and returns the covering IPNetwork
+// together with its origin ASNs and their Autnums, i.e. who owns and who
+// announces the address.
+//
+// Origin ASNs are taken from the arin_originas0 extension
+// (arin_originas0_originautnums), or failing that from remarks such as
+// "Origin AS: AS64496". Each origin ASN is then queried.
+//
+// Autnum queries are best-effort: failed queries are skipped. Only an error
+// for the IP query is returned (or a context error, e.g. timeout).
+func (c *Client) IPOrigin(req *Request) (*IPOrigin, error) {
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	network, ok := resp.Object.(*IPNetwork)
+	if !ok {
+		return nil, &ClientError{
+			Type: WrongResponseType,
+			Text: "The server returned a non-IPNetwork RDAP response",
+		}
+	}
+
+	origin := &IPOrigin{
+		Network:    network,
+		OriginASNs: originASNs(network),
+		Autnums:    map[uint32]*Autnum{},
+	}
+
+	for _, asn := range origin.OriginASNs {
+		if err := req.Context().Err(); err != nil {
+			return origin, err
+		}
+
+		autnumResp, err := c.Do(NewAutnumRequest(asn).WithContext(req.Context()))
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: Origin AS%d query failed: %s", asn, err))
+			continue
+		}
+
+		if a, ok := autnumResp.Object.(*Autnum); ok {
+			origin.Autnums[asn] = a
+		}
+	}
+
+	return origin, nil
+}
+
+// originASNs returns the origin AS numbers of the IPNetwork |n|, from the
+// arin_originas0 extension or its remarks.
+func originASNs(n *IPNetwork) []uint32 {
+	seen := map[uint32]bool{}
+	var asns []uint32
+
+	add := func(v uint64) {
+		if v > 0 && v <= 0xffffffff && !seen[uint32(v)] {
+			seen[uint32(v)] = true
+			asns = append(asns, uint32(v))
+		}
+	}
+
+	for _, asn := range n.OriginAutnums {
+		add(uint64(asn))
+	}
+
+	if len(asns) == 0 {
+		for _, r := range n.Remarks {
+			for _, line := range append([]string{r.Title}, r.Description...) {
+				for _, m := range originASRemark.FindAllStringSubmatch(line, -1) {
+					a, err := strconv.ParseUint(m[1]+m[2], 10, 32)
+					if err == nil {
+						add(a)
+					}
+				}
+			}
+		}
+	}
+
+	sort.Slice(asns, func(i, j int) bool {
+		return asns[i] < asns[j]
+	})
+
+	return asns
+}
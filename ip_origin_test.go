@@ -0,0 +1,73 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientIPOrigin(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	origin, err := client.IPOrigin(NewIPRequest(net.ParseIP("198.51.100.1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if origin.Network.Handle != "NET-198-51-100-0-1" {
+		t.Errorf("Unexpected network %s", origin.Network.Handle)
+	}
+
+	if !reflect.DeepEqual(origin.OriginASNs, []uint32{3354, 3356}) {
+		t.Errorf("Unexpected origin ASNs %v", origin.OriginASNs)
+	}
+
+	// AS3354 has no test response, so is skipped.
+	if len(origin.Autnums) != 1 || origin.Autnums[3356] == nil || origin.Autnums[3356].Name != "EXAMPLE-TRANSIT" {
+		t.Errorf("Unexpected autnums %v", origin.Autnums)
+	}
+}
+
+func TestClientIPOriginWrongType(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	if _, err := client.IPOrigin(NewDomainRequest("example.cz")); !isClientError(WrongResponseType, err) {
+		t.Errorf("Expected WrongResponseType, got %v", err)
+	}
+}
+
+func TestOriginASNsFromRemarks(t *testing.T) {
+	obj, err := NewDecoder([]byte(`{
+		"objectClassName": "ip network",
+		"remarks": [
+			{"title": "Routing", "description": ["Origin AS: AS64500", "origin: AS64496", "Originated 2019"]},
+			{"title": "Origin AS 64511"}
+		]
+	}`)).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	asns := originASNs(obj.(*IPNetwork))
+	if !reflect.DeepEqual(asns, []uint32{64496, 64500, 64511}) {
+		t.Errorf("Unexpected ASNs %v", asns)
+	}
+}
@@ -4,6 +4,11 @@
 
 package rdap
 
+import (
+	"fmt"
+	"net/netip"
+)
+
 // IPNetwork represents information of an IP Network.
 //
 // IPNetwork is a topmost RDAP response object.
@@ -29,4 +34,120 @@ type IPNetwork struct {
 	Links        []Link
 	Port43       string
 	Events       []Event
+	Redacted     []Redaction
+
+	// CIDR blocks of the network, from the cidr0 extension. The address
+	// range may not be a single CIDR block.
+	CIDR0CIDRs []CIDR0Prefix `rdap:"cidr0_cidrs"`
+
+	// AS numbers originating the network, from ARIN's arin_originas0
+	// extension.
+	OriginAutnums []uint32 `rdap:"arin_originas0_originautnums"`
+
+	// StartAddress and EndAddress, filled in by the decoder. Invalid if
+	// missing or unparsable.
+	Start netip.Addr `rdap:"-"`
+	End   netip.Addr `rdap:"-"`
+}
+
+// Contains returns true if |addr| is within the network's address range.
+func (n *IPNetwork) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !n.Start.IsValid() || !n.End.IsValid() || addr.BitLen() != n.Start.BitLen() {
+		return false
+	}
+
+	return n.Start.Compare(addr) <= 0 && addr.Compare(n.End) <= 0
+}
+
+// Prefixes returns the network's address range as the smallest list of CIDR
+// blocks covering it, e.g. 192.0.2.0-192.0.2.255 is 192.0.2.0/24.
+//
+// If the address range is invalid, the cidr0 CIDR blocks are returned instead
+// (see CIDRs()).
+func (n *IPNetwork) Prefixes() []netip.Prefix {
+	if !n.Start.IsValid() || !n.End.IsValid() || n.Start.BitLen() != n.End.BitLen() {
+		return n.CIDRs()
+	}
+
+	var prefixes []netip.Prefix
+
+	start := n.Start
+	for start.IsValid() && start.Compare(n.End) <= 0 {
+		// Widen the block while it starts at |start|, and ends within the
+		// range.
+		bits := start.BitLen()
+		for bits > 0 {
+			wider := netip.PrefixFrom(start, bits-1).Masked()
+			if wider.Addr() != start || lastAddr(wider).Compare(n.End) > 0 {
+				break
+			}
+
+			bits--
+		}
+
+		prefix := netip.PrefixFrom(start, bits)
+		prefixes = append(prefixes, prefix)
+
+		// Next() returns an invalid Addr at the end of the address space.
+		start = lastAddr(prefix).Next()
+	}
+
+	return prefixes
+}
+
+// lastAddr returns the last address in |prefix|.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+
+	addr, _ := netip.AddrFromSlice(b)
+
+	return addr
+}
+
+// CIDR0Prefix is a CIDR block of an IPNetwork, as per the cidr0 extension.
+//
+// https://bitbucket.org/nroregistries/rdap-extensions/src/master/cidr0.md
+type CIDR0Prefix struct {
+	DecodeData *DecodeData
+
+	V4Prefix string `rdap:"v4prefix"`
+	V6Prefix string `rdap:"v6prefix"`
+	Length   *uint8
+}
+
+// Prefix returns the CIDR block as a netip.Prefix.
+func (c CIDR0Prefix) Prefix() (netip.Prefix, error) {
+	address := c.V4Prefix
+	if address == "" {
+		address = c.V6Prefix
+	}
+
+	if c.Length == nil {
+		return netip.Prefix{}, fmt.Errorf("cidr0 prefix %q has no length", address)
+	}
+
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return addr.Prefix(int(*c.Length))
+}
+
+// CIDRs returns the network's valid cidr0 CIDR blocks (see CIDR0CIDRs).
+func (n *IPNetwork) CIDRs() []netip.Prefix {
+	var prefixes []netip.Prefix
+
+	for _, c := range n.CIDR0CIDRs {
+		if prefix, err := c.Prefix(); err == nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	return prefixes
 }
@@ -0,0 +1,98 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeIPNetworkExtensions(t *testing.T) {
+	n := decodeTestObject(t, `{
+		"objectClassName": "ip network",
+		"rdapConformance": ["rdap_level_0", "cidr0", "arin_originas0"],
+		"startAddress": "192.0.2.0",
+		"endAddress": "192.0.3.127",
+		"cidr0_cidrs": [
+			{"v4prefix": "192.0.2.0", "length": 24},
+			{"v4prefix": "192.0.3.0", "length": 25},
+			{"v6prefix": "2001:db8::", "length": 32},
+			{"v4prefix": "192.0.2.0"}
+		],
+		"arin_originas0_originautnums": [64496, 64497]
+	}`).(*IPNetwork)
+
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("192.0.3.0/25"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	}
+
+	if cidrs := n.CIDRs(); !reflect.DeepEqual(cidrs, expected) {
+		t.Errorf("Got CIDRs %v, expected %v", cidrs, expected)
+	}
+
+	if !reflect.DeepEqual(n.OriginAutnums, []uint32{64496, 64497}) {
+		t.Errorf("Got OriginAutnums %v", n.OriginAutnums)
+	}
+
+	if asns := originASNs(n); !reflect.DeepEqual(asns, []uint32{64496, 64497}) {
+		t.Errorf("Got origin ASNs %v", asns)
+	}
+}
+
+func TestIPNetworkAddresses(t *testing.T) {
+	n := decodeTestObject(t, `{
+		"objectClassName": "ip network",
+		"startAddress": "192.0.2.0",
+		"endAddress": "192.0.3.127"
+	}`).(*IPNetwork)
+
+	if n.Start != netip.MustParseAddr("192.0.2.0") || n.End != netip.MustParseAddr("192.0.3.127") {
+		t.Errorf("Got Start=%s End=%s", n.Start, n.End)
+	}
+
+	expected := []netip.Prefix{
+		netip.MustParsePrefix("192.0.2.0/24"),
+		netip.MustParsePrefix("192.0.3.0/25"),
+	}
+
+	if prefixes := n.Prefixes(); !reflect.DeepEqual(prefixes, expected) {
+		t.Errorf("Got prefixes %v, expected %v", prefixes, expected)
+	}
+
+	for addr, expected := range map[string]bool{
+		"192.0.2.0":          true,
+		"192.0.3.127":        true,
+		"::ffff:192.0.2.200": true,
+		"192.0.3.128":        false,
+		"2001:db8::":         false,
+	} {
+		if n.Contains(netip.MustParseAddr(addr)) != expected {
+			t.Errorf("Contains(%s) != %t", addr, expected)
+		}
+	}
+
+	all := &IPNetwork{Start: netip.MustParseAddr("::"), End: netip.MustParseAddr("ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")}
+	if prefixes := all.Prefixes(); len(prefixes) != 1 || prefixes[0] != netip.MustParsePrefix("::/0") {
+		t.Errorf("Got prefixes %v for the whole IPv6 address space", prefixes)
+	}
+
+	ns := decodeTestObject(t, `{
+		"objectClassName": "nameserver",
+		"ldhName": "ns1.example.com",
+		"ipAddresses": {"v4": ["192.0.2.1", "bad"], "v6": ["2001:db8::1"]}
+	}`).(*Nameserver)
+
+	addrs := []netip.Addr{netip.MustParseAddr("192.0.2.1"), netip.MustParseAddr("2001:db8::1")}
+	if !reflect.DeepEqual(ns.IPAddresses.Addrs, addrs) {
+		t.Errorf("Got nameserver addresses %v, expected %v", ns.IPAddresses.Addrs, addrs)
+	}
+
+	if notes := ns.IPAddresses.DecodeData.Notes("v4"); len(notes) != 1 {
+		t.Errorf("Expected a note for the unparsable address, got %v", notes)
+	}
+}
@@ -0,0 +1,185 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// KeyStyle is a naming convention for JSON object keys, see ConvertJSONKeys().
+type KeyStyle int
+
+const (
+	// Keys are left as-is, e.g. "objectClassName", "arin_originas0_originautnums".
+	KeyStyleAsIs KeyStyle = iota
+
+	// Keys are converted to snake_case, e.g. "object_class_name".
+	KeyStyleSnakeCase
+
+	// Keys are converted to camelCase, e.g. "arinOriginas0Originautnums".
+	KeyStyleCamelCase
+)
+
+// ConvertJSONKeys returns the JSON document |jsonBlob|, with every object key
+// converted to the naming convention |style|.
+//
+// RDAP responses mostly use camelCase keys, but extensions often don't (e.g.
+// "arin_originas0_originautnums", "fred_keyset"). Converting them gives a
+// single naming convention, e.g. for downstream schema validation. Values,
+// and the order of keys, are unchanged.
+//
+// If two keys of the same object convert to the same name, both are kept.
+func ConvertJSONKeys(jsonBlob []byte, style KeyStyle) ([]byte, error) {
+	var convert func(string) string
+	switch style {
+	case KeyStyleAsIs:
+		return jsonBlob, nil
+	case KeyStyleSnakeCase:
+		convert = toSnakeCase
+	case KeyStyleCamelCase:
+		convert = toCamelCase
+	default:
+		return nil, fmt.Errorf("unknown key style %d", style)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(jsonBlob))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	if err := convertJSONValue(dec, &out, convert); err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return out.Bytes(), nil
+}
+
+// convertJSONValue copies the next JSON value from |dec| to |out|, converting
+// object keys with |convert|.
+func convertJSONValue(dec *json.Decoder, out *bytes.Buffer, convert func(string) string) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t := token.(type) {
+	case json.Delim:
+		out.WriteRune(rune(t))
+
+		for i := 0; dec.More(); i++ {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+
+				b, _ := json.Marshal(convert(key.(string)))
+				out.Write(b)
+				out.WriteByte(':')
+			}
+
+			if err := convertJSONValue(dec, out, convert); err != nil {
+				return err
+			}
+		}
+
+		// The closing delimiter.
+		end, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		out.WriteRune(rune(end.(json.Delim)))
+	default:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		out.Write(b)
+	}
+
+	return nil
+}
+
+// splitKeyWords splits the key |key| into lower case words, at underscores,
+// hyphens, spaces, and case changes. e.g. "RDAPConformance" => [rdap,
+// conformance], "fred_keyset" => [fred, keyset].
+func splitKeyWords(key string) []string {
+	var words []string
+	var word []rune
+
+	runes := []rune(key)
+	for i, r := range runes {
+		if r == '_' || r == '-' || r == ' ' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		}
+
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, unicode.ToLower(r))
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// toSnakeCase converts |key| to snake_case, e.g. "objectClassName" =>
+// "object_class_name".
+func toSnakeCase(key string) string {
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	return strings.Join(words, "_")
+}
+
+// toCamelCase converts |key| to camelCase, e.g. "fred_keyset" =>
+// "fredKeyset".
+func toCamelCase(key string) string {
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	var b strings.Builder
+	for i, w := range words {
+		if i > 0 {
+			r := []rune(w)
+			r[0] = unicode.ToUpper(r[0])
+			w = string(r)
+		}
+
+		b.WriteString(w)
+	}
+
+	return b.String()
+}
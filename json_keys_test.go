@@ -0,0 +1,51 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"testing"
+)
+
+func TestConvertJSONKeys(t *testing.T) {
+	input := `{"objectClassName":"domain","RDAPConformance":["rdap_level_0"],` +
+		`"arin_originas0_originautnums":[1,2.50],"entities":[{"vcardArray":["vcard",[]],"ipv4Address":null}],` +
+		`"fred-keyset":{"handle":"K1","isSigned":true}}`
+
+	tests := []struct {
+		Style    KeyStyle
+		Expected string
+	}{
+		{
+			KeyStyleAsIs,
+			input,
+		},
+		{
+			KeyStyleSnakeCase,
+			`{"object_class_name":"domain","rdap_conformance":["rdap_level_0"],` +
+				`"arin_originas0_originautnums":[1,2.50],"entities":[{"vcard_array":["vcard",[]],"ipv4_address":null}],` +
+				`"fred_keyset":{"handle":"K1","is_signed":true}}`,
+		},
+		{
+			KeyStyleCamelCase,
+			`{"objectClassName":"domain","rdapConformance":["rdap_level_0"],` +
+				`"arinOriginas0Originautnums":[1,2.50],"entities":[{"vcardArray":["vcard",[]],"ipv4Address":null}],` +
+				`"fredKeyset":{"handle":"K1","isSigned":true}}`,
+		},
+	}
+
+	for _, test := range tests {
+		out, err := ConvertJSONKeys([]byte(input), test.Style)
+
+		if err != nil {
+			t.Errorf("style %d: unexpected error %s", test.Style, err)
+		} else if string(out) != test.Expected {
+			t.Errorf("style %d: expected %s, got %s", test.Style, test.Expected, out)
+		}
+	}
+
+	if _, err := ConvertJSONKeys([]byte(`{"a":`), KeyStyleSnakeCase); err == nil {
+		t.Errorf("Expected error for invalid JSON")
+	}
+}
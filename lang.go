@@ -0,0 +1,101 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "strings"
+
+// MatchLanguage returns the language tag in |available| best matching the
+// preferred languages |preferred| (most preferred first), or empty string if
+// none match.
+//
+// Tags are compared case insensitively, and match by prefix, so a preference
+// for "en" matches "en-US", and a preference for "en-US" matches "en".
+func MatchLanguage(preferred []string, available []string) string {
+	for _, want := range preferred {
+		// Exact matches first.
+		for _, tag := range available {
+			if strings.EqualFold(tag, want) {
+				return tag
+			}
+		}
+
+		for _, tag := range available {
+			if languageTagsMatch(tag, want) {
+				return tag
+			}
+		}
+	}
+
+	return ""
+}
+
+// languageTagsMatch returns true if the language tags |a| and |b| are equal,
+// or one is a prefix of the other, e.g. "en" and "en-GB".
+func languageTagsMatch(a string, b string) bool {
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+
+	return a == b || strings.HasPrefix(a, b+"-") || strings.HasPrefix(b, a+"-")
+}
+
+// SelectNotices returns the Notices of |notices| in the language best
+// matching |preferred| (e.g. []string{"cs", "en"}), for registries which
+// return each notice in several languages.
+//
+// Notices without a lang are always returned. If no preferred language is
+// available, all the notices are returned.
+func SelectNotices(notices []Notice, preferred []string) []Notice {
+	langs := make([]string, len(notices))
+	for i, n := range notices {
+		langs[i] = n.Lang
+	}
+
+	var result []Notice
+	for i, keep := range selectLanguage(langs, preferred) {
+		if keep {
+			result = append(result, notices[i])
+		}
+	}
+
+	return result
+}
+
+// SelectRemarks returns the Remarks of |remarks| in the language best
+// matching |preferred|, see SelectNotices().
+func SelectRemarks(remarks []Remark, preferred []string) []Remark {
+	langs := make([]string, len(remarks))
+	for i, r := range remarks {
+		langs[i] = r.Lang
+	}
+
+	var result []Remark
+	for i, keep := range selectLanguage(langs, preferred) {
+		if keep {
+			result = append(result, remarks[i])
+		}
+	}
+
+	return result
+}
+
+// selectLanguage returns which of the items with languages |langs| to keep,
+// as per SelectNotices().
+func selectLanguage(langs []string, preferred []string) []bool {
+	var available []string
+	for _, lang := range langs {
+		if lang != "" {
+			available = append(available, lang)
+		}
+	}
+
+	best := MatchLanguage(preferred, available)
+
+	keep := make([]bool, len(langs))
+	for i, lang := range langs {
+		keep[i] = best == "" || lang == "" || strings.EqualFold(lang, best)
+	}
+
+	return keep
+}
@@ -0,0 +1,73 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMatchLanguage(t *testing.T) {
+	tests := []struct {
+		Preferred []string
+		Available []string
+		Expected  string
+	}{
+		{[]string{"cs", "en"}, []string{"en", "cs"}, "cs"},
+		{[]string{"de", "en"}, []string{"en-US", "cs"}, "en-US"},
+		{[]string{"en-GB"}, []string{"EN", "cs"}, "EN"},
+		{[]string{"de"}, []string{"en", "cs"}, ""},
+		{nil, []string{"en"}, ""},
+	}
+
+	for _, test := range tests {
+		if actual := MatchLanguage(test.Preferred, test.Available); actual != test.Expected {
+			t.Errorf("MatchLanguage(%v, %v) = %q, expected %q", test.Preferred, test.Available, actual, test.Expected)
+		}
+	}
+}
+
+func TestSelectNotices(t *testing.T) {
+	d := decodeTestObject(t, `{
+		"objectClassName": "domain",
+		"ldhName": "example.cz",
+		"notices": [
+			{"lang": "cs", "title": "Podmínky", "description": ["..."]},
+			{"lang": "en", "title": "Terms", "description": ["..."]},
+			{"title": "Status Codes", "description": ["..."]}
+		],
+		"remarks": [
+			{"lang": "cs", "title": "Poznámka", "description": ["..."]},
+			{"lang": "en", "title": "Remark", "description": ["..."]}
+		]
+	}`).(*Domain)
+
+	titles := func(notices []Notice) string {
+		var t []string
+		for _, n := range notices {
+			t = append(t, n.Title)
+		}
+		return strings.Join(t, ",")
+	}
+
+	if actual := titles(SelectNotices(d.Notices, []string{"en"})); actual != "Terms,Status Codes" {
+		t.Errorf("Got notices %s", actual)
+	}
+
+	if actual := titles(SelectNotices(d.Notices, []string{"de"})); actual != "Podmínky,Terms,Status Codes" {
+		t.Errorf("Got notices %s with no matching language", actual)
+	}
+
+	if remarks := SelectRemarks(d.Remarks, []string{"cs-CZ"}); len(remarks) != 1 || remarks[0].Title != "Poznámka" {
+		t.Errorf("Got remarks %v", remarks)
+	}
+
+	var buf bytes.Buffer
+	(&Printer{Writer: &buf, Languages: []string{"cs"}}).Print(d)
+	if out := buf.String(); !strings.Contains(out, "Podmínky") || strings.Contains(out, "Terms") || strings.Contains(out, "Title: Remark") {
+		t.Errorf("Printer didn't select the preferred language:\n%s", out)
+	}
+}
@@ -0,0 +1,148 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DecoderLimits bounds the resources used to decode an RDAP response, so a
+// malicious or broken RDAP server can't make a long running service consume
+// unbounded memory.
+//
+// Zero values mean no limit.
+type DecoderLimits struct {
+	// Maximum response size, in bytes. The Client stops reading responses
+	// which exceed this.
+	MaxBodySize int64
+
+	// Maximum JSON nesting depth of arrays and objects.
+	MaxDepth int
+
+	// Maximum depth of nested entities, e.g. 1 allows top level entities
+	// only.
+	MaxEntityDepth int
+
+	// Maximum JSON array length.
+	MaxArrayLength int
+}
+
+// DefaultDecoderLimits are generous limits suitable for long running
+// services. The largest real world responses (big search results) are well
+// within these.
+var DefaultDecoderLimits = DecoderLimits{
+	MaxBodySize:    32 * 1024 * 1024,
+	MaxDepth:       64,
+	MaxEntityDepth: 8,
+	MaxArrayLength: 100000,
+}
+
+// LimitDecoding returns a DecoderOption which enforces |limits|.
+//
+// Responses exceeding a limit fail to decode with a DecoderError. With the
+// LenientDecoding option, entities nested too deeply are skipped instead.
+func LimitDecoding(limits DecoderLimits) DecoderOption {
+	return func(d *Decoder) {
+		d.limits = limits
+	}
+}
+
+// checkLimits checks the JSON document |data| against the body size,
+// nesting depth, and array length limits.
+//
+// This is a cheap scan of the raw bytes, run before the document is
+// unmarshalled.
+func (l DecoderLimits) checkLimits(data []byte) error {
+	if l.MaxBodySize > 0 && int64(len(data)) > l.MaxBodySize {
+		return DecoderError{text: fmt.Sprintf("response is %d bytes, exceeds limit of %d bytes", len(data), l.MaxBodySize)}
+	}
+
+	if l.MaxDepth <= 0 && l.MaxArrayLength <= 0 {
+		return nil
+	}
+
+	// One entry per open array/object: the number of array elements seen,
+	// or -1 for objects.
+	var stack []int
+	inString := false
+	escaped := false
+
+	// Counts the start of a value in the innermost array.
+	startValue := func() {
+		if n := len(stack); n > 0 && stack[n-1] == 0 {
+			stack[n-1] = 1
+		}
+	}
+
+	for _, c := range data {
+		if inString {
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			startValue()
+			inString = true
+		case '[', '{':
+			startValue()
+
+			if c == '[' {
+				stack = append(stack, 0)
+			} else {
+				stack = append(stack, -1)
+			}
+
+			if l.MaxDepth > 0 && len(stack) > l.MaxDepth {
+				return DecoderError{text: fmt.Sprintf("JSON nesting exceeds limit of %d", l.MaxDepth)}
+			}
+		case ']', '}':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case ',':
+			if n := len(stack); n > 0 && stack[n-1] > 0 {
+				stack[n-1]++
+
+				if l.MaxArrayLength > 0 && stack[n-1] > l.MaxArrayLength {
+					return DecoderError{text: fmt.Sprintf("JSON array length exceeds limit of %d", l.MaxArrayLength)}
+				}
+			}
+		case ' ', '\t', '\r', '\n', ':':
+		default:
+			startValue()
+		}
+	}
+
+	return nil
+}
+
+// enterEntity tracks the entity nesting depth while decoding the field
+// |keyName| into |dst|.
+//
+// Returns a function to call once |dst| is decoded, or an error if |dst| is
+// an Entity nested too deeply.
+func (d *Decoder) enterEntity(keyName string, dst reflect.Value) (func(), error) {
+	// A top level Entity response isn't counted.
+	if dst.Type() != reflect.TypeOf(Entity{}) || keyName == "" {
+		return func() {}, nil
+	}
+
+	if d.limits.MaxEntityDepth > 0 && d.entityDepth >= d.limits.MaxEntityDepth {
+		return nil, DecoderError{text: fmt.Sprintf("entity nesting exceeds limit of %d", d.limits.MaxEntityDepth)}
+	}
+
+	d.entityDepth++
+
+	return func() { d.entityDepth-- }, nil
+}
@@ -0,0 +1,131 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestDecoderLimits(t *testing.T) {
+	nested := `{"objectClassName": "domain", "entities": [
+		{"objectClassName": "entity", "handle": "A", "entities": [
+			{"objectClassName": "entity", "handle": "B", "entities": [
+				{"objectClassName": "entity", "handle": "C"}
+			]}
+		]}
+	]}`
+
+	tests := []struct {
+		JSON   string
+		Limits DecoderLimits
+		Error  string
+	}{
+		{`{"objectClassName": "domain"}`, DecoderLimits{MaxBodySize: 10}, "response is 29 bytes, exceeds limit of 10 bytes"},
+		{`{"a": [[[1]]]}`, DecoderLimits{MaxDepth: 3}, "JSON nesting exceeds limit of 3"},
+		{`{"a": [[1]], "b": "[[[[[["}`, DecoderLimits{MaxDepth: 3}, ""},
+		{`{"a": [1, 2, 3]}`, DecoderLimits{MaxArrayLength: 2}, "JSON array length exceeds limit of 2"},
+		{`{"a": [1, 2], "b": [{"c": 1, "d": 2, "e": 3}], "f": ["x,y,z"]}`, DecoderLimits{MaxArrayLength: 2}, ""},
+		{nested, DecoderLimits{MaxEntityDepth: 2}, "entity nesting exceeds limit of 2"},
+		{nested, DecoderLimits{MaxEntityDepth: 3}, ""},
+		{string(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json")), DefaultDecoderLimits, ""},
+	}
+
+	for i, test := range tests {
+		_, err := NewDecoder([]byte(test.JSON), LimitDecoding(test.Limits)).Decode()
+
+		if test.Error == "" && err != nil {
+			t.Errorf("Test %d: unexpected error %s", i, err)
+		} else if test.Error != "" && (err == nil || err.Error() != test.Error) {
+			t.Errorf("Test %d: got error %v, expected %s", i, err, test.Error)
+		}
+	}
+
+	// A top level entity isn't counted.
+	_, err := NewDecoder([]byte(`{"objectClassName": "entity", "entities": [{"handle": "A"}]}`), LimitDecoding(DecoderLimits{MaxEntityDepth: 1})).Decode()
+	if err != nil {
+		t.Errorf("Unexpected error %s for top level entity", err)
+	}
+
+	result, err := NewDecoder([]byte(nested), LenientDecoding(), LimitDecoding(DecoderLimits{MaxEntityDepth: 2})).Decode()
+	if err != nil {
+		t.Fatalf("Lenient decode failed: %s", err)
+	}
+
+	if d := result.(*Domain); len(d.Entities) != 1 || len(d.Entities[0].Entities) != 1 || len(d.Entities[0].Entities[0].Entities) != 0 {
+		t.Errorf("Expected the deepest entity to be skipped, got %+v", d.Entities)
+	}
+}
+
+func TestClientDecoderLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"objectClassName": "domain", "ldhName": "example.com", "port43": "` + strings.Repeat("x", 1000) + `"}`))
+	}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	client := &Client{DecoderLimits: DecoderLimits{MaxBodySize: 100}}
+	_, err := client.Do(NewRequest(DomainRequest, "example.com").WithServer(serverURL))
+
+	if !errors.Is(err, ErrNoWorkingServers) || !strings.Contains(errors.Unwrap(err).Error(), "exceeds limit of 100 bytes") {
+		t.Errorf("Expected body size limit error, got %v", err)
+	}
+
+	client.DecoderLimits = DefaultDecoderLimits
+	if _, err := client.Do(NewRequest(DomainRequest, "example.com").WithServer(serverURL)); err != nil {
+		t.Errorf("Unexpected error %s with default limits", err)
+	}
+}
+
+func TestClientDecoderLimitsRelated(t *testing.T) {
+	var serverURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/domain/example.com":
+			w.Write([]byte(`{"objectClassName": "domain", "ldhName": "example.com", "links": [
+				{"rel": "related", "type": "application/rdap+json", "href": "` + serverURL + `/registrar/domain/example.com"}
+			]}`))
+		case "/registrar/domain/example.com":
+			w.Write([]byte(`{"objectClassName": "domain", "ldhName": "example.com", "entities": [
+				{"objectClassName": "entity", "handle": "A", "entities": [
+					{"objectClassName": "entity", "handle": "B"}
+				]}
+			]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	serverURL = server.URL
+
+	u, _ := url.Parse(server.URL)
+
+	// The server supplied related link is decoded with the Client's limits.
+	client := &Client{FollowRelated: true, DecoderLimits: DecoderLimits{MaxEntityDepth: 1}}
+	resp, err := client.Do(NewRequest(DomainRequest, "example.com").WithServer(u))
+	if err != nil {
+		t.Fatal(err)
+	} else if resp.Related != nil {
+		t.Errorf("Expected related response exceeding the limits to be rejected")
+	}
+
+	client.DecoderLimits = DecoderLimits{}
+	if resp, err = client.Do(NewRequest(DomainRequest, "example.com").WithServer(u)); err != nil || resp.Related == nil {
+		t.Errorf("Expected related response without limits, got %v", err)
+	}
+
+	// So are error responses.
+	client.DecoderLimits = DecoderLimits{MaxDepth: 2}
+	if e := client.decodeRDAPError([]byte(`{"errorCode": 404, "notices": [{"description": ["x"]}]}`)); e != nil {
+		t.Errorf("Expected error response exceeding the limits to be rejected, got %+v", e)
+	}
+}
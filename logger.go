@@ -0,0 +1,130 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+// A LogLevel specifies the severity of a LogEvent.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota
+	LogInfo
+	LogWarn
+	LogError
+)
+
+// String returns the LogLevel as a string, e.g. "debug", "warn".
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	default:
+		panic("Unknown LogLevel")
+	}
+}
+
+// A LogEventType specifies the type of a LogEvent.
+type LogEventType int
+
+const (
+	// LogMessage is a general progress message (as previously sent to the
+	// Verbose callback).
+	LogMessage LogEventType = iota
+
+	// LogBootstrapStarted is logged before a bootstrap lookup.
+	// Fields: "registry", "query".
+	LogBootstrapStarted
+
+	// LogBootstrapFinished is logged after a bootstrap lookup.
+	// Fields: "registry", "entry", "urls".
+	LogBootstrapFinished
+
+	// LogHTTPRequest is logged before each RDAP HTTP request.
+	// Fields: "url".
+	LogHTTPRequest
+
+	// LogHTTPResponse is logged after each RDAP HTTP request.
+	// Fields: "url", "status_code", "content_type", "length", "duration",
+	// and "error" on failure.
+	LogHTTPResponse
+
+	// LogDecodeWarning is logged when an RDAP response fails to decode.
+	// Fields: "url", "error".
+	LogDecodeWarning
+)
+
+// String returns the LogEventType as a string, e.g. "http-request".
+func (t LogEventType) String() string {
+	switch t {
+	case LogMessage:
+		return "message"
+	case LogBootstrapStarted:
+		return "bootstrap-started"
+	case LogBootstrapFinished:
+		return "bootstrap-finished"
+	case LogHTTPRequest:
+		return "http-request"
+	case LogHTTPResponse:
+		return "http-response"
+	case LogDecodeWarning:
+		return "decode-warning"
+	default:
+		panic("Unknown LogEventType")
+	}
+}
+
+// LogEvent is a single structured log event emitted by a Client.
+type LogEvent struct {
+	Type  LogEventType
+	Level LogLevel
+
+	// Human readable message, as sent to the Verbose callback.
+	Message string
+
+	// Event specific fields, see the LogEventType documentation.
+	Fields map[string]string
+}
+
+// A Logger receives structured log events from a Client.
+//
+// Example usage, logging warnings and errors only:
+//
+//	type myLogger struct{}
+//
+//	func (l *myLogger) Log(e *rdap.LogEvent) {
+//	  if e.Level >= rdap.LogWarn {
+//	    log.Printf("%s %s %v", e.Level, e.Message, e.Fields)
+//	  }
+//	}
+//
+//	client := &rdap.Client{Logger: &myLogger{}}
+type Logger interface {
+	Log(event *LogEvent)
+}
+
+// log sends |event| to the Client's Logger and Verbose callback (if set).
+func (c *Client) log(event *LogEvent) {
+	if c.Verbose != nil {
+		c.Verbose(event.Message)
+	}
+
+	if c.Logger != nil {
+		c.Logger.Log(event)
+	}
+}
+
+// verbose logs a general LogMessage event.
+func (c *Client) verbose(text string) {
+	c.log(&LogEvent{
+		Type:    LogMessage,
+		Level:   LogDebug,
+		Message: text,
+	})
+}
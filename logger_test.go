@@ -0,0 +1,66 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+type testLogger struct {
+	events []*LogEvent
+}
+
+func (l *testLogger) Log(event *LogEvent) {
+	l.events = append(l.events, event)
+}
+
+func (l *testLogger) count(t LogEventType) int {
+	n := 0
+	for _, e := range l.events {
+		if e.Type == t {
+			n++
+		}
+	}
+
+	return n
+}
+
+func TestClientLogger(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	logger := &testLogger{}
+	client := &Client{
+		Logger: logger,
+	}
+
+	client.QueryDomain("example.cz")
+	client.QueryDomain("malformed.cz")
+
+	if logger.count(LogBootstrapStarted) != 2 || logger.count(LogBootstrapFinished) != 2 {
+		t.Errorf("Expected 2 bootstrap started/finished events")
+	}
+
+	if logger.count(LogHTTPRequest) != 2 || logger.count(LogHTTPResponse) != 2 {
+		t.Errorf("Expected 2 HTTP request/response events")
+	}
+
+	if logger.count(LogDecodeWarning) != 1 {
+		t.Errorf("Expected 1 decode warning event")
+	}
+
+	if logger.count(LogMessage) == 0 {
+		t.Errorf("Expected general log messages")
+	}
+
+	for _, e := range logger.events {
+		if e.Type == LogHTTPRequest && e.Fields["url"] == "" {
+			t.Errorf("HTTP request event missing url field")
+		}
+	}
+}
@@ -0,0 +1,383 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"sort"
+	"strings"
+)
+
+// Provenance records which RDAP response(s) a merged value came from.
+type Provenance uint8
+
+const (
+	FromRegistry Provenance = 1 << iota
+	FromRegistrar
+)
+
+// String returns the Provenance as a string, e.g. "registry",
+// "registry+registrar".
+func (p Provenance) String() string {
+	var sources []string
+
+	if p&FromRegistry != 0 {
+		sources = append(sources, "registry")
+	}
+
+	if p&FromRegistrar != 0 {
+		sources = append(sources, "registrar")
+	}
+
+	return strings.Join(sources, "+")
+}
+
+// MergedDomain is a consolidated view of a registry's and registrar's Domain
+// responses, see MergeDomains().
+type MergedDomain struct {
+	// The merged Domain.
+	Domain *Domain
+
+	// Provenance of each value in Domain.Events, Domain.Entities,
+	// Domain.Nameservers, Domain.Status, Domain.Links, Domain.Notices,
+	// Domain.Remarks, Domain.Conformance, Domain.Variants, Domain.PublicIDs,
+	// and Domain.Redacted respectively.
+	EventSources       []Provenance
+	EntitySources      []Provenance
+	NameserverSources  []Provenance
+	StatusSources      []Provenance
+	LinkSources        []Provenance
+	NoticeSources      []Provenance
+	RemarkSources      []Provenance
+	ConformanceSources []Provenance
+	VariantSources     []Provenance
+	PublicIDSources    []Provenance
+	RedactedSources    []Provenance
+
+	// Provenance of the Domain's single valued fields, named after them
+	// (e.g. HandleSource is the provenance of Domain.Handle). Zero if the
+	// field is empty.
+	HandleSource          Provenance
+	LDHNameSource         Provenance
+	UnicodeNameSource     Provenance
+	ObjectClassNameSource Provenance
+	LangSource            Provenance
+	Port43Source          Provenance
+	SecureDNSSource       Provenance
+	NetworkSource         Provenance
+	FredNSSetSource       Provenance
+	FredKeySetSource      Provenance
+	DecodeDataSource      Provenance
+}
+
+// MergeDomains combines the |registry| and |registrar| Domain responses into
+// a single Domain.
+//
+// The Domain's events, entities, nameservers, status, conformance, and
+// public ID values are the union of both responses. Where both responses
+// contain the same entity (matched by handle and roles), the registrar's copy
+// is used if the registry's has no contact information (VCard). The links,
+// notices, remarks, variants, and redactions are those of the registry's
+// response followed by the registrar's. The single valued fields (e.g.
+// Handle, SecureDNS) are taken from the registry's response, or from the
+// registrar's if empty.
+//
+// The provenance of every value is recorded, see MergedDomain.
+//
+// Either Domain may be nil. |registry| and |registrar| are not modified.
+func MergeDomains(registry *Domain, registrar *Domain) *MergedDomain {
+	m := &MergedDomain{
+		Domain: &Domain{},
+	}
+
+	if registry == nil && registrar == nil {
+		return m
+	}
+
+	if registry == nil {
+		registry = &Domain{}
+	}
+
+	if registrar == nil {
+		registrar = &Domain{}
+	}
+
+	d := m.Domain
+	*d = *registry
+
+	d.Handle, m.HandleSource = mergeString(registry.Handle, registrar.Handle)
+	d.LDHName, m.LDHNameSource = mergeString(registry.LDHName, registrar.LDHName)
+	d.UnicodeName, m.UnicodeNameSource = mergeString(registry.UnicodeName, registrar.UnicodeName)
+	d.ObjectClassName, m.ObjectClassNameSource = mergeString(registry.ObjectClassName, registrar.ObjectClassName)
+	d.Lang, m.LangSource = mergeString(registry.Lang, registrar.Lang)
+	d.Port43, m.Port43Source = mergeString(registry.Port43, registrar.Port43)
+
+	m.SecureDNSSource = pointerSource(registry.SecureDNS != nil, registrar.SecureDNS != nil)
+	if m.SecureDNSSource == FromRegistrar {
+		d.SecureDNS = registrar.SecureDNS
+	}
+
+	m.NetworkSource = pointerSource(registry.Network != nil, registrar.Network != nil)
+	if m.NetworkSource == FromRegistrar {
+		d.Network = registrar.Network
+	}
+
+	m.FredNSSetSource = pointerSource(registry.FredNSSet != nil, registrar.FredNSSet != nil)
+	if m.FredNSSetSource == FromRegistrar {
+		d.FredNSSet = registrar.FredNSSet
+	}
+
+	m.FredKeySetSource = pointerSource(registry.FredKeySet != nil, registrar.FredKeySet != nil)
+	if m.FredKeySetSource == FromRegistrar {
+		d.FredKeySet = registrar.FredKeySet
+	}
+
+	m.DecodeDataSource = pointerSource(registry.DecodeData != nil, registrar.DecodeData != nil)
+	if m.DecodeDataSource == FromRegistrar {
+		d.DecodeData = registrar.DecodeData
+	}
+
+	d.Conformance, m.ConformanceSources = mergeStrings(registry.Conformance, registrar.Conformance)
+
+	d.Variants = append(append([]Variant{}, registry.Variants...), registrar.Variants...)
+	m.VariantSources = concatSources(len(registry.Variants), len(registrar.Variants))
+
+	d.Redacted = append(append([]Redaction{}, registry.Redacted...), registrar.Redacted...)
+	m.RedactedSources = concatSources(len(registry.Redacted), len(registrar.Redacted))
+
+	d.Notices = append(append([]Notice{}, registry.Notices...), registrar.Notices...)
+	m.NoticeSources = concatSources(len(registry.Notices), len(registrar.Notices))
+
+	d.Remarks = append(append([]Remark{}, registry.Remarks...), registrar.Remarks...)
+	m.RemarkSources = concatSources(len(registry.Remarks), len(registrar.Remarks))
+
+	d.Links = append(append([]Link{}, registry.Links...), registrar.Links...)
+	m.LinkSources = concatSources(len(registry.Links), len(registrar.Links))
+
+	// Events.
+	d.Events = nil
+	eventIndex := map[string]int{}
+	for _, source := range []struct {
+		events     []Event
+		provenance Provenance
+	}{{registry.Events, FromRegistry}, {registrar.Events, FromRegistrar}} {
+		for _, e := range source.events {
+			key := strings.ToLower(e.Action) + "\x00" + e.Date
+
+			if i, ok := eventIndex[key]; ok {
+				m.EventSources[i] |= source.provenance
+				continue
+			}
+
+			eventIndex[key] = len(d.Events)
+			d.Events = append(d.Events, e)
+			m.EventSources = append(m.EventSources, source.provenance)
+		}
+	}
+
+	// Entities.
+	d.Entities = nil
+	entityIndex := map[string]int{}
+	for _, source := range []struct {
+		entities   []Entity
+		provenance Provenance
+	}{{registry.Entities, FromRegistry}, {registrar.Entities, FromRegistrar}} {
+		for _, e := range source.entities {
+			key := entityMergeKey(&e)
+
+			if i, ok := entityIndex[key]; ok {
+				m.EntitySources[i] |= source.provenance
+
+				if d.Entities[i].VCard == nil && e.VCard != nil {
+					d.Entities[i] = e
+				}
+				continue
+			}
+
+			entityIndex[key] = len(d.Entities)
+			d.Entities = append(d.Entities, e)
+			m.EntitySources = append(m.EntitySources, source.provenance)
+		}
+	}
+
+	// Nameservers.
+	d.Nameservers = nil
+	nameserverIndex := map[string]int{}
+	for _, source := range []struct {
+		nameservers []Nameserver
+		provenance  Provenance
+	}{{registry.Nameservers, FromRegistry}, {registrar.Nameservers, FromRegistrar}} {
+		for _, n := range source.nameservers {
+			key := strings.TrimSuffix(strings.ToLower(n.LDHName), ".")
+
+			if i, ok := nameserverIndex[key]; ok && key != "" {
+				m.NameserverSources[i] |= source.provenance
+				continue
+			}
+
+			nameserverIndex[key] = len(d.Nameservers)
+			d.Nameservers = append(d.Nameservers, n)
+			m.NameserverSources = append(m.NameserverSources, source.provenance)
+		}
+	}
+
+	// Public IDs.
+	d.PublicIDs = nil
+	publicIDIndex := map[string]int{}
+	for _, source := range []struct {
+		publicIDs  []PublicID
+		provenance Provenance
+	}{{registry.PublicIDs, FromRegistry}, {registrar.PublicIDs, FromRegistrar}} {
+		for _, p := range source.publicIDs {
+			key := p.Type + "\x00" + p.Identifier
+
+			if i, ok := publicIDIndex[key]; ok {
+				m.PublicIDSources[i] |= source.provenance
+				continue
+			}
+
+			publicIDIndex[key] = len(d.PublicIDs)
+			d.PublicIDs = append(d.PublicIDs, p)
+			m.PublicIDSources = append(m.PublicIDSources, source.provenance)
+		}
+	}
+
+	// Status.
+	d.Status = nil
+	statusIndex := map[string]int{}
+	for _, source := range []struct {
+		status     []string
+		provenance Provenance
+	}{{registry.Status, FromRegistry}, {registrar.Status, FromRegistrar}} {
+		for _, s := range source.status {
+			key := strings.ToLower(s)
+
+			if i, ok := statusIndex[key]; ok {
+				m.StatusSources[i] |= source.provenance
+				continue
+			}
+
+			statusIndex[key] = len(d.Status)
+			d.Status = append(d.Status, s)
+			m.StatusSources = append(m.StatusSources, source.provenance)
+		}
+	}
+
+	return m
+}
+
+// MergedDomain returns the merge of the Response's Domain and its Related
+// (registrar) Domain, see MergeDomains() and Client.FollowRelated.
+//
+// Returns nil if the Response is not a Domain.
+func (r *Response) MergedDomain() *MergedDomain {
+	registry, ok := r.Object.(*Domain)
+	if !ok {
+		return nil
+	}
+
+	var registrar *Domain
+	if r.Related != nil {
+		registrar, _ = r.Related.Object.(*Domain)
+	}
+
+	return MergeDomains(registry, registrar)
+}
+
+// entityMergeKey returns the key used to match the Entity |e| across
+// responses.
+func entityMergeKey(e *Entity) string {
+	roles := append([]string{}, e.Roles...)
+	sort.Strings(roles)
+
+	key := strings.ToLower(strings.Join(roles, ","))
+
+	if e.Handle != "" {
+		return key + "\x00" + e.Handle
+	} else if e.VCard != nil {
+		return key + "\x00\x00" + e.VCard.Name()
+	}
+
+	return key
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// mergeString returns the first non-empty value of |registry| and
+// |registrar|, and its provenance (both, if the values are the same).
+func mergeString(registry string, registrar string) (string, Provenance) {
+	switch {
+	case registry != "" && registry == registrar:
+		return registry, FromRegistry | FromRegistrar
+	case registry != "":
+		return registry, FromRegistry
+	case registrar != "":
+		return registrar, FromRegistrar
+	}
+
+	return "", 0
+}
+
+// concatSources returns the provenance of each value of a list made of
+// |numRegistry| registry values followed by |numRegistrar| registrar values.
+func concatSources(numRegistry int, numRegistrar int) []Provenance {
+	var sources []Provenance
+
+	for i := 0; i < numRegistry; i++ {
+		sources = append(sources, FromRegistry)
+	}
+
+	for i := 0; i < numRegistrar; i++ {
+		sources = append(sources, FromRegistrar)
+	}
+
+	return sources
+}
+
+// mergeStrings returns the union of |registry| and |registrar|, in order,
+// and the provenance of each value.
+func mergeStrings(registry []string, registrar []string) ([]string, []Provenance) {
+	var result []string
+	var sources []Provenance
+	index := map[string]int{}
+
+	for _, source := range []struct {
+		values     []string
+		provenance Provenance
+	}{{registry, FromRegistry}, {registrar, FromRegistrar}} {
+		for _, s := range source.values {
+			if i, ok := index[s]; ok {
+				sources[i] |= source.provenance
+				continue
+			}
+
+			index[s] = len(result)
+			result = append(result, s)
+			sources = append(sources, source.provenance)
+		}
+	}
+
+	return result, sources
+}
+
+// pointerSource returns the provenance of a single valued field (e.g.
+// SecureDNS), which is taken from the registry's response if
+// |inRegistry|, otherwise from the registrar's if |inRegistrar|.
+func pointerSource(inRegistry bool, inRegistrar bool) Provenance {
+	if inRegistry {
+		return FromRegistry
+	} else if inRegistrar {
+		return FromRegistrar
+	}
+
+	return 0
+}
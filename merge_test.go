@@ -0,0 +1,162 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestMergeDomains(t *testing.T) {
+	registry := &Domain{
+		LDHName: "example.com",
+		Status:  []string{"active", "client transfer prohibited"},
+		Events: []Event{
+			{Action: "registration", Date: "2000-01-01T00:00:00Z"},
+		},
+		Entities: []Entity{
+			{Handle: "R1", Roles: []string{"registrar"}},
+			{Handle: "C1", Roles: []string{"registrant"}},
+		},
+		Nameservers: []Nameserver{{LDHName: "ns1.example.net"}},
+		Port43:      "whois.example.com",
+		Links:       []Link{{Href: "https://rdap.example.com/domain/example.com"}},
+		Notices:     []Notice{{Title: "Terms of Use"}},
+	}
+
+	registrar := &Domain{
+		Handle: "D1-REGISTRAR",
+		Status: []string{"Client Transfer Prohibited", "client delete prohibited"},
+		Events: []Event{
+			{Action: "registration", Date: "2000-01-01T00:00:00Z"},
+			{Action: "expiration", Date: "2030-01-01T00:00:00Z"},
+		},
+		Entities: []Entity{
+			{Handle: "C1", Roles: []string{"registrant"}, VCard: &VCard{}},
+			{Handle: "A1", Roles: []string{"administrative"}},
+		},
+		Nameservers: []Nameserver{{LDHName: "NS1.EXAMPLE.NET."}, {LDHName: "ns2.example.net"}},
+		Port43:      "whois.example.com",
+		SecureDNS:   &SecureDNS{},
+		Notices:     []Notice{{Title: "Terms of Service"}},
+		Remarks:     []Remark{{Title: "Registrar remark"}},
+	}
+
+	m := MergeDomains(registry, registrar)
+	d := m.Domain
+
+	if d.LDHName != "example.com" || d.Handle != "D1-REGISTRAR" {
+		t.Errorf("Unexpected LDHName=%s Handle=%s", d.LDHName, d.Handle)
+	}
+
+	if len(d.Status) != 3 || m.StatusSources[1] != FromRegistry|FromRegistrar || m.StatusSources[2] != FromRegistrar {
+		t.Errorf("Unexpected status merge %v %v", d.Status, m.StatusSources)
+	}
+
+	if len(d.Events) != 2 || m.EventSources[0].String() != "registry+registrar" || m.EventSources[1].String() != "registrar" {
+		t.Errorf("Unexpected events merge %v", m.EventSources)
+	}
+
+	if len(d.Entities) != 3 || d.Entities[1].VCard == nil || m.EntitySources[1] != FromRegistry|FromRegistrar {
+		t.Errorf("Unexpected entities merge %v", m.EntitySources)
+	}
+
+	if len(d.Nameservers) != 2 || d.Nameservers[0].LDHName != "ns1.example.net" {
+		t.Errorf("Unexpected nameservers merge")
+	}
+
+	if m.HandleSource != FromRegistrar || m.Port43Source != FromRegistry|FromRegistrar ||
+		m.SecureDNSSource != FromRegistrar || m.NetworkSource != 0 {
+		t.Errorf("Unexpected field provenance %v %v %v %v",
+			m.HandleSource, m.Port43Source, m.SecureDNSSource, m.NetworkSource)
+	}
+
+	if len(m.LinkSources) != 1 || m.LinkSources[0] != FromRegistry ||
+		len(m.NoticeSources) != 2 || m.NoticeSources[1] != FromRegistrar ||
+		len(m.RemarkSources) != 1 || m.RemarkSources[0] != FromRegistrar {
+		t.Errorf("Unexpected links/notices/remarks provenance %v %v %v",
+			m.LinkSources, m.NoticeSources, m.RemarkSources)
+	}
+
+	if len(registry.Entities) != 2 || registry.Entities[1].VCard != nil {
+		t.Errorf("Registry Domain modified")
+	}
+}
+
+func TestMergeDomainsRegistrarOnly(t *testing.T) {
+	registrar := &Domain{
+		DecodeData:      &DecodeData{},
+		Common:          Common{Lang: "en"},
+		Conformance:     []string{"rdap_level_0"},
+		ObjectClassName: "domain",
+		Notices:         []Notice{{Title: "Terms of Service"}},
+		Handle:          "D1-REGISTRAR",
+		LDHName:         "xn--caf-dma.example",
+		UnicodeName:     "café.example",
+		Variants:        []Variant{{Relation: []string{"registered"}}},
+		Nameservers:     []Nameserver{{LDHName: "ns1.example.net"}},
+		SecureDNS:       &SecureDNS{},
+		Entities:        []Entity{{Handle: "R1", Roles: []string{"registrar"}}},
+		Status:          []string{"active"},
+		PublicIDs:       []PublicID{{Type: "IANA Registrar ID", Identifier: "1"}},
+		Remarks:         []Remark{{Title: "Registrar remark"}},
+		Links:           []Link{{Href: "https://rdap.registrar.example/domain/example"}},
+		Port43:          "whois.registrar.example",
+		Events:          []Event{{Action: "expiration", Date: "2030-01-01T00:00:00Z"}},
+		Network:         &IPNetwork{},
+		Redacted:        []Redaction{{}},
+		FredNSSet:       &FredNSSet{},
+		FredKeySet:      &FredKeySet{},
+	}
+
+	for _, registry := range []*Domain{nil, {}} {
+		m := MergeDomains(registry, registrar)
+
+		// Every field is taken from the registrar.
+		v := reflect.ValueOf(m.Domain).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).IsZero() {
+				t.Errorf("Domain.%s not merged", v.Type().Field(i).Name)
+			}
+		}
+
+		// Every provenance is the registrar.
+		v = reflect.ValueOf(m).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			switch f := v.Field(i).Interface().(type) {
+			case Provenance:
+				if f != FromRegistrar {
+					t.Errorf("MergedDomain.%s = %s, expected registrar", v.Type().Field(i).Name, f)
+				}
+			case []Provenance:
+				if len(f) != 1 || f[0] != FromRegistrar {
+					t.Errorf("MergedDomain.%s = %v, expected [registrar]", v.Type().Field(i).Name, f)
+				}
+			}
+		}
+	}
+}
+
+func TestResponseMergedDomain(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		FollowRelated: true,
+	}
+
+	resp, err := client.Do(NewDomainRequest("related.cz"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	m := resp.MergedDomain()
+	if m == nil || len(m.Domain.Entities) != 1 || m.EntitySources[0] != FromRegistrar {
+		t.Errorf("Unexpected MergedDomain")
+	}
+}
@@ -0,0 +1,66 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"errors"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+)
+
+// Metrics receives instrumentation events from a Client.
+//
+// Implementations typically bind these events to a monitoring system, e.g.
+// prometheus/client_golang counters and histograms:
+//
+//	type promMetrics struct {
+//	  queries  *prometheus.CounterVec   // Labels: type, status.
+//	  latency  *prometheus.HistogramVec // Labels: type.
+//	  ...
+//	}
+//
+//	func (m *promMetrics) ObserveQuery(t rdap.RequestType, status string, d time.Duration) {
+//	  m.queries.WithLabelValues(t.String(), status).Inc()
+//	  m.latency.WithLabelValues(t.String()).Observe(d.Seconds())
+//	}
+//
+//	client := &rdap.Client{Metrics: &promMetrics{...}}
+//
+// The methods may be called concurrently if the Client is shared.
+type Metrics interface {
+	// ObserveQuery is called once per Client.Do(), with the Request type, the
+	// outcome, and the total duration (including bootstrapping).
+	//
+	// |status| is "success", or the ClientErrorType string of the error
+	// (e.g. "object-does-not-exist"), or "error" for other errors.
+	ObserveQuery(requestType RequestType, status string, duration time.Duration)
+
+	// ObserveHTTP is called once per HTTP request to an RDAP server.
+	//
+	// |server| is the host (and port, if any) of the RDAP server.
+	// |statusCode| is zero if no HTTP response was received.
+	ObserveHTTP(server string, statusCode int, duration time.Duration)
+
+	// ObserveBootstrap is called once per bootstrap lookup.
+	//
+	// |cacheHit| is true if a cached Service Registry file was used, false if
+	// it was downloaded (or the lookup failed).
+	ObserveBootstrap(registry bootstrap.RegistryType, cacheHit bool, duration time.Duration)
+}
+
+// queryStatus returns the Metrics status string for the error |err|.
+func queryStatus(err error) string {
+	if err == nil {
+		return "success"
+	}
+
+	var ce *ClientError
+	if errors.As(err, &ce) {
+		return ce.Type.String()
+	}
+
+	return "error"
+}
@@ -0,0 +1,63 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/test"
+)
+
+type testMetrics struct {
+	queries   []string
+	http      []int
+	cacheHits int
+	cacheMiss int
+}
+
+func (m *testMetrics) ObserveQuery(requestType RequestType, status string, duration time.Duration) {
+	m.queries = append(m.queries, requestType.String()+":"+status)
+}
+
+func (m *testMetrics) ObserveHTTP(server string, statusCode int, duration time.Duration) {
+	m.http = append(m.http, statusCode)
+}
+
+func (m *testMetrics) ObserveBootstrap(registry bootstrap.RegistryType, cacheHit bool, duration time.Duration) {
+	if cacheHit {
+		m.cacheHits++
+	} else {
+		m.cacheMiss++
+	}
+}
+
+func TestClientMetrics(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	m := &testMetrics{}
+	client := &Client{
+		Verbose: verboseFunc(),
+		Metrics: m,
+	}
+
+	client.QueryDomain("example.cz")
+	client.QueryDomain("non-existent.cz")
+
+	if len(m.queries) != 2 || m.queries[0] != "domain:success" || m.queries[1] != "domain:object-does-not-exist" {
+		t.Errorf("Unexpected queries %v", m.queries)
+	}
+
+	if len(m.http) != 2 || m.http[0] != 200 || m.http[1] != 404 {
+		t.Errorf("Unexpected HTTP status codes %v", m.http)
+	}
+
+	if m.cacheMiss != 1 || m.cacheHits != 1 {
+		t.Errorf("Unexpected bootstrap cache hits=%d misses=%d", m.cacheHits, m.cacheMiss)
+	}
+}
@@ -4,6 +4,8 @@
 
 package rdap
 
+import "net/netip"
+
 // Nameserver represents information of a DNS nameserver.
 //
 // Nameserver is a topmost RDAP response object.
@@ -27,6 +29,7 @@ type Nameserver struct {
 	Links    []Link
 	Port43   string
 	Events   []Event
+	Redacted []Redaction
 }
 
 // IPAddressSet is a subfield of Nameserver.
@@ -36,4 +39,8 @@ type IPAddressSet struct {
 	Common
 	V6 []string
 	V4 []string
+
+	// The valid addresses of V4 and V6 (in that order), filled in by the
+	// decoder.
+	Addrs []netip.Addr `rdap:"-"`
 }
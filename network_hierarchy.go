@@ -0,0 +1,259 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+)
+
+// NetworkNode is a single IP network within an allocation hierarchy.
+//
+// See Client.NetworkHierarchy().
+type NetworkNode struct {
+	// URL the IP network was fetched from.
+	URL string
+
+	// The IP network.
+	Network *IPNetwork
+
+	// Parent (less specific) network. nil if unknown.
+	Parent *NetworkNode
+
+	// Child (more specific) networks.
+	Children []*NetworkNode
+}
+
+// Root returns the topmost known network in the hierarchy.
+func (n *NetworkNode) Root() *NetworkNode {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+
+	return root
+}
+
+// Path returns the list of networks from the Root() down to |n| inclusive.
+func (n *NetworkNode) Path() []*IPNetwork {
+	var path []*IPNetwork
+
+	for node := n; node != nil; node = node.Parent {
+		path = append([]*IPNetwork{node.Network}, path...)
+	}
+
+	return path
+}
+
+// NetworkHierarchyOptions limits the links followed by Client.NetworkHierarchy().
+type NetworkHierarchyOptions struct {
+	// Maximum number of links to follow in each direction.
+	//
+	// The default is 8.
+	MaxDepth int
+
+	// Maximum number of HTTP requests to make in total, including the initial
+	// query.
+	//
+	// The default is 16.
+	MaxRequests int
+
+	// FetchChildren enables following "down" links from the queried network,
+	// to find its sub-allocations.
+	FetchChildren bool
+}
+
+const (
+	defaultNetworkHierarchyMaxDepth    = 8
+	defaultNetworkHierarchyMaxRequests = 16
+)
+
+var (
+	networkUpRels   = []string{"up", "parent"}
+	networkDownRels = []string{"down"}
+)
+
+// NetworkHierarchy runs the IP network Request |req|, then follows the "up"
+// (and "parent") links of the resulting IPNetwork to build its allocation
+// hierarchy.
+//
+// If |opts|.FetchChildren is true, "down" links are also followed to find
+// sub-allocations.
+//
+// Returns the NetworkNode of the queried network. Use Root() or Path() to
+// access the less specific networks.
+//
+// Link following is best-effort: links which fail to fetch, or which don't
+// return an IPNetwork, are skipped. Only an error for the initial query is
+// returned (or a context error, e.g. timeout).
+func (c *Client) NetworkHierarchy(req *Request, opts NetworkHierarchyOptions) (*NetworkNode, error) {
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = defaultNetworkHierarchyMaxDepth
+	}
+
+	if opts.MaxRequests <= 0 {
+		opts.MaxRequests = defaultNetworkHierarchyMaxRequests
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	network, ok := resp.Object.(*IPNetwork)
+	if !ok {
+		return nil, &ClientError{
+			Type: WrongResponseType,
+			Text: "The server returned a non-IPNetwork RDAP response",
+		}
+	}
+
+	w := &networkWalker{
+		client:   c,
+		ctx:      req.Context(),
+		opts:     opts,
+		visited:  map[string]bool{},
+		requests: len(resp.HTTP),
+	}
+
+	node := &NetworkNode{
+		URL:     resp.HTTP[len(resp.HTTP)-1].URL,
+		Network: network,
+	}
+	w.markVisited(node)
+
+	// Walk up the hierarchy.
+	child := node
+	for depth := 0; depth < opts.MaxDepth; depth++ {
+		parents, err := w.follow(child.Network, networkUpRels, 1)
+		if err != nil {
+			return node, err
+		} else if len(parents) == 0 {
+			break
+		}
+
+		parent := parents[0]
+		parent.Children = append(parent.Children, child)
+		child.Parent = parent
+		child = parent
+	}
+
+	// Walk down the hierarchy.
+	if opts.FetchChildren {
+		if err := w.followChildren(node, opts.MaxDepth); err != nil {
+			return node, err
+		}
+	}
+
+	return node, nil
+}
+
+type networkWalker struct {
+	client   *Client
+	ctx      context.Context
+	opts     NetworkHierarchyOptions
+	visited  map[string]bool
+	requests int
+}
+
+func (w *networkWalker) markVisited(n *NetworkNode) {
+	w.visited[n.URL] = true
+
+	for _, l := range n.Network.Links {
+		if l.Rel == "self" && l.Href != "" {
+			w.visited[l.Href] = true
+		}
+	}
+}
+
+func (w *networkWalker) followChildren(n *NetworkNode, depth int) error {
+	if depth == 0 {
+		return nil
+	}
+
+	children, err := w.follow(n.Network, networkDownRels, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range children {
+		c.Parent = n
+		n.Children = append(n.Children, c)
+
+		if err := w.followChildren(c, depth-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// follow fetches the IPNetworks linked from |network| with any of the link
+// relations |rels|.
+//
+// At most |max| networks are returned, or unlimited if |max| is zero.
+func (w *networkWalker) follow(network *IPNetwork, rels []string, max int) ([]*NetworkNode, error) {
+	var nodes []*NetworkNode
+
+	for _, l := range network.Links {
+		if !linkHasRel(l, rels) || l.Href == "" || w.visited[l.Href] {
+			continue
+		}
+
+		if w.requests >= w.opts.MaxRequests {
+			w.client.verbose("client: Network hierarchy request limit reached")
+			break
+		}
+
+		w.visited[l.Href] = true
+
+		node, err := w.fetch(l.Href)
+		if err != nil {
+			return nodes, err
+		} else if node == nil {
+			continue
+		}
+
+		w.markVisited(node)
+		nodes = append(nodes, node)
+
+		if max > 0 && len(nodes) == max {
+			break
+		}
+	}
+
+	return nodes, nil
+}
+
+// fetch fetches the IPNetwork at |href|.
+//
+// Returns nil if the fetch failed, or the response was not an IPNetwork.
+// Only context errors are returned.
+func (w *networkWalker) fetch(href string) (*NetworkNode, error) {
+	w.requests++
+
+	resp, err := w.client.FetchLink(w.ctx, Link{Href: href}, IPRequest)
+	if w.ctx.Err() != nil {
+		return nil, w.ctx.Err()
+	} else if err != nil {
+		w.client.verbose(fmt.Sprintf("client: Skipping network link '%s': %s", href, err))
+		return nil, nil
+	}
+
+	return &NetworkNode{
+		URL:     href,
+		Network: resp.Object.(*IPNetwork),
+	}, nil
+}
+
+func linkHasRel(l Link, rels []string) bool {
+	for _, r := range rels {
+		if l.Rel == r {
+			return true
+		}
+	}
+
+	return false
+}
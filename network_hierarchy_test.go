@@ -0,0 +1,104 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func networkHandles(networks []*IPNetwork) []string {
+	var handles []string
+	for _, n := range networks {
+		handles = append(handles, n.Handle)
+	}
+
+	return handles
+}
+
+func TestNetworkHierarchyUp(t *testing.T) {
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	u, _ := url.Parse("https://rdap.arin.net/registry/ip/192.0.2.0/25")
+	node, err := client.NetworkHierarchy(NewRawRequest(u), NetworkHierarchyOptions{})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	handles := networkHandles(node.Path())
+	expected := []string{"NET-192-0-0-0-0", "NET-192-0-2-0-1", "NET-192-0-2-0-2"}
+
+	if len(handles) != len(expected) {
+		t.Fatalf("Got path %v, expected %v", handles, expected)
+	}
+
+	for i := range expected {
+		if handles[i] != expected[i] {
+			t.Fatalf("Got path %v, expected %v", handles, expected)
+		}
+	}
+
+	if node.Root().Network.Handle != "NET-192-0-0-0-0" {
+		t.Errorf("Unexpected root %s", node.Root().Network.Handle)
+	}
+}
+
+func TestNetworkHierarchyDown(t *testing.T) {
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	u, _ := url.Parse("https://rdap.arin.net/registry/ip/192.0.0.0/8")
+	node, err := client.NetworkHierarchy(NewRawRequest(u), NetworkHierarchyOptions{
+		FetchChildren: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if node.Parent != nil {
+		t.Errorf("Unexpected parent network")
+	}
+
+	if len(node.Children) != 1 || len(node.Children[0].Children) != 1 {
+		t.Fatalf("Unexpected children")
+	} else if node.Children[0].Children[0].Network.Handle != "NET-192-0-2-0-2" {
+		t.Errorf("Unexpected grandchild %s", node.Children[0].Children[0].Network.Handle)
+	}
+}
+
+func TestNetworkHierarchyRequestLimit(t *testing.T) {
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	u, _ := url.Parse("https://rdap.arin.net/registry/ip/192.0.2.0/25")
+	node, err := client.NetworkHierarchy(NewRawRequest(u), NetworkHierarchyOptions{
+		MaxRequests: 2,
+	})
+
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(node.Path()) != 2 {
+		t.Errorf("Expected 2 networks, got %v", networkHandles(node.Path()))
+	}
+}
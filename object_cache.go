@@ -0,0 +1,231 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// An ObjectCache is an in-memory LRU cache of decoded RDAP responses.
+//
+// Repeated Requests are answered from the cache, skipping bootstrapping, the
+// HTTP requests, and JSON decoding entirely. Only successful Responses are
+// cached.
+//
+// Cached Responses are shared between callers, and must not be modified.
+//
+// An ObjectCache is safe for concurrent use. Example usage:
+//
+//	client := &rdap.Client{
+//	  ObjectCache: rdap.NewObjectCache(10000, 64<<20, 10*time.Minute),
+//	}
+type ObjectCache struct {
+	// Maximum number of cached Responses. Zero for no limit.
+	MaxEntries int
+
+	// Maximum total size of the cached Responses, measured as the size of
+	// their HTTP response bodies. Zero for no limit.
+	MaxBytes int64
+
+	// Duration each Response is cached for. Zero for no expiry.
+	TTL time.Duration
+
+	mu    sync.Mutex
+	lru   *list.List
+	items map[string]*list.Element
+	bytes int64
+	stats ObjectCacheStats
+
+	now func() time.Time
+}
+
+// ObjectCacheStats contains ObjectCache statistics.
+type ObjectCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+
+	Entries int
+	Bytes   int64
+}
+
+type objectCacheEntry struct {
+	key     string
+	resp    *Response
+	size    int64
+	expires time.Time
+}
+
+// NewObjectCache creates a new ObjectCache, with at most |maxEntries| entries
+// totalling |maxBytes| bytes, each cached for |ttl|.
+func NewObjectCache(maxEntries int, maxBytes int64, ttl time.Duration) *ObjectCache {
+	return &ObjectCache{
+		MaxEntries: maxEntries,
+		MaxBytes:   maxBytes,
+		TTL:        ttl,
+	}
+}
+
+// Stats returns the cache's hit/miss statistics and current size.
+func (o *ObjectCache) Stats() ObjectCacheStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stats := o.stats
+	stats.Bytes = o.bytes
+	if o.lru != nil {
+		stats.Entries = o.lru.Len()
+	}
+
+	return stats
+}
+
+// Purge removes all cached Responses.
+func (o *ObjectCache) Purge() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.lru = nil
+	o.items = nil
+	o.bytes = 0
+}
+
+func (o *ObjectCache) init() {
+	if o.lru == nil {
+		o.lru = list.New()
+		o.items = make(map[string]*list.Element)
+	}
+
+	if o.now == nil {
+		o.now = time.Now
+	}
+}
+
+// get returns the cached Response for |key|.
+func (o *ObjectCache) get(key string) (*Response, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.init()
+
+	elem, ok := o.items[key]
+	if !ok {
+		o.stats.Misses++
+		return nil, false
+	}
+
+	entry := elem.Value.(*objectCacheEntry)
+	if !entry.expires.IsZero() && o.now().After(entry.expires) {
+		o.remove(elem)
+		o.stats.Misses++
+		return nil, false
+	}
+
+	o.lru.MoveToFront(elem)
+	o.stats.Hits++
+
+	return entry.resp, true
+}
+
+// put caches the Response |resp| under |key|.
+func (o *ObjectCache) put(key string, resp *Response) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.init()
+
+	var size int64
+	for _, h := range resp.HTTP {
+		size += int64(len(h.Body))
+	}
+
+	// Too large to ever cache?
+	if o.MaxBytes > 0 && size > o.MaxBytes {
+		return
+	}
+
+	if elem, ok := o.items[key]; ok {
+		o.remove(elem)
+	}
+
+	entry := &objectCacheEntry{
+		key:  key,
+		resp: resp,
+		size: size,
+	}
+	if o.TTL > 0 {
+		entry.expires = o.now().Add(o.TTL)
+	}
+
+	o.items[key] = o.lru.PushFront(entry)
+	o.bytes += size
+
+	for (o.MaxEntries > 0 && o.lru.Len() > o.MaxEntries) ||
+		(o.MaxBytes > 0 && o.bytes > o.MaxBytes) {
+		o.remove(o.lru.Back())
+		o.stats.Evictions++
+	}
+}
+
+func (o *ObjectCache) remove(elem *list.Element) {
+	entry := elem.Value.(*objectCacheEntry)
+
+	o.lru.Remove(elem)
+	delete(o.items, entry.key)
+	o.bytes -= entry.size
+}
+
+// objectCacheKey returns the ObjectCache key for |req|.
+//
+// The key includes every Request option which affects the Response.
+func (c *Client) objectCacheKey(req *Request) string {
+	server := ""
+	if req.Server != nil {
+		server = req.Server.String()
+	}
+
+	extensions := req.Extensions
+	if len(extensions) == 0 {
+		extensions = c.Extensions
+	}
+
+	languages := req.Languages
+	if len(languages) == 0 {
+		languages = c.Languages
+	}
+
+	related := ""
+	if c.FollowRelated {
+		related = "related"
+	}
+
+	// Keep responses found via different bootstrap services (e.g. IANA and
+	// test.rdap.net) apart.
+	namespace := ""
+	if req.Server == nil {
+		c.init()
+		namespace = c.Bootstrap.Namespace()
+	}
+
+	return strings.Join([]string{
+		req.Type.String(),
+		req.Query,
+		server,
+		req.Params.Encode(),
+		strings.Join(req.Sort, ","),
+		strconv.FormatBool(req.Count),
+		req.Cursor,
+		req.FieldSet,
+		strings.Join(req.FetchRoles, ","),
+		strings.Join(extensions, ","),
+		strings.Join(languages, ","),
+		related,
+		namespace,
+	}, "\x00")
+}
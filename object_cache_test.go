@@ -0,0 +1,142 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/test"
+)
+
+func newTestCachedResponse(size int) *Response {
+	return &Response{
+		HTTP: []*HTTPResponse{{Body: make([]byte, size)}},
+	}
+}
+
+func TestObjectCacheLRU(t *testing.T) {
+	o := NewObjectCache(2, 0, 0)
+
+	o.put("a", newTestCachedResponse(1))
+	o.put("b", newTestCachedResponse(1))
+	o.get("a")
+	o.put("c", newTestCachedResponse(1))
+
+	if _, ok := o.get("b"); ok {
+		t.Errorf("Expected b to be evicted")
+	}
+
+	if _, ok := o.get("a"); !ok {
+		t.Errorf("Expected a to be cached")
+	}
+
+	stats := o.Stats()
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Hits != 2 || stats.Misses != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestObjectCacheMaxBytes(t *testing.T) {
+	o := NewObjectCache(0, 10, 0)
+
+	o.put("a", newTestCachedResponse(6))
+	o.put("b", newTestCachedResponse(6))
+	o.put("c", newTestCachedResponse(11))
+
+	if _, ok := o.get("a"); ok {
+		t.Errorf("Expected a to be evicted")
+	} else if _, ok := o.get("c"); ok {
+		t.Errorf("Expected c to be too large to cache")
+	} else if stats := o.Stats(); stats.Bytes != 6 {
+		t.Errorf("Unexpected size %d", stats.Bytes)
+	}
+}
+
+func TestObjectCacheTTL(t *testing.T) {
+	now := time.Now()
+
+	o := NewObjectCache(0, 0, time.Minute)
+	o.now = func() time.Time { return now }
+
+	o.put("a", newTestCachedResponse(1))
+
+	now = now.Add(30 * time.Second)
+	if _, ok := o.get("a"); !ok {
+		t.Errorf("Expected a to be cached")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := o.get("a"); ok {
+		t.Errorf("Expected a to be expired")
+	}
+}
+
+func TestClientObjectCache(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose:     verboseFunc(),
+		ObjectCache: NewObjectCache(10, 0, time.Minute),
+	}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Do(NewDomainRequest("example.cz"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		} else if resp.Cached != (i == 1) {
+			t.Errorf("Request %d: unexpected Cached=%v", i, resp.Cached)
+		} else if _, ok := resp.Object.(*Domain); !ok {
+			t.Errorf("Request %d: expected Domain", i)
+		}
+	}
+
+	if stats := client.ObjectCache.Stats(); stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestObjectCacheKeyBootstrapNamespace(t *testing.T) {
+	testURL, _ := url.Parse("https://test.rdap.net/rdap")
+
+	iana := &Client{Bootstrap: &bootstrap.Client{}}
+	experimental := &Client{Bootstrap: &bootstrap.Client{BaseURL: testURL}}
+
+	req := NewDomainRequest("example.cz")
+	if iana.objectCacheKey(req) == experimental.objectCacheKey(req) {
+		t.Errorf("Expected different keys for different bootstrap services")
+	}
+
+	// The bootstrap service doesn't matter if the server is known.
+	req = req.WithServer(testURL)
+	if iana.objectCacheKey(req) != experimental.objectCacheKey(req) {
+		t.Errorf("Expected same keys for an explicit server")
+	}
+}
+
+func TestObjectCacheKeyPaging(t *testing.T) {
+	c := &Client{}
+
+	page1 := NewRequest(DomainSearchRequest, "example*.com")
+	keys := map[string]bool{c.objectCacheKey(page1): true}
+
+	for _, req := range []*Request{
+		{Type: DomainSearchRequest, Query: "example*.com", Cursor: "abc="},
+		{Type: DomainSearchRequest, Query: "example*.com", Sort: []string{"name:d"}},
+		{Type: DomainSearchRequest, Query: "example*.com", Count: true},
+		{Type: DomainSearchRequest, Query: "example*.com", FieldSet: FieldSetFull},
+	} {
+		key := c.objectCacheKey(req)
+		if keys[key] {
+			t.Errorf("Duplicate cache key for %+v", req)
+		}
+
+		keys[key] = true
+	}
+}
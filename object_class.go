@@ -0,0 +1,118 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"strings"
+)
+
+// InferObjectClass returns a DecoderOption which tolerates a missing or
+// unusual objectClassName in the top level object, as returned by several
+// ccTLD servers.
+//
+// Unusually formatted values (e.g. "Domain", "IP_NETWORK") are normalized.
+// When objectClassName is missing or unrecognised, the class is inferred from
+// the object's members:
+//
+//	startAddress, endAddress, or ipVersion           - ip network.
+//	startAutnum or endAutnum                         - autnum.
+//	ldhName/unicodeName, and nameservers, secureDNS,
+//	or variants                                      - domain.
+//	ldhName/unicodeName, and ipAddresses             - nameserver.
+//	vcardArray or roles                              - entity.
+//
+// Objects which match none of these are decoded as usual. Each normalized or
+// inferred objectClassName is recorded as a decode note.
+func InferObjectClass() DecoderOption {
+	return func(d *Decoder) {
+		d.inferClass = true
+	}
+}
+
+// normalizeObjectClassName returns the standard form of the objectClassName
+// |name|, e.g. "ip network" for "IP_Network". Unrecognised names are
+// returned unchanged.
+func normalizeObjectClassName(name string) string {
+	squashed := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(name))
+
+	switch squashed {
+	case "autnum", "domain", "entity", "nameserver":
+		return squashed
+	case "ipnetwork":
+		return "ip network"
+	case "frednsset":
+		return "fred_nsset"
+	case "fredkeyset":
+		return "fred_keyset"
+	}
+
+	return name
+}
+
+// inferObjectClass returns the objectClassName inferred from the members of
+// the object |src|, or empty string if it can't be inferred.
+func inferObjectClass(src map[string]interface{}) string {
+	has := func(names ...string) bool {
+		for _, name := range names {
+			if _, exists := src[name]; exists {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch {
+	case has("startAddress", "endAddress", "ipVersion"):
+		return "ip network"
+	case has("startAutnum", "endAutnum"):
+		return "autnum"
+	case has("ldhName", "unicodeName") && has("nameservers", "secureDNS", "variants"):
+		return "domain"
+	case has("ldhName", "unicodeName") && has("ipAddresses"):
+		return "nameserver"
+	case has("vcardArray", "roles"):
+		return "entity"
+	}
+
+	return ""
+}
+
+// objectOfClass returns a new result object for the objectClassName |name|,
+// or nil if |name| isn't recognised.
+func objectOfClass(name string) interface{} {
+	switch name {
+	case "autnum":
+		return &Autnum{}
+	case "domain":
+		return &Domain{}
+	case "entity":
+		return &Entity{}
+	case "ip network":
+		return &IPNetwork{}
+	case "nameserver":
+		return &Nameserver{}
+	case "fred_nsset":
+		return &FredNSSet{}
+	case "fred_keyset":
+		return &FredKeySet{}
+	}
+
+	return nil
+}
+
+// setObjectClassName sets the ObjectClassName field of the decoded object
+// |result| to |name|, recording the decode note |note|.
+func (d *Decoder) setObjectClassName(result reflect.Value, name string, note string) {
+	v := result.Elem()
+
+	if f := v.FieldByName("ObjectClassName"); f.IsValid() && f.Kind() == reflect.String {
+		f.SetString(name)
+	}
+
+	decodeData, _ := v.FieldByName("DecodeData").Interface().(*DecodeData)
+	d.addDecodeNote(decodeData, "objectClassName", note)
+}
@@ -0,0 +1,74 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+func TestInferObjectClass(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected string
+		note     string
+	}{
+		{`{"ldhName": "example.cz", "nameservers": [{"ldhName": "ns1.example.cz"}]}`, "domain", "objectClassName missing, inferred from structure"},
+		{`{"objectClassName": "Domain", "ldhName": "example.cz"}`, "domain", "normalized objectClassName 'Domain'"},
+		{`{"objectClassName": "IP_NETWORK", "startAddress": "192.0.2.0"}`, "ip network", "normalized objectClassName 'IP_NETWORK'"},
+		{`{"startAddress": "192.0.2.0", "endAddress": "192.0.2.255"}`, "ip network", "objectClassName missing, inferred from structure"},
+		{`{"objectClassName": "registrar", "handle": "R1", "roles": ["registrar"]}`, "entity", "objectClassName not recognised, inferred from structure"},
+		{`{"ldhName": "ns1.example.cz", "ipAddresses": {"v4": ["192.0.2.1"]}}`, "nameserver", "objectClassName missing, inferred from structure"},
+		{`{"startAutnum": 65536}`, "autnum", "objectClassName missing, inferred from structure"},
+	}
+
+	for _, test := range tests {
+		result, err := NewDecoder([]byte(test.json), InferObjectClass()).Decode()
+		if err != nil {
+			t.Errorf("%s: %s", test.json, err)
+			continue
+		}
+
+		var className string
+		var decodeData *DecodeData
+
+		switch v := result.(type) {
+		case *Domain:
+			className, decodeData = v.ObjectClassName, v.DecodeData
+		case *IPNetwork:
+			className, decodeData = v.ObjectClassName, v.DecodeData
+		case *Entity:
+			className, decodeData = v.ObjectClassName, v.DecodeData
+		case *Nameserver:
+			className, decodeData = v.ObjectClassName, v.DecodeData
+		case *Autnum:
+			className, decodeData = v.ObjectClassName, v.DecodeData
+		default:
+			t.Errorf("%s: decoded as %T", test.json, result)
+			continue
+		}
+
+		if className != test.expected {
+			t.Errorf("%s: objectClassName %q, expected %q", test.json, className, test.expected)
+		}
+
+		if notes := decodeData.Notes("objectClassName"); len(notes) != 1 || notes[0] != test.note {
+			t.Errorf("%s: notes %v, expected %q", test.json, notes, test.note)
+		}
+	}
+
+	// Without the option, the existing behaviour is unchanged.
+	if result, _ := NewDecoder([]byte(tests[0].json)).Decode(); result == nil {
+		t.Errorf("Expected decode without InferObjectClass")
+	} else if _, ok := result.(*Help); !ok {
+		t.Errorf("Decoded as %T without InferObjectClass, expected *Help", result)
+	}
+
+	if _, err := NewDecoder([]byte(tests[1].json)).Decode(); err == nil {
+		t.Errorf("Expected error for 'Domain' without InferObjectClass")
+	}
+
+	// Uninferrable objects are still rejected.
+	if _, err := NewDecoder([]byte(`{"objectClassName": "unknown"}`), InferObjectClass()).Decode(); err == nil {
+		t.Errorf("Expected error for unrecognised objectClassName")
+	}
+}
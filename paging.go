@@ -0,0 +1,105 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"strings"
+)
+
+// PagingMetadata describes a page of search results, as per RFC 8977.
+//
+// https://tools.ietf.org/html/rfc8977#section-2.2
+type PagingMetadata struct {
+	DecodeData *DecodeData
+
+	// Total number of search results. Only present if requested, see
+	// Request.Count.
+	TotalCount *uint64
+
+	// Number of results per page, and the current page number.
+	PageSize   *uint64
+	PageNumber *uint64
+
+	// Links to other pages, e.g. rel="next".
+	Links []Link
+}
+
+// NextURL returns the URL of the next page of search results (the
+// rel="next" link), or empty string if this is the last page.
+func (p *PagingMetadata) NextURL() string {
+	if p == nil {
+		return ""
+	}
+
+	for _, l := range p.Links {
+		if strings.EqualFold(l.Rel, "next") {
+			return l.Href
+		}
+	}
+
+	return ""
+}
+
+// NextCursor returns the cursor of the next page of search results (see
+// Request.Cursor), or empty string if there's no next page, or its URL has no
+// cursor.
+func (p *PagingMetadata) NextCursor() string {
+	u, err := url.Parse(p.NextURL())
+	if err != nil {
+		return ""
+	}
+
+	return u.Query().Get("cursor")
+}
+
+// SortingMetadata describes the sort order of search results, as per RFC
+// 8977.
+//
+// https://tools.ietf.org/html/rfc8977#section-2.1
+type SortingMetadata struct {
+	DecodeData *DecodeData
+
+	// The sort order used, e.g. "registrationDate:d".
+	CurrentSort string
+
+	// Sort properties supported by the server.
+	AvailableSorts []AvailableSort
+}
+
+// AvailableSort is a sort property supported by the server.
+type AvailableSort struct {
+	DecodeData *DecodeData
+
+	// Name of the sort property (e.g. "registrationDate"), and the JSON path
+	// of the values it sorts on.
+	Property string
+	JSONPath string `rdap:"jsonPath"`
+
+	// True if this is the default sort property.
+	Default *bool
+
+	// Links to the search results sorted by this property.
+	Links []Link
+}
+
+// pagingMetadataOf returns the PagingMetadata of the search results |obj|,
+// or nil if none.
+func pagingMetadataOf(obj RDAPObject) *PagingMetadata {
+	switch v := obj.(type) {
+	case *DomainSearchResults:
+		return v.PagingMetadata
+	case *NameserverSearchResults:
+		return v.PagingMetadata
+	case *EntitySearchResults:
+		return v.PagingMetadata
+	case *IPNetworkSearchResults:
+		return v.PagingMetadata
+	case *AutnumSearchResults:
+		return v.PagingMetadata
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,78 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testPagedDomainSearchResults = `
+{
+  "rdapConformance": ["rdap_level_0", "sorting", "paging"],
+  "sorting_metadata": {
+    "currentSort": "name:a",
+    "availableSorts": [
+      {"property": "registrationDate", "jsonPath": "$.domainSearchResults[*].events[?(@.eventAction==\"registration\")].eventDate", "default": false,
+       "links": [{"value": "https://example.com/rdap/domains?name=example*.com", "rel": "alternate", "href": "https://example.com/rdap/domains?name=example*.com&sort=registrationDate", "title": "Result Ascending Sort Link", "type": "application/rdap+json"}]}
+    ]
+  },
+  "paging_metadata": {
+    "totalCount": 43,
+    "pageSize": 2,
+    "pageNumber": 1,
+    "links": [{"value": "https://example.com/rdap/domains?name=example*.com", "rel": "next", "href": "https://example.com/rdap/domains?name=example*.com&cursor=wJlCDLIl6KTWypN7T6vc6nWEmEYe99Hjf1XY1xmqV-M=", "title": "Result Pagination Link", "type": "application/rdap+json"}]
+  },
+  "domainSearchResults": [
+    {"objectClassName": "domain", "ldhName": "example1.com"},
+    {"objectClassName": "domain", "ldhName": "example2.com"}
+  ]
+}`
+
+func TestDecodePagingMetadata(t *testing.T) {
+	sr := decodeTestObject(t, testPagedDomainSearchResults).(*DomainSearchResults)
+
+	pm := sr.PagingMetadata
+	if pm == nil || pm.TotalCount == nil || *pm.TotalCount != 43 || *pm.PageSize != 2 {
+		t.Fatalf("PagingMetadata decoded incorrectly: %+v", pm)
+	}
+
+	if c := pm.NextCursor(); c != "wJlCDLIl6KTWypN7T6vc6nWEmEYe99Hjf1XY1xmqV-M=" {
+		t.Errorf("NextCursor() = %q", c)
+	}
+
+	sm := sr.SortingMetadata
+	if sm == nil || sm.CurrentSort != "name:a" || len(sm.AvailableSorts) != 1 || sm.AvailableSorts[0].Property != "registrationDate" {
+		t.Errorf("SortingMetadata decoded incorrectly: %+v", sm)
+	}
+
+	var nilPaging *PagingMetadata
+	if nilPaging.NextURL() != "" || nilPaging.NextCursor() != "" {
+		t.Errorf("Expected no next page for nil PagingMetadata")
+	}
+
+	var buf bytes.Buffer
+	(&Printer{Writer: &buf}).Print(sr)
+	if !strings.Contains(buf.String(), "  Total Count: 43\n") || !strings.Contains(buf.String(), "  Sort: name:a\n") {
+		t.Errorf("Paging metadata not printed:\n%s", buf.String())
+	}
+}
+
+func TestRequestPagingParams(t *testing.T) {
+	server, _ := url.Parse("https://example.com/rdap/")
+
+	req := NewRequest(DomainSearchRequest, "example*.com")
+	req.Server = server
+	req.Sort = []string{"registrationDate:d", "name"}
+	req.Count = true
+	req.Cursor = "abc="
+
+	expected := "https://example.com/rdap/domains?count=true&cursor=abc%3D&name=example%2A.com&sort=registrationDate%3Ad%2Cname"
+	if u := req.URL().String(); u != expected {
+		t.Errorf("Got URL %s, expected %s", u, expected)
+	}
+}
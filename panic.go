@@ -0,0 +1,54 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned when a panic is recovered while decoding or printing
+// an RDAP response.
+//
+// Unexpected server data should never cause a panic, but if it does, the
+// panic is contained and returned as a PanicError. This prevents a single
+// unusual RDAP response from crashing a long running service. The fields
+// provide a diagnostics bundle for a bug report.
+type PanicError struct {
+	// Operation which panicked, "decode" or "print".
+	Op string
+
+	// Value passed to panic().
+	Value interface{}
+
+	// Stack trace of the panic.
+	Stack []byte
+
+	// Raw RDAP response body, if known.
+	Body []byte
+
+	// Offending RDAP field name (or Go type when printing), if known.
+	Field string
+}
+
+func (p *PanicError) Error() string {
+	if p.Field != "" {
+		return fmt.Sprintf("rdap: recovered panic during %s (field %s): %v", p.Op, p.Field, p.Value)
+	}
+
+	return fmt.Sprintf("rdap: recovered panic during %s: %v", p.Op, p.Value)
+}
+
+// newPanicError returns a PanicError for the recovered value |value|,
+// including the current stack trace.
+func newPanicError(op string, value interface{}, body []byte, field string) *PanicError {
+	return &PanicError{
+		Op:    op,
+		Value: value,
+		Stack: debug.Stack(),
+		Body:  body,
+		Field: field,
+	}
+}
@@ -5,6 +5,7 @@
 package rdap
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -49,9 +50,41 @@ type Printer struct {
 	// BriefLinks causes Link objects to be printed as a single line (the link),
 	// rather than as a multi-line object.
 	BriefLinks bool
+
+	// Preferred languages of Notices and Remarks, e.g. []string{"en"}. When
+	// set, notices and remarks provided in several languages are printed in
+	// the best matching language only, see SelectNotices().
+	Languages []string
+
+	// Context and first error of the current Print call.
+	ctx context.Context
+	err error
+
+	// Notices of the search results being printed. These are printed once,
+	// at the top, and not repeated for each result.
+	searchNotices []Notice
+
+	// Redactions of the topmost object being printed, used to print
+	// placeholders for redacted entity fields.
+	redacted []Redaction
 }
 
-func (p *Printer) Print(obj RDAPObject) {
+// Print prints the RDAP object |obj|.
+//
+// Returns the first error writing to the Writer. Printing stops at the
+// first error.
+//
+// See SafePrint to recover from panics.
+func (p *Printer) Print(obj RDAPObject) error {
+	return p.PrintContext(context.Background(), obj)
+}
+
+// PrintContext prints the RDAP object |obj|, as per Print, with the context
+// |ctx|.
+//
+// If |ctx| is cancelled (e.g. when printing large search results to a slow
+// writer), printing stops, and the context's error is returned.
+func (p *Printer) PrintContext(ctx context.Context, obj RDAPObject) error {
 	if p.Writer == nil {
 		p.Writer = os.Stdout
 	}
@@ -64,7 +97,28 @@ func (p *Printer) Print(obj RDAPObject) {
 		p.IndentChar = ' '
 	}
 
+	p.ctx = ctx
+	p.err = nil
+	p.searchNotices = nil
+	p.redacted = nil
+
 	p.printObject(obj, 0)
+
+	return p.err
+}
+
+// SafePrint prints the RDAP object |obj|, as per Print.
+//
+// Should printing panic, the panic is recovered and a *PanicError returned.
+// Any output written before the panic is not removed.
+func (p *Printer) SafePrint(obj RDAPObject) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError("print", r, nil, fmt.Sprintf("%T", obj))
+		}
+	}()
+
+	return p.Print(obj)
 }
 
 func (p *Printer) printObject(obj RDAPObject, indentLevel uint) {
@@ -83,6 +137,10 @@ func (p *Printer) printObject(obj RDAPObject, indentLevel uint) {
 		p.printAutnum(v, indentLevel)
 	case *IPNetwork:
 		p.printIPNetwork(v, indentLevel)
+	case *FredNSSet:
+		p.printFredNSSet(v, indentLevel)
+	case *FredKeySet:
+		p.printFredKeySet(v, indentLevel)
 	case *Help:
 		p.printHelp(v, indentLevel)
 	case *Error:
@@ -93,6 +151,10 @@ func (p *Printer) printObject(obj RDAPObject, indentLevel uint) {
 		p.printEntitySearchResults(v, indentLevel)
 	case *NameserverSearchResults:
 		p.printNameserverSearchResults(v, indentLevel)
+	case *IPNetworkSearchResults:
+		p.printIPNetworkSearchResults(v, indentLevel)
+	case *AutnumSearchResults:
+		p.printAutnumSearchResults(v, indentLevel)
 	}
 }
 
@@ -106,21 +168,24 @@ func (p *Printer) printNameserverSearchResults(sr *NameserverSearchResults, inde
 		}
 	}
 
-	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range sr.Notices {
-			p.printNotice(n, indentLevel)
-		}
-	}
+	p.printSearchNotices(sr, indentLevel)
+	defer func() { p.searchNotices = nil }()
+
+	p.printPagingMetadata(sr, indentLevel)
 
 	for _, n := range sr.Nameservers {
+		if p.err != nil {
+			return
+		}
+
 		p.printNameserver(&n, indentLevel)
 	}
 
 	p.printUnknowns(sr.DecodeData, indentLevel)
 }
 
-func (p *Printer) printEntitySearchResults(sr *EntitySearchResults, indentLevel uint) {
-	p.printHeading("Entity Search Results", indentLevel)
+func (p *Printer) printIPNetworkSearchResults(sr *IPNetworkSearchResults, indentLevel uint) {
+	p.printHeading("IP Network Search Results", indentLevel)
 	indentLevel++
 
 	if !p.BriefOutput {
@@ -129,13 +194,68 @@ func (p *Printer) printEntitySearchResults(sr *EntitySearchResults, indentLevel
 		}
 	}
 
-	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range sr.Notices {
-			p.printNotice(n, indentLevel)
+	p.printSearchNotices(sr, indentLevel)
+	defer func() { p.searchNotices = nil }()
+
+	p.printPagingMetadata(sr, indentLevel)
+
+	for _, n := range sr.Networks {
+		if p.err != nil {
+			return
 		}
+
+		p.printIPNetwork(&n, indentLevel)
 	}
 
+	p.printUnknowns(sr.DecodeData, indentLevel)
+}
+
+func (p *Printer) printAutnumSearchResults(sr *AutnumSearchResults, indentLevel uint) {
+	p.printHeading("Autnum Search Results", indentLevel)
+	indentLevel++
+
+	if !p.BriefOutput {
+		for _, c := range sr.Conformance {
+			p.printValue("Conformance", c, indentLevel)
+		}
+	}
+
+	p.printSearchNotices(sr, indentLevel)
+	defer func() { p.searchNotices = nil }()
+
+	p.printPagingMetadata(sr, indentLevel)
+
+	for _, a := range sr.Autnums {
+		if p.err != nil {
+			return
+		}
+
+		p.printAutnum(&a, indentLevel)
+	}
+
+	p.printUnknowns(sr.DecodeData, indentLevel)
+}
+
+func (p *Printer) printEntitySearchResults(sr *EntitySearchResults, indentLevel uint) {
+	p.printHeading("Entity Search Results", indentLevel)
+	indentLevel++
+
+	if !p.BriefOutput {
+		for _, c := range sr.Conformance {
+			p.printValue("Conformance", c, indentLevel)
+		}
+	}
+
+	p.printSearchNotices(sr, indentLevel)
+	defer func() { p.searchNotices = nil }()
+
+	p.printPagingMetadata(sr, indentLevel)
+
 	for _, e := range sr.Entities {
+		if p.err != nil {
+			return
+		}
+
 		p.printEntity(&e, indentLevel)
 	}
 
@@ -152,19 +272,60 @@ func (p *Printer) printDomainSearchResults(sr *DomainSearchResults, indentLevel
 		}
 	}
 
-	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range sr.Notices {
-			p.printNotice(n, indentLevel)
-		}
-	}
+	p.printSearchNotices(sr, indentLevel)
+	defer func() { p.searchNotices = nil }()
+
+	p.printPagingMetadata(sr, indentLevel)
 
 	for _, d := range sr.Domains {
+		if p.err != nil {
+			return
+		}
+
 		p.printDomain(&d, indentLevel)
 	}
 
 	p.printUnknowns(sr.DecodeData, indentLevel)
 }
 
+// printPagingMetadata prints the RFC 8977 paging and sorting information, and
+// the RFC 8982 field set of the search results |sr|, if any.
+func (p *Printer) printPagingMetadata(sr RDAPObject, indentLevel uint) {
+	if pm := pagingMetadataOf(sr); pm != nil {
+		if pm.TotalCount != nil {
+			p.printValue("Total Count", strconv.FormatUint(*pm.TotalCount, 10), indentLevel)
+		}
+
+		if pm.PageNumber != nil {
+			p.printValue("Page Number", strconv.FormatUint(*pm.PageNumber, 10), indentLevel)
+		}
+
+		p.printValue("Next Page", pm.NextURL(), indentLevel)
+	}
+
+	var sm *SortingMetadata
+	switch v := sr.(type) {
+	case *DomainSearchResults:
+		sm = v.SortingMetadata
+	case *NameserverSearchResults:
+		sm = v.SortingMetadata
+	case *EntitySearchResults:
+		sm = v.SortingMetadata
+	case *IPNetworkSearchResults:
+		sm = v.SortingMetadata
+	case *AutnumSearchResults:
+		sm = v.SortingMetadata
+	}
+
+	if sm != nil {
+		p.printValue("Sort", sm.CurrentSort, indentLevel)
+	}
+
+	if ssm := subsettingMetadataOf(sr); ssm != nil {
+		p.printValue("Field Set", ssm.CurrentFieldSet, indentLevel)
+	}
+}
+
 func (p *Printer) printError(e *Error, indentLevel uint) {
 	p.printHeading("Error", indentLevel)
 	indentLevel++
@@ -176,7 +337,7 @@ func (p *Printer) printError(e *Error, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range e.Notices {
+		for _, n := range p.notices(e.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
@@ -206,8 +367,12 @@ func (p *Printer) printHelp(h *Help, indentLevel uint) {
 		}
 	}
 
+	for _, r := range h.RateLimits {
+		p.printValue("Rate Limit", r.String(), indentLevel)
+	}
+
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range h.Notices {
+		for _, n := range p.notices(h.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
@@ -219,6 +384,10 @@ func (p *Printer) printDomain(d *Domain, indentLevel uint) {
 	p.printHeading("Domain", indentLevel)
 	indentLevel++
 
+	if len(d.Redacted) > 0 {
+		p.redacted = d.Redacted
+	}
+
 	p.printValue("Domain Name", d.LDHName, indentLevel)
 	p.printValue("Domain Name (Unicode)", d.UnicodeName, indentLevel)
 	p.printValue("Handle", d.Handle, indentLevel)
@@ -242,13 +411,13 @@ func (p *Printer) printDomain(d *Domain, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range d.Notices {
+		for _, n := range p.notices(d.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
 
 	if !p.BriefOutput || p.OmitRemarks {
-		for _, r := range d.Remarks {
+		for _, r := range p.remarks(d.Remarks) {
 			p.printRemark(r, indentLevel)
 		}
 	}
@@ -283,6 +452,20 @@ func (p *Printer) printDomain(d *Domain, indentLevel uint) {
 		p.printIPNetwork(d.Network, indentLevel)
 	}
 
+	if d.FredNSSet != nil {
+		p.printFredNSSet(d.FredNSSet, indentLevel)
+	}
+
+	if d.FredKeySet != nil {
+		p.printFredKeySet(d.FredKeySet, indentLevel)
+	}
+
+	if !p.BriefOutput {
+		for _, r := range d.Redacted {
+			p.printRedaction(r, indentLevel)
+		}
+	}
+
 	p.printUnknowns(d.DecodeData, indentLevel)
 }
 
@@ -291,6 +474,10 @@ func (p *Printer) printAutnum(a *Autnum, indentLevel uint) {
 
 	indentLevel++
 
+	if len(a.Redacted) > 0 {
+		p.redacted = a.Redacted
+	}
+
 	p.printValue("Handle", a.Handle, indentLevel)
 	p.printValue("Name", a.Name, indentLevel)
 	p.printValue("Type", a.Type, indentLevel)
@@ -325,13 +512,13 @@ func (p *Printer) printAutnum(a *Autnum, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range a.Notices {
+		for _, n := range p.notices(a.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
 
 	if !p.BriefOutput || p.OmitRemarks {
-		for _, r := range a.Remarks {
+		for _, r := range p.remarks(a.Remarks) {
 			p.printRemark(r, indentLevel)
 		}
 	}
@@ -350,6 +537,12 @@ func (p *Printer) printAutnum(a *Autnum, indentLevel uint) {
 		p.printEntity(&e, indentLevel)
 	}
 
+	if !p.BriefOutput {
+		for _, r := range a.Redacted {
+			p.printRedaction(r, indentLevel)
+		}
+	}
+
 	p.printUnknowns(a.DecodeData, indentLevel)
 }
 
@@ -358,6 +551,10 @@ func (p *Printer) printNameserver(n *Nameserver, indentLevel uint) {
 
 	indentLevel++
 
+	if len(n.Redacted) > 0 {
+		p.redacted = n.Redacted
+	}
+
 	p.printValue("Nameserver", n.LDHName, indentLevel)
 	p.printValue("Nameserver (Unicode)", n.UnicodeName, indentLevel)
 	p.printValue("Handle", n.Handle, indentLevel)
@@ -377,13 +574,13 @@ func (p *Printer) printNameserver(n *Nameserver, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range n.Notices {
+		for _, n := range p.notices(n.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
 
 	if !p.BriefOutput || p.OmitRemarks {
-		for _, r := range n.Remarks {
+		for _, r := range p.remarks(n.Remarks) {
 			p.printRemark(r, indentLevel)
 		}
 	}
@@ -406,6 +603,12 @@ func (p *Printer) printNameserver(n *Nameserver, indentLevel uint) {
 		p.printEntity(&e, indentLevel)
 	}
 
+	if !p.BriefOutput {
+		for _, r := range n.Redacted {
+			p.printRedaction(r, indentLevel)
+		}
+	}
+
 	p.printUnknowns(n.DecodeData, indentLevel)
 }
 
@@ -430,6 +633,10 @@ func (p *Printer) printEntity(e *Entity, indentLevel uint) {
 
 	indentLevel++
 
+	if len(e.Redacted) > 0 {
+		p.redacted = e.Redacted
+	}
+
 	p.printValue("Handle", e.Handle, indentLevel)
 
 	for _, s := range e.Status {
@@ -451,13 +658,13 @@ func (p *Printer) printEntity(e *Entity, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, n := range e.Notices {
+		for _, n := range p.notices(e.Notices) {
 			p.printNotice(n, indentLevel)
 		}
 	}
 
 	if !p.BriefOutput || p.OmitRemarks {
-		for _, r := range e.Remarks {
+		for _, r := range p.remarks(e.Remarks) {
 			p.printRemark(r, indentLevel)
 		}
 	}
@@ -471,12 +678,16 @@ func (p *Printer) printEntity(e *Entity, indentLevel uint) {
 			p.printEvent(e, indentLevel, false)
 		}
 
-		for _, e := range e.AsEventActor {
+		for _, e := range e.ActorEvents() {
 			p.printEvent(e, indentLevel, true)
 		}
 	}
 
 	for _, r := range e.Roles {
+		if e.rolesInferred {
+			r += " (inferred)"
+		}
+
 		p.printValue("Role", r, indentLevel)
 	}
 
@@ -488,6 +699,10 @@ func (p *Printer) printEntity(e *Entity, indentLevel uint) {
 		}
 	}
 
+	for _, field := range entityRedactions(p.redacted, e.Roles) {
+		p.printValue(field, "[REDACTED]", indentLevel)
+	}
+
 	if !p.BriefOutput {
 		for _, ipn := range e.Networks {
 			p.printIPNetwork(&ipn, indentLevel)
@@ -502,6 +717,12 @@ func (p *Printer) printEntity(e *Entity, indentLevel uint) {
 		}
 	}
 
+	if !p.BriefOutput {
+		for _, r := range e.Redacted {
+			p.printRedaction(r, indentLevel)
+		}
+	}
+
 	p.printUnknowns(e.DecodeData, indentLevel)
 }
 
@@ -510,6 +731,10 @@ func (p *Printer) printIPNetwork(n *IPNetwork, indentLevel uint) {
 
 	indentLevel++
 
+	if len(n.Redacted) > 0 {
+		p.redacted = n.Redacted
+	}
+
 	p.printValue("Handle", n.Handle, indentLevel)
 	p.printValue("Start Address", n.StartAddress, indentLevel)
 	p.printValue("End Address", n.EndAddress, indentLevel)
@@ -519,6 +744,16 @@ func (p *Printer) printIPNetwork(n *IPNetwork, indentLevel uint) {
 	p.printValue("Country", n.Country, indentLevel)
 	p.printValue("ParentHandle", n.ParentHandle, indentLevel)
 
+	for _, c := range n.CIDR0CIDRs {
+		if prefix, err := c.Prefix(); err == nil {
+			p.printValue("CIDR", prefix.String(), indentLevel)
+		}
+	}
+
+	for _, asn := range n.OriginAutnums {
+		p.printValue("Origin AS", "AS"+strconv.FormatUint(uint64(asn), 10), indentLevel)
+	}
+
 	for _, s := range n.Status {
 		p.printValue("Status", s, indentLevel)
 	}
@@ -528,13 +763,13 @@ func (p *Printer) printIPNetwork(n *IPNetwork, indentLevel uint) {
 	}
 
 	if !p.BriefOutput || p.OmitNotices {
-		for _, no := range n.Notices {
+		for _, no := range p.notices(n.Notices) {
 			p.printNotice(no, indentLevel)
 		}
 	}
 
 	if !p.BriefOutput || p.OmitRemarks {
-		for _, r := range n.Remarks {
+		for _, r := range p.remarks(n.Remarks) {
 			p.printRemark(r, indentLevel)
 		}
 	}
@@ -553,9 +788,137 @@ func (p *Printer) printIPNetwork(n *IPNetwork, indentLevel uint) {
 		}
 	}
 
+	if !p.BriefOutput {
+		for _, r := range n.Redacted {
+			p.printRedaction(r, indentLevel)
+		}
+	}
+
 	p.printUnknowns(n.DecodeData, indentLevel)
 }
 
+func (p *Printer) printFredNSSet(s *FredNSSet, indentLevel uint) {
+	p.printHeading("NSSET", indentLevel)
+
+	indentLevel++
+
+	p.printValue("Handle", s.Handle, indentLevel)
+
+	for _, st := range s.Status {
+		p.printValue("Status", st, indentLevel)
+	}
+
+	if !p.BriefOutput {
+		for _, c := range s.Conformance {
+			p.printValue("Conformance", c, indentLevel)
+		}
+	}
+
+	if !p.BriefOutput || p.OmitNotices {
+		for _, n := range p.notices(s.Notices) {
+			p.printNotice(n, indentLevel)
+		}
+	}
+
+	if !p.BriefOutput || p.OmitRemarks {
+		for _, r := range p.remarks(s.Remarks) {
+			p.printRemark(r, indentLevel)
+		}
+	}
+
+	for _, l := range s.Links {
+		p.printLink(l, indentLevel)
+	}
+
+	if !p.BriefOutput {
+		for _, e := range s.Events {
+			p.printEvent(e, indentLevel, false)
+		}
+	}
+
+	for _, e := range s.Entities {
+		p.printEntity(&e, indentLevel)
+	}
+
+	for _, n := range s.Nameservers {
+		p.printNameserver(&n, indentLevel)
+	}
+
+	p.printUnknowns(s.DecodeData, indentLevel)
+}
+
+func (p *Printer) printFredKeySet(s *FredKeySet, indentLevel uint) {
+	p.printHeading("KEYSET", indentLevel)
+
+	indentLevel++
+
+	p.printValue("Handle", s.Handle, indentLevel)
+
+	for _, st := range s.Status {
+		p.printValue("Status", st, indentLevel)
+	}
+
+	if !p.BriefOutput {
+		for _, c := range s.Conformance {
+			p.printValue("Conformance", c, indentLevel)
+		}
+	}
+
+	if !p.BriefOutput || p.OmitNotices {
+		for _, n := range p.notices(s.Notices) {
+			p.printNotice(n, indentLevel)
+		}
+	}
+
+	if !p.BriefOutput || p.OmitRemarks {
+		for _, r := range p.remarks(s.Remarks) {
+			p.printRemark(r, indentLevel)
+		}
+	}
+
+	for _, l := range s.Links {
+		p.printLink(l, indentLevel)
+	}
+
+	if !p.BriefOutput {
+		for _, e := range s.Events {
+			p.printEvent(e, indentLevel, false)
+		}
+	}
+
+	for _, k := range s.DNSKeys {
+		p.printFredDNSKey(k, indentLevel)
+	}
+
+	for _, e := range s.Entities {
+		p.printEntity(&e, indentLevel)
+	}
+
+	p.printUnknowns(s.DecodeData, indentLevel)
+}
+
+func (p *Printer) printFredDNSKey(k FredDNSKey, indentLevel uint) {
+	p.printHeading("DNSKEY", indentLevel)
+
+	indentLevel++
+
+	if k.Flags != nil {
+		p.printValue("Flags", strconv.FormatUint(uint64(*k.Flags), 10), indentLevel)
+	}
+
+	if k.Protocol != nil {
+		p.printValue("Protocol", strconv.FormatUint(uint64(*k.Protocol), 10), indentLevel)
+	}
+
+	if k.Algorithm != nil {
+		p.printValue("Algorithm", strconv.FormatUint(uint64(*k.Algorithm), 10), indentLevel)
+	}
+
+	p.printValue("Public Key", k.PublicKey, indentLevel)
+
+	p.printUnknowns(k.DecodeData, indentLevel)
+}
+
 func (p *Printer) printPublicID(pid PublicID, indentLevel uint) {
 	p.printHeading("Public ID", indentLevel)
 
@@ -626,6 +989,10 @@ func (p *Printer) printKeyData(k KeyData, indentLevel uint) {
 
 	p.printValue("Public Key", k.PublicKey, indentLevel)
 
+	if keyTag, err := k.KeyTag(); err == nil {
+		p.printValue("Key Tag", strconv.FormatUint(uint64(keyTag), 10), indentLevel)
+	}
+
 	if !p.BriefOutput {
 		for _, e := range k.Events {
 			p.printEvent(e, indentLevel, false)
@@ -704,6 +1071,20 @@ func (p *Printer) printVariantName(vn VariantName, indentLevel uint) {
 	p.printUnknowns(vn.DecodeData, indentLevel)
 }
 
+func (p *Printer) printRedaction(r Redaction, indentLevel uint) {
+	p.printHeading("Redacted", indentLevel)
+
+	indentLevel++
+	p.printValue("Name", r.Name.String(), indentLevel)
+	p.printValue("Method", r.RedactionMethod(), indentLevel)
+	p.printValue("Reason", r.Reason.String(), indentLevel)
+	p.printValue("Pre Path", r.PrePath, indentLevel)
+	p.printValue("Post Path", r.PostPath, indentLevel)
+	p.printValue("Replacement Path", r.ReplacementPath, indentLevel)
+
+	p.printUnknowns(r.DecodeData, indentLevel)
+}
+
 func (p *Printer) printRemark(r Remark, indentLevel uint) {
 	p.printHeading("Remark", indentLevel)
 
@@ -721,7 +1102,44 @@ func (p *Printer) printRemark(r Remark, indentLevel uint) {
 	p.printUnknowns(r.DecodeData, indentLevel)
 }
 
+// notices returns the |notices| to print, see Languages.
+func (p *Printer) notices(notices []Notice) []Notice {
+	if len(p.Languages) == 0 {
+		return notices
+	}
+
+	return SelectNotices(notices, p.Languages)
+}
+
+// remarks returns the |remarks| to print, see Languages.
+func (p *Printer) remarks(remarks []Remark) []Remark {
+	if len(p.Languages) == 0 {
+		return remarks
+	}
+
+	return SelectRemarks(remarks, p.Languages)
+}
+
+// printSearchNotices prints the notices of the search results |sr|,
+// including any repeated in the results themselves, see SearchNotices().
+func (p *Printer) printSearchNotices(sr RDAPObject, indentLevel uint) {
+	notices := p.notices(SearchNotices(sr))
+
+	if !p.BriefOutput || p.OmitNotices {
+		for _, n := range notices {
+			p.printNotice(n, indentLevel)
+		}
+	}
+
+	p.searchNotices = notices
+}
+
 func (p *Printer) printNotice(n Notice, indentLevel uint) {
+	if containsNotice(p.searchNotices, n) {
+		// Already printed at the top of the search results.
+		return
+	}
+
 	p.printHeading("Notice", indentLevel)
 
 	indentLevel++
@@ -762,7 +1180,7 @@ func (p *Printer) printLink(l Link, indent uint) {
 }
 
 func (p *Printer) printHeading(heading string, indentLevel uint) {
-	fmt.Fprintf(p.Writer, "%s%s:\n",
+	p.printf("%s%s:\n",
 		strings.Repeat(string(p.IndentChar), int(indentLevel*p.IndentSize)),
 		p.cleanString(heading))
 }
@@ -772,12 +1190,26 @@ func (p *Printer) printValue(name string, value string, indentLevel uint) {
 		return
 	}
 
-	fmt.Fprintf(p.Writer, "%s%s: %s\n",
+	p.printf("%s%s: %s\n",
 		strings.Repeat(string(p.IndentChar), int(indentLevel*p.IndentSize)),
 		p.cleanString(name),
 		p.cleanString(value))
 }
 
+// printf writes to the Writer, unless a previous write failed, or the context
+// is done. The first error is saved.
+func (p *Printer) printf(format string, args ...interface{}) {
+	if p.err != nil {
+		return
+	} else if p.ctx != nil {
+		if p.err = p.ctx.Err(); p.err != nil {
+			return
+		}
+	}
+
+	_, p.err = fmt.Fprintf(p.Writer, format, args...)
+}
+
 func (p *Printer) printEvent(e Event, indentLevel uint, asEventActor bool) {
 	if p.BriefOutput {
 		return
@@ -5,6 +5,9 @@
 package rdap
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/openrdap/rdap/test"
@@ -34,3 +37,59 @@ func loadObject(filename string) RDAPObject {
 
 	return result
 }
+
+// failingWriter fails every write after the first |n| bytes.
+type failingWriter struct {
+	n      int
+	writes int
+}
+
+func (f *failingWriter) Write(b []byte) (int, error) {
+	f.writes++
+
+	if len(b) > f.n {
+		return 0, errors.New("write failed")
+	}
+
+	f.n -= len(b)
+
+	return len(b), nil
+}
+
+func TestPrintWriteError(t *testing.T) {
+	obj := loadObject("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	w := &failingWriter{n: 20}
+	printer := &Printer{Writer: w}
+
+	err := printer.Print(obj)
+	if err == nil || err.Error() != "write failed" {
+		t.Errorf("Expected write error, got %v", err)
+	}
+
+	if w.writes > 3 {
+		t.Errorf("Printing continued after the write error (%d writes)", w.writes)
+	}
+
+	var buf bytes.Buffer
+	printer.Writer = &buf
+	if err := printer.Print(obj); err != nil || buf.Len() == 0 {
+		t.Errorf("Unexpected error reusing Printer: %v", err)
+	}
+}
+
+func TestPrintContextCancelled(t *testing.T) {
+	obj := loadObject("rdap/rdap.nic.cz/domain-example.cz.json")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var buf bytes.Buffer
+	printer := &Printer{Writer: &buf}
+
+	if err := printer.PrintContext(ctx, obj); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	} else if buf.Len() != 0 {
+		t.Errorf("Unexpected output after cancellation: %q", buf.String())
+	}
+}
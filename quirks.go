@@ -0,0 +1,108 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"strings"
+)
+
+// normalizeIPNetwork smooths over known differences between the five RIRs'
+// IP network responses, so IPNetworks can be handled uniformly:
+//
+//	RIPE NCC - status as a single string, and upper case status values
+//	           (e.g. "ACTIVE"), are converted to RFC 7483 form.
+//	APNIC    - zero-length (or blank) country values are treated as absent.
+//	LACNIC   - language tags using underscores (e.g. "pt_BR") are converted
+//	           to BCP 47 form ("pt-BR").
+//
+// Each change is recorded as a decode note. The original values remain
+// available via DecodeData.Value().
+//
+// For AFRINIC's http-only links, see upgradeLinks().
+func (d *Decoder) normalizeIPNetwork(n *IPNetwork) {
+	dd := n.DecodeData
+	if dd == nil {
+		return
+	}
+
+	// Status.
+	if s, ok := dd.Value("status").(string); ok {
+		n.Status = []string{s}
+		delete(dd.notes, "status")
+		d.addDecodeNote(dd, "status", "string converted to array")
+	}
+
+	for i, s := range n.Status {
+		normalized := strings.ToLower(strings.TrimSpace(strings.Replace(s, "_", " ", -1)))
+
+		if normalized != s {
+			n.Status[i] = normalized
+			d.addDecodeNote(dd, "status", "normalized status value '"+s+"'")
+		}
+	}
+
+	// Country.
+	if country := strings.ToUpper(strings.TrimSpace(n.Country)); country != n.Country {
+		n.Country = country
+		d.addDecodeNote(dd, "country", "normalized country code")
+	}
+
+	// Language tags.
+	d.normalizeLang(&n.Common, dd)
+
+	for i := range n.Entities {
+		d.normalizeEntityLang(&n.Entities[i])
+	}
+}
+
+// normalizeEntityLang normalizes the language tags of the Entity |e| and its
+// nested Entities.
+func (d *Decoder) normalizeEntityLang(e *Entity) {
+	d.normalizeLang(&e.Common, e.DecodeData)
+
+	for i := range e.Entities {
+		d.normalizeEntityLang(&e.Entities[i])
+	}
+}
+
+// normalizeLang converts an underscore separated language tag to BCP 47 form.
+func (d *Decoder) normalizeLang(c *Common, decodeData *DecodeData) {
+	if strings.Contains(c.Lang, "_") {
+		c.Lang = strings.Replace(c.Lang, "_", "-", -1)
+		d.addDecodeNote(decodeData, "lang", "converted language tag to BCP 47 form")
+	}
+}
+
+// upgradeLinks rewrites the IPNetwork |n|'s http:// links to https://, where
+// the link points at the same host as the HTTPS URL |responseURL| the network
+// was fetched from.
+//
+// AFRINIC's responses contain http-only links, even when queried over HTTPS.
+// Upgrading them keeps link following (e.g. Client.NetworkHierarchy())
+// consistent with the other RIRs, and compatible with a HostPolicy which
+// requires HTTPS.
+func upgradeLinks(n *IPNetwork, responseURL string) {
+	base, err := url.Parse(responseURL)
+	if err != nil || base.Scheme != "https" {
+		return
+	}
+
+	for i := range n.Links {
+		l := &n.Links[i]
+
+		u, err := url.Parse(l.Href)
+		if err != nil || u.Scheme != "http" || !strings.EqualFold(u.Host, base.Host) {
+			continue
+		}
+
+		u.Scheme = "https"
+		l.Href = u.String()
+
+		if l.DecodeData != nil {
+			l.DecodeData.notes["href"] = append(l.DecodeData.notes["href"], "upgraded http link to https")
+		}
+	}
+}
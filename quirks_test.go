@@ -0,0 +1,83 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func queryTestIPNetwork(t *testing.T, ip string) *IPNetwork {
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	resp, err := client.Do(NewIPRequest(net.ParseIP(ip)))
+	if err != nil {
+		t.Fatalf("Query %s: unexpected error: %s", ip, err)
+	}
+
+	n, ok := resp.Object.(*IPNetwork)
+	if !ok {
+		t.Fatalf("Query %s: expected IPNetwork, got %T", ip, resp.Object)
+	}
+
+	return n
+}
+
+func TestIPNetworkQuirks(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	// RIPE NCC: status as a string.
+	ripe := queryTestIPNetwork(t, "2.0.0.1")
+	if len(ripe.Status) != 1 || ripe.Status[0] != "active" {
+		t.Errorf("RIPE: unexpected status %v", ripe.Status)
+	} else if ripe.DecodeData.Value("status") != "ACTIVE" || len(ripe.DecodeData.Notes("status")) != 2 {
+		t.Errorf("RIPE: unexpected status decode data %v", ripe.DecodeData.Notes("status"))
+	}
+
+	// APNIC: zero-length country.
+	apnic := queryTestIPNetwork(t, "1.0.0.1")
+	if apnic.Country != "" || apnic.DecodeData.Notes("country") != nil {
+		t.Errorf("APNIC: unexpected country %q (%v)", apnic.Country, apnic.DecodeData.Notes("country"))
+	}
+
+	// LACNIC: underscore language tags, lower case country.
+	lacnic := queryTestIPNetwork(t, "177.0.0.1")
+	if lacnic.Lang != "pt-BR" || lacnic.Country != "BR" {
+		t.Errorf("LACNIC: unexpected lang %q, country %q", lacnic.Lang, lacnic.Country)
+	} else if len(lacnic.Entities) != 1 || lacnic.Entities[0].Lang != "es-UY" {
+		t.Errorf("LACNIC: unexpected entity lang")
+	}
+
+	// AFRINIC: http-only links.
+	afrinic := queryTestIPNetwork(t, "41.0.0.1")
+	expectedHrefs := []string{
+		"https://rdap.afrinic.net/rdap/ip/41.0.0.0/16",
+		"https://rdap.afrinic.net/rdap/ip/41.0.0.0/8",
+		"http://www.example.net/",
+	}
+	for i, l := range afrinic.Links {
+		if l.Href != expectedHrefs[i] {
+			t.Errorf("AFRINIC: link #%d expected %s, got %s", i, expectedHrefs[i], l.Href)
+		}
+	}
+}
+
+func TestUpgradeLinksRequiresHTTPS(t *testing.T) {
+	n := &IPNetwork{
+		Links: []Link{{Href: "http://rdap.example.net/ip/192.0.2.0/24"}},
+	}
+
+	upgradeLinks(n, "http://rdap.example.net/ip/192.0.2.1")
+
+	if n.Links[0].Href != "http://rdap.example.net/ip/192.0.2.0/24" {
+		t.Errorf("Link unexpectedly upgraded for an http response")
+	}
+}
@@ -0,0 +1,101 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+)
+
+// race queries the RDAP servers |reqs| concurrently, and returns as soon as
+// one responds usefully (without error, and without a 5xx status code). The
+// remaining queries are cancelled.
+//
+// Returns the completed HTTPResponses by Request, and |reqs| reordered with
+// the winner (if any) first. Cancelled queries are not returned, so they will
+// be retried if the caller falls back to them.
+func (c *Client) race(reqs []*Request) (map[*Request]*HTTPResponse, []*Request) {
+	c.verbose(fmt.Sprintf("client: Racing %d RDAP servers", len(reqs)))
+
+	type result struct {
+		req          *Request
+		httpResponse *HTTPResponse
+	}
+
+	results := make(chan result, len(reqs))
+	cancelFuncs := make([]context.CancelFunc, len(reqs))
+
+	for i, r := range reqs {
+		ctx, cancelFunc := context.WithCancel(r.Context())
+		cancelFuncs[i] = cancelFunc
+
+		go func(r *Request, ctx context.Context) {
+			results <- result{r, c.get(r.WithContext(ctx))}
+		}(r, ctx)
+	}
+
+	responses := map[*Request]*HTTPResponse{}
+	var winner *Request
+
+	for range reqs {
+		res := <-results
+
+		// Response from a cancelled loser?
+		if winner != nil {
+			continue
+		}
+
+		responses[res.req] = res.httpResponse
+
+		if isRaceWinner(res.httpResponse) {
+			winner = res.req
+			c.verbose(fmt.Sprintf("client: Race won by %s", res.httpResponse.URL))
+
+			for _, cancelFunc := range cancelFuncs {
+				cancelFunc()
+			}
+		}
+	}
+
+	for _, cancelFunc := range cancelFuncs {
+		cancelFunc()
+	}
+
+	if winner == nil {
+		return responses, reqs
+	}
+
+	ordered := []*Request{winner}
+	for _, r := range reqs {
+		if r != winner {
+			ordered = append(ordered, r)
+		}
+	}
+
+	return responses, ordered
+}
+
+// isRaceWinner returns true if |httpResponse| is a definitive answer from an
+// RDAP server.
+func isRaceWinner(httpResponse *HTTPResponse) bool {
+	return httpResponse.Error == nil &&
+		httpResponse.Response != nil &&
+		httpResponse.Response.StatusCode < 500
+}
+
+// raceCount returns the number of RDAP servers to race for |req|, given
+// |numServers| candidate servers.
+func (c *Client) raceCount(req *Request, numServers int) int {
+	n := req.RaceServers
+	if n == 0 {
+		n = c.RaceServers
+	}
+
+	if n > numServers {
+		n = numServers
+	}
+
+	return n
+}
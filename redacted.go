@@ -0,0 +1,124 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "strings"
+
+// Redaction describes a redacted field of an RDAP response, as per the RDAP
+// redaction extension (RFC 9537). gTLD registries and registrars use it to
+// mark contact data withheld for privacy.
+//
+// Redactions appear in the "redacted" member of a topmost object, e.g.
+// Domain.Redacted.
+//
+// https://tools.ietf.org/html/rfc9537
+type Redaction struct {
+	DecodeData *DecodeData
+
+	// Name of the redacted field, e.g. {Type: "Registrant Email"}.
+	Name *RedactionText
+
+	// JSON paths of the field: before redaction (for removed fields), after
+	// redaction, and of a replacement field (e.g. a contact web form URL).
+	PrePath         string
+	PostPath        string
+	ReplacementPath string
+
+	// Language of the JSON paths, default "jsonpath".
+	PathLang string
+
+	// Redaction method: "removal", "emptyValue", "partialValue", or
+	// "replacementValue". Default "removal".
+	Method string
+
+	// Reason for the redaction, e.g. {Type: "Server policy"}.
+	Reason *RedactionText
+}
+
+// RedactionText is the name or reason of a Redaction. It's either a
+// registered Type, or a free text Description.
+type RedactionText struct {
+	DecodeData *DecodeData
+
+	Type        string
+	Description string
+}
+
+// String returns the Type, or if empty, the Description.
+func (r *RedactionText) String() string {
+	if r == nil {
+		return ""
+	} else if r.Type != "" {
+		return r.Type
+	}
+
+	return r.Description
+}
+
+// RedactionMethod returns the redaction method, defaulting to "removal".
+func (r *Redaction) RedactionMethod() string {
+	if r.Method == "" {
+		return "removal"
+	}
+
+	return r.Method
+}
+
+// IsRedacted returns true if the Domain response redacts the field named
+// |name| (e.g. "Registrant Email"), case insensitively.
+func (d *Domain) IsRedacted(name string) bool {
+	return findRedaction(d.Redacted, name) != nil
+}
+
+// IsRedacted returns true if the Entity response redacts the field named
+// |name| (e.g. "Registrant Email"), case insensitively.
+func (e *Entity) IsRedacted(name string) bool {
+	return findRedaction(e.Redacted, name) != nil
+}
+
+// findRedaction returns the Redaction in |redacted| of the field named
+// |name|, or nil if none.
+func findRedaction(redacted []Redaction, name string) *Redaction {
+	for i, r := range redacted {
+		if strings.EqualFold(r.Name.String(), name) {
+			return &redacted[i]
+		}
+	}
+
+	return nil
+}
+
+// redactionRolePrefixes are the prefixes of redacted field names for each
+// entity role, as used by ICANN's gTLD RDAP profile (e.g. "Tech Email").
+var redactionRolePrefixes = map[string]string{
+	"registrant":     "Registrant ",
+	"administrative": "Admin ",
+	"technical":      "Tech ",
+	"billing":        "Billing ",
+}
+
+// entityRedactions returns the names (without role prefix, e.g. "Email") of
+// the fields of an Entity with the roles |roles| which are redacted by
+// |redacted|.
+func entityRedactions(redacted []Redaction, roles []string) []string {
+	var fields []string
+
+	for _, role := range roles {
+		prefix, ok := redactionRolePrefixes[strings.ToLower(role)]
+		if !ok {
+			continue
+		}
+
+		for _, r := range redacted {
+			name := r.Name.String()
+
+			if len(name) > len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				fields = append(fields, name[len(prefix):])
+			}
+		}
+	}
+
+	return fields
+}
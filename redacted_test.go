@@ -0,0 +1,80 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const redactedDomainJSON = `{
+	"objectClassName": "domain",
+	"rdapConformance": ["rdap_level_0", "redacted"],
+	"ldhName": "example.com",
+	"entities": [
+		{"objectClassName": "entity", "roles": ["registrant"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", ""]]]}
+	],
+	"redacted": [
+		{
+			"name": {"type": "Registrant Email"},
+			"prePath": "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='email')]",
+			"method": "removal",
+			"reason": {"type": "Server policy"}
+		},
+		{
+			"name": {"type": "Registrant Name"},
+			"postPath": "$.entities[?(@.roles[0]=='registrant')].vcardArray[1][?(@[0]=='fn')][3]",
+			"pathLang": "jsonpath",
+			"method": "emptyValue"
+		},
+		{
+			"name": {"description": "Administrative Contact"},
+			"prePath": "$.entities[?(@.roles[0]=='administrative')]"
+		}
+	]
+}`
+
+func TestDecodeRedacted(t *testing.T) {
+	domain := decodeTestObject(t, redactedDomainJSON).(*Domain)
+
+	if len(domain.Redacted) != 3 {
+		t.Fatalf("Expected 3 redactions, got %d", len(domain.Redacted))
+	}
+
+	r := domain.Redacted[0]
+	if r.Name.Type != "Registrant Email" || r.Reason.String() != "Server policy" || r.RedactionMethod() != "removal" || !strings.HasPrefix(r.PrePath, "$.entities") {
+		t.Errorf("Redaction decoded incorrectly: %+v", r)
+	}
+
+	if domain.Redacted[2].Name.String() != "Administrative Contact" || domain.Redacted[2].RedactionMethod() != "removal" {
+		t.Errorf("Redaction with description name decoded incorrectly")
+	}
+
+	if !domain.IsRedacted("registrant email") || !domain.IsRedacted("Administrative Contact") || domain.IsRedacted("Tech Email") {
+		t.Errorf("IsRedacted incorrect")
+	}
+}
+
+func TestPrintRedacted(t *testing.T) {
+	domain := decodeTestObject(t, redactedDomainJSON)
+
+	var buf bytes.Buffer
+	printer := &Printer{Writer: &buf}
+	if err := printer.Print(domain); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, expected := range []string{
+		"    Email: [REDACTED]\n",
+		"    Name: [REDACTED]\n",
+		"  Redacted:\n    Name: Registrant Email\n    Method: removal\n    Reason: Server policy\n",
+	} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Output missing %q:\n%s", expected, out)
+		}
+	}
+}
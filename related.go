@@ -0,0 +1,231 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// followRelated implements Client.FollowRelated.
+//
+// The rel="related" links of the Domain |resp|.Object (typically from a gTLD
+// registry to the sponsoring registrar's RDAP server) are tried in order,
+// falling back to Domain.RegistrarRDAPURL(). The first which returns a Domain
+// is stored in |resp|.Related.
+//
+// Failed fetches are noted via Verbose and otherwise ignored.
+func (c *Client) followRelated(req *Request, resp *Response) {
+	domain, ok := resp.Object.(*Domain)
+	if !ok {
+		return
+	}
+
+	self := selfLink(domain.Links)
+
+	hrefs := relatedDomainLinks(domain.Links)
+	if len(hrefs) == 0 {
+		if href := domain.RegistrarRDAPURL(); href != "" {
+			hrefs = []string{href}
+		}
+	}
+
+	for _, href := range hrefs {
+		if href == self || href == req.URL().String() {
+			continue
+		}
+
+		u, err := url.Parse(href)
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: Bad related link '%s': %s", href, err))
+			continue
+		}
+
+		if c.HostPolicy != nil {
+			if err := c.HostPolicy.Check(u); err != nil {
+				c.verbose(fmt.Sprintf("client: Skipping related link: %s", err))
+				continue
+			}
+		}
+
+		c.verbose(fmt.Sprintf("client: Following related link %s", href))
+
+		httpResponse := c.get(NewRawRequest(u).WithContext(req.Context()))
+		related := &Response{
+			HTTP: []*HTTPResponse{httpResponse},
+		}
+
+		if httpResponse.Error != nil {
+			c.verbose(fmt.Sprintf("client: Related link error: %s", httpResponse.Error))
+			continue
+		} else if httpResponse.Response.StatusCode != 200 {
+			c.verbose(fmt.Sprintf("client: Related link returned status-code=%d",
+				httpResponse.Response.StatusCode))
+			continue
+		}
+
+		related.Object, err = c.newDecoder(httpResponse.Body).Decode()
+		if err != nil {
+			c.verbose(fmt.Sprintf("client: Error decoding related response: %s", err))
+			continue
+		}
+
+		if _, ok := related.Object.(*Domain); !ok {
+			c.verbose("client: Related link returned a non-Domain response")
+			continue
+		}
+
+		if c.InferRoles {
+			InferRoles(related.Object)
+		}
+
+		resp.Related = related
+		return
+	}
+}
+
+// RegistrarRDAPURL returns the URL of the Domain's record on the sponsoring
+// registrar's RDAP server, or empty string if unknown.
+//
+// The URL is taken from the Domain's rel="related" RDAP links (as used by
+// gTLD registries). Otherwise, if the Domain has an Entity with the
+// "registrar" role, that Entity's rel="related" RDAP links are used: a link to
+// a domain object is returned as-is, and for a link to an RDAP service base
+// URL (e.g. "https://rdap.registrar.example/"), the URL
+// "<base>/domain/<ldhName>" is returned. Other links (e.g. to the registrar's
+// own entity object) are ignored.
+func (d *Domain) RegistrarRDAPURL() string {
+	if hrefs := relatedDomainLinks(d.Links); len(hrefs) > 0 {
+		return hrefs[0]
+	}
+
+	if d.LDHName == "" {
+		return ""
+	}
+
+	for _, e := range d.Entities {
+		if !hasFetchRole(e.Roles, []string{"registrar"}) {
+			continue
+		}
+
+		for _, l := range e.Links {
+			if l.Rel != "related" || l.Href == "" || !isRDAPLinkType(l.Type) {
+				continue
+			}
+
+			if strings.Contains(l.Href, "/domain/") {
+				return l.Href
+			} else if isRDAPBaseURL(l.Href) {
+				return strings.TrimSuffix(l.Href, "/") + "/domain/" + d.LDHName
+			}
+		}
+	}
+
+	return ""
+}
+
+// QueryDomainAtRegistrar makes an RDAP request for the |domain| at its
+// registry, then queries the sponsoring registrar's RDAP server (see
+// Domain.RegistrarRDAPURL()).
+//
+// Both Domains are returned. If the registry's response does not specify a
+// registrar RDAP server, or the registrar query fails, the registry's Domain
+// is returned with the error.
+//
+// The timeout is 30s for each query.
+func (c *Client) QueryDomainAtRegistrar(domain string) (registry *Domain, registrar *Domain, err error) {
+	registry, err = c.QueryDomain(domain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registrarURL := registry.RegistrarRDAPURL()
+	if registrarURL == "" {
+		return registry, nil, &ClientError{
+			Type: BootstrapNoMatch,
+			Text: fmt.Sprintf("No registrar RDAP server found for '%s'", domain),
+		}
+	}
+
+	u, err := url.Parse(registrarURL)
+	if err != nil {
+		return registry, nil, &ClientError{
+			Type: InputError,
+			Text: fmt.Sprintf("Bad registrar RDAP URL '%s': %s", registrarURL, err),
+		}
+	}
+
+	resp, err := c.doQuickRequest(NewRawRequest(u))
+	if err != nil {
+		return registry, nil, err
+	}
+
+	if registrar, ok := resp.Object.(*Domain); ok {
+		return registry, registrar, nil
+	} else if respError, ok := resp.Object.(*Error); ok {
+		return registry, nil, clientErrorFromRDAPError(respError)
+	}
+
+	return registry, nil, &ClientError{
+		Type: WrongResponseType,
+		Text: "The registrar returned a non-Domain RDAP response",
+	}
+}
+
+// relatedDomainLinks returns the hrefs of the rel="related" links in |links|
+// which point to an RDAP domain object.
+func relatedDomainLinks(links []Link) []string {
+	var hrefs []string
+
+	for _, l := range links {
+		if l.Rel != "related" || l.Href == "" {
+			continue
+		}
+
+		if !isRDAPLinkType(l.Type) {
+			continue
+		}
+
+		if !strings.Contains(l.Href, "/domain/") {
+			continue
+		}
+
+		hrefs = append(hrefs, l.Href)
+	}
+
+	return hrefs
+}
+
+// isRDAPBaseURL returns true if |href| looks like an RDAP service base URL,
+// e.g. "https://rdap.example/" or "https://example/rdap/", rather than an
+// RDAP object (e.g. "https://rdap.example/entity/123").
+//
+// A base URL ends with a "/" (RFC 9224), has no query, and has no RDAP path
+// segment (e.g. "entity", "domain") in its path.
+func isRDAPBaseURL(href string) bool {
+	u, err := url.Parse(href)
+	if err != nil || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return false
+	}
+
+	if u.Path != "" && !strings.HasSuffix(u.Path, "/") {
+		return false
+	}
+
+	for _, segment := range strings.Split(u.Path, "/") {
+		switch strings.ToLower(segment) {
+		case "domain", "domains", "entity", "entities", "nameserver", "nameservers", "ip", "ips", "autnum", "autnums", "help":
+			return false
+		}
+	}
+
+	return true
+}
+
+// isRDAPLinkType returns true if the Link media type |t| is (or may be) RDAP.
+func isRDAPLinkType(t string) bool {
+	return t == "" || strings.HasPrefix(t, "application/rdap+json")
+}
@@ -9,9 +9,11 @@ import (
 	"fmt"
 	"net"
 	"net/url"
-	"strconv"
 	"strings"
 	"time"
+
+	"github.com/openrdap/rdap/bootstrap"
+	"github.com/openrdap/rdap/internal/autnum"
 )
 
 // A RequestType specifies an RDAP request type.
@@ -33,6 +35,10 @@ const (
 	NameserverSearchByNameserverIPRequest
 	EntitySearchRequest
 	EntitySearchByHandleRequest
+	IPSearchRequest
+	IPSearchByHandleRequest
+	AutnumSearchRequest
+	AutnumSearchByHandleRequest
 
 	// RawRequest is a request with a fixed RDAP URL.
 	RawRequest
@@ -68,6 +74,14 @@ func (r RequestType) String() string {
 		return "entity-search"
 	case EntitySearchByHandleRequest:
 		return "entity-search-by-handle"
+	case IPSearchRequest:
+		return "ip-search"
+	case IPSearchByHandleRequest:
+		return "ip-search-by-handle"
+	case AutnumSearchRequest:
+		return "autnum-search"
+	case AutnumSearchByHandleRequest:
+		return "autnum-search-by-handle"
 	case RawRequest:
 		return "url"
 	default:
@@ -100,6 +114,10 @@ func (r RequestType) String() string {
 //	rdap.NameserverSearchByNameserverIPRequest | No            | nameservers?ip=QUERY    | 192.0.2.0
 //	rdap.EntitySearchRequest                   | No            | entities?fn=QUERY       | ABC*-VRSN
 //	rdap.EntitySearchByHandleRequest           | No            | entities?handle=QUERY   | ABC*-VRSN
+//	rdap.IPSearchRequest                       | No            | ips?name=QUERY          | EXAMPLE-NET*
+//	rdap.IPSearchByHandleRequest               | No            | ips?handle=QUERY        | NET-192-0-2*
+//	rdap.AutnumSearchRequest                   | No            | autnums?name=QUERY      | EXAMPLE-AS*
+//	rdap.AutnumSearchByHandleRequest           | No            | autnums?handle=QUERY    | AS6449*
 //	                                           |               |                         |
 //	rdap.RawRequest                            | N/A           | N/A                     | N/A
 //
@@ -174,7 +192,71 @@ type Request struct {
 	// The default is no timeout.
 	Timeout time.Duration
 
+	// Maximum duration of the bootstrap phase (i.e. downloading the Service
+	// Registry files), before timeout.
+	//
+	// This prevents a slow bootstrap download from consuming the whole
+	// request duration. The default is Client.BootstrapTimeout.
+	BootstrapTimeout time.Duration
+
+	// Maximum duration of each RDAP server query, before timeout.
+	//
+	// The default is Client.QueryTimeout.
+	QueryTimeout time.Duration
+
+	// Optional list of preferred response languages, most preferred first.
+	// e.g. []string{"ja", "en"}.
+	//
+	// These are sent in the HTTP Accept-Language header. Servers which
+	// support localized responses indicate the language used with "lang"
+	// fields (see the Common struct). The default is Client.Languages.
+	Languages []string
+
+	// Optional list of RDAP extensions the client supports, e.g.
+	// []string{"rdap_level_0", "redacted"}.
+	//
+	// These are advertised in the HTTP Accept header (RDAP-X media type
+	// parameter). Use Response.NegotiatedExtensions() to find which the
+	// server supports. The default is Client.Extensions.
+	Extensions []string
+
+	// Optional search result sort order (RFC 8977), e.g.
+	// []string{"registrationDate:d", "name"}. Each entry is a sort property,
+	// optionally suffixed by ":a" (ascending, the default) or ":d"
+	// (descending). Servers list their sort properties in
+	// SortingMetadata.AvailableSorts.
+	Sort []string
+
+	// Request the total number of search results (RFC 8977 "count"), see
+	// PagingMetadata.TotalCount.
+	Count bool
+
+	// Optional search result page cursor (RFC 8977), from the previous
+	// page's "next" link. See PagingMetadata.NextCursor().
+	Cursor string
+
+	// Optional search result field set (RFC 8982), e.g. FieldSetBrief. Smaller
+	// field sets reduce response sizes, e.g. when enumerating many objects.
+	// Servers list their field sets in SubsettingMetadata.AvailableFieldSets.
+	FieldSet string
+
+	// Number of RDAP server URLs to query concurrently. The default is
+	// Client.RaceServers.
+	RaceServers int
+
+	// Optional key/value metadata for the Request, e.g. a case number, or the
+	// log line which triggered the query.
+	//
+	// Tags are not sent to the RDAP server. They are copied to Response.Tags,
+	// and included in Tracer span attributes (as "rdap.tag.KEY"), so results
+	// can be joined back to their originating context.
+	Tags map[string]string
+
 	ctx context.Context
+
+	// Bootstrap answer which selected Server, if any. Recorded by the
+	// Archive.
+	bootstrapAnswer *bootstrap.Answer
 }
 
 func (r *Request) pathAndValues() (string, url.Values) {
@@ -216,6 +298,18 @@ func (r *Request) pathAndValues() (string, url.Values) {
 	case EntitySearchByHandleRequest:
 		path = "entities"
 		values["handle"] = []string{r.Query}
+	case IPSearchRequest:
+		path = "ips"
+		values["name"] = []string{r.Query}
+	case IPSearchByHandleRequest:
+		path = "ips"
+		values["handle"] = []string{r.Query}
+	case AutnumSearchRequest:
+		path = "autnums"
+		values["name"] = []string{r.Query}
+	case AutnumSearchByHandleRequest:
+		path = "autnums"
+		values["handle"] = []string{r.Query}
 	case RawRequest:
 		// Server URL(s) are the entire request.
 	default:
@@ -280,6 +374,18 @@ func (r *Request) URL() *url.URL {
 		for k, v := range values {
 			query[k] = v
 		}
+		if len(r.Sort) > 0 {
+			query.Set("sort", strings.Join(r.Sort, ","))
+		}
+		if r.Count {
+			query.Set("count", "true")
+		}
+		if r.Cursor != "" {
+			query.Set("cursor", r.Cursor)
+		}
+		if r.FieldSet != "" {
+			query.Set("fieldSet", r.FieldSet)
+		}
 		resultURL.RawQuery = query.Encode()
 
 		resultURL.Fragment = r.Server.Fragment
@@ -464,10 +570,10 @@ func NewAutoRequest(queryText string) *Request {
 		return NewIPNetRequest(ipNet)
 	}
 
-	// AS number? (formats: AS1234, as1234, 1234).
-	autnum, err := parseAutnum(queryText)
+	// AS number? (formats: AS1234, as1234, 1234, and asdot AS1.10, 1.10).
+	asn, err := autnum.Parse(queryText)
 	if err == nil {
-		return NewAutnumRequest(autnum)
+		return NewAutnumRequest(asn)
 	}
 
 	// Looks like a domain name?
@@ -478,15 +584,3 @@ func NewAutoRequest(queryText string) *Request {
 	// Otherwise call it an entity query.
 	return NewEntityRequest(queryText)
 }
-
-func parseAutnum(autnum string) (uint32, error) {
-	autnum = strings.ToUpper(autnum)
-	autnum = strings.TrimPrefix(autnum, "AS")
-	result, err := strconv.ParseUint(autnum, 10, 32)
-
-	if err != nil {
-		return 0, err
-	}
-
-	return uint32(result), nil
-}
@@ -165,6 +165,26 @@ func TestNewSearchRequests(t *testing.T) {
 			"MY-HANDLE*&x=1",
 			"entities?handle=MY-HANDLE%2A%26x%3D1",
 		},
+		{
+			IPSearchRequest,
+			"EXAMPLE-NET*",
+			"ips?name=EXAMPLE-NET%2A",
+		},
+		{
+			IPSearchByHandleRequest,
+			"NET-192-0-2*",
+			"ips?handle=NET-192-0-2%2A",
+		},
+		{
+			AutnumSearchRequest,
+			"EXAMPLE-AS*",
+			"autnums?name=EXAMPLE-AS%2A",
+		},
+		{
+			AutnumSearchByHandleRequest,
+			"AS6449*",
+			"autnums?handle=AS6449%2A",
+		},
 	}
 
 	for _, test := range tests {
@@ -221,6 +241,8 @@ func TestNewAutoRequest(t *testing.T) {
 		{"as12", AutnumRequest},
 		{"aS123", AutnumRequest},
 		{"1234", AutnumRequest},
+		{"AS1.10", AutnumRequest},
+		{"1.10", AutnumRequest},
 
 		{"example.com", DomainRequest},
 
@@ -238,3 +260,9 @@ func TestNewAutoRequest(t *testing.T) {
 		}
 	}
 }
+
+func TestAutoRequestASDot(t *testing.T) {
+	if r := NewAutoRequest("AS1.10"); r.Query != "65546" {
+		t.Errorf("Expected asdot normalized to asplain 65546, got %s", r.Query)
+	}
+}
@@ -15,10 +15,64 @@ type Response struct {
 	Object          RDAPObject
 	BootstrapAnswer *bootstrap.Answer
 	HTTP            []*HTTPResponse
+
+	// Warnings about how the response was obtained, e.g. a fallback to an
+	// alternate RDAP server after a TLS certificate error.
+	Warnings []string
+
+	// Tags copied from Request.Tags.
+	Tags map[string]string
+
+	// Cached is true if the Response was returned from the Client's
+	// ObjectCache.
+	Cached bool
+
+	// DNSPrecheckSkipped is true if the RDAP query was skipped, because
+	// Client.DNSPrecheck found the domain doesn't exist.
+	DNSPrecheckSkipped bool
+
+	// Related response, e.g. the registrar's RDAP response for a gTLD domain.
+	// See Client.FollowRelated.
+	Related *Response
 }
 
 type RDAPObject interface{}
 
+// ContentLanguage returns the Content-Language header of the RDAP response
+// which was decoded into Object.
+//
+// Returns empty string if the server did not specify a Content-Language.
+// Servers may also (or instead) specify the language in "lang" fields.
+func (r *Response) ContentLanguage() string {
+	for _, h := range r.HTTP {
+		if h.Error == nil && h.Response != nil && h.Response.StatusCode >= 200 && h.Response.StatusCode <= 299 {
+			return h.Response.Header.Get("Content-Language")
+		}
+	}
+
+	return ""
+}
+
+// objectBody returns the body of the HTTP response which was decoded into
+// Object, or nil if none.
+//
+// Responses loaded from a ResponseStore have a Body, but no Response.
+func (r *Response) objectBody() []byte {
+	for _, h := range r.HTTP {
+		if h.Error != nil {
+			continue
+		}
+
+		if h.Response != nil && h.Response.StatusCode >= 200 && h.Response.StatusCode <= 299 {
+			return h.Body
+		} else if h.Response == nil && h.Body != nil {
+			return h.Body
+		}
+	}
+
+	return nil
+}
+
 type HTTPResponse struct {
 	URL      string
 	Response *http.Response
@@ -92,14 +146,14 @@ func (r *Response) ToWhoisStyleResponse() *WhoisStyleResponse {
 		}
 
 		// "Registrar IANA ID"
-		for _, id := range registrar.PublicIDs {
-			if id.Type == "IANA Registrar ID" {
-				w.add("Registrar IANA ID", id.Identifier)
-			}
-		}
+		w.add("Registrar IANA ID", d.RegistrarIANAID())
 
 		// "Registrar Abuse Contact Email"
 		// "Registrar Abuse Contact Phone"
+		if abuse := d.AbuseContact(); abuse != nil {
+			w.add("Registrar Abuse Contact Email", abuse.Email)
+			w.add("Registrar Abuse Contact Phone", abuse.Phone)
+		}
 	}
 
 	// "Domain Status"
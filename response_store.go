@@ -0,0 +1,137 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ResponseStore is a persistent cache of RDAP responses, see
+// Client.ResponseStore.
+//
+// cache.BoltCache implements ResponseStore, e.g.:
+//
+//	db := cache.NewBoltCache("/var/cache/rdap.db")
+//
+//	client := &rdap.Client{
+//	  Bootstrap:     &bootstrap.Client{Cache: db},
+//	  ResponseStore: db,
+//	}
+type ResponseStore interface {
+	// LoadResponse returns the response saved under |key|. An error is
+	// returned if the response is absent or has expired.
+	LoadResponse(key string) ([]byte, error)
+
+	// SaveResponse saves the response |data| under |key|, for |ttl|.
+	SaveResponse(key string, data []byte, ttl time.Duration) error
+}
+
+const (
+	defaultResponseStoreTTL = time.Hour
+)
+
+// storedResponse is a Response saved in a ResponseStore.
+type storedResponse struct {
+	URL     string          `json:"url"`
+	Body    json.RawMessage `json:"body"`
+	Related *storedResponse `json:"related,omitempty"`
+}
+
+// loadStoredResponse returns the Response saved in the ResponseStore under
+// |key|, or nil if none.
+func (c *Client) loadStoredResponse(key string) *Response {
+	data, err := c.ResponseStore.LoadResponse(key)
+	if err != nil {
+		return nil
+	}
+
+	s := &storedResponse{}
+	if err := json.Unmarshal(data, s); err != nil {
+		c.verbose(fmt.Sprintf("client: Bad stored response (%s), ignoring", err))
+		return nil
+	}
+
+	resp := s.response(c)
+	if resp != nil && c.InferRoles {
+		InferRoles(resp.Object)
+	}
+
+	return resp
+}
+
+// response decodes the stored response, with the Client |c|'s decoding
+// options. Returns nil on error.
+func (s *storedResponse) response(c *Client) *Response {
+	obj, err := c.newDecoder(s.Body).Decode()
+	if err != nil {
+		return nil
+	}
+
+	if n, ok := obj.(*IPNetwork); ok {
+		upgradeLinks(n, s.URL)
+	}
+
+	resp := &Response{
+		Object: obj,
+		HTTP: []*HTTPResponse{
+			{
+				URL:  s.URL,
+				Body: []byte(s.Body),
+			},
+		},
+		Cached: true,
+	}
+
+	if s.Related != nil {
+		resp.Related = s.Related.response(c)
+	}
+
+	return resp
+}
+
+// storeResponse saves the successful Response |resp| in the ResponseStore
+// under |key|.
+func (c *Client) storeResponse(key string, resp *Response, url string) {
+	s := newStoredResponse(resp, url)
+	if s == nil {
+		return
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return
+	}
+
+	ttl := c.ResponseStoreTTL
+	if ttl <= 0 {
+		ttl = defaultResponseStoreTTL
+	}
+
+	if err := c.ResponseStore.SaveResponse(key, data, ttl); err != nil {
+		c.verbose(fmt.Sprintf("client: Error saving response to the ResponseStore: %s", err))
+	}
+}
+
+// newStoredResponse returns the storedResponse for |resp|, fetched from
+// |url|. Returns nil if |resp| has no response body.
+func newStoredResponse(resp *Response, url string) *storedResponse {
+	body := resp.objectBody()
+	if body == nil {
+		return nil
+	}
+
+	s := &storedResponse{
+		URL:  url,
+		Body: json.RawMessage(body),
+	}
+
+	if r := resp.Related; r != nil && len(r.HTTP) > 0 {
+		s.Related = newStoredResponse(r, r.HTTP[len(r.HTTP)-1].URL)
+	}
+
+	return s
+}
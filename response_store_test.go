@@ -0,0 +1,62 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openrdap/rdap/bootstrap/cache"
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientResponseStore(t *testing.T) {
+	db := cache.NewBoltCache(filepath.Join(t.TempDir(), "rdap.db"))
+
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+
+	client := &Client{
+		Verbose:          verboseFunc(),
+		ResponseStore:    db,
+		ResponseStoreTTL: time.Minute,
+	}
+
+	resp, err := client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Fatal(err)
+	} else if resp.Cached {
+		t.Errorf("Unexpected Cached response")
+	}
+
+	test.Finish()
+
+	// A new Client, without network access, is answered from the store.
+	test.Start(test.BootstrapHTTPError)
+	defer test.Finish()
+
+	client = &Client{
+		Verbose:       verboseFunc(),
+		ResponseStore: db,
+	}
+
+	resp, err = client.Do(NewDomainRequest("example.cz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domain, ok := resp.Object.(*Domain)
+	if !ok || domain.LDHName != "example.cz" || !resp.Cached {
+		t.Errorf("Unexpected response %+v", resp)
+	} else if resp.HTTP[0].URL != "https://rdap.nic.cz/domain/example.cz" {
+		t.Errorf("Unexpected URL %s", resp.HTTP[0].URL)
+	}
+
+	// Other queries still require network access.
+	if _, err := client.Do(NewDomainRequest("example.br")); err == nil {
+		t.Errorf("Unexpected success")
+	}
+}
@@ -0,0 +1,255 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// SearchIterator iterates over the results of a search Request, transparently
+// fetching each further page of results (RFC 8977 rel="next" links).
+//
+// Example usage:
+//
+//	it := client.SearchDomains(ctx, server, "example*.com")
+//	it.Limit = 500
+//
+//	for it.Next() {
+//	  fmt.Println(it.Domain().LDHName)
+//	}
+//
+//	if err := it.Err(); err != nil {
+//	  ...
+//	}
+//
+// A SearchIterator isn't safe for concurrent use.
+type SearchIterator struct {
+	// Maximum number of results to return. The default is no limit.
+	Limit int
+
+	// Maximum number of pages to fetch. The default is no limit.
+	MaxPages int
+
+	client  *Client
+	req     *Request
+	nextURL string
+	seen    map[string]bool
+
+	results  []RDAPObject
+	current  RDAPObject
+	response *Response
+	count    int
+	pages    int
+	err      error
+}
+
+// Search runs the search Request |req| (e.g. a DomainSearchRequest), and
+// returns an iterator over all of its results, across all pages.
+//
+// No request is made until the first call to Next().
+func (c *Client) Search(req *Request) *SearchIterator {
+	return &SearchIterator{
+		client: c,
+		req:    req,
+		seen:   map[string]bool{},
+	}
+}
+
+// SearchDomains returns an iterator over the domains matching |pattern|
+// (e.g. "example*.com"), on the RDAP server |server|. See Search().
+//
+// Search requests aren't bootstrapped, so the server must be specified.
+func (c *Client) SearchDomains(ctx context.Context, server *url.URL, pattern string) *SearchIterator {
+	req := NewRequest(DomainSearchRequest, pattern).WithServer(server)
+
+	return c.Search(req.WithContext(ctx))
+}
+
+// SearchEntities returns an iterator over the entities with names matching
+// |pattern| (e.g. "Example*"), on the RDAP server |server|. See Search().
+func (c *Client) SearchEntities(ctx context.Context, server *url.URL, pattern string) *SearchIterator {
+	req := NewRequest(EntitySearchRequest, pattern).WithServer(server)
+
+	return c.Search(req.WithContext(ctx))
+}
+
+// SearchNameservers returns an iterator over the nameservers matching
+// |pattern| (e.g. "ns*.example.com"), on the RDAP server |server|. See
+// Search().
+func (c *Client) SearchNameservers(ctx context.Context, server *url.URL, pattern string) *SearchIterator {
+	req := NewRequest(NameserverSearchRequest, pattern).WithServer(server)
+
+	return c.Search(req.WithContext(ctx))
+}
+
+// Next advances to the next search result, fetching the next page of results
+// if required. Returns false when there are no more results, the Limit is
+// reached, or on error (see Err()).
+func (it *SearchIterator) Next() bool {
+	it.current = nil
+
+	if it.err != nil || (it.Limit > 0 && it.count >= it.Limit) {
+		return false
+	}
+
+	for len(it.results) == 0 {
+		if it.pages > 0 && it.nextURL == "" {
+			return false
+		} else if it.MaxPages > 0 && it.pages >= it.MaxPages {
+			return false
+		}
+
+		if !it.fetchPage() {
+			return false
+		}
+	}
+
+	it.current = it.results[0]
+	it.results = it.results[1:]
+	it.count++
+
+	return true
+}
+
+// fetchPage fetches the next page of results. Returns false on error.
+func (it *SearchIterator) fetchPage() bool {
+	var resp *Response
+	var err error
+
+	if it.pages == 0 {
+		resp, err = it.client.Do(it.req)
+	} else {
+		if it.seen[it.nextURL] {
+			it.err = &ClientError{
+				Type: RDAPServerError,
+				Text: fmt.Sprintf("Search results paging loop at %s", it.nextURL),
+				URL:  it.nextURL,
+			}
+			return false
+		}
+
+		resp, err = it.client.FetchLink(it.req.Context(), Link{Rel: "next", Href: it.nextURL}, it.req.Type)
+	}
+
+	it.pages++
+
+	if err != nil {
+		it.err = err
+		return false
+	} else if respError, ok := resp.Object.(*Error); ok {
+		it.err = clientErrorFromRDAPError(respError)
+		return false
+	}
+
+	results, ok := searchResultObjects(resp.Object)
+	if !ok {
+		it.err = &ClientError{
+			Type: WrongResponseType,
+			Text: fmt.Sprintf("The server returned a %T, expected search results", resp.Object),
+		}
+		return false
+	}
+
+	if it.nextURL != "" {
+		it.seen[it.nextURL] = true
+	}
+
+	it.response = resp
+	it.results = results
+	it.nextURL = pagingMetadataOf(resp.Object).NextURL()
+
+	return true
+}
+
+// Object returns the current search result: a *Domain, *Entity, or
+// *Nameserver.
+func (it *SearchIterator) Object() RDAPObject {
+	return it.current
+}
+
+// Domain returns the current search result as a *Domain, or nil if it isn't
+// one.
+func (it *SearchIterator) Domain() *Domain {
+	d, _ := it.current.(*Domain)
+	return d
+}
+
+// Entity returns the current search result as an *Entity, or nil if it isn't
+// one.
+func (it *SearchIterator) Entity() *Entity {
+	e, _ := it.current.(*Entity)
+	return e
+}
+
+// Nameserver returns the current search result as a *Nameserver, or nil if
+// it isn't one.
+func (it *SearchIterator) Nameserver() *Nameserver {
+	n, _ := it.current.(*Nameserver)
+	return n
+}
+
+// IPNetwork returns the current search result as an *IPNetwork, or nil if
+// it isn't one.
+func (it *SearchIterator) IPNetwork() *IPNetwork {
+	n, _ := it.current.(*IPNetwork)
+	return n
+}
+
+// Autnum returns the current search result as an *Autnum, or nil if it isn't
+// one.
+func (it *SearchIterator) Autnum() *Autnum {
+	a, _ := it.current.(*Autnum)
+	return a
+}
+
+// Response returns the Response of the current page of results.
+func (it *SearchIterator) Response() *Response {
+	return it.response
+}
+
+// Pages returns the number of pages fetched so far.
+func (it *SearchIterator) Pages() int {
+	return it.pages
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
+// searchResultObjects returns the results of the search results |obj|.
+// Returns false if |obj| isn't a search results object.
+func searchResultObjects(obj RDAPObject) ([]RDAPObject, bool) {
+	var results []RDAPObject
+
+	switch v := obj.(type) {
+	case *DomainSearchResults:
+		for i := range v.Domains {
+			results = append(results, &v.Domains[i])
+		}
+	case *EntitySearchResults:
+		for i := range v.Entities {
+			results = append(results, &v.Entities[i])
+		}
+	case *NameserverSearchResults:
+		for i := range v.Nameservers {
+			results = append(results, &v.Nameservers[i])
+		}
+	case *IPNetworkSearchResults:
+		for i := range v.Networks {
+			results = append(results, &v.Networks[i])
+		}
+	case *AutnumSearchResults:
+		for i := range v.Autnums {
+			results = append(results, &v.Autnums[i])
+		}
+	default:
+		return nil, false
+	}
+
+	return results, true
+}
@@ -12,9 +12,19 @@ type DomainSearchResults struct {
 
 	Common
 	Conformance []string `rdap:"rdapConformance"`
-	Notices     []Notice
+
+	// Notices about the search as a whole, e.g. rate limits, truncated
+	// results, or terms of use. See also SearchNotices().
+	Notices []Notice
 
 	Domains []Domain `rdap:"domainSearchResults"`
+
+	// Paging and sorting information (RFC 8977), if supported by the server.
+	PagingMetadata  *PagingMetadata  `rdap:"paging_metadata"`
+	SortingMetadata *SortingMetadata `rdap:"sorting_metadata"`
+
+	// Field set information (RFC 8982), if supported by the server.
+	SubsettingMetadata *SubsettingMetadata `rdap:"subsetting_metadata"`
 }
 
 // NameserverSearchResults represents a nameserver search response.
@@ -25,9 +35,19 @@ type NameserverSearchResults struct {
 
 	Common
 	Conformance []string `rdap:"rdapConformance"`
-	Notices     []Notice
+
+	// Notices about the search as a whole, e.g. rate limits, truncated
+	// results, or terms of use. See also SearchNotices().
+	Notices []Notice
 
 	Nameservers []Nameserver `rdap:"nameserverSearchResults"`
+
+	// Paging and sorting information (RFC 8977), if supported by the server.
+	PagingMetadata  *PagingMetadata  `rdap:"paging_metadata"`
+	SortingMetadata *SortingMetadata `rdap:"sorting_metadata"`
+
+	// Field set information (RFC 8982), if supported by the server.
+	SubsettingMetadata *SubsettingMetadata `rdap:"subsetting_metadata"`
 }
 
 // EntitySearchResults represents an entity search response.
@@ -38,7 +58,142 @@ type EntitySearchResults struct {
 
 	Common
 	Conformance []string `rdap:"rdapConformance"`
-	Notices     []Notice
+
+	// Notices about the search as a whole, e.g. rate limits, truncated
+	// results, or terms of use. See also SearchNotices().
+	Notices []Notice
 
 	Entities []Entity `rdap:"entitySearchResults"`
+
+	// Paging and sorting information (RFC 8977), if supported by the server.
+	PagingMetadata  *PagingMetadata  `rdap:"paging_metadata"`
+	SortingMetadata *SortingMetadata `rdap:"sorting_metadata"`
+
+	// Field set information (RFC 8982), if supported by the server.
+	SubsettingMetadata *SubsettingMetadata `rdap:"subsetting_metadata"`
+}
+
+// IPNetworkSearchResults represents an IP network search response, from an
+// RIR's /ips search endpoint.
+//
+// IPNetworkSearchResults is a topmost RDAP response object.
+type IPNetworkSearchResults struct {
+	DecodeData *DecodeData
+
+	Common
+	Conformance []string `rdap:"rdapConformance"`
+
+	// Notices about the search as a whole, e.g. rate limits, truncated
+	// results, or terms of use. See also SearchNotices().
+	Notices []Notice
+
+	Networks []IPNetwork `rdap:"networkSearchResults"`
+
+	// Paging and sorting information (RFC 8977), if supported by the server.
+	PagingMetadata  *PagingMetadata  `rdap:"paging_metadata"`
+	SortingMetadata *SortingMetadata `rdap:"sorting_metadata"`
+
+	// Field set information (RFC 8982), if supported by the server.
+	SubsettingMetadata *SubsettingMetadata `rdap:"subsetting_metadata"`
+}
+
+// AutnumSearchResults represents an autnum search response, from an RIR's
+// /autnums search endpoint.
+//
+// AutnumSearchResults is a topmost RDAP response object.
+type AutnumSearchResults struct {
+	DecodeData *DecodeData
+
+	Common
+	Conformance []string `rdap:"rdapConformance"`
+
+	// Notices about the search as a whole, e.g. rate limits, truncated
+	// results, or terms of use. See also SearchNotices().
+	Notices []Notice
+
+	Autnums []Autnum `rdap:"autnumSearchResults"`
+
+	// Paging and sorting information (RFC 8977), if supported by the server.
+	PagingMetadata  *PagingMetadata  `rdap:"paging_metadata"`
+	SortingMetadata *SortingMetadata `rdap:"sorting_metadata"`
+
+	// Field set information (RFC 8982), if supported by the server.
+	SubsettingMetadata *SubsettingMetadata `rdap:"subsetting_metadata"`
+}
+
+// SearchNotices returns the top level Notices of the search results |obj|,
+// followed by any other notices repeated in the results themselves (some
+// servers put them there too). Each notice is returned once.
+//
+// Returns nil if |obj| isn't a search results object.
+func SearchNotices(obj RDAPObject) []Notice {
+	var notices []Notice
+	var memberNotices [][]Notice
+
+	switch v := obj.(type) {
+	case *DomainSearchResults:
+		notices = v.Notices
+		for _, d := range v.Domains {
+			memberNotices = append(memberNotices, d.Notices)
+		}
+	case *NameserverSearchResults:
+		notices = v.Notices
+		for _, n := range v.Nameservers {
+			memberNotices = append(memberNotices, n.Notices)
+		}
+	case *EntitySearchResults:
+		notices = v.Notices
+		for _, e := range v.Entities {
+			memberNotices = append(memberNotices, e.Notices)
+		}
+	case *IPNetworkSearchResults:
+		notices = v.Notices
+		for _, n := range v.Networks {
+			memberNotices = append(memberNotices, n.Notices)
+		}
+	case *AutnumSearchResults:
+		notices = v.Notices
+		for _, a := range v.Autnums {
+			memberNotices = append(memberNotices, a.Notices)
+		}
+	default:
+		return nil
+	}
+
+	result := append([]Notice{}, notices...)
+	for _, ns := range memberNotices {
+		for _, n := range ns {
+			if !containsNotice(result, n) {
+				result = append(result, n)
+			}
+		}
+	}
+
+	return result
+}
+
+// containsNotice returns true if |notices| contains a notice with the same
+// title, type, and description as |n|.
+func containsNotice(notices []Notice, n Notice) bool {
+	for _, o := range notices {
+		if o.Title == n.Title && o.Type == n.Type && equalStrings(o.Description, n.Description) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
 }
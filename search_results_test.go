@@ -0,0 +1,109 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testDomainSearchResults = `
+{
+  "rdapConformance": ["rdap_level_0"],
+  "notices": [
+    {"title": "Terms of Use", "description": ["Don't be evil."]},
+    {"title": "Search Policy", "type": "result set truncated due to unexplainable reasons", "description": ["Truncated."]}
+  ],
+  "domainSearchResults": [
+    {
+      "objectClassName": "domain",
+      "ldhName": "example1.cz",
+      "notices": [{"title": "Terms of Use", "description": ["Don't be evil."]}]
+    },
+    {
+      "objectClassName": "domain",
+      "ldhName": "example2.cz",
+      "notices": [{"title": "Rate Limit", "description": ["Slow down."]}]
+    }
+  ]
+}`
+
+func TestSearchNotices(t *testing.T) {
+	sr := decodeTestObject(t, testDomainSearchResults).(*DomainSearchResults)
+
+	if len(sr.Notices) != 2 || sr.Notices[1].Type != "result set truncated due to unexplainable reasons" {
+		t.Fatalf("Unexpected top level notices %v", sr.Notices)
+	}
+
+	notices := SearchNotices(sr)
+
+	var titles []string
+	for _, n := range notices {
+		titles = append(titles, n.Title)
+	}
+
+	if strings.Join(titles, ",") != "Terms of Use,Search Policy,Rate Limit" {
+		t.Errorf("Unexpected notices %v", titles)
+	}
+
+	if SearchNotices(&Domain{}) != nil {
+		t.Errorf("Expected no search notices for a domain")
+	}
+}
+
+func TestPrintSearchNotices(t *testing.T) {
+	sr := decodeTestObject(t, testDomainSearchResults)
+
+	var out bytes.Buffer
+	printer := &Printer{Writer: &out}
+	if err := printer.Print(sr); err != nil {
+		t.Fatalf("Print error: %s", err)
+	}
+
+	output := out.String()
+
+	if n := strings.Count(output, "Notice:"); n != 3 {
+		t.Errorf("Expected 3 notices, got %d:\n%s", n, output)
+	} else if strings.Index(output, "Rate Limit") > strings.Index(output, "Domain:") {
+		t.Errorf("Expected all notices before the results:\n%s", output)
+	}
+}
+
+func TestDecodeRIRSearchResults(t *testing.T) {
+	networks := decodeTestObject(t, `{
+		"rdapConformance": ["rdap_level_0", "rirSearch1"],
+		"networkSearchResults": [
+			{"objectClassName": "ip network", "handle": "NET-192-0-2-0-1", "startAddress": "192.0.2.0", "endAddress": "192.0.2.255"},
+			{"objectClassName": "ip network", "handle": "NET-198-51-100-0-1", "startAddress": "198.51.100.0", "endAddress": "198.51.100.255"}
+		]
+	}`).(*IPNetworkSearchResults)
+
+	if len(networks.Networks) != 2 || networks.Networks[1].Handle != "NET-198-51-100-0-1" {
+		t.Errorf("Unexpected networks %+v", networks.Networks)
+	}
+
+	autnums := decodeTestObject(t, `{
+		"rdapConformance": ["rdap_level_0", "rirSearch1"],
+		"notices": [{"title": "Terms of Use", "description": ["..."]}],
+		"autnumSearchResults": [
+			{"objectClassName": "autnum", "handle": "AS64496", "startAutnum": 64496, "endAutnum": 64496}
+		]
+	}`).(*AutnumSearchResults)
+
+	if len(autnums.Autnums) != 1 || autnums.Autnums[0].Handle != "AS64496" || len(SearchNotices(autnums)) != 1 {
+		t.Errorf("Unexpected autnums %+v", autnums)
+	}
+
+	var out bytes.Buffer
+	(&Printer{Writer: &out}).Print(networks)
+	if !strings.HasPrefix(out.String(), "IP Network Search Results:\n") || strings.Count(out.String(), "IP Network:\n") != 2 {
+		t.Errorf("Unexpected output:\n%s", out.String())
+	}
+
+	if !isObjectOfType(autnums, AutnumSearchByHandleRequest) || isObjectOfType(autnums, IPSearchRequest) {
+		t.Errorf("isObjectOfType failed for AutnumSearchResults")
+	}
+}
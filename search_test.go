@@ -0,0 +1,96 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// newSearchTestServer returns a server with |pages| pages of two domain
+// search results each. With |loop|, the last page links back to the first.
+func newSearchTestServer(pages int, loop bool) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("cursor"), "page%d", &page)
+
+		next := ""
+		if page < pages {
+			next = fmt.Sprintf(`, "links": [{"rel": "next", "href": "%s/domains?name=x*&cursor=page%d"}]`, server.URL, page+1)
+		} else if loop {
+			next = fmt.Sprintf(`, "links": [{"rel": "next", "href": "%s/domains?name=x*&cursor=page1"}]`, server.URL)
+		}
+
+		fmt.Fprintf(w, `{
+			"rdapConformance": ["rdap_level_0", "paging"],
+			"paging_metadata": {"pageNumber": %d%s},
+			"domainSearchResults": [
+				{"objectClassName": "domain", "ldhName": "x%d-a.example"},
+				{"objectClassName": "domain", "ldhName": "x%d-b.example"}
+			]
+		}`, page, next, page, page)
+	}))
+
+	return server
+}
+
+func TestSearchIterator(t *testing.T) {
+	server := newSearchTestServer(3, false)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	client := &Client{}
+
+	var names []string
+	it := client.SearchDomains(context.Background(), serverURL, "x*")
+	for it.Next() {
+		names = append(names, it.Domain().LDHName)
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	} else if len(names) != 6 || names[0] != "x1-a.example" || names[5] != "x3-b.example" {
+		t.Errorf("Unexpected results %v", names)
+	} else if it.Pages() != 3 {
+		t.Errorf("Expected 3 pages, got %d", it.Pages())
+	}
+
+	it = client.SearchDomains(context.Background(), serverURL, "x*")
+	it.Limit = 3
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if count != 3 || it.Pages() != 2 {
+		t.Errorf("Limit: got %d results from %d pages, expected 3 from 2", count, it.Pages())
+	}
+}
+
+func TestSearchIteratorLoop(t *testing.T) {
+	server := newSearchTestServer(2, true)
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+
+	it := (&Client{}).SearchDomains(context.Background(), serverURL, "x*")
+
+	count := 0
+	for it.Next() {
+		count++
+	}
+
+	if !isClientError(RDAPServerError, it.Err()) {
+		t.Errorf("Expected RDAPServerError for a paging loop, got %v", it.Err())
+	} else if count != 6 {
+		t.Errorf("Expected 6 results before the loop was detected, got %d", count)
+	}
+}
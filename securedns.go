@@ -0,0 +1,210 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// DS digest types, see KeyData.DS().
+//
+// https://www.iana.org/assignments/ds-rr-types/ds-rr-types.xhtml
+const (
+	DigestSHA1   uint8 = 1
+	DigestSHA256 uint8 = 2
+	DigestSHA384 uint8 = 4
+)
+
+// rdata returns the DNSKEY RDATA of the key: flags, protocol, algorithm, and
+// the public key (RFC 4034 section 2.1).
+func (k KeyData) rdata() ([]byte, error) {
+	if k.Flags == nil || k.Protocol == nil || k.Algorithm == nil {
+		return nil, errors.New("keyData is missing flags, protocol, or algorithm")
+	}
+
+	publicKey, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(k.PublicKey), ""))
+	if err != nil {
+		return nil, fmt.Errorf("keyData publicKey is not base64: %s", err)
+	}
+
+	rdata := make([]byte, 4, 4+len(publicKey))
+	binary.BigEndian.PutUint16(rdata, *k.Flags)
+	rdata[2] = *k.Protocol
+	rdata[3] = *k.Algorithm
+
+	return append(rdata, publicKey...), nil
+}
+
+// KeyTag returns the key's DNSSEC key tag, as per RFC 4034 appendix B.
+func (k KeyData) KeyTag() (uint16, error) {
+	rdata, err := k.rdata()
+	if err != nil {
+		return 0, err
+	}
+
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+	ac += ac >> 16 & 0xFFFF
+
+	return uint16(ac & 0xFFFF), nil
+}
+
+// DS returns the DS record of the key, for the zone |owner| (e.g.
+// "example.cz"), using the digest type |digestType| (e.g. DigestSHA256).
+//
+// This is the DS record the parent zone should publish for the key (RFC 4034
+// section 5.1.4).
+func (k KeyData) DS(owner string, digestType uint8) (DSData, error) {
+	var h hash.Hash
+	switch digestType {
+	case DigestSHA1:
+		h = sha1.New()
+	case DigestSHA256:
+		h = sha256.New()
+	case DigestSHA384:
+		h = sha512.New384()
+	default:
+		return DSData{}, fmt.Errorf("unsupported DS digest type %d", digestType)
+	}
+
+	rdata, err := k.rdata()
+	if err != nil {
+		return DSData{}, err
+	}
+
+	name, err := canonicalWireName(owner)
+	if err != nil {
+		return DSData{}, err
+	}
+
+	h.Write(name)
+	h.Write(rdata)
+
+	keyTag, _ := k.KeyTag()
+	keyTag64 := uint64(keyTag)
+	algorithm := *k.Algorithm
+
+	return DSData{
+		KeyTag:     &keyTag64,
+		Algorithm:  &algorithm,
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+		DigestType: &digestType,
+	}, nil
+}
+
+// Matches returns true if the DS records |d| and |other| are equal: the same
+// key tag, algorithm, digest type, and digest. Digests are compared case
+// insensitively, ignoring whitespace.
+func (d DSData) Matches(other DSData) bool {
+	equal := func(a *uint64, b *uint64) bool {
+		return a != nil && b != nil && *a == *b
+	}
+	equal8 := func(a *uint8, b *uint8) bool {
+		return a != nil && b != nil && *a == *b
+	}
+
+	return equal(d.KeyTag, other.KeyTag) &&
+		equal8(d.Algorithm, other.Algorithm) &&
+		equal8(d.DigestType, other.DigestType) &&
+		normalizeDigest(d.Digest) == normalizeDigest(other.Digest)
+}
+
+// MatchesKey returns true if the DS record |d| is the DS record of the key
+// |key|, for the zone |owner|.
+func (d DSData) MatchesKey(owner string, key KeyData) (bool, error) {
+	if d.DigestType == nil {
+		return false, errors.New("dsData is missing digestType")
+	}
+
+	ds, err := key.DS(owner, *d.DigestType)
+	if err != nil {
+		return false, err
+	}
+
+	return d.Matches(ds), nil
+}
+
+// VerifyDS checks each DS record of the Domain |d| matches one of its keys.
+// Only meaningful when the server publishes both dsData and keyData.
+//
+// Returns an error for each DS record which doesn't match, or nil if all
+// match.
+func (d *Domain) VerifyDS() []error {
+	if d.SecureDNS == nil {
+		return nil
+	}
+
+	owner := d.LDHName
+	if owner == "" {
+		owner = d.UnicodeName
+	}
+
+	var errs []error
+	for i, ds := range d.SecureDNS.DS {
+		matched := false
+
+		var lastErr error
+		for _, key := range d.SecureDNS.Keys {
+			ok, err := ds.MatchesKey(owner, key)
+			if err != nil {
+				lastErr = err
+			} else if ok {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			if lastErr != nil {
+				errs = append(errs, fmt.Errorf("dsData[%d]: %s", i, lastErr))
+			} else {
+				errs = append(errs, fmt.Errorf("dsData[%d]: no matching keyData", i))
+			}
+		}
+	}
+
+	return errs
+}
+
+// canonicalWireName returns the DNS name |name| in canonical (lower case)
+// wire format, as per RFC 4034 section 6.2.
+func canonicalWireName(name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if len(label) == 0 || len(label) > 63 {
+				return nil, fmt.Errorf("invalid DNS name %q", name)
+			}
+
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	return append(wire, 0), nil
+}
+
+// normalizeDigest returns the hex digest |digest| in upper case, without
+// whitespace.
+func normalizeDigest(digest string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(digest), ""))
+}
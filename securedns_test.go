@@ -0,0 +1,70 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "testing"
+
+// testSecureDNSDomain is the DNSKEY and DS example of RFC 4034 section 5.4.
+const testSecureDNSDomain = `{
+	"objectClassName": "domain",
+	"ldhName": "dskey.example.com",
+	"secureDNS": {
+		"delegationSigned": true,
+		"dsData": [
+			{"keyTag": 60485, "algorithm": 5, "digestType": 1, "digest": "2BB183AF5F22588179A53B0A98631FAD1A292118"}
+		],
+		"keyData": [
+			{
+				"flags": 256, "protocol": 3, "algorithm": 5,
+				"publicKey": "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw==",
+				"events": [{"eventAction": "last changed", "eventDate": "2017-01-01T00:00:00Z"}],
+				"links": [{"href": "https://example.com/key"}]
+			}
+		]
+	}
+}`
+
+func TestSecureDNSValidation(t *testing.T) {
+	d := decodeTestObject(t, testSecureDNSDomain).(*Domain)
+	key := d.SecureDNS.Keys[0]
+
+	if len(key.Events) != 1 || len(key.Links) != 1 {
+		t.Errorf("KeyData events/links not decoded: %+v", key)
+	}
+
+	if tag, err := key.KeyTag(); err != nil || tag != 60485 {
+		t.Errorf("Got key tag %d, %v", tag, err)
+	}
+
+	ds, err := key.DS("DSKEY.example.com.", DigestSHA1)
+	if err != nil {
+		t.Fatal(err)
+	} else if ds.Digest != "2BB183AF5F22588179A53B0A98631FAD1A292118" || !ds.Matches(d.SecureDNS.DS[0]) {
+		t.Errorf("Got DS %s", ds.Digest)
+	}
+
+	if errs := d.VerifyDS(); len(errs) != 0 {
+		t.Errorf("Unexpected VerifyDS errors %v", errs)
+	}
+
+	ds256, err := key.DS("dskey.example.com", DigestSHA256)
+	if err != nil || len(ds256.Digest) != 64 || ds256.Matches(ds) {
+		t.Errorf("Unexpected SHA-256 DS %+v, %v", ds256, err)
+	}
+
+	if _, err := key.DS("dskey.example.com", 3); err == nil {
+		t.Errorf("Expected error for unsupported digest type")
+	}
+
+	d.SecureDNS.DS[0].Digest = "2bb183af 5f22588179a53b0a98631fad1a292118"
+	if errs := d.VerifyDS(); len(errs) != 0 {
+		t.Errorf("Digest comparison should ignore case and whitespace: %v", errs)
+	}
+
+	d.LDHName = "other.example.com"
+	if errs := d.VerifyDS(); len(errs) != 1 {
+		t.Errorf("Expected a DS mismatch, got %v", errs)
+	}
+}
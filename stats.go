@@ -0,0 +1,87 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"fmt"
+	"time"
+)
+
+// ResponseStats summarizes what was fetched to produce a Response, e.g. to
+// show the cost of Request.FetchRoles in bulk jobs.
+type ResponseStats struct {
+	// Object class of the Response, e.g. "domain", or "domainSearchResults".
+	ObjectClass string
+
+	// Number of distinct entities in the Response (including the Related
+	// response).
+	Entities int
+
+	// Number of HTTP requests made, including failed attempts and extra
+	// fetches.
+	Requests int
+
+	// Number of extra HTTP requests made after the RDAP query itself, for
+	// Request.FetchRoles and Client.FollowRelated.
+	ExtraFetches int
+
+	// Total size of the response bodies, in bytes.
+	Bytes int
+
+	// Total duration of the HTTP requests.
+	Duration time.Duration
+}
+
+// Stats returns a summary of what was fetched to produce the Response.
+//
+// Responses from the Client's ObjectCache or ResponseStore made no HTTP
+// requests, so only ObjectClass and Entities are set.
+func (r *Response) Stats() ResponseStats {
+	var s ResponseStats
+	if r.Object != nil {
+		s.ObjectClass = objectClassOf(r.Object)
+	}
+
+	g := NewGraph()
+	for resp := r; resp != nil; resp = resp.Related {
+		g.Add(resp.Object)
+	}
+
+	for _, n := range g.Nodes {
+		if n.Type == "entity" {
+			s.Entities++
+		}
+	}
+
+	if r.Cached {
+		return s
+	}
+
+	for resp := r; resp != nil; resp = resp.Related {
+		queried := false
+
+		for _, h := range resp.HTTP {
+			s.Requests++
+			s.Bytes += len(h.Body)
+			s.Duration += h.Duration
+
+			// Requests after the successful query are extra fetches.
+			if queried || resp != r {
+				s.ExtraFetches++
+			} else if h.Error == nil && h.Response != nil && h.Response.StatusCode >= 200 && h.Response.StatusCode <= 299 {
+				queried = true
+			}
+		}
+	}
+
+	return s
+}
+
+// String returns a one line summary, e.g. "domain, 4 entities, 3 HTTP
+// requests (2 extra fetches), 10523 bytes in 1.2s".
+func (s ResponseStats) String() string {
+	return fmt.Sprintf("%s, %d entities, %d HTTP requests (%d extra fetches), %d bytes in %s",
+		s.ObjectClass, s.Entities, s.Requests, s.ExtraFetches, s.Bytes, s.Duration.Round(time.Millisecond))
+}
@@ -0,0 +1,43 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestResponseStats(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+	}
+
+	req := NewDomainRequest("fetch-roles.cz")
+	req.FetchRoles = []string{"registrant"}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	s := resp.Stats()
+	if s.ObjectClass != "domain" || s.Requests != 2 || s.ExtraFetches != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	} else if s.Bytes != len(resp.HTTP[0].Body)+len(resp.HTTP[1].Body) {
+		t.Errorf("Unexpected byte count %d", s.Bytes)
+	} else if s.Entities != 2 {
+		t.Errorf("Expected 2 entities, got %d", s.Entities)
+	}
+
+	resp.Cached = true
+	if s := resp.Stats(); s.Requests != 0 || s.Bytes != 0 || s.Entities != 2 {
+		t.Errorf("Unexpected cached stats %+v", s)
+	}
+}
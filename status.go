@@ -0,0 +1,184 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "strings"
+
+// Status is an RDAP status value, e.g. "client transfer prohibited".
+//
+// RDAP objects keep their status values as raw strings (e.g. Domain.Status).
+// Use ParseStatus() or ParseStatuses() to convert them.
+//
+// https://tools.ietf.org/html/rfc9083#section-10.2.2
+type Status string
+
+// Status values registered by RFC 9083 and RFC 8056.
+const (
+	StatusValidated                Status = "validated"
+	StatusRenewProhibited          Status = "renew prohibited"
+	StatusUpdateProhibited         Status = "update prohibited"
+	StatusTransferProhibited       Status = "transfer prohibited"
+	StatusDeleteProhibited         Status = "delete prohibited"
+	StatusProxy                    Status = "proxy"
+	StatusPrivate                  Status = "private"
+	StatusRemoved                  Status = "removed"
+	StatusObscured                 Status = "obscured"
+	StatusAssociated               Status = "associated"
+	StatusActive                   Status = "active"
+	StatusInactive                 Status = "inactive"
+	StatusLocked                   Status = "locked"
+	StatusPendingCreate            Status = "pending create"
+	StatusPendingRenew             Status = "pending renew"
+	StatusPendingTransfer          Status = "pending transfer"
+	StatusPendingUpdate            Status = "pending update"
+	StatusPendingDelete            Status = "pending delete"
+	StatusAddPeriod                Status = "add period"
+	StatusAutoRenewPeriod          Status = "auto renew period"
+	StatusClientDeleteProhibited   Status = "client delete prohibited"
+	StatusClientHold               Status = "client hold"
+	StatusClientRenewProhibited    Status = "client renew prohibited"
+	StatusClientTransferProhibited Status = "client transfer prohibited"
+	StatusClientUpdateProhibited   Status = "client update prohibited"
+	StatusPendingRestore           Status = "pending restore"
+	StatusRedemptionPeriod         Status = "redemption period"
+	StatusRenewPeriod              Status = "renew period"
+	StatusServerDeleteProhibited   Status = "server delete prohibited"
+	StatusServerRenewProhibited    Status = "server renew prohibited"
+	StatusServerTransferProhibited Status = "server transfer prohibited"
+	StatusServerUpdateProhibited   Status = "server update prohibited"
+	StatusServerHold               Status = "server hold"
+	StatusTransferPeriod           Status = "transfer period"
+)
+
+// statusEPP maps each registered Status to its EPP status code (RFC 5731,
+// RFC 5732, RFC 5733, RFC 3915), as per RFC 8056. Statuses without an EPP
+// equivalent map to empty string.
+var statusEPP = map[Status]string{
+	StatusValidated:                "",
+	StatusRenewProhibited:          "",
+	StatusUpdateProhibited:         "",
+	StatusTransferProhibited:       "",
+	StatusDeleteProhibited:         "",
+	StatusProxy:                    "",
+	StatusPrivate:                  "",
+	StatusRemoved:                  "",
+	StatusObscured:                 "",
+	StatusAssociated:               "linked",
+	StatusActive:                   "ok",
+	StatusInactive:                 "inactive",
+	StatusLocked:                   "",
+	StatusPendingCreate:            "pendingCreate",
+	StatusPendingRenew:             "pendingRenew",
+	StatusPendingTransfer:          "pendingTransfer",
+	StatusPendingUpdate:            "pendingUpdate",
+	StatusPendingDelete:            "pendingDelete",
+	StatusAddPeriod:                "addPeriod",
+	StatusAutoRenewPeriod:          "autoRenewPeriod",
+	StatusClientDeleteProhibited:   "clientDeleteProhibited",
+	StatusClientHold:               "clientHold",
+	StatusClientRenewProhibited:    "clientRenewProhibited",
+	StatusClientTransferProhibited: "clientTransferProhibited",
+	StatusClientUpdateProhibited:   "clientUpdateProhibited",
+	StatusPendingRestore:           "pendingRestore",
+	StatusRedemptionPeriod:         "redemptionPeriod",
+	StatusRenewPeriod:              "renewPeriod",
+	StatusServerDeleteProhibited:   "serverDeleteProhibited",
+	StatusServerRenewProhibited:    "serverRenewProhibited",
+	StatusServerTransferProhibited: "serverTransferProhibited",
+	StatusServerUpdateProhibited:   "serverUpdateProhibited",
+	StatusServerHold:               "serverHold",
+	StatusTransferPeriod:           "transferPeriod",
+}
+
+// statusByKey maps the statusKey() of each registered Status and EPP status
+// code to its Status.
+var statusByKey = map[string]Status{}
+
+func init() {
+	for s, epp := range statusEPP {
+		statusByKey[statusKey(string(s))] = s
+
+		if epp != "" {
+			statusByKey[statusKey(epp)] = s
+		}
+	}
+}
+
+// statusKey returns |s| lower cased, with spaces, underscores, and hyphens
+// removed, so e.g. "clientHold", "CLIENT_HOLD", and "client hold" all match.
+func statusKey(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '_', '-':
+			return -1
+		}
+
+		return r
+	}, strings.ToLower(s))
+}
+
+// ParseStatus converts the status value |s| to a Status.
+//
+// Case and separator variants of the registered values (e.g. "ACTIVE",
+// "client_hold", "Client Hold"), and EPP status codes (e.g. "clientHold",
+// "ok"), are normalized to the registered value. Other values are lower
+// cased, with underscores replaced by spaces.
+func ParseStatus(s string) Status {
+	s = strings.TrimSpace(s)
+
+	if status, ok := statusByKey[statusKey(s)]; ok {
+		return status
+	}
+
+	return Status(strings.ToLower(strings.Replace(s, "_", " ", -1)))
+}
+
+// ParseStatuses converts the status values |statuses| (e.g. Domain.Status)
+// using ParseStatus().
+func ParseStatuses(statuses []string) []Status {
+	result := make([]Status, 0, len(statuses))
+
+	for _, s := range statuses {
+		result = append(result, ParseStatus(s))
+	}
+
+	return result
+}
+
+// StatusFromEPP returns the Status of the EPP status code |code| (e.g.
+// "clientTransferProhibited"), as per RFC 8056.
+//
+// Returns false if |code| isn't a known EPP status code.
+func StatusFromEPP(code string) (Status, bool) {
+	for s, epp := range statusEPP {
+		if epp != "" && strings.EqualFold(epp, strings.TrimSpace(code)) {
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+// EPP returns the EPP status code of the Status (e.g. "ok" for "active"), as
+// per RFC 8056.
+//
+// Returns false if there's no EPP equivalent.
+func (s Status) EPP() (string, bool) {
+	epp := statusEPP[s]
+
+	return epp, epp != ""
+}
+
+// Known returns true if the Status is a value registered by RFC 9083 or
+// RFC 8056.
+func (s Status) Known() bool {
+	_, ok := statusEPP[s]
+
+	return ok
+}
+
+func (s Status) String() string {
+	return string(s)
+}
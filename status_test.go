@@ -0,0 +1,64 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		Input    string
+		Expected Status
+	}{
+		{"active", StatusActive},
+		{"ACTIVE", StatusActive},
+		{"ok", StatusActive},
+		{" Client Transfer Prohibited ", StatusClientTransferProhibited},
+		{"clientTransferProhibited", StatusClientTransferProhibited},
+		{"CLIENT_TRANSFER_PROHIBITED", StatusClientTransferProhibited},
+		{"linked", StatusAssociated},
+		{"pending-delete", StatusPendingDelete},
+		{"ALLOCATED_PA", Status("allocated pa")},
+	}
+
+	for _, test := range tests {
+		if actual := ParseStatus(test.Input); actual != test.Expected {
+			t.Errorf("ParseStatus(%q) = %q, expected %q", test.Input, actual, test.Expected)
+		}
+	}
+
+	statuses := ParseStatuses([]string{"ok", "serverHold"})
+	if !reflect.DeepEqual(statuses, []Status{StatusActive, StatusServerHold}) {
+		t.Errorf("ParseStatuses got %v", statuses)
+	}
+}
+
+func TestStatusEPP(t *testing.T) {
+	if epp, ok := StatusClientHold.EPP(); !ok || epp != "clientHold" {
+		t.Errorf("StatusClientHold.EPP() = %q, %v", epp, ok)
+	}
+
+	if epp, ok := StatusActive.EPP(); !ok || epp != "ok" {
+		t.Errorf("StatusActive.EPP() = %q, %v", epp, ok)
+	}
+
+	if _, ok := StatusObscured.EPP(); ok {
+		t.Errorf("Unexpected EPP code for StatusObscured")
+	}
+
+	if s, ok := StatusFromEPP("serverTransferProhibited"); !ok || s != StatusServerTransferProhibited {
+		t.Errorf("StatusFromEPP got %q, %v", s, ok)
+	}
+
+	if _, ok := StatusFromEPP("bogus"); ok {
+		t.Errorf("Unexpected Status for bogus EPP code")
+	}
+
+	if !StatusLocked.Known() || Status("allocated pa").Known() {
+		t.Errorf("Known() incorrect")
+	}
+}
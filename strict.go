@@ -0,0 +1,122 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// verifyResponse checks that the RDAP object |obj| corresponds to the Request
+// |req|, see Client.Strict.
+//
+// The object's class must match the request type. For lookups, the object's
+// identity must also match the query:
+//
+//	Domain, Nameserver - ldhName or unicodeName equals the query.
+//	Entity             - handle equals the query.
+//	Autnum             - the AS number is within startAutnum-endAutnum.
+//	IPNetwork          - the IP address/network is within
+//	                     startAddress-endAddress.
+//
+// Returns a WrongResponseType *ClientError describing the mismatch, or nil.
+// RDAP Error responses are not checked.
+func verifyResponse(req *Request, obj RDAPObject, url string) error {
+	if _, ok := obj.(*Error); ok {
+		return nil
+	}
+
+	mismatch := func(format string, args ...interface{}) error {
+		return &ClientError{
+			Type: WrongResponseType,
+			Text: fmt.Sprintf("Strict mode: %s query '%s': ", req.Type, req.Query) + fmt.Sprintf(format, args...),
+			URL:  url,
+		}
+	}
+
+	if !isObjectOfType(obj, req.Type) {
+		return mismatch("server returned a %T, expected a %s response", obj, req.Type)
+	}
+
+	if req.Type == RawRequest {
+		return nil
+	}
+
+	switch o := obj.(type) {
+	case *Domain:
+		if !nameMatches(req.Query, o.LDHName, o.UnicodeName) {
+			return mismatch("response is for domain '%s'", firstNonEmpty(o.LDHName, o.UnicodeName))
+		}
+	case *Nameserver:
+		if !nameMatches(req.Query, o.LDHName, o.UnicodeName) {
+			return mismatch("response is for nameserver '%s'", firstNonEmpty(o.LDHName, o.UnicodeName))
+		}
+	case *Entity:
+		if !strings.EqualFold(o.Handle, req.Query) {
+			return mismatch("response is for entity '%s'", o.Handle)
+		}
+	case *Autnum:
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(req.Query), "AS"), 10, 32)
+		if err != nil {
+			return nil
+		}
+
+		if o.StartAutnum == nil || uint64(*o.StartAutnum) > asn ||
+			(o.EndAutnum != nil && uint64(*o.EndAutnum) < asn) ||
+			(o.EndAutnum == nil && uint64(*o.StartAutnum) != asn) {
+			return mismatch("response is for autnum '%s'", o.Handle)
+		}
+	case *IPNetwork:
+		if !ipNetworkContains(o, req.Query) {
+			return mismatch("response is for IP network %s-%s", o.StartAddress, o.EndAddress)
+		}
+	}
+
+	return nil
+}
+
+// nameMatches returns true if the domain name |query| equals any of |names|,
+// ignoring case and a trailing dot.
+func nameMatches(query string, names ...string) bool {
+	query = strings.TrimSuffix(query, ".")
+
+	for _, name := range names {
+		if name != "" && strings.EqualFold(strings.TrimSuffix(name, "."), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ipNetworkContains returns true if the IP address or CIDR network |query| is
+// within the IPNetwork |n|'s address range.
+func ipNetworkContains(n *IPNetwork, query string) bool {
+	var first, last net.IP
+
+	if ip, cidr, err := net.ParseCIDR(query); err == nil {
+		first = ip.Mask(cidr.Mask)
+		last = make(net.IP, len(first))
+		for i := range first {
+			last[i] = first[i] | ^cidr.Mask[i]
+		}
+	} else if ip := net.ParseIP(query); ip != nil {
+		first, last = ip, ip
+	} else {
+		// Unknown query format, can't check.
+		return true
+	}
+
+	start := net.ParseIP(n.StartAddress)
+	end := net.ParseIP(n.EndAddress)
+	if start == nil || end == nil {
+		return false
+	}
+
+	return bytes.Compare(start.To16(), first.To16()) <= 0 && bytes.Compare(last.To16(), end.To16()) <= 0
+}
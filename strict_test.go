@@ -0,0 +1,89 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestClientStrict(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	client := &Client{
+		Verbose: verboseFunc(),
+		Strict:  true,
+	}
+
+	rawURL, _ := url.Parse("https://rdap.nic.cz/domain/misrouted.cz")
+
+	for _, req := range []*Request{
+		NewDomainRequest("example.cz"),
+		NewIPNetRequest(parseTestCIDR("192.0.2.0/24")),
+		NewRawRequest(rawURL),
+	} {
+		if _, err := client.Do(req); err != nil {
+			t.Errorf("%s query '%s': unexpected error: %s", req.Type, req.Query, err)
+		}
+	}
+
+	for _, req := range []*Request{
+		NewDomainRequest("misrouted.cz"),
+		NewDomainRequest("wrong-response-type.cz"),
+	} {
+		_, err := client.Do(req)
+
+		var ce *ClientError
+		if !errors.Is(err, ErrWrongResponseType) || !errors.As(err, &ce) || ce.URL == "" {
+			t.Errorf("%s query '%s': expected WrongResponseType error, got %v", req.Type, req.Query, err)
+		}
+	}
+
+	client.Strict = false
+	if _, err := client.Do(NewDomainRequest("misrouted.cz")); err != nil {
+		t.Errorf("Unexpected error with Strict disabled: %s", err)
+	}
+}
+
+func parseTestCIDR(cidr string) *net.IPNet {
+	_, n, _ := net.ParseCIDR(cidr)
+	return n
+}
+
+func TestVerifyResponseIdentity(t *testing.T) {
+	start, end := uint32(100), uint32(200)
+	autnum := &Autnum{Handle: "AS100-AS200", StartAutnum: &start, EndAutnum: &end}
+	network := &IPNetwork{StartAddress: "192.0.2.0", EndAddress: "192.0.2.255"}
+
+	tests := []struct {
+		Req *Request
+		Obj RDAPObject
+		OK  bool
+	}{
+		{NewAutnumRequest(150), autnum, true},
+		{NewAutnumRequest(201), autnum, false},
+		{&Request{Type: IPRequest, Query: "192.0.2.128/25"}, network, true},
+		{&Request{Type: IPRequest, Query: "192.0.2.0/23"}, network, false},
+		{&Request{Type: IPRequest, Query: "198.51.100.1"}, network, false},
+		{NewDomainRequest("EXAMPLE.CZ."), &Domain{LDHName: "example.cz"}, true},
+		{NewDomainRequest("example.cz"), &Domain{LDHName: "example.com"}, false},
+		{NewEntityRequest("cz-registrant"), &Entity{Handle: "CZ-REGISTRANT"}, true},
+		{NewEntityRequest("OTHER"), &Entity{Handle: "CZ-REGISTRANT"}, false},
+	}
+
+	for _, test := range tests {
+		err := verifyResponse(test.Req, test.Obj, "")
+
+		if (err == nil) != test.OK {
+			t.Errorf("%s query '%s': expected ok=%v, got %v", test.Req.Type, test.Req.Query, test.OK, err)
+		}
+	}
+}
@@ -83,6 +83,7 @@ func loadTestDatasets() {
 	load(Bootstrap, 200, "https://data.iana.org/rdap/ipv4.json", "bootstrap/ipv4.json")
 	load(Bootstrap, 200, "https://data.iana.org/rdap/ipv6.json", "bootstrap/ipv6.json")
 	load(Bootstrap, 200, "https://data.iana.org/rdap/object-tags.json", "bootstrap/object-tags.json")
+	load(Bootstrap, 200, "https://data.iana.org/TLD/tlds-alpha-by-domain.txt", "iana/tlds-alpha-by-domain.txt")
 
 	// Malformed bootstrap files.
 	load(BootstrapMalformed, 200, "https://www.example.org/dns_bad_services.json", "bootstrap_malformed/dns_bad_services.json")
@@ -104,6 +105,24 @@ func loadTestDatasets() {
 	load(Responses, 404, "https://rdap.nic.cz/domain/non-existent.cz", "misc/empty.html")
 	load(Responses, 200, "https://rdap.nic.cz/domain/wrong-response-type.cz", "rdap/rdap.nic.cz/nameserver-ns2.pipni.cz.json")
 	load(Responses, 200, "https://rdap.nic.cz/domain/malformed.cz", "misc/malformed.json")
+	load(Responses, 200, "https://rdap.nic.cz/domain/misrouted.cz", "rdap/rdap.nic.cz/domain-example.cz.json")
+	load(Responses, 200, "https://rdap.nic.cz/domain/fetch-roles.cz", "rdap/rdap.nic.cz/domain-fetch-roles.cz.json")
+	load(Responses, 200, "https://rdap.nic.cz/entity/CZ-REGISTRANT", "rdap/rdap.nic.cz/entity-CZ-REGISTRANT.json")
+	load(Responses, 200, "https://rdap.nic.cz/domain/related.cz", "rdap/rdap.nic.cz/domain-related.cz.json")
+	load(Responses, 200, "https://rdap.registrar.example/domain/related.cz", "rdap/rdap.registrar.example/domain-related.cz.json")
+
+	// IP network hierarchy.
+	load(Responses, 200, "https://rdap.arin.net/registry/ip/192.0.2.0/25", "rdap/rdap.arin.net/ip-192.0.2.0-25.json")
+	load(Responses, 200, "https://rdap.arin.net/registry/ip/192.0.2.0/24", "rdap/rdap.arin.net/ip-192.0.2.0-24.json")
+	load(Responses, 200, "https://rdap.arin.net/registry/ip/192.0.0.0/8", "rdap/rdap.arin.net/ip-192.0.0.0-8.json")
+	load(Responses, 200, "https://rdap.arin.net/registry/ip/198.51.100.1", "rdap/rdap.arin.net/ip-198.51.100.1.json")
+	load(Responses, 200, "https://rdap.arin.net/registry/autnum/3356", "rdap/rdap.arin.net/autnum-3356.json")
+
+	// RIR IP network quirks.
+	load(Responses, 200, "https://rdap.db.ripe.net/ip/2.0.0.1", "rdap/rdap.db.ripe.net/ip-2.0.0.1.json")
+	load(Responses, 200, "https://rdap.apnic.net/ip/1.0.0.1", "rdap/rdap.apnic.net/ip-1.0.0.1.json")
+	load(Responses, 200, "https://rdap.lacnic.net/rdap/ip/177.0.0.1", "rdap/rdap.lacnic.net/ip-177.0.0.1.json")
+	load(Responses, 200, "https://rdap.afrinic.net/rdap/ip/41.0.0.1", "rdap/rdap.afrinic.net/ip-41.0.0.1.json")
 }
 
 func load(set TestDataset, status int, url string, filename string) {
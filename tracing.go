@@ -0,0 +1,68 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import "context"
+
+// Tracer creates tracing spans for the lifecycle of a Client's queries.
+//
+// Implementations typically wrap a distributed tracing library, e.g.
+// OpenTelemetry:
+//
+//	type otelTracer struct {
+//	  tracer trace.Tracer
+//	}
+//
+//	func (t *otelTracer) StartSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, rdap.Span) {
+//	  ctx, span := t.tracer.Start(ctx, name)
+//	  for k, v := range attrs {
+//	    span.SetAttributes(attribute.String(k, v))
+//	  }
+//	  return ctx, &otelSpan{span}
+//	}
+//
+//	client := &rdap.Client{Tracer: &otelTracer{otel.Tracer("rdap")}}
+//
+// The following spans are created:
+//
+//	Name            | Parent     | Attributes
+//	----------------+------------+------------------------------------
+//	rdap.query      | (caller's) | rdap.type, rdap.query, rdap.tag.KEY
+//	rdap.bootstrap  | rdap.query | rdap.registry
+//	rdap.http       | rdap.query | http.url
+//	rdap.decode     | rdap.query |
+//
+// The context returned by StartSpan() is used for all operations within the
+// span, including the HTTP requests. This allows an instrumented
+// http.RoundTripper (e.g. otelhttp) to attach its spans correctly.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, Span)
+}
+
+// Span represents a single traced operation, see Tracer.
+type Span interface {
+	// SetAttribute sets the attribute |key| to |value|.
+	SetAttribute(key string, value string)
+
+	// End completes the span. |err| is the operation's error, or nil on
+	// success.
+	End(err error)
+}
+
+type nopSpan struct{}
+
+func (nopSpan) SetAttribute(key string, value string) {}
+func (nopSpan) End(err error)                         {}
+
+// startSpan starts a span using the Client's Tracer.
+//
+// If no Tracer is set, |ctx| is returned unmodified with a no-op Span.
+func (c *Client) startSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	if c.Tracer == nil {
+		return ctx, nopSpan{}
+	}
+
+	return c.Tracer.StartSpan(ctx, name, attributes)
+}
@@ -0,0 +1,74 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+type testSpanKey struct{}
+
+type testTracer struct {
+	spans []string
+	ended int
+}
+
+type testSpan struct {
+	t *testTracer
+}
+
+func (t *testTracer) StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	t.spans = append(t.spans, parent+">"+name)
+
+	return context.WithValue(ctx, testSpanKey{}, name), &testSpan{t}
+}
+
+func (s *testSpan) SetAttribute(key string, value string) {}
+
+func (s *testSpan) End(err error) {
+	s.t.ended++
+}
+
+func TestClientTracer(t *testing.T) {
+	test.Start(test.Bootstrap)
+	test.Start(test.Responses)
+	defer test.Finish()
+
+	tracer := &testTracer{}
+	client := &Client{
+		Verbose: verboseFunc(),
+		Tracer:  tracer,
+	}
+
+	_, err := client.QueryDomain("example.cz")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := []string{
+		">rdap.query",
+		"rdap.query>rdap.bootstrap",
+		"rdap.query>rdap.http",
+		"rdap.query>rdap.decode",
+	}
+
+	if len(tracer.spans) != len(expected) {
+		t.Fatalf("Got spans %v, expected %v", tracer.spans, expected)
+	}
+
+	for i := range expected {
+		if tracer.spans[i] != expected[i] {
+			t.Fatalf("Got spans %v, expected %v", tracer.spans, expected)
+		}
+	}
+
+	if tracer.ended != len(expected) {
+		t.Errorf("Expected %d spans ended, got %d", len(expected), tracer.ended)
+	}
+}
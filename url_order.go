@@ -0,0 +1,88 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// A URLOrderFunc reorders the bootstrapped RDAP base URLs |urls| for the
+// Request |req|, before they are queried.
+//
+// URLs may also be removed (e.g. known broken endpoints). The returned slice
+// may be |urls| modified in place.
+//
+// See Client.OrderURLs.
+type URLOrderFunc func(req *Request, urls []*url.URL) []*url.URL
+
+// PreferHTTPS is a URLOrderFunc which moves https URLs before http URLs. The
+// order is otherwise unchanged.
+func PreferHTTPS(req *Request, urls []*url.URL) []*url.URL {
+	sort.SliceStable(urls, func(i, j int) bool {
+		return urls[i].Scheme == "https" && urls[j].Scheme != "https"
+	})
+
+	return urls
+}
+
+// PreferHosts returns a URLOrderFunc which moves URLs with the hostnames
+// |hosts| to the front, in the order given. The order is otherwise unchanged.
+func PreferHosts(hosts ...string) URLOrderFunc {
+	return func(req *Request, urls []*url.URL) []*url.URL {
+		rank := func(u *url.URL) int {
+			for i, h := range hosts {
+				if strings.EqualFold(u.Hostname(), h) {
+					return i
+				}
+			}
+
+			return len(hosts)
+		}
+
+		sort.SliceStable(urls, func(i, j int) bool {
+			return rank(urls[i]) < rank(urls[j])
+		})
+
+		return urls
+	}
+}
+
+// ExcludeHosts returns a URLOrderFunc which removes URLs with the hostnames
+// |hosts|.
+func ExcludeHosts(hosts ...string) URLOrderFunc {
+	return func(req *Request, urls []*url.URL) []*url.URL {
+		var result []*url.URL
+
+		for _, u := range urls {
+			excluded := false
+			for _, h := range hosts {
+				if strings.EqualFold(u.Hostname(), h) {
+					excluded = true
+					break
+				}
+			}
+
+			if !excluded {
+				result = append(result, u)
+			}
+		}
+
+		return result
+	}
+}
+
+// ChainURLOrders returns a URLOrderFunc which applies each of |funcs| in
+// turn.
+func ChainURLOrders(funcs ...URLOrderFunc) URLOrderFunc {
+	return func(req *Request, urls []*url.URL) []*url.URL {
+		for _, f := range funcs {
+			urls = f(req, urls)
+		}
+
+		return urls
+	}
+}
@@ -0,0 +1,67 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func parseURLs(rawURLs ...string) []*url.URL {
+	var urls []*url.URL
+	for _, r := range rawURLs {
+		u, _ := url.Parse(r)
+		urls = append(urls, u)
+	}
+
+	return urls
+}
+
+func joinURLs(urls []*url.URL) string {
+	var s []string
+	for _, u := range urls {
+		s = append(s, u.String())
+	}
+
+	return strings.Join(s, " ")
+}
+
+func TestURLOrder(t *testing.T) {
+	tests := []struct {
+		Order    URLOrderFunc
+		Expected string
+	}{
+		{PreferHTTPS, "https://b.example https://d.example http://a.example http://c.example"},
+		{PreferHosts("c.example", "d.example"), "http://c.example https://d.example http://a.example https://b.example"},
+		{ExcludeHosts("a.example", "B.EXAMPLE"), "http://c.example https://d.example"},
+		{ChainURLOrders(ExcludeHosts("b.example"), PreferHTTPS), "https://d.example http://a.example http://c.example"},
+	}
+
+	for i, test := range tests {
+		urls := parseURLs("http://a.example", "https://b.example", "http://c.example", "https://d.example")
+
+		actual := joinURLs(test.Order(nil, urls))
+		if actual != test.Expected {
+			t.Errorf("Test %d: got %s, expected %s", i, actual, test.Expected)
+		}
+	}
+}
+
+func TestClientOrderURLs(t *testing.T) {
+	test.Start(test.Bootstrap)
+	defer test.Finish()
+
+	client := &Client{
+		OrderURLs: ExcludeHosts("rdap.nic.cz"),
+	}
+
+	_, err := client.Do(NewDomainRequest("example.cz"))
+	if !isClientError(NoWorkingServers, err) {
+		t.Errorf("Expected NoWorkingServers, got %v", err)
+	}
+}
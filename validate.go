@@ -0,0 +1,334 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Violation is a departure from RFC 9083 found in an RDAP response, see
+// ValidateResponse().
+type Violation struct {
+	// JSON path of the offending member, e.g. "$.entities[0].events[1]".
+	Path string
+
+	// Description of the problem.
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ValidationError is returned by a Decoder with the StrictDecoding option,
+// when the response doesn't conform to RFC 9083.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (v *ValidationError) Error() string {
+	if len(v.Violations) == 1 {
+		return "RDAP response violates RFC 9083: " + v.Violations[0].String()
+	}
+
+	return fmt.Sprintf("RDAP response violates RFC 9083: %s (and %d more)", v.Violations[0], len(v.Violations)-1)
+}
+
+// StrictDecoding returns a DecoderOption which validates the response
+// against RFC 9083 before decoding it, see ValidateResponse().
+//
+// Instead of the usual best-effort decoding, Decode() returns a
+// *ValidationError listing the violations, if there are any.
+func StrictDecoding() DecoderOption {
+	return func(d *Decoder) {
+		d.strict = true
+	}
+}
+
+// ValidateResponse checks the RDAP response |jsonBlob| against RFC 9083,
+// e.g. for registries testing their own servers.
+//
+// The checks include:
+//
+//   - The response is a JSON object, with an rdapConformance array.
+//   - Nested objects (entities, nameservers, networks, autnums, and search
+//     results) have the correct objectClassName.
+//   - Members have the correct JSON types, e.g. status is an array of
+//     strings, and startAutnum is a number.
+//   - Required members are present: eventAction and eventDate of events,
+//     href of links, description of notices and remarks, and type and
+//     identifier of publicIds.
+//   - eventDates are RFC 3339 dates.
+//   - asEventActor events omit eventActor.
+//
+// Unknown members (e.g. extensions) are not checked.
+//
+// Returns the violations found (nil if none), or an error if |jsonBlob|
+// isn't valid JSON.
+func ValidateResponse(jsonBlob []byte) ([]Violation, error) {
+	var src interface{}
+	if err := json.Unmarshal(jsonBlob, &src); err != nil {
+		return nil, err
+	}
+
+	v := &responseValidator{}
+
+	obj, ok := src.(map[string]interface{})
+	if !ok {
+		v.add("$", "response is not a JSON object")
+		return v.violations, nil
+	}
+
+	if _, exists := obj["rdapConformance"]; !exists {
+		v.add("$", "missing rdapConformance")
+	}
+
+	class := ""
+	if c, ok := obj["objectClassName"].(string); ok {
+		class = c
+	}
+
+	v.checkObject("$", obj, class)
+
+	for member, class := range map[string]string{
+		"domainSearchResults":     "domain",
+		"nameserverSearchResults": "nameserver",
+		"entitySearchResults":     "entity",
+		"networkSearchResults":    "ip network",
+		"autnumSearchResults":     "autnum",
+	} {
+		if _, exists := obj[member]; exists {
+			v.checkObjects("$."+member, obj[member], class)
+		}
+	}
+
+	return v.violations, nil
+}
+
+// responseValidator implements ValidateResponse.
+type responseValidator struct {
+	violations []Violation
+}
+
+func (v *responseValidator) add(path string, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// checkObjects checks |src| is an array of objects of class |class|.
+func (v *responseValidator) checkObjects(path string, src interface{}, class string) {
+	array, ok := src.([]interface{})
+	if !ok {
+		v.add(path, "must be an array")
+		return
+	}
+
+	for i, item := range array {
+		itemPath := fmt.Sprintf("%s[%d]", path, i)
+
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			v.add(itemPath, "must be an object")
+			continue
+		}
+
+		if c, exists := obj["objectClassName"]; !exists {
+			v.add(itemPath, "missing objectClassName")
+		} else if c != class {
+			v.add(itemPath+".objectClassName", "must be %q, got %v", class, c)
+		}
+
+		v.checkObject(itemPath, obj, class)
+	}
+}
+
+// checkObject checks the members of the object |obj|, of class |class|
+// (empty string for top level objects without an objectClassName).
+func (v *responseValidator) checkObject(path string, obj map[string]interface{}, class string) {
+	for _, name := range []string{"objectClassName", "handle", "port43", "ldhName", "unicodeName", "lang", "startAddress", "endAddress", "name", "type", "country", "parentHandle", "ipVersion"} {
+		v.checkString(path, obj, name)
+	}
+
+	for _, name := range []string{"rdapConformance", "status", "roles"} {
+		v.checkStrings(path, obj, name)
+	}
+
+	for _, name := range []string{"startAutnum", "endAutnum", "errorCode"} {
+		if value, exists := obj[name]; exists {
+			if _, ok := value.(float64); !ok {
+				v.add(path+"."+name, "must be a number")
+			}
+		}
+	}
+
+	if ipVersion, ok := obj["ipVersion"].(string); ok && ipVersion != "v4" && ipVersion != "v6" {
+		v.add(path+".ipVersion", "must be \"v4\" or \"v6\", got %q", ipVersion)
+	}
+
+	if value, exists := obj["description"]; exists && class == "" {
+		// Error response description.
+		if !isStringArray(value) {
+			v.add(path+".description", "must be an array of strings")
+		}
+	}
+
+	v.checkArray(path, obj, "events", v.checkEvent)
+	v.checkArray(path, obj, "asEventActor", v.checkActorEvent)
+	v.checkArray(path, obj, "links", v.checkLink)
+	v.checkArray(path, obj, "notices", v.checkNotice)
+	v.checkArray(path, obj, "remarks", v.checkNotice)
+	v.checkArray(path, obj, "publicIds", v.checkPublicID)
+
+	if value, exists := obj["vcardArray"]; exists {
+		if array, ok := value.([]interface{}); !ok || len(array) != 2 || array[0] != "vcard" {
+			v.add(path+".vcardArray", "must be [\"vcard\", [properties]]")
+		}
+	}
+
+	if value, exists := obj["entities"]; exists {
+		v.checkObjects(path+".entities", value, "entity")
+	}
+
+	if value, exists := obj["nameservers"]; exists {
+		v.checkObjects(path+".nameservers", value, "nameserver")
+	}
+
+	if value, exists := obj["autnums"]; exists {
+		v.checkObjects(path+".autnums", value, "autnum")
+	}
+
+	if value, exists := obj["networks"]; exists {
+		v.checkObjects(path+".networks", value, "ip network")
+	}
+
+	if value, exists := obj["network"]; exists {
+		v.checkObjects(path+".network", []interface{}{value}, "ip network")
+	}
+}
+
+// checkArray checks |obj|'s member |name| (if present) is an array of
+// objects, each checked by |check|.
+func (v *responseValidator) checkArray(path string, obj map[string]interface{}, name string, check func(path string, item map[string]interface{})) {
+	value, exists := obj[name]
+	if !exists {
+		return
+	}
+
+	array, ok := value.([]interface{})
+	if !ok {
+		v.add(path+"."+name, "must be an array")
+		return
+	}
+
+	for i, item := range array {
+		itemPath := fmt.Sprintf("%s.%s[%d]", path, name, i)
+
+		if itemObj, ok := item.(map[string]interface{}); ok {
+			check(itemPath, itemObj)
+		} else {
+			v.add(itemPath, "must be an object")
+		}
+	}
+}
+
+func (v *responseValidator) checkEvent(path string, event map[string]interface{}) {
+	v.checkRequiredString(path, event, "eventAction")
+	v.checkRequiredString(path, event, "eventDate")
+	v.checkString(path, event, "eventActor")
+	v.checkArray(path, event, "links", v.checkLink)
+
+	if date, ok := event["eventDate"].(string); ok {
+		if _, err := time.Parse(time.RFC3339, date); err != nil {
+			v.add(path+".eventDate", "not an RFC 3339 date: %q", date)
+		}
+	}
+}
+
+func (v *responseValidator) checkActorEvent(path string, event map[string]interface{}) {
+	v.checkEvent(path, event)
+
+	if _, exists := event["eventActor"]; exists {
+		v.add(path+".eventActor", "must be omitted in asEventActor")
+	}
+}
+
+func (v *responseValidator) checkLink(path string, link map[string]interface{}) {
+	v.checkRequiredString(path, link, "href")
+
+	for _, name := range []string{"value", "rel", "type", "title", "media"} {
+		v.checkString(path, link, name)
+	}
+
+	if hreflang, exists := link["hreflang"]; exists {
+		if _, ok := hreflang.(string); !ok && !isStringArray(hreflang) {
+			v.add(path+".hreflang", "must be a string or an array of strings")
+		}
+	}
+
+	if href, ok := link["href"].(string); ok && !strings.Contains(href, ":") {
+		v.add(path+".href", "not an absolute URI: %q", href)
+	}
+}
+
+func (v *responseValidator) checkNotice(path string, notice map[string]interface{}) {
+	v.checkString(path, notice, "title")
+	v.checkString(path, notice, "type")
+	v.checkArray(path, notice, "links", v.checkLink)
+
+	if description, exists := notice["description"]; !exists {
+		v.add(path, "missing description")
+	} else if !isStringArray(description) {
+		v.add(path+".description", "must be an array of strings")
+	}
+}
+
+func (v *responseValidator) checkPublicID(path string, publicID map[string]interface{}) {
+	v.checkRequiredString(path, publicID, "type")
+	v.checkRequiredString(path, publicID, "identifier")
+}
+
+// checkString checks |obj|'s member |name|, if present, is a string.
+func (v *responseValidator) checkString(path string, obj map[string]interface{}, name string) {
+	if value, exists := obj[name]; exists {
+		if _, ok := value.(string); !ok {
+			v.add(path+"."+name, "must be a string")
+		}
+	}
+}
+
+// checkRequiredString checks |obj|'s member |name| is present, and a string.
+func (v *responseValidator) checkRequiredString(path string, obj map[string]interface{}, name string) {
+	if _, exists := obj[name]; !exists {
+		v.add(path, "missing %s", name)
+		return
+	}
+
+	v.checkString(path, obj, name)
+}
+
+// checkStrings checks |obj|'s member |name|, if present, is an array of
+// strings.
+func (v *responseValidator) checkStrings(path string, obj map[string]interface{}, name string) {
+	if value, exists := obj[name]; exists && !isStringArray(value) {
+		v.add(path+"."+name, "must be an array of strings")
+	}
+}
+
+func isStringArray(value interface{}) bool {
+	array, ok := value.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, item := range array {
+		if _, ok := item.(string); !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,80 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestValidateResponse(t *testing.T) {
+	violations, err := ValidateResponse(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json"))
+	if err != nil {
+		t.Fatal(err)
+	} else if len(violations) != 0 {
+		t.Errorf("Valid response has violations: %v", violations)
+	}
+
+	json := `{
+		"objectClassName": "domain",
+		"ldhName": "example.com",
+		"status": "active",
+		"events": [{"eventAction": "registration", "eventDate": "yesterday"}, {"eventDate": "2017-01-01T00:00:00Z"}],
+		"links": [{"href": "/relative"}],
+		"notices": [{"title": "Terms"}],
+		"entities": [{"handle": "X", "roles": ["registrant"]}],
+		"nameservers": [{"objectClassName": "entity", "ldhName": "ns1.example.com"}]
+	}`
+
+	violations, err = ValidateResponse([]byte(json))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Violation{
+		{"$", "missing rdapConformance"},
+		{"$.status", "must be an array of strings"},
+		{"$.events[0].eventDate", "not an RFC 3339 date: \"yesterday\""},
+		{"$.events[1]", "missing eventAction"},
+		{"$.links[0].href", "not an absolute URI: \"/relative\""},
+		{"$.notices[0]", "missing description"},
+		{"$.entities[0]", "missing objectClassName"},
+		{"$.nameservers[0].objectClassName", "must be \"nameserver\", got entity"},
+	}
+
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Got violations %v, expected %v", violations, expected)
+	}
+
+	if _, err := ValidateResponse([]byte("{")); err == nil {
+		t.Errorf("Expected error for invalid JSON")
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	json := `{"objectClassName": "domain", "rdapConformance": ["rdap_level_0"], "events": [{}]}`
+
+	if _, err := NewDecoder([]byte(json)).Decode(); err != nil {
+		t.Errorf("Lenient decode failed: %s", err)
+	}
+
+	result, err := NewDecoder([]byte(json), StrictDecoding()).Decode()
+	validationErr, ok := err.(*ValidationError)
+	if !ok || result != nil {
+		t.Fatalf("Expected *ValidationError, got %v, %v", result, err)
+	}
+
+	if len(validationErr.Violations) != 2 {
+		t.Errorf("Expected 2 violations, got %v", validationErr.Violations)
+	}
+
+	json = `{"objectClassName": "domain", "rdapConformance": ["rdap_level_0"], "ldhName": "example.com"}`
+	result, err = NewDecoder([]byte(json), StrictDecoding()).Decode()
+	if d, ok := result.(*Domain); err != nil || !ok || d.LDHName != "example.com" {
+		t.Errorf("Strict decode of valid response failed: %v, %v", result, err)
+	}
+}
@@ -360,61 +360,64 @@ func (v *VCard) Name() string {
 	return v.getFirstPropertySingleString("fn")
 }
 
-// POBox returns the address's PO Box.
+// POBox returns the preferred address's PO Box, see Addresses().
 //
 // Returns empty string if no address is present.
 func (v *VCard) POBox() string {
-	return v.getFirstAddressField(0)
+	return v.preferredAddress().POBox
 }
 
-// ExtendedAddress returns the "extended address", e.g. an apartment
-// or suite number.
+// ExtendedAddress returns the preferred address's "extended address", e.g. an
+// apartment or suite number.
 //
 // Returns empty string if no address is present.
 func (v *VCard) ExtendedAddress() string {
-	return v.getFirstAddressField(1)
+	return v.preferredAddress().ExtendedAddress
 }
 
-// StreetAddress returns the street address.
+// StreetAddress returns the preferred address's street address.
 //
 // Returns empty string if no address is present.
 func (v *VCard) StreetAddress() string {
-	return v.getFirstAddressField(2)
+	return v.preferredAddress().StreetAddress
 }
 
-// Locality returns the address locality.
+// Locality returns the preferred address's locality.
 //
 // Returns empty string if no address is present.
 func (v *VCard) Locality() string {
-	return v.getFirstAddressField(3)
+	return v.preferredAddress().Locality
 }
 
-// Region returns the address region (e.g. state or province).
+// Region returns the preferred address's region (e.g. state or province).
 //
 // Returns empty string if no address is present.
 func (v *VCard) Region() string {
-	return v.getFirstAddressField(4)
+	return v.preferredAddress().Region
 }
 
-// PostalCode returns the address postal code (e.g. zip code).
+// PostalCode returns the preferred address's postal code (e.g. zip code).
 //
 // Returns empty string if no address is present.
 func (v *VCard) PostalCode() string {
-	return v.getFirstAddressField(5)
+	return v.preferredAddress().PostalCode
 }
 
-// Country returns the address country name.
+// Country returns the preferred address's country name.
 //
 // This is the full country name.
 //
 // Returns empty string if no address is present.
 func (v *VCard) Country() string {
-	return v.getFirstAddressField(6)
+	return v.preferredAddress().Country
 }
 
 // Tel returns the VCard's first (voice) telephone number.
 //
 // Returns empty string if the VCard contains no suitable telephone number.
+//
+// The number is returned as-is, e.g. "tel:+1-418-656-9254;ext=102". For
+// normalized numbers, see Phones().
 func (v *VCard) Tel() string {
 	properties := v.Get("tel")
 
@@ -464,6 +467,8 @@ func (v *VCard) Fax() string {
 // Email returns the VCard's first email address.
 //
 // Returns empty string if the VCard contains no email addresses.
+//
+// The address is returned as-is. For normalized addresses, see Emails().
 func (v *VCard) Email() string {
 	return v.getFirstPropertySingleString("email")
 }
@@ -474,18 +479,3 @@ func (v *VCard) Email() string {
 func (v *VCard) Org() string {
 	return v.getFirstPropertySingleString("org")
 }
-
-func (v *VCard) getFirstAddressField(index int) string {
-	adr := v.GetFirst("adr")
-	if adr == nil {
-		return ""
-	}
-
-	values := adr.Values()
-
-	if index >= len(values) {
-		return ""
-	}
-
-	return values[index]
-}
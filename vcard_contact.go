@@ -0,0 +1,340 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Phone is a normalized vCard telephone number.
+//
+// RDAP servers use a mixture of formats for telephone numbers, e.g.
+// "tel:+1-418-656-9254;ext=102", "+1.4186569254", or "+1 418 656 9254 x102".
+// These are all normalized to Number "+14186569254", Extension "102".
+type Phone struct {
+	// Number with the tel: prefix, URI parameters, and visual separators
+	// removed. International numbers start with "+". e.g. "+14186569254".
+	Number string
+
+	// Extension, e.g. "102". Empty string if none.
+	Extension string
+
+	// vCard type parameter values, e.g. ["work", "voice"].
+	Types []string
+
+	// The original value, e.g. "tel:+1-418-656-9254;ext=102".
+	Original string
+}
+
+// textExtension matches a trailing extension of a plain text telephone
+// number, e.g. "+1 418 656 9254 ext. 102" or "+1.4186569254x102".
+var textExtension = regexp.MustCompile(`(?i)\s*(?:ext\.?|x)\s*(\d+)$`)
+
+// ParsePhone parses the telephone number |value|, which is either a tel: URI
+// (https://tools.ietf.org/html/rfc3966) or plain text.
+func ParsePhone(value string) Phone {
+	p := Phone{Original: value}
+
+	s := strings.TrimSpace(value)
+	if len(s) >= 4 && strings.EqualFold(s[0:4], "tel:") {
+		s = s[4:]
+	}
+
+	// URI parameters, e.g. ";ext=102".
+	s, params, _ := strings.Cut(s, ";")
+	for _, param := range strings.Split(params, ";") {
+		if name, value, _ := strings.Cut(param, "="); strings.EqualFold(name, "ext") {
+			p.Extension = value
+		}
+	}
+
+	if m := textExtension.FindStringSubmatchIndex(s); m != nil && p.Extension == "" {
+		p.Extension = s[m[2]:m[3]]
+		s = s[:m[0]]
+	}
+
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(s) {
+		if r == '+' && i == 0 {
+			b.WriteRune(r)
+		} else if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	p.Number = b.String()
+
+	return p
+}
+
+// E164 returns the number in E.164 format, e.g. "+14186569254".
+//
+// Returns empty string if the number isn't international (doesn't start with
+// "+"), or is too long. The extension is not included.
+func (p Phone) E164() string {
+	digits := strings.TrimPrefix(p.Number, "+")
+
+	if digits == p.Number || len(digits) == 0 || len(digits) > 15 {
+		return ""
+	}
+
+	return p.Number
+}
+
+// URI returns the number as a tel: URI, e.g. "tel:+14186569254;ext=102".
+//
+// Returns empty string if there's no number.
+func (p Phone) URI() string {
+	if p.Number == "" {
+		return ""
+	}
+
+	uri := "tel:" + p.Number
+	if p.Extension != "" {
+		uri += ";ext=" + p.Extension
+	}
+
+	return uri
+}
+
+// String returns the number in display form, e.g. "+14186569254 ext. 102".
+func (p Phone) String() string {
+	if p.Extension == "" {
+		return p.Number
+	}
+
+	return p.Number + " ext. " + p.Extension
+}
+
+// EmailAddress is a normalized vCard email address.
+//
+// RDAP servers send plain addresses, and mailto: URIs (sometimes with
+// percent encoding, or a ?subject= query). These are all normalized to a plain
+// address, with a lower case domain.
+type EmailAddress struct {
+	// Address, e.g. "user@example.com". The domain may be an internationalized
+	// domain name, see ASCII() and Unicode().
+	Address string
+
+	// vCard type parameter values, e.g. ["work"].
+	Types []string
+
+	// The original value, e.g. "mailto:user@EXAMPLE.com".
+	Original string
+}
+
+// ParseEmail parses the email address |value|, which is either a mailto: URI
+// (https://tools.ietf.org/html/rfc6068) or plain text.
+func ParseEmail(value string) EmailAddress {
+	e := EmailAddress{Original: value}
+
+	s := strings.TrimSpace(value)
+	if len(s) >= 7 && strings.EqualFold(s[0:7], "mailto:") {
+		s, _, _ = strings.Cut(s[7:], "?")
+
+		if unescaped, err := url.PathUnescape(s); err == nil {
+			s = unescaped
+		}
+	}
+
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "<"), ">"))
+
+	if i := strings.LastIndex(s, "@"); i != -1 {
+		s = s[:i+1] + strings.TrimSuffix(strings.ToLower(s[i+1:]), ".")
+	}
+
+	e.Address = s
+
+	return e
+}
+
+// ASCII returns the address with its domain in ASCII (punycode) form, e.g.
+// "user@xn--bcher-kva.example".
+//
+// Returns the Address unchanged if the domain can't be converted.
+func (e EmailAddress) ASCII() string {
+	return e.convertDomain(idna.Lookup.ToASCII)
+}
+
+// Unicode returns the address with its domain in Unicode form, e.g.
+// "user@bücher.example".
+//
+// Returns the Address unchanged if the domain can't be converted.
+func (e EmailAddress) Unicode() string {
+	return e.convertDomain(idna.Lookup.ToUnicode)
+}
+
+func (e EmailAddress) convertDomain(convert func(string) (string, error)) string {
+	i := strings.LastIndex(e.Address, "@")
+	if i == -1 {
+		return e.Address
+	}
+
+	domain, err := convert(e.Address[i+1:])
+	if err != nil {
+		return e.Address
+	}
+
+	return e.Address[:i+1] + domain
+}
+
+// String returns the Address.
+func (e EmailAddress) String() string {
+	return e.Address
+}
+
+// Address is a vCard delivery address (an adr property).
+type Address struct {
+	POBox string
+
+	// Extended address, e.g. an apartment or suite number.
+	ExtendedAddress string
+
+	StreetAddress string
+	Locality      string
+
+	// Region, e.g. state or province.
+	Region string
+
+	// Postal code, e.g. zip code.
+	PostalCode string
+
+	// Full country name.
+	Country string
+
+	// Formatted address label parameter, e.g. "2875 Laurier\nQuebec". Empty
+	// string if none.
+	Label string
+
+	// vCard type parameter values, e.g. ["work"].
+	Types []string
+
+	// vCard pref parameter value, from 1 (most preferred) to 100. Zero if
+	// unspecified.
+	Pref int
+}
+
+// String returns the address on one line, e.g. "2875 Laurier, Quebec, QC,
+// Canada", or the Label if the address has no components.
+func (a Address) String() string {
+	var lines []string
+	for _, line := range []string{a.POBox, a.ExtendedAddress, a.StreetAddress, a.Locality, a.Region, a.PostalCode, a.Country} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(lines) > 0 {
+		return strings.Join(lines, ", ")
+	}
+
+	return strings.Join(strings.Fields(a.Label), " ")
+}
+
+// Addresses returns all of the VCard's delivery addresses, in order.
+//
+// Address components with multiple values (e.g. two street address lines)
+// are joined by ", ". Addresses with no components and no label are skipped.
+//
+// For the components of the preferred address, see POBox(), StreetAddress(),
+// etc.
+func (v *VCard) Addresses() []Address {
+	var addresses []Address
+
+	for _, p := range v.Get("adr") {
+		var a Address
+
+		if components, ok := p.Value.([]interface{}); ok {
+			for i, dst := range []*string{&a.POBox, &a.ExtendedAddress, &a.StreetAddress, &a.Locality, &a.Region, &a.PostalCode, &a.Country} {
+				if i < len(components) {
+					*dst = strings.Join((&VCardProperty{Value: components[i]}).Values(), ", ")
+				}
+			}
+		}
+
+		if labels := p.Parameters["label"]; len(labels) > 0 {
+			a.Label = labels[0]
+		}
+
+		a.Types = p.Parameters["type"]
+
+		if prefs := p.Parameters["pref"]; len(prefs) > 0 {
+			if pref, err := strconv.Atoi(prefs[0]); err == nil && pref >= 1 && pref <= 100 {
+				a.Pref = pref
+			}
+		}
+
+		if a.String() != "" {
+			addresses = append(addresses, a)
+		}
+	}
+
+	return addresses
+}
+
+// preferredAddress returns the VCard's address with the lowest pref value,
+// or the first address if none have a pref value. Returns an empty Address if
+// the VCard has no addresses.
+func (v *VCard) preferredAddress() Address {
+	var preferred Address
+
+	for i, a := range v.Addresses() {
+		if i == 0 || (a.Pref != 0 && (preferred.Pref == 0 || a.Pref < preferred.Pref)) {
+			preferred = a
+		}
+	}
+
+	return preferred
+}
+
+// Phones returns the VCard's telephone numbers (voice, fax, etc), normalized.
+//
+// For the first voice/fax number as-is, see Tel() and Fax().
+func (v *VCard) Phones() []Phone {
+	var phones []Phone
+
+	for _, p := range v.Get("tel") {
+		values := p.Values()
+		if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+			continue
+		}
+
+		phone := ParsePhone(values[0])
+		phone.Types = p.Parameters["type"]
+
+		if phone.Number != "" {
+			phones = append(phones, phone)
+		}
+	}
+
+	return phones
+}
+
+// Emails returns the VCard's email addresses, normalized.
+//
+// For the first email address as-is, see Email().
+func (v *VCard) Emails() []EmailAddress {
+	var emails []EmailAddress
+
+	for _, p := range v.Get("email") {
+		values := p.Values()
+		if len(values) == 0 {
+			continue
+		}
+
+		email := ParseEmail(values[0])
+		email.Types = p.Parameters["type"]
+
+		if email.Address != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
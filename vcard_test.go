@@ -6,6 +6,7 @@ package rdap
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/openrdap/rdap/test"
@@ -179,3 +180,206 @@ func TestVCardQuickAccessors(t *testing.T) {
 		t.Errorf("Got %v expected %v\n", got, expected)
 	}
 }
+
+func TestVCardPhones(t *testing.T) {
+	j, err := NewVCard(test.LoadFile("jcard/example.json"))
+	if j == nil || err != nil {
+		t.Fatalf("jCard parse failed %v %s\n", j, err)
+	}
+
+	phones := j.Phones()
+	if len(phones) != 2 {
+		t.Fatalf("Expected 2 phones, got %v", phones)
+	}
+
+	p := phones[0]
+	if p.Number != "+14186569254" || p.Extension != "102" || p.Original != "tel:+1-418-656-9254;ext=102" {
+		t.Errorf("Unexpected phone %+v", p)
+	} else if p.URI() != "tel:+14186569254;ext=102" || p.String() != "+14186569254 ext. 102" {
+		t.Errorf("Unexpected phone formatting %s, %s", p.URI(), p)
+	} else if !reflect.DeepEqual(p.Types, []string{"work", "voice"}) {
+		t.Errorf("Unexpected phone types %v", p.Types)
+	}
+}
+
+func TestParsePhone(t *testing.T) {
+	tests := []struct {
+		Value     string
+		Number    string
+		Extension string
+		E164      string
+	}{
+		{"tel:+1-418-656-9254;ext=102", "+14186569254", "102", "+14186569254"},
+		{"TEL:+44.2079460958", "+442079460958", "", "+442079460958"},
+		{"+1 (418) 656 9254 x102", "+14186569254", "102", "+14186569254"},
+		{"+420.222745111 ext. 7", "+420222745111", "7", "+420222745111"},
+		{"tel:7042;phone-context=example.com", "7042", "", ""},
+		{"tel:", "", "", ""},
+	}
+
+	for _, test := range tests {
+		p := ParsePhone(test.Value)
+
+		if p.Number != test.Number || p.Extension != test.Extension || p.E164() != test.E164 {
+			t.Errorf("%q: expected %q/%q/%q, got %q/%q/%q", test.Value,
+				test.Number, test.Extension, test.E164, p.Number, p.Extension, p.E164())
+		}
+	}
+}
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		Value   string
+		Address string
+		ASCII   string
+	}{
+		{"user@example.com", "user@example.com", "user@example.com"},
+		{" User@EXAMPLE.com. ", "User@example.com", "User@example.com"},
+		{"mailto:abuse@example.net?subject=Abuse", "abuse@example.net", "abuse@example.net"},
+		{"MAILTO:user%2Btag@example.net", "user+tag@example.net", "user+tag@example.net"},
+		{"<user@bücher.example>", "user@bücher.example", "user@xn--bcher-kva.example"},
+	}
+
+	for _, test := range tests {
+		e := ParseEmail(test.Value)
+
+		if e.Address != test.Address || e.ASCII() != test.ASCII || e.Original != test.Value {
+			t.Errorf("%q: expected %q/%q, got %q/%q", test.Value, test.Address, test.ASCII, e.Address, e.ASCII())
+		}
+	}
+
+	if e := ParseEmail("user@xn--bcher-kva.example"); e.Unicode() != "user@bücher.example" {
+		t.Errorf("Unexpected Unicode() %q", e.Unicode())
+	}
+}
+
+func TestVCardMarshalText(t *testing.T) {
+	j, err := NewVCard(test.LoadFile("jcard/example.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text, err := j.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCARD",
+		"VERSION:4.0",
+		"FN:Simon Perreault",
+		"N:Perreault;Simon;;;ing. jr,M.Sc.",
+		"BDAY:--0203",
+		"ANNIVERSARY:20090808T143000-0500",
+		"GENDER:M",
+		"LANG;PREF=1:fr",
+		"LANG;PREF=2:en",
+		"ORG;TYPE=work:Viagenie",
+		"ADR;TYPE=work:;Suite D2-630;2875 Laurier;Quebec;QC;G1V 2M2;Canada",
+		"TEL;VALUE=uri;PREF=1;TYPE=work,voice:tel:+1-418-656-9254;ext=102",
+		"TEL;VALUE=uri;TYPE=work,cell,voice,video,text:tel:+1-418-262-6501",
+		"EMAIL;TYPE=work:simon.perreault@viagenie.ca",
+		"GEO;TYPE=work:geo:46.772673,-71.282945",
+		"KEY;TYPE=work:http://www.viagenie.ca/simon.perreault/simon.asc",
+		"TZ;VALUE=utc-offset:-0500",
+		"URL;TYPE=home:http://nomis80.org",
+		"END:VCARD",
+		"",
+	}, "\r\n")
+
+	if string(text) != expected {
+		t.Errorf("Got:\n%s\nExpected:\n%s", text, expected)
+	}
+}
+
+func TestVCardMarshalTextEscaping(t *testing.T) {
+	j, err := NewVCard([]byte(`["vcard", [
+		["fn", {"group": "item1"}, "text", "Smith, Jones; Partners"],
+		["adr", {"label": "1 Main St\nSpringfield, \"USA\""}, "text", ["", "", "1 Main St", "Springfield", "", "", "USA"]],
+		["categories", {}, "text", "a,b", "c"],
+		["note", {}, "text", "` + strings.Repeat("é", 50) + `"],
+		["bad name", {}, "text", "x"]
+	]]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := j.MarshalText(); err == nil {
+		t.Errorf("Expected error for invalid property name")
+	}
+
+	j.Properties = j.Properties[0:4]
+	text, err := j.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(string(text), "\r\n")
+
+	expected := []string{
+		`item1.FN:Smith\, Jones\; Partners`,
+		`ADR;LABEL="1 Main St^nSpringfield, ^'USA^'":;;1 Main St;Springfield;;;USA`,
+		`CATEGORIES:a\,b,c`,
+	}
+
+	if !reflect.DeepEqual(lines[2:5], expected) {
+		t.Errorf("Got %q, expected %q", lines[2:5], expected)
+	}
+
+	// The 50 two-octet note characters are folded.
+	for _, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("Line not folded: %q", l)
+		}
+	}
+
+	if note := lines[5] + strings.TrimPrefix(lines[6], " "); note != "NOTE:"+strings.Repeat("é", 50) {
+		t.Errorf("Bad folded note %q %q", lines[5], lines[6])
+	}
+}
+
+func TestVCardAddresses(t *testing.T) {
+	j, err := NewVCard([]byte(`["vcard", [
+		["adr", {"type": "home"}, "text", ["", "", ["1 Main St", "Unit 2"], "Springfield", "", "", "USA"]],
+		["adr", {"type": "work", "pref": "1", "label": "2875 Laurier\nQuebec"}, "text", ["", "Suite D2-630", "2875 Laurier", "Quebec", "QC", "G1V 2M2", "Canada"]],
+		["adr", {"label": "PO Box 1, Nowhere"}, "text", ["", "", "", "", "", "", ""]],
+		["adr", {}, "text", ["", "", "", "", "", "", ""]]
+	]]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addresses := j.Addresses()
+	if len(addresses) != 3 {
+		t.Fatalf("Expected 3 addresses, got %+v", addresses)
+	}
+
+	expected := Address{
+		ExtendedAddress: "Suite D2-630",
+		StreetAddress:   "2875 Laurier",
+		Locality:        "Quebec",
+		Region:          "QC",
+		PostalCode:      "G1V 2M2",
+		Country:         "Canada",
+		Label:           "2875 Laurier\nQuebec",
+		Types:           []string{"work"},
+		Pref:            1,
+	}
+
+	if !reflect.DeepEqual(addresses[1], expected) {
+		t.Errorf("Got %+v, expected %+v", addresses[1], expected)
+	}
+
+	if s := addresses[0].String(); s != "1 Main St, Unit 2, Springfield, USA" {
+		t.Errorf("Unexpected address %q", s)
+	}
+
+	if s := addresses[2].String(); s != "PO Box 1, Nowhere" {
+		t.Errorf("Unexpected label only address %q", s)
+	}
+
+	// The quick accessors use the preferred address.
+	if j.StreetAddress() != "2875 Laurier" || j.Country() != "Canada" {
+		t.Errorf("Quick accessors didn't use the preferred address: %q, %q", j.StreetAddress(), j.Country())
+	}
+}
@@ -0,0 +1,238 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// vCardDefaultTypes are the RFC 6350 default value types of properties whose
+// default isn't "text". The VALUE parameter is omitted for default types.
+var vCardDefaultTypes = map[string]string{
+	"anniversary": "date-and-or-time",
+	"bday":        "date-and-or-time",
+	"caladruri":   "uri",
+	"caluri":      "uri",
+	"fburl":       "uri",
+	"geo":         "uri",
+	"impp":        "uri",
+	"key":         "uri",
+	"lang":        "language-tag",
+	"logo":        "uri",
+	"member":      "uri",
+	"photo":       "uri",
+	"related":     "uri",
+	"rev":         "timestamp",
+	"sound":       "uri",
+	"source":      "uri",
+	"uid":         "uri",
+	"url":         "uri",
+}
+
+// vCardStructuredProperties are the properties with structured values, whose
+// components are separated by semicolons.
+var vCardStructuredProperties = map[string]bool{
+	"adr":          true,
+	"clientpidmap": true,
+	"gender":       true,
+	"n":            true,
+	"org":          true,
+}
+
+// MarshalText returns the vCard in the RFC 6350 text format (a .vcf file),
+// e.g.:
+//
+//	BEGIN:VCARD
+//	VERSION:4.0
+//	FN:Simon Perreault
+//	N:Perreault;Simon;;;ing. jr,M.Sc.
+//	TEL;VALUE=uri;PREF=1;TYPE=work,voice:tel:+1-418-656-9254;ext=102
+//	END:VCARD
+//
+// The conversion follows RFC 7095 section 3 in reverse: jCard value types
+// other than the property's default become VALUE parameters, the "group"
+// parameter becomes a property group prefix, and dates and times are
+// converted to the basic format. Lines are terminated by CRLF, and folded at
+// 75 octets.
+//
+// Returns an error if a property name is not a valid vCard name.
+func (v *VCard) MarshalText() ([]byte, error) {
+	var b bytes.Buffer
+
+	writeVCardLine(&b, "BEGIN:VCARD")
+	writeVCardLine(&b, "VERSION:4.0")
+
+	for _, p := range v.Properties {
+		if strings.EqualFold(p.Name, "version") {
+			continue
+		}
+
+		line, err := p.text()
+		if err != nil {
+			return nil, err
+		}
+
+		writeVCardLine(&b, line)
+	}
+
+	writeVCardLine(&b, "END:VCARD")
+
+	return b.Bytes(), nil
+}
+
+// text returns the VCardProperty as an unfolded RFC 6350 content line.
+func (p *VCardProperty) text() (string, error) {
+	if !isVCardName(p.Name) {
+		return "", vCardError(fmt.Sprintf("invalid property name %q", p.Name))
+	}
+
+	name := strings.ToLower(p.Name)
+
+	var b strings.Builder
+
+	if groups := p.Parameters["group"]; len(groups) > 0 && isVCardName(groups[0]) {
+		b.WriteString(groups[0] + ".")
+	}
+
+	b.WriteString(strings.ToUpper(name))
+
+	defaultType, ok := vCardDefaultTypes[name]
+	if !ok {
+		defaultType = "text"
+	}
+
+	if p.Type != "" && p.Type != "unknown" && p.Type != defaultType {
+		b.WriteString(";VALUE=" + p.Type)
+	}
+
+	var keys []string
+	for k := range p.Parameters {
+		if k != "group" && isVCardName(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		var values []string
+		for _, value := range p.Parameters[k] {
+			values = append(values, vCardParameterValue(value))
+		}
+
+		b.WriteString(";" + strings.ToUpper(k) + "=" + strings.Join(values, ","))
+	}
+
+	b.WriteString(":")
+
+	if a, ok := p.Value.([]interface{}); ok && vCardStructuredProperties[name] {
+		components := make([]string, 0, len(a))
+		for _, c := range a {
+			components = append(components, vCardValueText(c, p.Type))
+		}
+
+		b.WriteString(strings.Join(components, ";"))
+	} else {
+		b.WriteString(vCardValueText(p.Value, p.Type))
+	}
+
+	return b.String(), nil
+}
+
+// vCardValueText returns the text form of the jCard value |value| of type
+// |valueType|. Arrays are converted to comma separated lists.
+func vCardValueText(value interface{}, valueType string) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value))
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case string:
+		switch valueType {
+		case "text", "unknown", "":
+			return strings.NewReplacer(`\`, `\\`, ",", `\,`, ";", `\;`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+		case "date", "date-time", "date-and-or-time", "timestamp":
+			date, time, hasTime := strings.Cut(value, "T")
+
+			prefix := len(date) - len(strings.TrimLeft(date, "-"))
+			date = date[:prefix] + strings.Replace(date[prefix:], "-", "", -1)
+
+			if hasTime {
+				date += "T" + strings.Replace(time, ":", "", -1)
+			}
+
+			return date
+		case "time", "utc-offset":
+			return strings.Replace(value, ":", "", -1)
+		}
+
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, vCardValueText(v, valueType))
+		}
+
+		return strings.Join(values, ",")
+	}
+
+	return ""
+}
+
+// vCardParameterValue returns the parameter value |value|, escaped as per RFC
+// 6868, and quoted if necessary.
+func vCardParameterValue(value string) string {
+	value = strings.NewReplacer("^", "^^", "\r\n", "^n", "\n", "^n", `"`, "^'").Replace(value)
+
+	if strings.ContainsAny(value, ",;:") {
+		return `"` + value + `"`
+	}
+
+	return value
+}
+
+// isVCardName returns true if |name| is a valid property, parameter, or group
+// name (letters, digits, and hyphens).
+func isVCardName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// writeVCardLine writes the content line |line| to |b|, folded at 75 octets
+// without splitting UTF-8 sequences.
+func writeVCardLine(b *bytes.Buffer, line string) {
+	const maxLength = 75
+
+	limit := maxLength
+	for len(line) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(line[i]) {
+			i--
+		}
+
+		b.WriteString(line[:i] + "\r\n ")
+		line = line[i:]
+
+		// Continuation lines start with a space.
+		limit = maxLength - 1
+	}
+
+	b.WriteString(line + "\r\n")
+}
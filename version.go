@@ -0,0 +1,83 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// libraryVersion is the version of this package.
+const libraryVersion = "0.9.1"
+
+// Build metadata. These can be set at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/openrdap/rdap.buildCommit=$(git rev-parse HEAD)"
+//
+// Otherwise, the Go toolchain's version control information is used, if
+// available.
+var (
+	buildCommit string
+	buildDate   string
+)
+
+// BuildInfo describes the build of this package.
+type BuildInfo struct {
+	// Version, e.g. "0.9.1".
+	Version string `json:"version"`
+
+	// Version control commit hash and date (RFC 3339), if known.
+	Commit string `json:"commit,omitempty"`
+	Date   string `json:"date,omitempty"`
+
+	// Go version used to build, e.g. "go1.19.4".
+	GoVersion string `json:"go_version"`
+}
+
+// Version returns the version of this package, e.g. "0.9.1".
+func Version() string {
+	return libraryVersion
+}
+
+// Build returns the version and build metadata of this package, e.g. for
+// bug reports.
+func Build() BuildInfo {
+	b := BuildInfo{
+		Version:   libraryVersion,
+		Commit:    buildCommit,
+		Date:      buildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && b.Commit == "" {
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				b.Commit = s.Value
+			case "vcs.time":
+				if b.Date == "" {
+					b.Date = s.Value
+				}
+			}
+		}
+	}
+
+	return b
+}
+
+// DefaultUserAgent returns the User-Agent sent by a Client with no
+// UserAgent set, e.g. "OpenRDAP/0.9.1 (+https://www.openrdap.org; 0123abc)".
+//
+// This lets RDAP server operators identify client versions in their logs.
+func DefaultUserAgent() string {
+	b := Build()
+
+	details := "+https://www.openrdap.org"
+	if len(b.Commit) >= 7 {
+		details += "; " + b.Commit[0:7]
+	}
+
+	return "OpenRDAP/" + b.Version + " (" + details + ")"
+}
@@ -0,0 +1,44 @@
+// OpenRDAP
+// Copyright 2017 Tom Harwood
+// MIT License, see the LICENSE file.
+
+package rdap
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openrdap/rdap/test"
+)
+
+func TestVersion(t *testing.T) {
+	b := Build()
+
+	if b.Version != Version() || b.GoVersion == "" {
+		t.Errorf("Unexpected BuildInfo %+v", b)
+	}
+
+	if ua := DefaultUserAgent(); !strings.HasPrefix(ua, "OpenRDAP/"+Version()+" (") {
+		t.Errorf("Unexpected DefaultUserAgent %q", ua)
+	}
+}
+
+func TestDefaultUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write(test.LoadFile("rdap/rdap.nic.cz/domain-example.cz.json"))
+	}))
+	defer server.Close()
+
+	client := &Client{}
+	req := NewDomainRequest("example.cz").WithServer(parseURLs(server.URL)[0])
+
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("Do() error: %s", err)
+	} else if userAgent != DefaultUserAgent() {
+		t.Errorf("Expected User-Agent %q, got %q", DefaultUserAgent(), userAgent)
+	}
+}